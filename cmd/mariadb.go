@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
+	"CheckHealthDO/internal/utils/finder"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	discoverJSON bool
+)
+
+// mariadbCmd groups the MariaDB utility commands
+var mariadbCmd = &cobra.Command{
+	Use:   "mariadb",
+	Short: "MariaDB utilities",
+	Long:  `Utilities for inspecting the MariaDB server monitored by CheckHealth.`,
+}
+
+// discoverCmd prints the MariaDB settings found by auto-discovery
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Show MariaDB settings discovered from my.cnf and the running server",
+	Long: `Read my.cnf and the files it includes, look up the systemd unit and query the
+running server for @@log_error, @@datadir, @@slow_query_log_file and @@socket.
+These are the values CheckHealth uses when the configuration leaves them empty.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Fall back to the defaults so discovery also works without a config file
+		cfg := config.GetDefaultConfig()
+		if foundConfigPath, err := finder.FindConfigFile(configPath, true); err == nil {
+			loaded, err := config.LoadConfig(foundConfigPath)
+			if err != nil {
+				fmt.Printf("Failed to load configuration: %v\n", err)
+				os.Exit(1)
+			}
+			cfg = loaded
+		}
+
+		// Keep the command output readable, logs only go to the configured files
+		logCfg := *cfg
+		logCfg.Logs.Stdout = false
+		if err := logger.Init(&logCfg); err != nil {
+			fmt.Printf("Failed to initialize logger: %v\n", err)
+		}
+
+		discovery := mariadb.Discover(cmd.Context(), cfg, mariadb.OptionFilePaths(cfg))
+		defer mariadb.ClosePools()
+
+		// Never print the password itself
+		if setting, ok := discovery.Settings[mariadb.SettingPassword]; ok {
+			setting.Value = "********"
+			discovery.Settings[mariadb.SettingPassword] = setting
+		}
+
+		if discoverJSON {
+			data, err := json.MarshalIndent(discovery, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to encode discovery result: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		printDiscovery(discovery)
+	},
+}
+
+// printDiscovery writes the discovery result as a table
+func printDiscovery(discovery *mariadb.Discovery) {
+	fmt.Println("Option files:")
+	if len(discovery.OptionFiles) == 0 {
+		fmt.Println("  (none found)")
+	}
+	for _, file := range discovery.OptionFiles {
+		fmt.Printf("  %s\n", file)
+	}
+
+	if discovery.Connected {
+		fmt.Println("Server: connected")
+	} else {
+		fmt.Printf("Server: not reachable (%s)\n", discovery.Error)
+	}
+	fmt.Println()
+
+	names := make([]string, 0, len(discovery.Settings))
+	for name := range discovery.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "SETTING\tVALUE\tSOURCE")
+	for _, name := range names {
+		setting := discovery.Settings[name]
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", name, setting.Value, setting.Source)
+	}
+	writer.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(mariadbCmd)
+	mariadbCmd.AddCommand(discoverCmd)
+	discoverCmd.Flags().BoolVar(&discoverJSON, "json", false, "Print the result as JSON")
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/simulate"
+	"CheckHealthDO/internal/utils/finder"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	simulateInput string
+	simulateJSON  bool
+)
+
+// simulateCmd replays recorded metrics through the alert logic
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Replay recorded metrics to see which alerts a configuration would send",
+	Long: `Feed recorded or synthetic CPU, memory, disk and MariaDB samples through the
+monitors and alert handlers on a virtual clock, using the thresholds and throttling
+from --config. Every notification that would have been sent or suppressed is
+reported. No email is sent and no service is restarted.
+
+The input holds one JSON sample per line, for example:
+
+  {"time":"2025-01-06T08:00:00Z","type":"cpu","usage":93.5,"load":[6.1,4.2,3.0]}
+  {"time":"2025-01-06T08:00:00Z","type":"memory","usage":81}
+  {"time":"2025-01-06T08:00:00Z","type":"disk","disks":[{"mount_point":"/","usage":91.2}]}
+  {"time":"2025-01-06T08:01:00Z","type":"mariadb","status":"stopped","reason":"Out of Memory"}
+
+CPU and memory samples take a usage percentage or the full "cpu" or "memory"
+record served by the API. MariaDB samples are running, stopped or unresponsive.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if simulateInput == "" {
+			fmt.Println("Missing --input file with the samples to replay")
+			os.Exit(1)
+		}
+
+		foundConfigPath, err := finder.FindConfigFile(configPath, true)
+		if err != nil {
+			fmt.Printf("Failed to find configuration file: %v\n", err)
+			os.Exit(1)
+		}
+		cfg, err := config.LoadConfig(foundConfigPath)
+		if err != nil {
+			fmt.Printf("Failed to load configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Keep the command output readable, logs only go to the configured files
+		logCfg := *cfg
+		logCfg.Logs.Stdout = false
+		if err := logger.Init(&logCfg); err != nil {
+			fmt.Printf("Failed to initialize logger: %v\n", err)
+		}
+
+		samples, err := simulate.ReadSamplesFile(simulateInput)
+		if err != nil {
+			fmt.Printf("Failed to read samples: %v\n", err)
+			os.Exit(1)
+		}
+
+		report, err := simulate.New(cfg).Run(samples)
+		if err != nil {
+			fmt.Printf("Simulation failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		if simulateJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to encode simulation report: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		printSimulation(foundConfigPath, report)
+	},
+}
+
+// printSimulation writes the simulation report as a table
+func printSimulation(configFile string, report *simulate.Report) {
+	fmt.Printf("Configuration: %s\n", configFile)
+	if report.Samples == 0 {
+		fmt.Println("No samples to replay")
+		return
+	}
+	fmt.Printf("Replayed %d samples from %s to %s\n\n", report.Samples,
+		report.From.Format(time.RFC3339), report.To.Format(time.RFC3339))
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TIME\tMONITOR\tSTATUS\tRESULT\tDETAIL")
+	for _, event := range report.Events {
+		result := event.Kind
+		if event.Count > 1 {
+			result = fmt.Sprintf("%s x%d", event.Kind, event.Count)
+		}
+		detail := event.Reason
+		if event.Kind == simulate.EventFired {
+			detail = event.Subject
+		}
+		if event.Until != nil {
+			detail = fmt.Sprintf("%s (until %s)", detail, event.Until.Format(time.RFC3339))
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", event.Time.Format(time.RFC3339),
+			event.Monitor, event.Status, result, detail)
+	}
+	writer.Flush()
+
+	fmt.Printf("\n%d notifications would have been sent, %d suppressed\n", report.Fired, report.Suppressed)
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+	simulateCmd.Flags().StringVarP(&simulateInput, "input", "i", "", "JSON Lines file with the samples to replay")
+	simulateCmd.Flags().BoolVar(&simulateJSON, "json", false, "Print the report as JSON")
+}
@@ -69,6 +69,14 @@ type NotificationManager interface {
 	SendEmail(subject, body string) error
 }
 
+// OutcomeReporter is implemented by notification managers that also want to know
+// why an alert was held back and which recovery actions were taken, such as the
+// Recorder behind a simulation
+type OutcomeReporter interface {
+	ReportSuppressed(reason string)
+	ReportAction(action string)
+}
+
 // ConfigProvider is an interface for accessing configuration
 type ConfigProvider interface {
 	GetNotificationManagers() NotificationManager
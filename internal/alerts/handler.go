@@ -52,10 +52,17 @@ func (h *Handler) ShouldThrottleAlert(statusChanged bool, counter *int, alertTyp
 		return false
 	}
 
+	// Providers with their own clock, such as monitors under test, decide what now is
+	now := time.Now()
+	if clockProvider, ok := h.config.(interface{ Now() time.Time }); ok {
+		now = clockProvider.Now()
+	}
+
 	cooldownDuration := time.Duration(cooldownPeriod) * time.Second
-	if time.Since(h.config.GetLastAlertTime()) < cooldownDuration {
+	if now.Sub(h.config.GetLastAlertTime()) < cooldownDuration {
 		// Increment the counter and only log periodically
 		*counter++
+		h.ReportSuppressed(fmt.Sprintf("%s notifications are in the cooldown period", alertType))
 		if *counter%h.suppressLogFrequency == 1 { // Log on 1, 61, 121, etc.
 			logger.Debug(fmt.Sprintf("Suppressing %s notifications due to cooldown period", alertType),
 				logger.Int("suppressed_count", *counter))
@@ -89,3 +96,19 @@ func (h *Handler) SendNotifications(title, message, level string) {
 			logger.String("error", err.Error()))
 	}
 }
+
+// ReportSuppressed tells the notification manager why a notification was not sent,
+// when it implements OutcomeReporter
+func (h *Handler) ReportSuppressed(reason string) {
+	if reporter, ok := h.config.GetNotificationManagers().(OutcomeReporter); ok {
+		reporter.ReportSuppressed(reason)
+	}
+}
+
+// ReportAction tells the notification manager about a recovery action, when it
+// implements OutcomeReporter
+func (h *Handler) ReportAction(action string) {
+	if reporter, ok := h.config.GetNotificationManagers().(OutcomeReporter); ok {
+		reporter.ReportAction(action)
+	}
+}
@@ -0,0 +1,81 @@
+package alerts
+
+import "sync"
+
+// Notification is an alert handed to a NotificationManager
+type Notification struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Outcome kinds
+const (
+	OutcomeSuppressed = "suppressed" // A notification was held back
+	OutcomeAction     = "action"     // A recovery action was taken or skipped
+)
+
+// Outcome is a reported alert decision that did not produce a notification
+type Outcome struct {
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+// Recorder is a NotificationManager that keeps notifications instead of sending
+// them, for tests and dry runs. It also keeps the reported outcomes.
+type Recorder struct {
+	mu            sync.Mutex
+	notifications []Notification
+	outcomes      []Outcome
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// SendEmail implements NotificationManager
+func (r *Recorder) SendEmail(subject, body string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, Notification{Subject: subject, Body: body})
+	return nil
+}
+
+// Notifications returns everything recorded so far
+func (r *Recorder) Notifications() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification{}, r.notifications...)
+}
+
+// Subjects returns the subjects of the recorded notifications in order
+func (r *Recorder) Subjects() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subjects := make([]string, len(r.notifications))
+	for i, notification := range r.notifications {
+		subjects[i] = notification.Subject
+	}
+	return subjects
+}
+
+// ReportSuppressed implements OutcomeReporter
+func (r *Recorder) ReportSuppressed(reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, Outcome{Kind: OutcomeSuppressed, Reason: reason})
+}
+
+// ReportAction implements OutcomeReporter
+func (r *Recorder) ReportAction(action string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, Outcome{Kind: OutcomeAction, Reason: action})
+}
+
+// Outcomes returns the reported outcomes in order
+func (r *Recorder) Outcomes() []Outcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Outcome{}, r.outcomes...)
+}
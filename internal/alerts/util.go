@@ -18,10 +18,16 @@ func GetServerInfoForAlert() *ServerInfo {
 		return nil
 	}
 
+	// Assume the first IP is the main one; hosts without a network have none
+	ipAddress := ""
+	if len(sysinfo.IPAddresses) > 0 {
+		ipAddress = sysinfo.IPAddresses[0]
+	}
+
 	// Convert system info to ServerInfo structure
 	return &ServerInfo{
 		Hostname:        sysinfo.Hostname,
-		IPAddress:       sysinfo.IPAddresses[0], // Assuming first IP is the main one
+		IPAddress:       ipAddress,
 		Platform:        sysinfo.Platform,
 		PlatformVersion: sysinfo.PlatformVersion,
 		KernelVersion:   sysinfo.KernelVersion,
@@ -0,0 +1,28 @@
+package backup
+
+import (
+	backupMonitor "CheckHealthDO/internal/monitoring/services/backup"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes backup verification results over the API
+type Handler struct {
+	monitor *backupMonitor.Monitor
+}
+
+// NewHandler creates a new backup handler
+func NewHandler(monitor *backupMonitor.Monitor) *Handler {
+	return &Handler{
+		monitor: monitor,
+	}
+}
+
+// GetBackups returns the latest verification result of every backup target
+func (h *Handler) GetBackups(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"backups": h.monitor.GetStatuses(),
+	})
+}
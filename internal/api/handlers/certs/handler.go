@@ -0,0 +1,28 @@
+package certs
+
+import (
+	certsMonitor "CheckHealthDO/internal/monitoring/certs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes certificate expiry information over the API
+type Handler struct {
+	monitor *certsMonitor.Monitor
+}
+
+// NewHandler creates a new certificates handler
+func NewHandler(monitor *certsMonitor.Monitor) *Handler {
+	return &Handler{
+		monitor: monitor,
+	}
+}
+
+// GetCertificates returns the latest state of every monitored certificate
+func (h *Handler) GetCertificates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":       "success",
+		"certificates": h.monitor.GetCertificates(),
+	})
+}
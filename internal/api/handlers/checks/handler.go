@@ -0,0 +1,59 @@
+package checks
+
+import (
+	checksMonitor "CheckHealthDO/internal/monitoring/checks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes synthetic check results over the API
+type Handler struct {
+	monitor *checksMonitor.Monitor
+}
+
+// NewHandler creates a new checks handler
+func NewHandler(monitor *checksMonitor.Monitor) *Handler {
+	return &Handler{
+		monitor: monitor,
+	}
+}
+
+// GetChecks returns the latest result of every configured check
+func (h *Handler) GetChecks(c *gin.Context) {
+	results := h.monitor.GetResults()
+
+	summary := map[string]int{
+		"normal":   0,
+		"warning":  0,
+		"critical": 0,
+		"unknown":  0,
+	}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"summary": summary,
+		"checks":  results,
+	})
+}
+
+// GetCheck returns the latest result of a single check
+func (h *Handler) GetCheck(c *gin.Context) {
+	name := c.Param("name")
+
+	result, ok := h.monitor.GetResult(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Check not found: " + name,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"check":  result,
+	})
+}
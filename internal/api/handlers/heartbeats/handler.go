@@ -0,0 +1,81 @@
+package heartbeats
+
+import (
+	heartbeatsMonitor "CheckHealthDO/internal/monitoring/heartbeats"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBodyBytes limits how much of a ping body is kept as the run message
+const maxBodyBytes = 10 * 1024
+
+// Handler exposes heartbeat pings and state over the API
+type Handler struct {
+	monitor *heartbeatsMonitor.Monitor
+}
+
+// NewHandler creates a new heartbeats handler
+func NewHandler(monitor *heartbeatsMonitor.Monitor) *Handler {
+	return &Handler{
+		monitor: monitor,
+	}
+}
+
+// GetHeartbeats returns the state of every configured heartbeat
+func (h *Handler) GetHeartbeats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":     "success",
+		"heartbeats": h.monitor.GetStates(),
+	})
+}
+
+// Ping records a successful run
+func (h *Handler) Ping(c *gin.Context) {
+	h.record(c, heartbeatsMonitor.PingSuccess)
+}
+
+// Start records the start of a run
+func (h *Handler) Start(c *gin.Context) {
+	h.record(c, heartbeatsMonitor.PingStart)
+}
+
+// Success records a successful run
+func (h *Handler) Success(c *gin.Context) {
+	h.record(c, heartbeatsMonitor.PingSuccess)
+}
+
+// Fail records a failed run
+func (h *Handler) Fail(c *gin.Context) {
+	h.record(c, heartbeatsMonitor.PingFail)
+}
+
+// record passes a ping to the monitor and translates errors to HTTP responses
+func (h *Handler) record(c *gin.Context, kind string) {
+	token := c.GetHeader("X-Heartbeat-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxBodyBytes))
+
+	state, err := h.monitor.RecordPing(c.Param("name"), kind, token, string(body))
+	if err != nil {
+		switch {
+		case errors.Is(err, heartbeatsMonitor.ErrUnknownHeartbeat):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Heartbeat not found: " + c.Param("name")})
+		case errors.Is(err, heartbeatsMonitor.ErrInvalidToken):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid heartbeat token"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"heartbeat": state,
+	})
+}
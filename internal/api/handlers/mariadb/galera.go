@@ -0,0 +1,40 @@
+package mariadb
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetGaleraStatus returns the latest Galera cluster health
+func (h *Handler) GetGaleraStatus(c *gin.Context) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB Galera monitoring is disabled",
+		})
+		return
+	}
+
+	status, ok := h.monitor.GetGaleraStatus()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB Galera monitoring is disabled",
+		})
+		return
+	}
+
+	if status == nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":  "pending",
+			"message": "Galera status has not been sampled yet",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"galera": status,
+	})
+}
@@ -14,6 +14,9 @@ type Handler struct {
 	service *ServiceHandler
 	status  *StatusHandler
 	monitor *mariadbMonitor.Monitor // Add monitor reference
+
+	// Monitors of additional instances, listed by GetInstances
+	instances []*mariadbMonitor.Monitor
 }
 
 // NewHandler creates a new MariaDB handler
@@ -28,7 +28,7 @@ func (h *Handler) GetInfo(c *gin.Context) {
 
 // GetInfo handles the MariaDB information endpoint
 func (h *InfoHandler) GetInfo(c *gin.Context) {
-	info, err := mariadb.GetMariaDBInfo(h.config)
+	info, err := mariadb.GetMariaDBInfo(c.Request.Context(), h.config)
 	if err != nil {
 		logger.Error("API error: failed to get MariaDB info",
 			logger.String("error", err.Error()))
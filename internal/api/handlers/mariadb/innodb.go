@@ -0,0 +1,67 @@
+package mariadb
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetInnoDBStatus returns the latest parsed SHOW ENGINE INNODB STATUS output
+func (h *Handler) GetInnoDBStatus(c *gin.Context) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB InnoDB status sampling is disabled",
+		})
+		return
+	}
+
+	status, ok := h.monitor.GetInnoDBStatus()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB InnoDB status sampling is disabled",
+		})
+		return
+	}
+
+	if status == nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":  "pending",
+			"message": "InnoDB status has not been sampled yet",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"innodb": status,
+	})
+}
+
+// GetDeadlocks returns the captured InnoDB deadlock history, newest first
+func (h *Handler) GetDeadlocks(c *gin.Context) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB InnoDB status sampling is disabled",
+		})
+		return
+	}
+
+	deadlocks, lastHour, ok := h.monitor.GetDeadlocks()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB InnoDB status sampling is disabled",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":          "success",
+		"count":           len(deadlocks),
+		"last_hour_count": lastHour,
+		"deadlocks":       deadlocks,
+	})
+}
@@ -0,0 +1,58 @@
+package mariadb
+
+import (
+	mariadbMonitor "CheckHealthDO/internal/monitoring/services/mariadb"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InstanceSummary describes a monitored MariaDB instance and where to reach it
+type InstanceSummary struct {
+	Name        string `json:"name"`
+	ServiceName string `json:"service_name"`
+	Status      string `json:"status"`
+	APIPath     string `json:"api_path"`
+	WSPath      string `json:"ws_path"`
+}
+
+// SetInstances sets the monitors of the additional instances
+func (h *Handler) SetInstances(monitors []*mariadbMonitor.Monitor) {
+	h.instances = monitors
+}
+
+// GetInstances lists the default instance and every additional instance
+func (h *Handler) GetInstances(c *gin.Context) {
+	instances := make([]InstanceSummary, 0, len(h.instances)+1)
+
+	if h.monitor != nil {
+		instances = append(instances, summarizeInstance(h.monitor, "/api/mariadb", "/ws/mariadb"))
+	}
+	for _, monitor := range h.instances {
+		instances = append(instances, summarizeInstance(monitor,
+			"/api/mariadb/"+monitor.Instance(), "/ws/mariadb/"+monitor.Instance()))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"instances": instances,
+	})
+}
+
+// summarizeInstance builds the listing entry for a monitor
+func summarizeInstance(monitor *mariadbMonitor.Monitor, apiPath, wsPath string) InstanceSummary {
+	summary := InstanceSummary{
+		Name:        monitor.Instance(),
+		ServiceName: monitor.GetConfig().Monitoring.MariaDB.ServiceName,
+		Status:      "unknown",
+		APIPath:     apiPath,
+		WSPath:      wsPath,
+	}
+	if summary.Name == "" {
+		summary.Name = "default"
+	}
+	if status := monitor.GetStatus(); status != nil && status.Status != "" {
+		summary.Status = status.Status
+	}
+	return summary
+}
@@ -0,0 +1,45 @@
+package mariadb
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLogs returns classified MariaDB error log events.
+// The optional since parameter is either an RFC3339 timestamp or the ID of
+// the last event the client has seen.
+func (h *Handler) GetLogs(c *gin.Context) {
+	if h.monitor == nil || !h.monitor.LogWatcherEnabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB error log monitoring is disabled",
+		})
+		return
+	}
+
+	var since time.Time
+	var afterID uint64
+	if raw := c.Query("since"); raw != "" {
+		if id, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			afterID = id
+		} else if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+			since = ts
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "since must be an RFC3339 timestamp or an event ID",
+			})
+			return
+		}
+	}
+
+	events := h.monitor.GetLogEvents(since, afterID)
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"count":  len(events),
+		"events": events,
+	})
+}
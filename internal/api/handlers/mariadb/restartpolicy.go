@@ -0,0 +1,33 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRestartPolicy returns the state of the automatic restart policy
+func (h *Handler) GetRestartPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "success",
+		"restart_policy": mariadb.GetRestartPolicy(h.config).Status(),
+	})
+}
+
+// ResetRestartPolicy clears an inhibited restart policy so automatic restarts resume
+func (h *Handler) ResetRestartPolicy(c *gin.Context) {
+	policy := mariadb.GetRestartPolicy(h.config)
+	policy.Reset()
+
+	logger.Info("MariaDB restart policy reset via API",
+		logger.String("service", h.config.Monitoring.MariaDB.ServiceName),
+		logger.String("client_ip", c.ClientIP()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "success",
+		"message":        "Automatic restarts are enabled again",
+		"restart_policy": policy.Status(),
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
 	"CheckHealthDO/internal/services/mariadb"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -31,9 +32,11 @@ func (h *ServiceHandler) SetMonitor(monitor *mariadbMonitor.Monitor) {
 // StartService handles starting the MariaDB service
 func (h *ServiceHandler) StartService(c *gin.Context) {
 	serviceName := h.config.Monitoring.MariaDB.ServiceName
+	// Once begun the action is seen through when the client disconnects
+	ctx := context.WithoutCancel(c.Request.Context())
 
 	// Check if the service is already running
-	isRunning, err := mariadb.CheckServiceStatus(serviceName, nil)
+	isRunning, err := mariadb.CheckServiceStatus(ctx, serviceName, nil)
 	if err != nil {
 		logger.Error("API error: failed to check MariaDB service status",
 			logger.String("error", err.Error()))
@@ -60,7 +63,7 @@ func (h *ServiceHandler) StartService(c *gin.Context) {
 	}
 
 	// Attempt to start the service
-	err = mariadb.StartMariaDBService(serviceName)
+	err = mariadb.StartMariaDBService(ctx, serviceName)
 	if err != nil {
 		logger.Error("API error: failed to start MariaDB service",
 			logger.String("error", err.Error()))
@@ -81,9 +84,11 @@ func (h *ServiceHandler) StartService(c *gin.Context) {
 // StopService handles stopping the MariaDB service
 func (h *ServiceHandler) StopService(c *gin.Context) {
 	serviceName := h.config.Monitoring.MariaDB.ServiceName
+	// Once begun the action is seen through when the client disconnects
+	ctx := context.WithoutCancel(c.Request.Context())
 
 	// Check if the service is already stopped
-	isRunning, err := mariadb.CheckServiceStatus(serviceName, nil)
+	isRunning, err := mariadb.CheckServiceStatus(ctx, serviceName, nil)
 	if err != nil {
 		logger.Error("API error: failed to check MariaDB service status",
 			logger.String("error", err.Error()))
@@ -110,7 +115,7 @@ func (h *ServiceHandler) StopService(c *gin.Context) {
 	}
 
 	// Attempt to stop the service
-	err = mariadb.StopMariaDBService(serviceName)
+	err = mariadb.StopMariaDBService(ctx, serviceName)
 	if err != nil {
 		logger.Error("API error: failed to stop MariaDB service",
 			logger.String("error", err.Error()))
@@ -131,9 +136,11 @@ func (h *ServiceHandler) StopService(c *gin.Context) {
 // RestartService handles restarting the MariaDB service
 func (h *ServiceHandler) RestartService(c *gin.Context) {
 	serviceName := h.config.Monitoring.MariaDB.ServiceName
+	// Once begun the action is seen through when the client disconnects
+	ctx := context.WithoutCancel(c.Request.Context())
 
 	// Check if the service is running
-	isRunning, err := mariadb.CheckServiceStatus(serviceName, nil)
+	isRunning, err := mariadb.CheckServiceStatus(ctx, serviceName, nil)
 	if err != nil {
 		logger.Error("API error: failed to check MariaDB service status",
 			logger.String("error", err.Error()))
@@ -149,7 +156,7 @@ func (h *ServiceHandler) RestartService(c *gin.Context) {
 	if !isRunning {
 		logger.Info("MariaDB service is not running, starting it first before restart")
 
-		err = mariadb.StartMariaDBService(serviceName)
+		err = mariadb.StartMariaDBService(ctx, serviceName)
 		if err != nil {
 			logger.Error("API error: failed to start MariaDB service before restart",
 				logger.String("error", err.Error()))
@@ -168,7 +175,7 @@ func (h *ServiceHandler) RestartService(c *gin.Context) {
 	}
 
 	// Now restart the service
-	err = mariadb.RestartMariaDBService(serviceName)
+	err = mariadb.RestartMariaDBService(ctx, serviceName)
 	if err != nil {
 		logger.Error("API error: failed to restart MariaDB service",
 			logger.String("error", err.Error()))
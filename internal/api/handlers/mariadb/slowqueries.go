@@ -0,0 +1,79 @@
+package mariadb
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSlowQueries returns slow query log digests for the optional from/to
+// range (RFC3339). The optional limit parameter caps the number of digests.
+func (h *Handler) GetSlowQueries(c *gin.Context) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB slow query log monitoring is disabled",
+		})
+		return
+	}
+
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	digests, total, ok := h.monitor.GetSlowQueryDigests(from, to)
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB slow query log monitoring is disabled",
+		})
+		return
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "limit must be a positive integer"})
+			return
+		}
+		if len(digests) > limit {
+			digests = digests[:limit]
+		}
+	}
+
+	response := gin.H{
+		"status":        "success",
+		"total_queries": total,
+		"digests":       digests,
+	}
+	if !from.IsZero() {
+		response["from"] = from
+	}
+	if !to.IsZero() {
+		response["to"] = to
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// parseTimeQuery parses an optional RFC3339 query parameter
+func parseTimeQuery(c *gin.Context, name string) (time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+	}
+	return t, nil
+}
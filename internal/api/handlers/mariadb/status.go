@@ -32,10 +32,11 @@ func (h *Handler) GetStatusDetails(c *gin.Context) {
 // GetStatusDetails provides detailed status information with logs and diagnostics
 func (h *StatusHandler) GetStatusDetails(c *gin.Context) {
 	serviceName := h.config.Monitoring.MariaDB.ServiceName
+	ctx := c.Request.Context()
 	logPath := h.config.Monitoring.MariaDB.LogPath
 
 	// Check if the service is running with the improved check
-	isRunning, err := mariadb.CheckServiceStatus(serviceName, h.config)
+	isRunning, err := mariadb.CheckServiceStatus(ctx, serviceName, h.config)
 	if err != nil {
 		logger.Error("API error: failed to check MariaDB service status",
 			logger.String("error", err.Error()))
@@ -66,20 +67,20 @@ func (h *StatusHandler) GetStatusDetails(c *gin.Context) {
 		dbConfig := mariadb.GetDBConfigFromConfig(h.config)
 
 		// Get uptime
-		uptime, err := mariadb.GetUptime(dbConfig)
+		uptime, err := mariadb.GetUptime(ctx, dbConfig)
 		if err == nil {
 			response["uptime"] = mariadb.FormatUptime(uptime)
 			response["uptime_seconds"] = uptime
 		}
 
 		// Get version
-		version, err := mariadb.GetVersion(dbConfig)
+		version, err := mariadb.GetVersion(ctx, dbConfig)
 		if err == nil {
 			response["version"] = version
 		}
 
 		// Get active connections
-		connections, err := mariadb.GetActiveConnections(dbConfig)
+		connections, err := mariadb.GetActiveConnections(ctx, dbConfig)
 		if err == nil {
 			response["connections_active"] = connections
 		}
@@ -118,7 +119,7 @@ func (h *StatusHandler) GetStatusDetails(c *gin.Context) {
 			logSamples = append(logSamples, logMessage)
 
 			// If MariaDB logs aren't available, try to get systemd logs as fallback
-			systemLogs, _ := mariadb.GetSystemdServiceLogs(serviceName, 5)
+			systemLogs, _ := mariadb.GetSystemdServiceLogs(ctx, serviceName, 5)
 			if len(systemLogs) > 0 {
 				logSamples = append(logSamples, "System logs:")
 				logSamples = append(logSamples, systemLogs...)
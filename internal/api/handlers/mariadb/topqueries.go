@@ -0,0 +1,40 @@
+package mariadb
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTopQueries returns the busiest performance_schema statement digests of the last interval
+func (h *Handler) GetTopQueries(c *gin.Context) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB top query sampling is disabled",
+		})
+		return
+	}
+
+	topQueries, ok := h.monitor.GetTopQueries()
+	if !ok {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB top query sampling is disabled",
+		})
+		return
+	}
+
+	if topQueries == nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":  "pending",
+			"message": "Waiting for the first complete sampling interval",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":      "success",
+		"top_queries": topQueries,
+	})
+}
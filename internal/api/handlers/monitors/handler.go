@@ -0,0 +1,116 @@
+package monitors
+
+import (
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/monitoring/registry"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes every registered monitor over the API
+type Handler struct {
+	monitors *registry.Registry
+}
+
+// NewHandler creates a new monitors handler
+func NewHandler(monitors *registry.Registry) *Handler {
+	return &Handler{
+		monitors: monitors,
+	}
+}
+
+// monitorHealth is a monitor name with its health
+type monitorHealth struct {
+	Name   string            `json:"name"`
+	Health monitoring.Health `json:"health"`
+}
+
+// GetMonitors returns the health of every registered monitor
+func (h *Handler) GetMonitors(c *gin.Context) {
+	names := h.monitors.Names()
+	monitors := make([]monitorHealth, 0, len(names))
+	healthy := 0
+	for _, name := range names {
+		health, ok := h.monitors.Health(name)
+		if !ok {
+			continue
+		}
+		if health.OK() {
+			healthy++
+		}
+		monitors = append(monitors, monitorHealth{Name: name, Health: health})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"healthy":  healthy,
+		"total":    len(monitors),
+		"monitors": monitors,
+	})
+}
+
+// GetMonitor returns the health and latest results of a single monitor
+func (h *Handler) GetMonitor(c *gin.Context) {
+	name := c.Param("name")
+
+	monitor, ok := h.monitors.Get(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Monitor not found: " + name,
+		})
+		return
+	}
+	health, _ := h.monitors.Health(name)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"name":     name,
+		"health":   health,
+		"snapshot": monitor.Snapshot(),
+	})
+}
+
+// GetMonitorHealth returns the health of a single monitor, with 503 when it is not
+// running or not checking on schedule so load balancers and probes can use it
+func (h *Handler) GetMonitorHealth(c *gin.Context) {
+	name := c.Param("name")
+
+	health, ok := h.monitors.Health(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Monitor not found: " + name,
+		})
+		return
+	}
+
+	code := http.StatusOK
+	if !health.OK() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"name":   name,
+		"health": health,
+	})
+}
+
+// GetScheduler returns the counters the scheduler keeps about every collection
+func (h *Handler) GetScheduler(c *gin.Context) {
+	jobs := scheduler.Default().Stats()
+
+	var skips, errors, timeouts int64
+	for _, job := range jobs {
+		skips += job.Skips
+		errors += job.Errors
+		timeouts += job.Timeouts
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"skips":    skips,
+		"errors":   errors,
+		"timeouts": timeouts,
+		"jobs":     jobs,
+	})
+}
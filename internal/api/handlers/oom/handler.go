@@ -0,0 +1,29 @@
+package oom
+
+import (
+	oomMonitor "CheckHealthDO/internal/monitoring/server/oom"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes the OOM kill history over the API
+type Handler struct {
+	monitor *oomMonitor.Monitor
+}
+
+// NewHandler creates a new OOM kill handler
+func NewHandler(monitor *oomMonitor.Monitor) *Handler {
+	return &Handler{
+		monitor: monitor,
+	}
+}
+
+// GetEvents returns the processes killed by the OOM killer, newest first
+func (h *Handler) GetEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"source": h.monitor.Source(),
+		"events": h.monitor.GetEvents(),
+	})
+}
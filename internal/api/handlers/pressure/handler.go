@@ -0,0 +1,36 @@
+package pressure
+
+import (
+	pressureMonitor "CheckHealthDO/internal/monitoring/server/pressure"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler exposes pressure stall information over the API
+type Handler struct {
+	monitor *pressureMonitor.Monitor
+}
+
+// NewHandler creates a new pressure handler
+func NewHandler(monitor *pressureMonitor.Monitor) *Handler {
+	return &Handler{
+		monitor: monitor,
+	}
+}
+
+// GetPressure returns the latest stall averages of every resource
+func (h *Handler) GetPressure(c *gin.Context) {
+	info := h.monitor.GetLastInfo()
+	if info == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "No pressure stall information collected yet",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"pressure": info,
+	})
+}
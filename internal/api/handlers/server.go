@@ -28,7 +28,7 @@ func NewServerHandler(cfg *config.Config) *ServerHandler {
 // GetServerMetrics handles requests to get all server metrics
 func (h *ServerHandler) GetServerMetrics(c *gin.Context) {
 	// Pass the config argument to GetServerAllInfo
-	metrics, err := server.GetServerAllInfo(h.config)
+	metrics, err := server.GetServerAllInfo(c.Request.Context(), h.config)
 	if err != nil {
 		logger.Error("Failed to get server metrics",
 			logger.String("error", err.Error()))
@@ -68,7 +68,7 @@ func (h *ServerHandler) GetServerInfo(c *gin.Context) {
 // GetCPUInfo handles the CPU information endpoint
 func (h *ServerHandler) GetCPUInfo(c *gin.Context) {
 	// Use CPU module directly with thresholds from configuration
-	info, err := cpu.GetCPUInfo(
+	info, err := cpu.GetCPUInfo(c.Request.Context(),
 		h.config.Monitoring.CPU.WarningThreshold,
 		h.config.Monitoring.CPU.CriticalThreshold,
 	)
@@ -81,9 +81,45 @@ func (h *ServerHandler) GetCPUInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, info)
 }
 
+// GetCPUStaticInfo handles the static CPU information endpoint. The facts are
+// cached; pass refresh=true to collect them again.
+func (h *ServerHandler) GetCPUStaticInfo(c *gin.Context) {
+	var (
+		info *cpu.StaticInfo
+		err  error
+	)
+	if c.Query("refresh") == "true" {
+		info, err = cpu.RefreshStaticInfo()
+	} else {
+		info, err = cpu.GetStaticInfo()
+	}
+	if err != nil {
+		logger.Error("Failed to get static CPU info",
+			logger.String("error", err.Error()))
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// GetCPUUsage handles the CPU usage endpoint, which leaves out the static facts
+func (h *ServerHandler) GetCPUUsage(c *gin.Context) {
+	info, err := cpu.GetDynamicInfo(c.Request.Context(),
+		h.config.Monitoring.CPU.WarningThreshold,
+		h.config.Monitoring.CPU.CriticalThreshold,
+	)
+	if err != nil {
+		logger.Error("Failed to get CPU usage",
+			logger.String("error", err.Error()))
+		HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
 // GetMemoryInfo handles the memory information endpoint
 func (h *ServerHandler) GetMemoryInfo(c *gin.Context) {
-	info, err := memory.GetMemoryInfo(
+	info, err := memory.GetMemoryInfo(c.Request.Context(),
 		h.config.Monitoring.Memory.WarningThreshold,
 		h.config.Monitoring.Memory.CriticalThreshold,
 	)
@@ -97,7 +133,7 @@ func (h *ServerHandler) GetMemoryInfo(c *gin.Context) {
 // GetDiskInfo handles requests to get disk information
 func (h *ServerHandler) GetDiskInfo(c *gin.Context) {
 	// Pass monitored paths from config to GetStorageInfo
-	storageInfos, totalStorage, err := disk.GetStorageInfo()
+	storageInfos, totalStorage, err := disk.GetStorageInfo(c.Request.Context())
 	if err != nil {
 		logger.Error("Failed to get disk information",
 			logger.String("error", err.Error()))
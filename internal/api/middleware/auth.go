@@ -9,8 +9,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// JWTAuthMiddleware creates a middleware to validate JWT tokens
-func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// JWTAuthMiddleware creates a middleware to validate JWT tokens. Pings to the
+// heartbeats in tokenHeartbeats are authenticated by their own token instead.
+func JWTAuthMiddleware(jwtSecret string, tokenHeartbeats map[string]bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Paths that don't require auth
 		excludedPaths := []string{
@@ -27,6 +28,14 @@ func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			}
 		}
 
+		// Heartbeat pings come from cron jobs that cannot log in; those with a
+		// per-heartbeat token are checked against it by the handler. Heartbeats
+		// without one still need a JWT, or anyone could report them healthy.
+		if c.Request.Method == http.MethodPost && tokenHeartbeats[heartbeatName(currentPath)] {
+			c.Next()
+			return
+		}
+
 		// For WebSocket connections, verify token from query param or header
 		if c.Request.Header.Get("Upgrade") == "websocket" {
 			// Try to get token from query param first (useful for WebSocket connections)
@@ -93,3 +102,14 @@ func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// heartbeatName returns the heartbeat a ping path such as /api/heartbeats/<name>/start
+// is for, or an empty string for other paths
+func heartbeatName(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/heartbeats/")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "/")
+	return name
+}
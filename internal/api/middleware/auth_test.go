@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"CheckHealthDO/internal/pkg/jwt"
+	"CheckHealthDO/internal/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func TestHeartbeatPingsNeedJWTWithoutToken(t *testing.T) {
+	const secret = "test-secret"
+	engine := gin.New()
+	engine.Use(JWTAuthMiddleware(secret, map[string]bool{"nightly-backup": true}))
+	engine.POST("/api/heartbeats/:name/start", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/api/heartbeats", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, err := jwt.GenerateToken("admin", secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		jwt    string
+		want   int
+	}{
+		{name: "ping of a heartbeat with a token", method: http.MethodPost, path: "/api/heartbeats/nightly-backup/start", want: http.StatusOK},
+		{name: "ping of a heartbeat without a token", method: http.MethodPost, path: "/api/heartbeats/cleanup/start", want: http.StatusUnauthorized},
+		{name: "ping of a heartbeat without a token with a JWT", method: http.MethodPost, path: "/api/heartbeats/cleanup/start", jwt: token, want: http.StatusOK},
+		{name: "listing heartbeats", method: http.MethodGet, path: "/api/heartbeats", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.jwt != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.jwt)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
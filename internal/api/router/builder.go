@@ -1,125 +1,189 @@
 package router
 
 import (
+	"CheckHealthDO/internal/alerts"
+	backupRoutes "CheckHealthDO/internal/api/router/routes/backup"
+	certsRoutes "CheckHealthDO/internal/api/router/routes/certs"
+	checksRoutes "CheckHealthDO/internal/api/router/routes/checks"
+	heartbeatsRoutes "CheckHealthDO/internal/api/router/routes/heartbeats"
+	mariadbRoutes "CheckHealthDO/internal/api/router/routes/mariadb"
+	oomRoutes "CheckHealthDO/internal/api/router/routes/oom"
+	pressureRoutes "CheckHealthDO/internal/api/router/routes/pressure"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/monitoring/certs"
+	"CheckHealthDO/internal/monitoring/checks"
+	"CheckHealthDO/internal/monitoring/heartbeats"
+	"CheckHealthDO/internal/monitoring/registry"
 	"CheckHealthDO/internal/monitoring/server/cpu"
 	"CheckHealthDO/internal/monitoring/server/disk"
 	"CheckHealthDO/internal/monitoring/server/memory"
+	"CheckHealthDO/internal/monitoring/server/oom"
+	"CheckHealthDO/internal/monitoring/server/pressure"
 	"CheckHealthDO/internal/monitoring/server/sysinfo"
+	"CheckHealthDO/internal/monitoring/services/backup"
 	"CheckHealthDO/internal/monitoring/services/mariadb"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	mariadbService "CheckHealthDO/internal/services/mariadb"
 	"context"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // Builder provides a fluent interface for constructing a router
 type Builder struct {
-	router *Router
-	ctx    context.Context
-	cancel context.CancelFunc
-
-	// Monitors for lifecycle management
-	monitors struct {
-		mariaDB *mariadb.Monitor
-		cpu     *cpu.Monitor
-		memory  *memory.Monitor
-		sysInfo *sysinfo.Monitor
-		disk    *disk.Monitor
-	}
+	router   *Router
+	ctx      context.Context
+	cancel   context.CancelFunc
+	monitors *registry.Registry // Monitors for lifecycle management
 }
 
-// NewBuilder creates a new router builder
+// NewBuilder creates a new router builder, registering and starting every monitor
+// enabled in the configuration
 func NewBuilder(cfg *config.Config) *Builder {
 	// Create cancellable context for monitors
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Create monitors
-	cpuMonitor := createCPUMonitor(cfg)
-	memoryMonitor := createMemoryMonitor(cfg)
-	mariaDBMonitor := createMariaDBMonitor(cfg, ctx)
-	sysInfoMonitor := createSysInfoMonitor(cfg)
-	diskMonitor := createDiskMonitor(cfg)
-
-	// Create builder
-	builder := &Builder{
-		router: New(cfg, mariaDBMonitor, cpuMonitor, memoryMonitor, sysInfoMonitor, diskMonitor),
-		ctx:    ctx,
-		cancel: cancel,
-	}
+	scheduler.SetDefault(scheduler.New(schedulerOptions(cfg)))
 
-	// Store monitors for lifecycle management
-	builder.monitors.mariaDB = mariaDBMonitor
-	builder.monitors.cpu = cpuMonitor
-	builder.monitors.memory = memoryMonitor
-	builder.monitors.sysInfo = sysInfoMonitor
-	builder.monitors.disk = diskMonitor
+	monitors := registry.New()
+	monitors.SetNotificationManager(alerts.NewEmailNotifier(cfg))
+	registerMonitors(ctx, monitors, cfg)
+	monitors.StartAll(ctx)
 
-	return builder
+	return &Builder{
+		router:   New(cfg, monitors),
+		ctx:      ctx,
+		cancel:   cancel,
+		monitors: monitors,
+	}
 }
 
-// createCPUMonitor creates and starts the CPU monitor
-func createCPUMonitor(cfg *config.Config) *cpu.Monitor {
-	monitor := cpu.NewMonitor(cfg)
-	if monitor != nil {
-		if err := monitor.StartMonitoring(); err != nil {
-			logger.Warn("Failed to start CPU monitor", logger.String("error", err.Error()))
-		} else {
-			logger.Debug("Started CPU monitoring service")
-		}
+// schedulerOptions converts the scheduler configuration
+func schedulerOptions(cfg *config.Config) scheduler.Options {
+	schedulerCfg := cfg.Monitoring.Scheduler
+	return scheduler.Options{
+		Jitter:    schedulerCfg.Jitter / 100,
+		MaxJitter: time.Duration(schedulerCfg.MaxJitter) * time.Second,
+		Timeout:   time.Duration(schedulerCfg.Timeout) * time.Second,
 	}
-	return monitor
 }
 
-// createMemoryMonitor creates and starts the Memory monitor
-func createMemoryMonitor(cfg *config.Config) *memory.Monitor {
-	monitor := memory.NewMonitor(cfg)
-	if monitor != nil {
-		if err := monitor.StartMonitoring(); err != nil {
-			logger.Warn("Failed to start Memory monitor", logger.String("error", err.Error()))
-		} else {
-			logger.Debug("Started Memory monitoring service")
+// registerMonitors registers every monitor with the routes it serves. The server
+// monitors are always registered; when disabled in the configuration they fail to
+// start and report so in their health.
+func registerMonitors(ctx context.Context, monitors *registry.Registry, cfg *config.Config) {
+	register := func(monitor monitoring.Monitor, routes ...registry.RouteFunc) {
+		if err := monitors.Register(monitor, routes...); err != nil {
+			logger.Warn("Failed to register monitor", logger.String("error", err.Error()))
 		}
 	}
-	return monitor
-}
 
-// createDiskMonitor creates and starts the Disk monitor
-func createDiskMonitor(cfg *config.Config) *disk.Monitor {
-	monitor := disk.NewMonitor(cfg)
-	if monitor != nil {
-		if err := monitor.StartMonitoring(); err != nil {
-			logger.Warn("Failed to start Disk monitor", logger.String("error", err.Error()))
-		} else {
-			logger.Debug("Started Disk monitoring service")
-		}
+	register(cpu.NewMonitor(cfg))
+	register(memory.NewMonitor(cfg))
+	register(disk.NewMonitor(cfg))
+	register(sysinfo.NewMonitor(cfg))
+
+	if cfg.Monitoring.Pressure.Enabled {
+		monitor := pressure.NewMonitor(cfg)
+		register(monitor, func(engine *gin.Engine) {
+			pressureRoutes.RegisterRoutes(engine, monitor)
+		})
 	}
-	return monitor
-}
 
-// createSysInfoMonitor creates and starts the SysInfo monitor
-func createSysInfoMonitor(cfg *config.Config) *sysinfo.Monitor {
-	monitor := sysinfo.NewMonitor(cfg)
-	if monitor != nil {
-		go monitor.StartMonitoring()
-		logger.Debug("Started SysInfo monitoring service")
+	if cfg.Monitoring.OOM.Enabled {
+		monitor := oom.NewMonitor(cfg)
+		register(monitor, func(engine *gin.Engine) {
+			oomRoutes.RegisterRoutes(engine, monitor)
+		})
 	}
-	return monitor
-}
 
-// createMariaDBMonitor creates and starts the MariaDB monitor
-func createMariaDBMonitor(cfg *config.Config, ctx context.Context) *mariadb.Monitor {
-	monitor, err := mariadb.NewMonitor(cfg)
-	if err != nil {
+	instances := createMariaDBInstanceMonitors(ctx, cfg)
+	if monitor, err := mariadb.NewMonitor(cfg); err != nil {
 		logger.Warn("Failed to create MariaDB monitor", logger.String("error", err.Error()))
-		return nil
+	} else {
+		register(monitor, func(engine *gin.Engine) {
+			mariadbRoutes.RegisterRoutes(engine, cfg, monitor, instances)
+		})
+	}
+	for _, instance := range instances {
+		instance := instance
+		register(instance, func(engine *gin.Engine) {
+			mariadbRoutes.RegisterInstanceRoutes(engine, []*mariadb.Monitor{instance})
+		})
+	}
+
+	if cfg.Monitoring.Checks.Enabled {
+		monitor := checks.NewMonitor(cfg)
+		register(monitor, func(engine *gin.Engine) {
+			checksRoutes.RegisterRoutes(engine, monitor)
+		})
+	}
+
+	if cfg.Monitoring.Certs.Enabled {
+		monitor := certs.NewMonitor(cfg)
+		register(monitor, func(engine *gin.Engine) {
+			certsRoutes.RegisterRoutes(engine, monitor)
+		})
+	}
+
+	if cfg.Monitoring.Heartbeats.Enabled {
+		monitor := heartbeats.NewMonitor(cfg)
+		register(monitor, func(engine *gin.Engine) {
+			heartbeatsRoutes.RegisterRoutes(engine, monitor)
+		})
+	}
+
+	if cfg.Monitoring.Backup.Enabled {
+		monitor := backup.NewMonitor(cfg)
+		register(monitor, func(engine *gin.Engine) {
+			backupRoutes.RegisterRoutes(engine, monitor)
+		})
 	}
+}
+
+// createMariaDBInstanceMonitors creates a monitor for every enabled additional MariaDB
+// instance. ctx bounds the queries that look up missing log paths.
+func createMariaDBInstanceMonitors(ctx context.Context, cfg *config.Config) []*mariadb.Monitor {
+	var monitors []*mariadb.Monitor
+	seen := make(map[string]bool)
+
+	for _, instance := range cfg.Monitoring.MariaDB.Instances {
+		if !instance.Enabled {
+			continue
+		}
+		if err := mariadbRoutes.ValidateInstanceName(instance.Name); err != nil {
+			logger.Warn("Skipping MariaDB instance", logger.String("error", err.Error()))
+			continue
+		}
+		if seen[instance.Name] {
+			logger.Warn("Skipping duplicate MariaDB instance", logger.String("instance", instance.Name))
+			continue
+		}
+		seen[instance.Name] = true
+
+		// Ask the instance itself for its log paths when they are not configured
+		instanceCfg := cfg.ForMariaDBInstance(instance)
+		if instanceCfg.Monitoring.MariaDB.LogPath == "" {
+			discovery := mariadbService.NewDiscovery()
+			mariadbService.DiscoverServer(ctx, discovery, mariadbService.GetDBConfigFromConfig(instanceCfg))
+			mariadbService.ApplyDiscovery(instanceCfg, discovery)
+		}
+
+		monitor, err := mariadb.NewMonitor(instanceCfg)
+		if err != nil {
+			logger.Warn("Failed to create MariaDB instance monitor",
+				logger.String("instance", instance.Name),
+				logger.String("error", err.Error()))
+			continue
+		}
 
-	if monitor != nil {
-		go monitor.Start(ctx)
-		logger.Debug("Started MariaDB monitoring service")
+		monitors = append(monitors, monitor)
 	}
-	return monitor
+
+	return monitors
 }
 
 // WithMiddleware adds a middleware to the router
@@ -164,34 +228,14 @@ func (b *Builder) Build() (*Builder, error) {
 
 // Shutdown stops all monitors
 func (b *Builder) Shutdown() {
-	// Cancel context to stop MariaDB monitor
+	// Cancel context to stop monitors started with it
 	if b.cancel != nil {
 		b.cancel()
 	}
 
 	// Stop all monitors explicitly
-	if b.monitors.mariaDB != nil {
-		b.monitors.mariaDB.Stop()
-		logger.Info("Stopped MariaDB monitoring service")
-	}
+	b.monitors.StopAll()
 
-	if b.monitors.cpu != nil {
-		b.monitors.cpu.StopMonitoring()
-		logger.Info("Stopped CPU monitoring service")
-	}
-
-	if b.monitors.memory != nil {
-		b.monitors.memory.StopMonitoring()
-		logger.Info("Stopped Memory monitoring service")
-	}
-
-	if b.monitors.sysInfo != nil {
-		b.monitors.sysInfo.StopMonitoring()
-		logger.Info("Stopped SysInfo monitoring service")
-	}
-
-	if b.monitors.disk != nil {
-		b.monitors.disk.StopMonitoring()
-		logger.Info("Stopped Disk monitoring service")
-	}
+	// Close shared MariaDB connections once no collector uses them anymore
+	mariadbService.ClosePools()
 }
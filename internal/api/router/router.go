@@ -4,14 +4,10 @@ import (
 	"CheckHealthDO/internal/api/handlers"
 	"CheckHealthDO/internal/api/middleware"
 	"CheckHealthDO/internal/api/router/routes/auth"
-	"CheckHealthDO/internal/api/router/routes/mariadb"
+	monitorsRoutes "CheckHealthDO/internal/api/router/routes/monitors"
 	"CheckHealthDO/internal/api/router/routes/server"
 	"CheckHealthDO/internal/api/router/routes/websocket"
-	"CheckHealthDO/internal/monitoring/server/cpu"
-	"CheckHealthDO/internal/monitoring/server/disk"
-	"CheckHealthDO/internal/monitoring/server/memory"
-	"CheckHealthDO/internal/monitoring/server/sysinfo"
-	mariadbMonitor "CheckHealthDO/internal/monitoring/services/mariadb"
+	"CheckHealthDO/internal/monitoring/registry"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
 	"fmt"
@@ -28,18 +24,12 @@ type Router struct {
 	serverHandler *handlers.ServerHandler
 	dbHandler     *handlers.DatabaseHandler
 
-	// Monitors
-	monitors struct {
-		mariaDB *mariadbMonitor.Monitor
-		cpu     *cpu.Monitor
-		memory  *memory.Monitor
-		sysInfo *sysinfo.Monitor
-		disk    *disk.Monitor
-	}
+	// Registered monitors, which bring their own routes and WebSocket topics
+	monitors *registry.Registry
 }
 
-// New creates a new router instance with the given configuration
-func New(cfg *config.Config, mariaDBMonitor *mariadbMonitor.Monitor, cpuMonitor *cpu.Monitor, memoryMonitor *memory.Monitor, sysInfoMonitor *sysinfo.Monitor, diskMonitor *disk.Monitor) *Router {
+// New creates a new router instance with the given configuration and monitors
+func New(cfg *config.Config, monitors *registry.Registry) *Router {
 	// Configure gin mode based on config
 	if cfg.Logs.Level != "debug" {
 		gin.SetMode(gin.ReleaseMode)
@@ -56,15 +46,9 @@ func New(cfg *config.Config, mariaDBMonitor *mariadbMonitor.Monitor, cpuMonitor
 		engine:        engine,
 		serverHandler: serverHandler,
 		dbHandler:     dbHandler,
+		monitors:      monitors,
 	}
 
-	// Store monitors
-	r.monitors.mariaDB = mariaDBMonitor
-	r.monitors.cpu = cpuMonitor
-	r.monitors.memory = memoryMonitor
-	r.monitors.sysInfo = sysInfoMonitor
-	r.monitors.disk = diskMonitor
-
 	return r
 }
 
@@ -84,7 +68,7 @@ func (r *Router) Initialize() *Router {
 			r.config.API.Auth.JWTSecret = "default-secret-please-change-in-production"
 		}
 		// JWT middleware will handle all routes including WebSocket connections
-		r.engine.Use(middleware.JWTAuthMiddleware(r.config.API.Auth.JWTSecret))
+		r.engine.Use(middleware.JWTAuthMiddleware(r.config.API.Auth.JWTSecret, r.tokenHeartbeats()))
 		logger.Info("JWT authentication middleware enabled for all routes")
 	}
 
@@ -111,22 +95,16 @@ func (r *Router) registerAPIRoutes() {
 	// Register server routes
 	server.RegisterRoutes(r.engine, r.serverHandler)
 
-	// Register MariaDB routes if monitor is available
-	if r.monitors.mariaDB != nil {
-		mariadb.RegisterRoutes(r.engine, r.config, r.monitors.mariaDB)
-	}
+	// Register the routes shared by every monitor
+	monitorsRoutes.RegisterRoutes(r.engine, r.monitors)
+
+	// Register the routes specific to each monitor
+	r.monitors.RegisterRoutes(r.engine)
 }
 
 // registerWebSocketRoutes registers all WebSocket routes
 func (r *Router) registerWebSocketRoutes() {
-	websocket.RegisterWebSocketRoutes(
-		r.engine,
-		r.monitors.cpu,
-		r.monitors.mariaDB,
-		r.monitors.memory,
-		r.monitors.sysInfo,
-		r.monitors.disk,
-	)
+	websocket.RegisterWebSocketRoutes(r.engine, r.monitors)
 }
 
 // registerRootAPIEndpoint provides a simple API health check endpoint
@@ -196,6 +174,23 @@ func LoggerMiddleware() gin.HandlerFunc {
 	}
 }
 
+// tokenHeartbeats returns the names of the heartbeats that have their own ping token
+func (r *Router) tokenHeartbeats() map[string]bool {
+	names := make(map[string]bool)
+	if !r.config.Monitoring.Heartbeats.Enabled {
+		return names
+	}
+	for _, job := range r.config.Monitoring.Heartbeats.Jobs {
+		if job.Token == "" {
+			logger.Warn("Heartbeat has no token, pings need a JWT while API auth is enabled",
+				logger.String("heartbeat", job.Name))
+			continue
+		}
+		names[job.Name] = true
+	}
+	return names
+}
+
 // Start starts the HTTP server
 func (r *Router) Start() {
 	addr := fmt.Sprintf("%s:%d", r.config.Server.Host, r.config.Server.Port)
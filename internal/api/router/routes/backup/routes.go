@@ -0,0 +1,15 @@
+package backup
+
+import (
+	"CheckHealthDO/internal/api/handlers/backup"
+	backupMonitor "CheckHealthDO/internal/monitoring/services/backup"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all backup monitoring routes
+func RegisterRoutes(engine *gin.Engine, monitor *backupMonitor.Monitor) {
+	handler := backup.NewHandler(monitor)
+
+	engine.GET("/api/backups", handler.GetBackups)
+}
@@ -0,0 +1,15 @@
+package certs
+
+import (
+	"CheckHealthDO/internal/api/handlers/certs"
+	certsMonitor "CheckHealthDO/internal/monitoring/certs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all certificate monitoring routes
+func RegisterRoutes(engine *gin.Engine, monitor *certsMonitor.Monitor) {
+	handler := certs.NewHandler(monitor)
+
+	engine.GET("/api/certificates", handler.GetCertificates)
+}
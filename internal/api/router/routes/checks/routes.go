@@ -0,0 +1,19 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/api/handlers/checks"
+	checksMonitor "CheckHealthDO/internal/monitoring/checks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all synthetic check routes
+func RegisterRoutes(engine *gin.Engine, monitor *checksMonitor.Monitor) {
+	handler := checks.NewHandler(monitor)
+
+	checksGroup := engine.Group("/api/checks")
+	{
+		checksGroup.GET("", handler.GetChecks)
+		checksGroup.GET("/:name", handler.GetCheck)
+	}
+}
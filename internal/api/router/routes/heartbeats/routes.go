@@ -0,0 +1,24 @@
+package heartbeats
+
+import (
+	"CheckHealthDO/internal/api/handlers/heartbeats"
+	heartbeatsMonitor "CheckHealthDO/internal/monitoring/heartbeats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all heartbeat routes
+func RegisterRoutes(engine *gin.Engine, monitor *heartbeatsMonitor.Monitor) {
+	handler := heartbeats.NewHandler(monitor)
+
+	heartbeatsGroup := engine.Group("/api/heartbeats")
+	{
+		heartbeatsGroup.GET("", handler.GetHeartbeats)
+
+		// Ping endpoints, called by cron jobs and backup scripts
+		heartbeatsGroup.POST("/:name", handler.Ping)
+		heartbeatsGroup.POST("/:name/start", handler.Start)
+		heartbeatsGroup.POST("/:name/success", handler.Success)
+		heartbeatsGroup.POST("/:name/fail", handler.Fail)
+	}
+}
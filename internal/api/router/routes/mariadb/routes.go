@@ -4,18 +4,55 @@ import (
 	"CheckHealthDO/internal/api/handlers/mariadb"
 	monitorMariadb "CheckHealthDO/internal/monitoring/services/mariadb"
 	"CheckHealthDO/internal/pkg/config"
+	"fmt"
+	"regexp"
 
 	"github.com/gin-gonic/gin"
 )
 
+// instanceNamePattern restricts instance names to safe URL path segments
+var instanceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// reservedInstanceNames are path segments already used below /api/mariadb and /ws/mariadb
+var reservedInstanceNames = map[string]bool{
+	"start": true, "stop": true, "restart": true, "status": true, "info": true,
+	"logs": true, "slow-queries": true, "top-queries": true, "innodb": true,
+	"deadlocks": true, "galera": true, "restart-policy": true, "instances": true, "default": true,
+}
+
+// ValidateInstanceName checks that an instance name can be used as a route segment
+func ValidateInstanceName(name string) error {
+	if !instanceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid MariaDB instance name %q: use letters, digits, '-' and '_'", name)
+	}
+	if reservedInstanceNames[name] {
+		return fmt.Errorf("invalid MariaDB instance name %q: the name is reserved", name)
+	}
+	return nil
+}
+
 // RegisterRoutes registers all MariaDB-related routes
-func RegisterRoutes(engine *gin.Engine, config *config.Config, monitor *monitorMariadb.Monitor) {
+func RegisterRoutes(engine *gin.Engine, config *config.Config, monitor *monitorMariadb.Monitor, instances []*monitorMariadb.Monitor) {
 	// Create MariaDB handler
 	handler := mariadb.NewHandler(config)
 	handler.SetMonitor(monitor)
+	handler.SetInstances(instances)
 
 	mariadbGroup := engine.Group("/api/mariadb")
 	RegisterRoutesWithGroup(mariadbGroup, handler)
+	mariadbGroup.GET("/instances", handler.GetInstances)
+}
+
+// RegisterInstanceRoutes registers the same routes below /api/mariadb/<instance>
+// for every additional instance
+func RegisterInstanceRoutes(engine *gin.Engine, instances []*monitorMariadb.Monitor) {
+	for _, monitor := range instances {
+		// Each handler works on the instance's own service unit and connection
+		handler := mariadb.NewHandler(monitor.GetConfig())
+		handler.SetMonitor(monitor)
+
+		RegisterRoutesWithGroup(engine.Group("/api/mariadb/"+monitor.Instance()), handler)
+	}
 }
 
 // RegisterRoutesWithGroup registers routes with a pre-configured group
@@ -28,4 +65,14 @@ func RegisterRoutesWithGroup(group *gin.RouterGroup, handler *mariadb.Handler) {
 	// Status and information endpoints
 	group.GET("/status", handler.GetStatusDetails)
 	group.GET("/info", handler.GetInfo)
+	group.GET("/logs", handler.GetLogs)
+	group.GET("/slow-queries", handler.GetSlowQueries)
+	group.GET("/top-queries", handler.GetTopQueries)
+	group.GET("/innodb", handler.GetInnoDBStatus)
+	group.GET("/deadlocks", handler.GetDeadlocks)
+	group.GET("/galera", handler.GetGaleraStatus)
+
+	// Crash-loop protection for automatic restarts
+	group.GET("/restart-policy", handler.GetRestartPolicy)
+	group.POST("/restart-policy/reset", handler.ResetRestartPolicy)
 }
@@ -0,0 +1,22 @@
+package monitors
+
+import (
+	"CheckHealthDO/internal/api/handlers/monitors"
+	"CheckHealthDO/internal/monitoring/registry"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers the routes shared by every monitor and the scheduler statistics
+func RegisterRoutes(engine *gin.Engine, registered *registry.Registry) {
+	handler := monitors.NewHandler(registered)
+
+	monitorsGroup := engine.Group("/api/monitors")
+	{
+		monitorsGroup.GET("", handler.GetMonitors)
+		monitorsGroup.GET("/:name", handler.GetMonitor)
+		monitorsGroup.GET("/:name/health", handler.GetMonitorHealth)
+	}
+
+	engine.GET("/api/scheduler", handler.GetScheduler)
+}
@@ -0,0 +1,15 @@
+package oom
+
+import (
+	"CheckHealthDO/internal/api/handlers/oom"
+	oomMonitor "CheckHealthDO/internal/monitoring/server/oom"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all OOM kill monitoring routes
+func RegisterRoutes(engine *gin.Engine, monitor *oomMonitor.Monitor) {
+	handler := oom.NewHandler(monitor)
+
+	engine.GET("/api/server/oom", handler.GetEvents)
+}
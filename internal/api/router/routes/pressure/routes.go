@@ -0,0 +1,15 @@
+package pressure
+
+import (
+	"CheckHealthDO/internal/api/handlers/pressure"
+	pressureMonitor "CheckHealthDO/internal/monitoring/server/pressure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all pressure monitoring routes
+func RegisterRoutes(engine *gin.Engine, monitor *pressureMonitor.Monitor) {
+	handler := pressure.NewHandler(monitor)
+
+	engine.GET("/api/server/pressure", handler.GetPressure)
+}
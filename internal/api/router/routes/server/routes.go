@@ -15,6 +15,8 @@ func RegisterRoutes(engine *gin.Engine, serverHandler *handlers.ServerHandler) {
 
 		// Specific monitoring endpoints
 		serverGroup.GET("/cpu", serverHandler.GetCPUInfo)
+		serverGroup.GET("/cpu/static", serverHandler.GetCPUStaticInfo)
+		serverGroup.GET("/cpu/usage", serverHandler.GetCPUUsage)
 		serverGroup.GET("/memory", serverHandler.GetMemoryInfo)
 		serverGroup.GET("/disk", serverHandler.GetDiskInfo)
 		serverGroup.GET("/sysinfo", serverHandler.GetSystemInfoHandler)
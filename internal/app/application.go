@@ -3,7 +3,10 @@ package app
 import (
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
 	"fmt"
+	"strings"
 )
 
 // Application represents the main application
@@ -35,6 +38,16 @@ func (a *Application) Initialize() error {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	// Fill MariaDB paths and credentials the configuration leaves empty
+	if cfg.Monitoring.MariaDB.Enabled {
+		discovery := mariadb.Discover(context.Background(), cfg, mariadb.OptionFilePaths(cfg))
+		if applied := mariadb.ApplyDiscovery(cfg, discovery); len(applied) > 0 {
+			logger.Info("Applied discovered MariaDB settings",
+				logger.String("settings", strings.Join(applied, ", ")),
+				logger.String("log_path", cfg.Monitoring.MariaDB.LogPath))
+		}
+	}
+
 	logger.Debug("Application initialized successfully")
 	a.isRunning = true
 	return nil
@@ -0,0 +1,141 @@
+package certs
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/logger"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertHandler sends notifications when a certificate approaches expiry
+type AlertHandler struct {
+	monitor  *Monitor
+	handler  *alerts.Handler
+	notified map[string]string // Last status a notification was sent for, by source
+}
+
+// NewAlertHandler creates a new alert handler for certificates
+func NewAlertHandler(monitor *Monitor) *AlertHandler {
+	return &AlertHandler{
+		monitor:  monitor,
+		handler:  alerts.NewHandler(monitor, nil),
+		notified: make(map[string]string),
+	}
+}
+
+// HandleCertificate sends a notification when a certificate enters warning or
+// critical state, or cannot be read for errorThreshold checks in a row, and a
+// recovery notice once it has been renewed or is readable again.
+func (a *AlertHandler) HandleCertificate(info *CertificateInfo, previousStatus string) {
+	if !a.monitor.config.Notifications.Email.Enabled {
+		return
+	}
+
+	lastNotified := a.notified[info.Source]
+
+	switch info.Status {
+	case "warning", "critical":
+		if lastNotified == info.Status {
+			return
+		}
+		a.notified[info.Source] = info.Status
+		a.sendAlert(info, alerts.AlertType(info.Status), previousStatus != info.Status, lastNotified)
+	case "unknown":
+		if info.Error == "" || info.ErrorCount < a.monitor.errorThreshold() || lastNotified == "unknown" {
+			return
+		}
+		a.notified[info.Source] = info.Status
+		a.sendAlert(info, alerts.AlertTypeCritical, true, lastNotified)
+	case "normal":
+		if lastNotified == "" {
+			return
+		}
+		delete(a.notified, info.Source)
+		a.sendAlert(info, alerts.AlertTypeNormal, true, lastNotified)
+	}
+}
+
+// sendAlert builds and sends the notification email for a certificate. lastNotified
+// is the status of the previous notification for the source.
+func (a *AlertHandler) sendAlert(info *CertificateInfo, alertType alerts.AlertType, statusChanged bool, lastNotified string) {
+	style := a.handler.GetAlertStyle(alertType)
+
+	var title, subject, additionalContent string
+	switch {
+	case info.Error != "":
+		title = "CERTIFICATE CHECK FAILED"
+		subject = fmt.Sprintf("Certificate Check Failed: %s", info.Source)
+		additionalContent = fmt.Sprintf(`<p><b>Recommendation:</b> The certificate could not be inspected in %d consecutive checks, so its expiry is no longer being watched. Verify that it exists, is readable and is reachable.</p>`,
+			info.ErrorCount)
+	case alertType == alerts.AlertTypeNormal && lastNotified == "unknown":
+		title = "CERTIFICATE CHECK RECOVERED"
+		subject = fmt.Sprintf("Certificate Check Recovered: %s", info.Source)
+		additionalContent = `<p>The certificate can be inspected again and is not close to expiry.</p>`
+	case alertType == alerts.AlertTypeNormal:
+		title = "CERTIFICATE RENEWED"
+		subject = fmt.Sprintf("Certificate Renewed: %s", info.Source)
+		additionalContent = `<p>The certificate is no longer close to expiry. No further action is required.</p>`
+	case info.DaysRemaining < 0:
+		title = "CERTIFICATE EXPIRED"
+		subject = fmt.Sprintf("Certificate Expired: %s", info.Source)
+		additionalContent = `<p><b>Recommendation:</b> The certificate has expired. Clients will reject connections until it is replaced.</p>`
+	default:
+		title = fmt.Sprintf("CERTIFICATE EXPIRY %s", strings.ToUpper(string(alertType)))
+		subject = fmt.Sprintf("Certificate Expiring in %d Days: %s", info.DaysRemaining, info.Source)
+		additionalContent = `<p><b>Recommendation:</b> Renew the certificate and reload the services that use it before it expires.</p>`
+	}
+
+	if !info.ChainValid && info.ChainError != "" {
+		additionalContent += fmt.Sprintf(`
+	<p><b>Chain validation:</b> %s</p>`, info.ChainError)
+	}
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		title,
+		statusChanged,
+		a.createTableContent(info, style),
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, string(alertType))
+	a.monitor.UpdateLastAlertTime()
+
+	logger.Info("Sent certificate notification",
+		logger.String("source", info.Source),
+		logger.String("status", info.Status),
+		logger.Int("days_remaining", info.DaysRemaining))
+}
+
+// createTableContent renders the certificate details as an HTML table
+func (a *AlertHandler) createTableContent(info *CertificateInfo, style alerts.AlertStyle) string {
+	statusLine := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText)
+
+	if info.Error != "" {
+		return statusLine + alerts.CreateTable([]alerts.TableRow{
+			{Label: "Source", Value: fmt.Sprintf("%s (%s)", info.Source, info.SourceType)},
+			{Label: "Error", Value: info.Error},
+			{Label: "Consecutive Errors", Value: fmt.Sprintf("%d", info.ErrorCount)},
+		})
+	}
+
+	chain := "Valid"
+	if !info.ChainValid {
+		chain = "Invalid"
+	}
+
+	rows := []alerts.TableRow{
+		{Label: "Source", Value: fmt.Sprintf("%s (%s)", info.Source, info.SourceType)},
+		{Label: "Subject", Value: info.Subject},
+		{Label: "Issuer", Value: info.Issuer},
+		{Label: "SANs", Value: strings.Join(info.SANs, ", ")},
+		{Label: "Expires", Value: info.NotAfter.Format(time.RFC1123)},
+		{Label: "Days Remaining", Value: fmt.Sprintf("%d", info.DaysRemaining)},
+		{Label: "Chain", Value: chain},
+	}
+
+	return statusLine + alerts.CreateTable(rows)
+}
@@ -0,0 +1,141 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"time"
+)
+
+// loadPEMFile reads all certificates from a PEM encoded file
+func loadPEMFile(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate file: %w", err)
+	}
+
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certificates = append(certificates, cert)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+
+	return certificates, nil
+}
+
+// fetchEndpoint performs a TLS handshake and returns the peer certificate chain.
+// Verification is done separately so expired or untrusted certificates can still be reported.
+func fetchEndpoint(address string, timeout time.Duration) ([]*x509.Certificate, string, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, "", fmt.Errorf("endpoint must be in host:port form: %w", err)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, host, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	certificates := conn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return nil, host, fmt.Errorf("endpoint presented no certificates")
+	}
+
+	return certificates, host, nil
+}
+
+// loadRoots returns the system roots plus the certificates in caFile, if set
+func loadRoots(caFile string) (*x509.CertPool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if caFile == "" {
+		return roots, nil
+	}
+
+	certificates, err := loadPEMFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA %s: %w", caFile, err)
+	}
+	for _, cert := range certificates {
+		roots.AddCert(cert)
+	}
+	return roots, nil
+}
+
+// verifyChain validates the leaf against the given roots using the remaining certificates as intermediates
+func verifyChain(certificates []*x509.Certificate, dnsName string, roots *x509.CertPool) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		DNSName:       dnsName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// describe builds a CertificateInfo from a certificate chain
+func describe(src source, certificates []*x509.Certificate, dnsName string, roots *x509.CertPool, now time.Time) *CertificateInfo {
+	leaf := certificates[0]
+
+	sans := make([]string, 0, len(leaf.DNSNames)+len(leaf.IPAddresses))
+	sans = append(sans, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	info := &CertificateInfo{
+		Source:        src.location,
+		SourceType:    src.sourceType,
+		Subject:       leaf.Subject.String(),
+		Issuer:        leaf.Issuer.String(),
+		SANs:          sans,
+		SerialNumber:  leaf.SerialNumber.String(),
+		NotBefore:     leaf.NotBefore,
+		NotAfter:      leaf.NotAfter,
+		DaysRemaining: daysRemaining(leaf.NotAfter, now),
+		ChainValid:    true,
+		LastCheck:     now,
+	}
+
+	if err := verifyChain(certificates, dnsName, roots); err != nil {
+		info.ChainValid = false
+		info.ChainError = err.Error()
+	}
+
+	return info
+}
+
+// daysRemaining returns the whole days until expiry, negative once expired
+func daysRemaining(notAfter, now time.Time) int {
+	return int(math.Floor(notAfter.Sub(now).Hours() / 24))
+}
@@ -0,0 +1,339 @@
+package certs
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	mariadbService "CheckHealthDO/internal/services/mariadb"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default values used when the configuration leaves them unset
+const (
+	defaultCheckInterval  = 3600
+	defaultWarningDays    = 30
+	defaultCriticalDays   = 7
+	defaultTimeout        = 10
+	defaultErrorThreshold = 3
+)
+
+// Monitor periodically inspects TLS certificates from files and endpoints
+type Monitor struct {
+	config        *config.Config
+	cancelJob     func() // Removes the checks from the scheduler
+	stopChan      chan struct{}
+	isRunning     bool
+	mutex         sync.Mutex
+	certificates  map[string]*CertificateInfo
+	lastAlertTime time.Time
+	emailManager  alerts.NotificationManager
+	alertHandler  *AlertHandler
+}
+
+// NewMonitor creates a new certificate monitor instance
+func NewMonitor(cfg *config.Config) *Monitor {
+	m := &Monitor{
+		config:       cfg,
+		stopChan:     make(chan struct{}),
+		certificates: make(map[string]*CertificateInfo),
+		emailManager: notifications.NewEmailManager(cfg),
+	}
+	m.alertHandler = NewAlertHandler(m)
+	return m
+}
+
+// SetNotificationManager routes expiry alerts to manager. Call it before
+// StartMonitoring.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// StartMonitoring starts the certificate monitoring process
+func (m *Monitor) StartMonitoring() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isRunning {
+		return fmt.Errorf("certificate monitor is already running")
+	}
+
+	if !m.config.Monitoring.Certs.Enabled {
+		return fmt.Errorf("certificate monitoring is disabled in configuration")
+	}
+
+	interval := m.checkInterval()
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			m.checkAll(ctx)
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule certificate checks: %w", err)
+	}
+	m.cancelJob = cancelJob
+	m.isRunning = true
+
+	logger.Info("Starting certificate monitoring",
+		logger.Int("interval_seconds", int(interval.Seconds())),
+		logger.Int("warning_days", m.warningDays()),
+		logger.Int("critical_days", m.criticalDays()))
+
+	return nil
+}
+
+// StopMonitoring stops the certificate monitoring process
+func (m *Monitor) StopMonitoring() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+
+	m.cancelJob()
+	close(m.stopChan)
+	m.isRunning = false
+	logger.Info("Certificate monitoring stopped")
+}
+
+// Name returns "certs"
+func (m *Monitor) Name() string {
+	return "certs"
+}
+
+// Start inspects the certificates on the check interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes the certificate checks from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns a []CertificateInfo for every file and endpoint inspected
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetCertificates()
+}
+
+// Health measures staleness from the most recently inspected certificate
+func (m *Monitor) Health() monitoring.Health {
+	certificates := m.GetCertificates()
+
+	var lastCheck time.Time
+	for _, info := range certificates {
+		if info.LastCheck.After(lastCheck) {
+			lastCheck = info.LastCheck
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return monitoring.NewHealth(m.isRunning, lastCheck, m.checkInterval())
+}
+
+// checkInterval returns the configured time between checks, or the default
+func (m *Monitor) checkInterval() time.Duration {
+	interval := m.config.Monitoring.Certs.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// checkAll inspects every configured source and raises alerts on status changes
+func (m *Monitor) checkAll(ctx context.Context) {
+	now := time.Now()
+	checked := make(map[string]*CertificateInfo)
+
+	for _, src := range m.sources(ctx) {
+		info := m.inspect(src, now)
+		checked[src.location] = info
+
+		m.mutex.Lock()
+		previous, ok := m.certificates[src.location]
+		m.mutex.Unlock()
+
+		previousStatus := "unknown"
+		if ok {
+			previousStatus = previous.Status
+		}
+
+		if info.Error != "" {
+			info.ErrorCount = 1
+			if ok {
+				info.ErrorCount = previous.ErrorCount + 1
+			}
+			logger.Warn("Failed to inspect certificate",
+				logger.String("source", src.location),
+				logger.String("error", info.Error),
+				logger.Int("consecutive_errors", info.ErrorCount))
+		}
+
+		m.alertHandler.HandleCertificate(info, previousStatus)
+	}
+
+	m.mutex.Lock()
+	m.certificates = checked
+	m.mutex.Unlock()
+}
+
+// sources builds the list of certificate locations to inspect
+func (m *Monitor) sources(ctx context.Context) []source {
+	cfg := m.config.Monitoring.Certs
+	var sources []source
+
+	for _, path := range cfg.Files {
+		sources = append(sources, source{location: path, sourceType: SourceFile, caFile: cfg.CAFile})
+	}
+	for _, endpoint := range cfg.Endpoints {
+		sources = append(sources, source{location: endpoint, sourceType: SourceEndpoint, caFile: cfg.CAFile})
+	}
+
+	if cfg.IncludeMariaDB {
+		path, caPath, err := mariadbService.GetSSLPaths(ctx, mariadbService.GetDBConfigFromConfig(m.config))
+		if err != nil {
+			logger.Warn("Failed to determine MariaDB certificate path", logger.String("error", err.Error()))
+		} else if path != "" {
+			// The server's certificate is usually signed by its own CA, not a public one
+			if caPath == "" {
+				caPath = cfg.CAFile
+			}
+			sources = append(sources, source{location: path, sourceType: SourceMariaDB, caFile: caPath})
+		}
+	}
+
+	if cfg.APICertFile != "" {
+		sources = append(sources, source{location: cfg.APICertFile, sourceType: SourceAPI, caFile: cfg.CAFile})
+	}
+
+	return sources
+}
+
+// inspect loads the certificate from a source and evaluates its status
+func (m *Monitor) inspect(src source, now time.Time) *CertificateInfo {
+	var info *CertificateInfo
+
+	roots, err := loadRoots(src.caFile)
+	if err != nil {
+		return &CertificateInfo{Source: src.location, SourceType: src.sourceType, Status: "unknown", Error: err.Error(), LastCheck: now}
+	}
+
+	if src.sourceType == SourceEndpoint {
+		timeout := m.config.Monitoring.Certs.Timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		certificates, host, err := fetchEndpoint(src.location, time.Duration(timeout)*time.Second)
+		if err != nil {
+			return &CertificateInfo{Source: src.location, SourceType: src.sourceType, Status: "unknown", Error: err.Error(), LastCheck: now}
+		}
+		info = describe(src, certificates, host, roots, now)
+	} else {
+		certificates, err := loadPEMFile(src.location)
+		if err != nil {
+			return &CertificateInfo{Source: src.location, SourceType: src.sourceType, Status: "unknown", Error: err.Error(), LastCheck: now}
+		}
+		info = describe(src, certificates, "", roots, now)
+	}
+
+	info.Status = m.determineStatus(info)
+	return info
+}
+
+// determineStatus maps days remaining and chain validity to a status
+func (m *Monitor) determineStatus(info *CertificateInfo) string {
+	switch {
+	case info.DaysRemaining <= m.criticalDays():
+		return "critical"
+	case info.DaysRemaining <= m.warningDays():
+		return "warning"
+	case !info.ChainValid:
+		return "warning"
+	default:
+		return "normal"
+	}
+}
+
+// warningDays returns the configured warning threshold in days
+func (m *Monitor) warningDays() int {
+	if m.config.Monitoring.Certs.WarningDays > 0 {
+		return m.config.Monitoring.Certs.WarningDays
+	}
+	return defaultWarningDays
+}
+
+// errorThreshold returns how many consecutive errors a source may have before alerting
+func (m *Monitor) errorThreshold() int {
+	if m.config.Monitoring.Certs.ErrorThreshold > 0 {
+		return m.config.Monitoring.Certs.ErrorThreshold
+	}
+	return defaultErrorThreshold
+}
+
+// criticalDays returns the configured critical threshold in days
+func (m *Monitor) criticalDays() int {
+	if m.config.Monitoring.Certs.CriticalDays > 0 {
+		return m.config.Monitoring.Certs.CriticalDays
+	}
+	return defaultCriticalDays
+}
+
+// GetCertificates returns the latest results sorted by days remaining
+func (m *Monitor) GetCertificates() []CertificateInfo {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	certificates := make([]CertificateInfo, 0, len(m.certificates))
+	for _, info := range m.certificates {
+		certificates = append(certificates, *info)
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].DaysRemaining < certificates[j].DaysRemaining
+	})
+	return certificates
+}
+
+// GetConfig returns the monitor's configuration
+// Returns interface{} to match the alerts.ConfigProvider interface
+func (m *Monitor) GetConfig() interface{} {
+	return m.config
+}
+
+// GetConfigPtr returns the monitor's configuration as a concrete type pointer
+func (m *Monitor) GetConfigPtr() *config.Config {
+	return m.config
+}
+
+// UpdateLastAlertTime updates the last alert time
+func (m *Monitor) UpdateLastAlertTime() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = time.Now()
+}
+
+// GetLastAlertTime returns the last alert time
+func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastAlertTime
+}
+
+// GetNotificationManagers returns the notification managers
+func (m *Monitor) GetNotificationManagers() alerts.NotificationManager {
+	return m.emailManager
+}
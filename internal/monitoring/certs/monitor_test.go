@@ -0,0 +1,139 @@
+package certs
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// writeCertificate writes a PEM certificate valid for the given days, signed by
+// parent or self-signed when parent is nil, and returns it with its key
+func writeCertificate(t *testing.T, path string, days int, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: filepath.Base(path)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Duration(days) * 24 * time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// newTestMonitor creates a monitor for one certificate file that records its notifications
+func newTestMonitor(file, caFile string) (*Monitor, *alerts.Recorder) {
+	cfg := &config.Config{}
+	cfg.Notifications.Email.Enabled = true
+	cfg.Monitoring.Certs.Files = []string{file}
+	cfg.Monitoring.Certs.CAFile = caFile
+
+	recorder := alerts.NewRecorder()
+	monitor := NewMonitor(cfg)
+	monitor.SetNotificationManager(recorder)
+	return monitor, recorder
+}
+
+func TestChainVerifiedAgainstConfiguredCA(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	leafFile := filepath.Join(dir, "server-cert.pem")
+	ca, caKey := writeCertificate(t, caFile, 3650, true, nil, nil)
+	writeCertificate(t, leafFile, 365, false, ca, caKey)
+
+	tests := []struct {
+		name   string
+		caFile string
+		valid  bool
+		status string
+	}{
+		{name: "system roots only", valid: false, status: "warning"},
+		{name: "configured CA", caFile: caFile, valid: true, status: "normal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor, _ := newTestMonitor(leafFile, tt.caFile)
+			monitor.checkAll(context.Background())
+
+			info := monitor.GetCertificates()[0]
+			if info.ChainValid != tt.valid || info.Status != tt.status {
+				t.Errorf("chain valid = %v, status = %s (%s), want %v, %s", info.ChainValid, info.Status, info.ChainError, tt.valid, tt.status)
+			}
+		})
+	}
+}
+
+func TestPersistentErrorAlerts(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	file := filepath.Join(dir, "app.pem")
+	ca, caKey := writeCertificate(t, caFile, 3650, true, nil, nil)
+	writeCertificate(t, file, 365, false, ca, caKey)
+
+	monitor, recorder := newTestMonitor(file, caFile)
+	monitor.checkAll(context.Background())
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	// The third failure in a row alerts, the fourth stays quiet
+	for i := 0; i < 4; i++ {
+		monitor.checkAll(context.Background())
+	}
+
+	info := monitor.GetCertificates()[0]
+	if info.Status != "unknown" || info.ErrorCount != 4 {
+		t.Errorf("status = %s after %d errors, want unknown after 4", info.Status, info.ErrorCount)
+	}
+
+	writeCertificate(t, file, 365, false, ca, caKey)
+	monitor.checkAll(context.Background())
+
+	want := []string{"Certificate Check Failed: " + file, "Certificate Check Recovered: " + file}
+	if got := recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+}
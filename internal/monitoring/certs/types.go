@@ -0,0 +1,37 @@
+package certs
+
+import "time"
+
+// Certificate source types
+const (
+	SourceFile     = "file"
+	SourceEndpoint = "endpoint"
+	SourceMariaDB  = "mariadb"
+	SourceAPI      = "api"
+)
+
+// CertificateInfo describes the leaf certificate found at a single source
+type CertificateInfo struct {
+	Source        string    `json:"source"`      // File path or host:port
+	SourceType    string    `json:"source_type"` // file, endpoint, mariadb or api
+	Subject       string    `json:"subject,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	SANs          []string  `json:"sans,omitempty"`
+	SerialNumber  string    `json:"serial_number,omitempty"`
+	NotBefore     time.Time `json:"not_before,omitempty"`
+	NotAfter      time.Time `json:"not_after,omitempty"`
+	DaysRemaining int       `json:"days_remaining"`
+	ChainValid    bool      `json:"chain_valid"`
+	ChainError    string    `json:"chain_error,omitempty"`
+	Status        string    `json:"status"` // normal, warning, critical or unknown
+	Error         string    `json:"error,omitempty"`
+	ErrorCount    int       `json:"error_count,omitempty"` // Consecutive checks that failed with an error
+	LastCheck     time.Time `json:"last_check"`
+}
+
+// source identifies a location to read a certificate from
+type source struct {
+	location   string
+	sourceType string
+	caFile     string // CA trusted besides the system roots when verifying the chain
+}
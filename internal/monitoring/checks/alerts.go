@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/logger"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertHandler sends notifications when a synthetic check changes state
+type AlertHandler struct {
+	monitor  *Monitor
+	handler  *alerts.Handler
+	notified map[string]string // Last status a notification was sent for, by check name
+	mutex    sync.Mutex        // Check loops run concurrently
+}
+
+// NewAlertHandler creates a new alert handler for synthetic checks
+func NewAlertHandler(monitor *Monitor) *AlertHandler {
+	return &AlertHandler{
+		monitor:  monitor,
+		handler:  alerts.NewHandler(monitor, nil),
+		notified: make(map[string]string),
+	}
+}
+
+// HandleResult decides whether the latest result warrants a notification.
+// Critical alerts are sent once the failure threshold is reached, and a
+// recovery notice is sent when a previously alerted check succeeds again.
+// Script checks also notify on warning, since the plugin decides that state, and
+// treat an unknown result (exit code 3 or a plugin that could not run) as a warning.
+func (a *AlertHandler) HandleResult(result *Result, previousStatus string) {
+	if !a.monitor.config.Notifications.Email.Enabled {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	lastNotified := a.notified[result.Name]
+
+	status := result.Status
+	if status == "unknown" && result.Type == TypeScript {
+		status = "warning"
+	}
+
+	switch status {
+	case "critical":
+		if lastNotified == "critical" {
+			return
+		}
+		a.notified[result.Name] = result.Status
+		a.sendAlert(result, alerts.AlertTypeCritical, previousStatus != result.Status)
+	case "warning":
+		if result.Type != TypeScript || lastNotified == "warning" {
+			return
+		}
+		a.notified[result.Name] = status
+		a.sendAlert(result, alerts.AlertTypeWarning, previousStatus != result.Status)
+	case "normal":
+		if lastNotified == "" {
+			return
+		}
+		delete(a.notified, result.Name)
+		a.sendAlert(result, alerts.AlertTypeNormal, true)
+	}
+}
+
+// sendAlert builds and sends the notification email for a check
+func (a *AlertHandler) sendAlert(result *Result, alertType alerts.AlertType, statusChanged bool) {
+	style := a.handler.GetAlertStyle(alertType)
+
+	var title, subject, additionalContent string
+	switch alertType {
+	case alerts.AlertTypeWarning:
+		title = "SYNTHETIC CHECK WARNING"
+		subject = fmt.Sprintf("Check Warning: %s", result.Name)
+		additionalContent = `<p><b>Recommendation:</b> Please monitor the check closely if this condition persists.</p>`
+	case alerts.AlertTypeCritical:
+		title = "SYNTHETIC CHECK FAILED"
+		subject = fmt.Sprintf("Check Failed: %s", result.Name)
+		additionalContent = fmt.Sprintf(`<p><b>Recommendation:</b> The %s check has failed %d consecutive times. Verify that the target is reachable and responding correctly.</p>`,
+			strings.ToUpper(result.Type), result.ConsecutiveFailures)
+	default:
+		title = "SYNTHETIC CHECK RECOVERED"
+		subject = fmt.Sprintf("Check Recovered: %s", result.Name)
+		additionalContent = `<p>The check is passing again. No further action is required.</p>`
+	}
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		title,
+		statusChanged,
+		a.createTableContent(result, style),
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, string(alertType))
+	a.monitor.UpdateLastAlertTime()
+
+	logger.Info("Sent synthetic check notification",
+		logger.String("name", result.Name),
+		logger.String("status", result.Status))
+}
+
+// createTableContent renders the check details as an HTML table
+func (a *AlertHandler) createTableContent(result *Result, style alerts.AlertStyle) string {
+	statusLine := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText)
+
+	rows := []alerts.TableRow{
+		{Label: "Check", Value: result.Name},
+		{Label: "Type", Value: strings.ToUpper(result.Type)},
+		{Label: "Target", Value: result.Target},
+		{Label: "Result", Value: result.Message},
+		{Label: "Latency", Value: fmt.Sprintf("%.2f ms", result.LatencyMs)},
+		{Label: "Consecutive Failures", Value: fmt.Sprintf("%d (threshold %d)", result.ConsecutiveFailures, result.FailureThreshold)},
+	}
+
+	if result.StatusCode > 0 {
+		rows = append(rows, alerts.TableRow{Label: "HTTP Status", Value: fmt.Sprintf("%d", result.StatusCode)})
+	}
+	if result.ExitCode != nil {
+		rows = append(rows, alerts.TableRow{Label: "Exit Code", Value: fmt.Sprintf("%d", *result.ExitCode)})
+	}
+	for _, metric := range result.Metrics {
+		rows = append(rows, alerts.TableRow{Label: metric.Label, Value: fmt.Sprintf("%g%s", metric.Value, metric.UOM)})
+	}
+	if len(result.ResolvedValues) > 0 {
+		rows = append(rows, alerts.TableRow{Label: "Resolved Values", Value: strings.Join(result.ResolvedValues, ", ")})
+	}
+	if !result.LastSuccess.IsZero() {
+		rows = append(rows, alerts.TableRow{Label: "Last Success", Value: result.LastSuccess.Format(time.RFC1123)})
+	}
+	rows = append(rows, alerts.TableRow{Label: "Checked At", Value: result.LastCheck.Format(time.RFC1123)})
+
+	return statusLine + alerts.CreateTable(rows)
+}
@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// runDNSCheck resolves the configured name and verifies the expected values are present
+func runDNSCheck(ctx context.Context, check config.CheckConfig) probeResult {
+	resolver := net.DefaultResolver
+	if check.Resolver != "" {
+		address := check.Resolver
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, address)
+			},
+		}
+	}
+
+	recordType := strings.ToUpper(check.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	start := time.Now()
+	values, err := lookup(ctx, resolver, recordType, check.Target)
+	latency := time.Since(start)
+	if err != nil {
+		return probeResult{latency: latency, message: fmt.Sprintf("lookup failed: %v", err)}
+	}
+
+	result := probeResult{
+		latency:        latency,
+		resolvedValues: values,
+	}
+
+	if len(values) == 0 {
+		result.message = fmt.Sprintf("no %s records returned", recordType)
+		return result
+	}
+
+	if missing := missingValues(values, check.ExpectedValues); len(missing) > 0 {
+		result.message = fmt.Sprintf("expected %s not found in answer %s",
+			strings.Join(missing, ", "), strings.Join(values, ", "))
+		return result
+	}
+
+	result.success = true
+	result.message = fmt.Sprintf("resolved %d %s record(s)", len(values), recordType)
+	return result
+}
+
+// lookup performs the lookup for the requested record type
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			values = append(values, ip.String())
+		}
+		return values, nil
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return []string{strings.TrimSuffix(cname, ".")}, nil
+	case "MX":
+		records, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, 0, len(records))
+		for _, mx := range records {
+			values = append(values, strings.TrimSuffix(mx.Host, "."))
+		}
+		return values, nil
+	case "TXT":
+		return resolver.LookupTXT(ctx, name)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+// missingValues returns the expected values that are absent from the answer
+func missingValues(values, expected []string) []string {
+	present := make(map[string]bool, len(values))
+	for _, value := range values {
+		present[strings.ToLower(strings.TrimSuffix(value, "."))] = true
+	}
+
+	var missing []string
+	for _, want := range expected {
+		if !present[strings.ToLower(strings.TrimSuffix(want, "."))] {
+			missing = append(missing, want)
+		}
+	}
+	return missing
+}
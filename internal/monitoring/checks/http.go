@@ -0,0 +1,88 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxBodyBytes limits how much of the response body is read for body matching
+const maxBodyBytes = 1 << 20
+
+// runHTTPCheck performs a single HTTP(S) probe against the configured URL
+func runHTTPCheck(ctx context.Context, check config.CheckConfig, bodyPattern *regexp.Regexp) probeResult {
+	method := strings.ToUpper(check.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, check.Target, nil)
+	if err != nil {
+		return probeResult{message: fmt.Sprintf("invalid request: %v", err)}
+	}
+	req.Header.Set("User-Agent", "CheckHealthDO-Checks/1.0")
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: check.InsecureSkipVerify},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return probeResult{latency: time.Since(start), message: fmt.Sprintf("request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	latency := time.Since(start)
+	if err != nil {
+		return probeResult{
+			latency:    latency,
+			statusCode: resp.StatusCode,
+			message:    fmt.Sprintf("failed to read response body: %v", err),
+		}
+	}
+
+	result := probeResult{
+		latency:    latency,
+		statusCode: resp.StatusCode,
+	}
+
+	if !isExpectedStatus(resp.StatusCode, check.ExpectedStatus) {
+		result.message = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+		return result
+	}
+
+	if bodyPattern != nil && !bodyPattern.Match(body) {
+		result.message = fmt.Sprintf("response body does not match %q", check.BodyMatch)
+		return result
+	}
+
+	result.success = true
+	result.message = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	return result
+}
+
+// isExpectedStatus reports whether the status code is acceptable for the check.
+// Without an explicit list any 2xx or 3xx response is considered healthy.
+func isExpectedStatus(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, want := range expected {
+		if code == want {
+			return true
+		}
+	}
+	return false
+}
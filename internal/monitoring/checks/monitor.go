@@ -0,0 +1,396 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default values applied to checks that leave them unset
+const (
+	defaultInterval         = 60
+	defaultTimeout          = 10
+	defaultFailureThreshold = 3
+)
+
+// deadlineGrace is how long a probe may run past its own timeout before the
+// scheduler counts the run as overdue
+const deadlineGrace = 5 * time.Second
+
+// Monitor periodically probes the configured synthetic check targets
+type Monitor struct {
+	config        *config.Config
+	stopChan      chan struct{}
+	isRunning     bool
+	mutex         sync.Mutex
+	results       map[string]*Result
+	lastAlertTime time.Time
+	emailManager  alerts.NotificationManager
+	alertHandler  *AlertHandler
+	cancelJobs    []func() // Remove the checks from the scheduler
+}
+
+// NewMonitor creates a new checks monitor instance
+func NewMonitor(cfg *config.Config) *Monitor {
+	m := &Monitor{
+		config:       cfg,
+		stopChan:     make(chan struct{}),
+		results:      make(map[string]*Result),
+		emailManager: notifications.NewEmailManager(cfg),
+	}
+	m.alertHandler = NewAlertHandler(m)
+	return m
+}
+
+// SetNotificationManager routes check failure and recovery alerts to manager. Call
+// it before StartMonitoring.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// StartMonitoring schedules every configured check
+func (m *Monitor) StartMonitoring() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isRunning {
+		return fmt.Errorf("checks monitor is already running")
+	}
+
+	if !m.config.Monitoring.Checks.Enabled {
+		return fmt.Errorf("synthetic checks are disabled in configuration")
+	}
+
+	seen := make(map[string]bool)
+	for _, target := range m.config.Monitoring.Checks.Targets {
+		check := applyDefaults(target)
+
+		if err := validateCheck(check); err != nil {
+			logger.Warn("Skipping invalid synthetic check",
+				logger.String("name", check.Name),
+				logger.String("error", err.Error()))
+			continue
+		}
+		if seen[check.Name] {
+			logger.Warn("Skipping duplicate synthetic check", logger.String("name", check.Name))
+			continue
+		}
+		seen[check.Name] = true
+
+		var bodyPattern *regexp.Regexp
+		if check.Type == TypeHTTP && check.BodyMatch != "" {
+			pattern, err := regexp.Compile(check.BodyMatch)
+			if err != nil {
+				logger.Warn("Skipping synthetic check with invalid body_match pattern",
+					logger.String("name", check.Name),
+					logger.String("error", err.Error()))
+				continue
+			}
+			bodyPattern = pattern
+		}
+		if check.Type == TypeScript {
+			if err := allowScript(check); err != nil {
+				logger.Warn("Skipping script check that cannot be allowlisted",
+					logger.String("name", check.Name),
+					logger.String("error", err.Error()))
+				continue
+			}
+		}
+
+		m.results[check.Name] = &Result{
+			Name:             check.Name,
+			Type:             check.Type,
+			Target:           check.Target,
+			Status:           "unknown",
+			FailureThreshold: check.FailureThreshold,
+		}
+
+		cancelJob, err := scheduler.Schedule(scheduler.Job{
+			Name:     m.Name() + "/" + check.Name,
+			Interval: time.Duration(check.Interval) * time.Second,
+			Timeout:  time.Duration(check.Timeout)*time.Second + deadlineGrace,
+			Run: func(ctx context.Context) error {
+				m.runCheck(ctx, check, bodyPattern)
+				return nil
+			},
+		})
+		if err != nil {
+			logger.Warn("Skipping synthetic check that cannot be scheduled",
+				logger.String("name", check.Name),
+				logger.String("error", err.Error()))
+			delete(m.results, check.Name)
+			continue
+		}
+		m.cancelJobs = append(m.cancelJobs, cancelJob)
+	}
+
+	m.isRunning = true
+	logger.Info("Starting synthetic checks monitor",
+		logger.Int("checks", len(m.results)))
+
+	return nil
+}
+
+// StopMonitoring removes all checks from the scheduler
+func (m *Monitor) StopMonitoring() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+	for _, cancelJob := range m.cancelJobs {
+		cancelJob()
+	}
+	m.cancelJobs = nil
+	close(m.stopChan)
+	m.isRunning = false
+	logger.Info("Synthetic checks monitor stopped")
+}
+
+// Name returns "checks"
+func (m *Monitor) Name() string {
+	return "checks"
+}
+
+// Start schedules one job per valid check until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes every check job from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns a []Result with the latest result of every check, sorted by name
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetResults()
+}
+
+// Health never goes stale, since each check has its own interval; it reports the
+// most recent result as the last check
+func (m *Monitor) Health() monitoring.Health {
+	results := m.GetResults()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Checks run at their own intervals, so only the latest result counts
+	var lastCheck time.Time
+	for _, result := range results {
+		if result.LastCheck.After(lastCheck) {
+			lastCheck = result.LastCheck
+		}
+	}
+	return monitoring.NewHealth(m.isRunning, lastCheck, 0)
+}
+
+// runCheck executes a probe and updates the stored result
+func (m *Monitor) runCheck(ctx context.Context, check config.CheckConfig, bodyPattern *regexp.Regexp) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(check.Timeout)*time.Second)
+	defer cancel()
+
+	var probe probeResult
+	switch check.Type {
+	case TypeHTTP:
+		probe = runHTTPCheck(ctx, check, bodyPattern)
+	case TypeTCP:
+		probe = runTCPCheck(ctx, check)
+	case TypeDNS:
+		probe = runDNSCheck(ctx, check)
+	case TypeScript:
+		probe = runScriptCheck(ctx, check)
+	}
+
+	now := time.Now()
+
+	m.mutex.Lock()
+	result, ok := m.results[check.Name]
+	if !ok {
+		m.mutex.Unlock()
+		return
+	}
+
+	previousStatus := result.Status
+	result.Success = probe.success
+	result.LatencyMs = float64(probe.latency.Microseconds()) / 1000
+	result.Message = probe.message
+	result.StatusCode = probe.statusCode
+	result.ResolvedValues = probe.resolvedValues
+	result.Metrics = probe.metrics
+	result.LastCheck = now
+
+	if check.Type == TypeScript {
+		exitCode := probe.exitCode
+		result.ExitCode = &exitCode
+	}
+
+	if probe.success {
+		result.ConsecutiveFailures = 0
+		result.LastSuccess = now
+	} else {
+		result.ConsecutiveFailures++
+		result.LastFailure = now
+	}
+
+	if probe.status != "" {
+		result.Status = probe.status
+	} else {
+		result.Status = determineStatus(result, check)
+	}
+	snapshot := *result
+	m.mutex.Unlock()
+
+	if !probe.success {
+		logger.Debug("Synthetic check failed",
+			logger.String("name", check.Name),
+			logger.String("target", check.Target),
+			logger.Int("consecutive_failures", snapshot.ConsecutiveFailures),
+			logger.String("message", probe.message))
+	}
+
+	if previousStatus != snapshot.Status {
+		logger.Info("Synthetic check status changed",
+			logger.String("name", check.Name),
+			logger.String("previous", previousStatus),
+			logger.String("current", snapshot.Status))
+	}
+
+	m.alertHandler.HandleResult(&snapshot, previousStatus)
+	m.broadcastResults()
+}
+
+// determineStatus maps the probe outcome and failure count to a status
+func determineStatus(result *Result, check config.CheckConfig) string {
+	if !result.Success {
+		if result.ConsecutiveFailures >= check.FailureThreshold {
+			return "critical"
+		}
+		return "warning"
+	}
+
+	if check.MaxLatency > 0 && result.LatencyMs > float64(check.MaxLatency) {
+		return "warning"
+	}
+
+	return "normal"
+}
+
+// applyDefaults fills unset check fields with sensible defaults
+func applyDefaults(check config.CheckConfig) config.CheckConfig {
+	check.Type = strings.ToLower(strings.TrimSpace(check.Type))
+	if check.Name == "" {
+		check.Name = fmt.Sprintf("%s:%s", check.Type, check.Target)
+	}
+	if check.Interval <= 0 {
+		check.Interval = defaultInterval
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = defaultTimeout
+	}
+	if check.FailureThreshold <= 0 {
+		check.FailureThreshold = defaultFailureThreshold
+	}
+	return check
+}
+
+// validateCheck ensures a check has enough information to run
+func validateCheck(check config.CheckConfig) error {
+	if check.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+
+	switch check.Type {
+	case TypeHTTP:
+		if !strings.HasPrefix(check.Target, "http://") && !strings.HasPrefix(check.Target, "https://") {
+			return fmt.Errorf("http target must start with http:// or https://")
+		}
+	case TypeTCP:
+		if !strings.Contains(check.Target, ":") {
+			return fmt.Errorf("tcp target must be in host:port form")
+		}
+	case TypeScript:
+		if !filepath.IsAbs(check.Target) {
+			return fmt.Errorf("script target must be an absolute path")
+		}
+	case TypeDNS:
+	default:
+		return fmt.Errorf("unsupported check type %q", check.Type)
+	}
+
+	return nil
+}
+
+// GetResults returns a copy of all check results sorted by name
+func (m *Monitor) GetResults() []Result {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	results := make([]Result, 0, len(m.results))
+	for _, result := range m.results {
+		results = append(results, *result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// GetResult returns the result of a single check by name
+func (m *Monitor) GetResult(name string) (*Result, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result, ok := m.results[name]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *result
+	return &snapshot, true
+}
+
+// GetConfig returns the monitor's configuration
+// Returns interface{} to match the alerts.ConfigProvider interface
+func (m *Monitor) GetConfig() interface{} {
+	return m.config
+}
+
+// GetConfigPtr returns the monitor's configuration as a concrete type pointer
+func (m *Monitor) GetConfigPtr() *config.Config {
+	return m.config
+}
+
+// UpdateLastAlertTime updates the last alert time
+func (m *Monitor) UpdateLastAlertTime() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = time.Now()
+}
+
+// GetLastAlertTime returns the last alert time
+func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastAlertTime
+}
+
+// GetNotificationManagers returns the notification managers
+func (m *Monitor) GetNotificationManagers() alerts.NotificationManager {
+	return m.emailManager
+}
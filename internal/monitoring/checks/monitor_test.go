@@ -0,0 +1,220 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+func TestHTTPCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			fmt.Fprint(w, `{"status":"degraded"}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		expected  []int
+		bodyMatch string
+		success   bool
+		message   string
+	}{
+		{name: "2xx", path: "/health", success: true, message: "HTTP 200"},
+		{name: "body matches", path: "/health", bodyMatch: `"ok"`, success: true},
+		{name: "body does not match", path: "/other", bodyMatch: `"ok"`, message: "response body does not match"},
+		{name: "unexpected status", path: "/missing", message: "unexpected status code 404"},
+		{name: "expected 404", path: "/missing", expected: []int{404}, success: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := applyDefaults(config.CheckConfig{Type: TypeHTTP, Target: server.URL + tt.path, ExpectedStatus: tt.expected, BodyMatch: tt.bodyMatch})
+			var pattern *regexp.Regexp
+			if tt.bodyMatch != "" {
+				pattern = regexp.MustCompile(tt.bodyMatch)
+			}
+
+			probe := runHTTPCheck(context.Background(), check, pattern)
+			if probe.success != tt.success || !strings.Contains(probe.message, tt.message) {
+				t.Errorf("probe = %v %q, want %v %q", probe.success, probe.message, tt.success, tt.message)
+			}
+		})
+	}
+}
+
+func TestTCPCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+
+	if probe := runTCPCheck(context.Background(), config.CheckConfig{Target: address}); !probe.success {
+		t.Errorf("open port: %q, want success", probe.message)
+	}
+
+	listener.Close()
+	if probe := runTCPCheck(context.Background(), config.CheckConfig{Target: address}); probe.success || !strings.Contains(probe.message, "connection failed") {
+		t.Errorf("closed port: %v %q, want a connection failure", probe.success, probe.message)
+	}
+}
+
+// serveDNS answers every A query on a local UDP port with the given address
+func serveDNS(t *testing.T, answer net.IP) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := dnsReply(buf[:n], answer); reply != nil {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// dnsReply builds a response to a single question query with one A record
+func dnsReply(query []byte, answer net.IP) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	// The question is the name followed by its type and class
+	end := 12
+	for end < len(query) && query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end += 5
+	if end > len(query) {
+		return nil
+	}
+
+	reply := append([]byte{}, query[:2]...)
+	reply = append(reply, 0x81, 0x80, 0, 1, 0, 1, 0, 0, 0, 0)
+	reply = append(reply, query[12:end]...)
+	if binary.BigEndian.Uint16(query[end-4:end-2]) != 1 {
+		// Only A records are served; answer other types with no records
+		reply[7] = 0
+		return reply
+	}
+	reply = append(reply, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+	return append(reply, answer.To4()...)
+}
+
+func TestDNSCheck(t *testing.T) {
+	resolver := serveDNS(t, net.ParseIP("192.0.2.10"))
+
+	tests := []struct {
+		name     string
+		expected []string
+		success  bool
+		message  string
+	}{
+		{name: "any answer", success: true, message: "resolved 1 A record(s)"},
+		{name: "expected value present", expected: []string{"192.0.2.10"}, success: true},
+		{name: "expected value missing", expected: []string{"192.0.2.99"}, message: "expected 192.0.2.99 not found in answer 192.0.2.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := config.CheckConfig{Type: TypeDNS, Target: "db.example.test", Resolver: resolver, ExpectedValues: tt.expected}
+
+			probe := runDNSCheck(context.Background(), check)
+			if probe.success != tt.success || !strings.Contains(probe.message, tt.message) {
+				t.Errorf("probe = %v %q, want %v %q", probe.success, probe.message, tt.success, tt.message)
+			}
+		})
+	}
+}
+
+func TestAlertAndRecovery(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	cfg := &config.Config{}
+	cfg.Notifications.Email.Enabled = true
+	recorder := alerts.NewRecorder()
+	monitor := NewMonitor(cfg)
+	monitor.SetNotificationManager(recorder)
+
+	check := applyDefaults(config.CheckConfig{Name: "db", Type: TypeTCP, Target: address, Timeout: 1, FailureThreshold: 2})
+	monitor.results[check.Name] = &Result{Name: check.Name, Type: check.Type, Target: check.Target, Status: "unknown"}
+
+	var statuses []string
+	run := func() {
+		monitor.runCheck(context.Background(), check, nil)
+		result, _ := monitor.GetResult(check.Name)
+		statuses = append(statuses, result.Status)
+	}
+
+	// Two failures reach the threshold, a third stays quiet
+	run()
+	run()
+	run()
+
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("port %s was taken before the recovery could be tested: %v", address, err)
+	}
+	defer listener.Close()
+	run()
+	run()
+
+	if got := strings.Join(statuses, ","); got != "warning,critical,critical,normal,normal" {
+		t.Errorf("statuses = %s", got)
+	}
+	want := []string{"Check Failed: db", "Check Recovered: db"}
+	if got := recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+}
+
+func TestDetermineStatusLatency(t *testing.T) {
+	check := config.CheckConfig{FailureThreshold: 3, MaxLatency: 100}
+	result := &Result{Success: true, LatencyMs: 250}
+	if status := determineStatus(result, check); status != "warning" {
+		t.Errorf("slow success = %s, want warning", status)
+	}
+
+	result.LatencyMs = 20
+	if status := determineStatus(result, check); status != "normal" {
+		t.Errorf("fast success = %s, want normal", status)
+	}
+}
@@ -0,0 +1,212 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/runner"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScriptOutput limits how much plugin output is kept
+const maxScriptOutput = 64 * 1024
+
+// Nagios plugin exit codes
+const (
+	exitOK       = 0
+	exitWarning  = 1
+	exitCritical = 2
+	exitUnknown  = 3
+)
+
+// PerfData is a single performance metric reported by a Nagios plugin
+type PerfData struct {
+	Label    string   `json:"label"`
+	Value    float64  `json:"value"`
+	UOM      string   `json:"uom,omitempty"`
+	Warning  string   `json:"warning,omitempty"`
+	Critical string   `json:"critical,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+}
+
+// allowScript adds a configured plugin to the command allowlist. The target must
+// be an absolute path that does not shadow an allowlisted command.
+func allowScript(check config.CheckConfig) error {
+	return runner.AllowScript(check.Target, runner.Spec{MaxOutput: maxScriptOutput})
+}
+
+// runScriptCheck executes a Nagios compatible plugin and maps its exit code to a status
+func runScriptCheck(ctx context.Context, check config.CheckConfig) probeResult {
+	start := time.Now()
+	result, err := runner.Run(ctx, runner.Command{
+		Name:    check.Target,
+		Args:    check.Args,
+		Timeout: time.Duration(check.Timeout) * time.Second,
+	})
+	latency := time.Since(start)
+
+	if errors.Is(err, runner.ErrTimeout) {
+		return probeResult{
+			latency:  latency,
+			status:   "critical",
+			exitCode: exitCritical,
+			message:  fmt.Sprintf("script timed out after %ds", check.Timeout),
+		}
+	}
+
+	exitCode := exitOK
+	if err != nil {
+		var exitErr *runner.ExitError
+		if !errors.As(err, &exitErr) {
+			return probeResult{
+				latency:  latency,
+				status:   "unknown",
+				exitCode: exitUnknown,
+				message:  fmt.Sprintf("failed to execute script: %v", err),
+			}
+		}
+		exitCode = exitErr.ExitCode
+	}
+
+	stdout := string(result.Stdout)
+	message, perfData := parsePluginOutput(stdout)
+	if message == "" {
+		message = fmt.Sprintf("script exited with code %d", exitCode)
+	}
+
+	return probeResult{
+		success:  exitCode == exitOK,
+		latency:  latency,
+		message:  message,
+		status:   statusFromExitCode(exitCode),
+		exitCode: exitCode,
+		metrics:  perfData,
+	}
+}
+
+// statusFromExitCode maps a Nagios plugin exit code to a monitor status
+func statusFromExitCode(code int) string {
+	switch code {
+	case exitOK:
+		return "normal"
+	case exitWarning:
+		return "warning"
+	case exitCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// parsePluginOutput splits plugin output into the status text of the first
+// line and the performance data found after '|' on any line.
+func parsePluginOutput(output string) (string, []PerfData) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	var message string
+	var perfData []PerfData
+	for i, line := range lines {
+		text, perf, hasPerf := strings.Cut(line, "|")
+		if i == 0 {
+			message = strings.TrimSpace(text)
+		}
+		if hasPerf {
+			perfData = append(perfData, parsePerfData(perf)...)
+		}
+	}
+
+	return message, perfData
+}
+
+// parsePerfData parses space separated 'label'=value[UOM];[warn];[crit];[min];[max] entries
+func parsePerfData(perf string) []PerfData {
+	var metrics []PerfData
+
+	for _, token := range splitPerfTokens(perf) {
+		label, rest, ok := strings.Cut(token, "=")
+		if !ok {
+			continue
+		}
+		label = strings.Trim(label, "'")
+
+		fields := strings.Split(rest, ";")
+		value, uom := splitValueUOM(fields[0])
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+
+		metric := PerfData{Label: label, Value: parsed, UOM: uom}
+		if len(fields) > 1 {
+			metric.Warning = fields[1]
+		}
+		if len(fields) > 2 {
+			metric.Critical = fields[2]
+		}
+		if len(fields) > 3 {
+			metric.Min = parseOptionalFloat(fields[3])
+		}
+		if len(fields) > 4 {
+			metric.Max = parseOptionalFloat(fields[4])
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
+
+// splitPerfTokens splits perfdata on whitespace while keeping quoted labels intact
+func splitPerfTokens(perf string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range perf {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// splitValueUOM separates the numeric value from its unit of measure
+func splitValueUOM(raw string) (string, string) {
+	i := len(raw)
+	for i > 0 {
+		c := raw[i-1]
+		if (c >= '0' && c <= '9') || c == '.' {
+			break
+		}
+		i--
+	}
+	return raw[:i], raw[i:]
+}
+
+// parseOptionalFloat parses a perfdata min/max field, returning nil when empty
+func parseOptionalFloat(raw string) *float64 {
+	if raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,181 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/config"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// float returns a pointer for the optional perfdata fields
+func float(v float64) *float64 {
+	return &v
+}
+
+func TestStatusFromExitCode(t *testing.T) {
+	tests := map[int]string{
+		0:   "normal",
+		1:   "warning",
+		2:   "critical",
+		3:   "unknown",
+		4:   "unknown",
+		-1:  "unknown",
+		127: "unknown",
+	}
+	for code, want := range tests {
+		if got := statusFromExitCode(code); got != want {
+			t.Errorf("statusFromExitCode(%d) = %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestSplitPerfTokens(t *testing.T) {
+	tests := []struct {
+		perf string
+		want []string
+	}{
+		{"", nil},
+		{"load1=0.5 load5=0.7", []string{"load1=0.5", "load5=0.7"}},
+		{"  time=1s\tsize=2B  ", []string{"time=1s", "size=2B"}},
+		{"'free space'=10GB;5;2;; 'used'=1", []string{"'free space'=10GB;5;2;;", "'used'=1"}},
+	}
+	for _, tt := range tests {
+		if got := splitPerfTokens(tt.perf); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitPerfTokens(%q) = %q, want %q", tt.perf, got, tt.want)
+		}
+	}
+}
+
+func TestParsePerfData(t *testing.T) {
+	tests := []struct {
+		name string
+		perf string
+		want []PerfData
+	}{
+		{
+			name: "quoted label with thresholds",
+			perf: "'quoted label'=1;2;3;;",
+			want: []PerfData{{Label: "quoted label", Value: 1, Warning: "2", Critical: "3"}},
+		},
+		{
+			name: "units, min and max",
+			perf: "time=0.25s;1;2;0;10 used=80%;90;95 size=1.5KB",
+			want: []PerfData{
+				{Label: "time", Value: 0.25, UOM: "s", Warning: "1", Critical: "2", Min: float(0), Max: float(10)},
+				{Label: "used", Value: 80, UOM: "%", Warning: "90", Critical: "95"},
+				{Label: "size", Value: 1.5, UOM: "KB"},
+			},
+		},
+		{
+			name: "ranges kept as text",
+			perf: "conns=12;@10:20;~:30",
+			want: []PerfData{{Label: "conns", Value: 12, Warning: "@10:20", Critical: "~:30"}},
+		},
+		{
+			name: "malformed entries skipped",
+			perf: "novalue label=abc ok=3c",
+			want: []PerfData{{Label: "ok", Value: 3, UOM: "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePerfData(tt.perf); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePerfData(%q) = %+v, want %+v", tt.perf, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePluginOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		message string
+		labels  []string
+	}{
+		{"empty", "", "", nil},
+		{"text only", "OK - all good\n", "OK - all good", nil},
+		{"single line", "DISK OK - free 10GB | '/ free'=10GB;5;2;0;100\n", "DISK OK - free 10GB", []string{"/ free"}},
+		{
+			name:    "multi-line",
+			output:  "MYSQL WARNING - 90 connections | conns=90;80;100\nthreads running: 4\nslow queries: 2 | threads=4 slow=2c\n",
+			message: "MYSQL WARNING - 90 connections",
+			labels:  []string{"conns", "threads", "slow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, perfData := parsePluginOutput(tt.output)
+			if message != tt.message {
+				t.Errorf("message = %q, want %q", message, tt.message)
+			}
+			var labels []string
+			for _, metric := range perfData {
+				labels = append(labels, metric.Label)
+			}
+			if !reflect.DeepEqual(labels, tt.labels) {
+				t.Errorf("labels = %q, want %q", labels, tt.labels)
+			}
+		})
+	}
+}
+
+func TestScriptTargetMustBeAbsolute(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		target string
+		ok     bool
+	}{
+		{filepath.Join(dir, "check_mysql"), true},
+		{"check_mysql", false},
+		{"plugins/check_mysql", false},
+		{filepath.Join(dir, "systemctl"), false},
+	}
+	for _, tt := range tests {
+		check := applyDefaults(config.CheckConfig{Type: TypeScript, Target: tt.target})
+		err := validateCheck(check)
+		if err == nil {
+			err = allowScript(check)
+		}
+		if (err == nil) != tt.ok {
+			t.Errorf("script target %s: err = %v, want ok %v", tt.target, err, tt.ok)
+		}
+	}
+}
+
+func TestScriptUnknownAlertsAsWarning(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "check_unknown")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'UNKNOWN - cannot reach server'\nexit 3\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Notifications.Email.Enabled = true
+	recorder := alerts.NewRecorder()
+	monitor := NewMonitor(cfg)
+	monitor.SetNotificationManager(recorder)
+
+	check := applyDefaults(config.CheckConfig{Name: "plugin", Type: TypeScript, Target: script})
+	if err := allowScript(check); err != nil {
+		t.Fatal(err)
+	}
+	monitor.results[check.Name] = &Result{Name: check.Name, Type: check.Type, Target: check.Target, Status: "unknown"}
+
+	monitor.runCheck(context.Background(), check, nil)
+	monitor.runCheck(context.Background(), check, nil)
+
+	result, _ := monitor.GetResult(check.Name)
+	if result.Status != "unknown" || result.ExitCode == nil || *result.ExitCode != exitUnknown || result.Message != "UNKNOWN - cannot reach server" {
+		t.Errorf("result = %+v, want the unknown plugin output", result)
+	}
+	want := []string{"Check Warning: plugin"}
+	if got := recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// runTCPCheck measures how long it takes to open a TCP connection to host:port
+func runTCPCheck(ctx context.Context, check config.CheckConfig) probeResult {
+	var dialer net.Dialer
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", check.Target)
+	latency := time.Since(start)
+	if err != nil {
+		return probeResult{latency: latency, message: fmt.Sprintf("connection failed: %v", err)}
+	}
+	conn.Close()
+
+	return probeResult{
+		success: true,
+		latency: latency,
+		message: fmt.Sprintf("connected to %s", check.Target),
+	}
+}
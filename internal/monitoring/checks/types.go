@@ -0,0 +1,43 @@
+package checks
+
+import "time"
+
+// Check types supported by the checks monitor
+const (
+	TypeHTTP   = "http"
+	TypeTCP    = "tcp"
+	TypeDNS    = "dns"
+	TypeScript = "script"
+)
+
+// Result represents the latest state of a single synthetic check
+type Result struct {
+	Name                string     `json:"name"`
+	Type                string     `json:"type"`
+	Target              string     `json:"target"`
+	Status              string     `json:"status"`                    // normal, warning, critical or unknown
+	Success             bool       `json:"success"`                   // Whether the last probe succeeded
+	LatencyMs           float64    `json:"latency_ms"`                // Latency of the last probe in milliseconds
+	Message             string     `json:"message,omitempty"`         // Human readable outcome of the last probe
+	StatusCode          int        `json:"status_code,omitempty"`     // HTTP status code (http checks only)
+	ResolvedValues      []string   `json:"resolved_values,omitempty"` // DNS answer (dns checks only)
+	ExitCode            *int       `json:"exit_code,omitempty"`       // Plugin exit code (script checks only)
+	Metrics             []PerfData `json:"metrics,omitempty"`         // Plugin performance data (script checks only)
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailureThreshold    int        `json:"failure_threshold"`
+	LastCheck           time.Time  `json:"last_check"`
+	LastSuccess         time.Time  `json:"last_success,omitempty"`
+	LastFailure         time.Time  `json:"last_failure,omitempty"`
+}
+
+// probeResult is the raw outcome of a single probe before status evaluation
+type probeResult struct {
+	success        bool
+	latency        time.Duration
+	message        string
+	statusCode     int
+	resolvedValues []string
+	status         string // Explicit status reported by the probe, overrides threshold evaluation
+	exitCode       int
+	metrics        []PerfData
+}
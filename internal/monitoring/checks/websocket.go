@@ -0,0 +1,58 @@
+package checks
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/websocket"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketHandler creates a handler function for synthetic checks WebSocket
+func (m *Monitor) WebSocketHandler(c *gin.Context) {
+	// Ensure monitor is properly initialized
+	if m == nil {
+		logger.Error("Checks monitor is nil in WebSocketHandler")
+		c.String(500, "Internal server error: checks monitor not initialized")
+		return
+	}
+
+	// Initialize the WebSocket registry if needed
+	registry := websocket.GetRegistry()
+	handler := registry.HandlerFor(websocket.TopicChecks)
+
+	logger.Info("New WebSocket client connected for synthetic checks",
+		logger.String("client_ip", c.ClientIP()))
+
+	// Let the central registry handle the WebSocket connection
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// broadcastResults pushes the current results of all checks to WebSocket clients
+func (m *Monitor) broadcastResults() {
+	registry := websocket.GetRegistry()
+	if registry.GetHandler(websocket.TopicChecks) == nil {
+		return
+	}
+
+	results := m.GetResults()
+	summary := map[string]int{"normal": 0, "warning": 0, "critical": 0, "unknown": 0}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	timestamp := time.Now()
+	registry.Broadcast(websocket.TopicChecks, map[string]interface{}{
+		"metric_type": "checks",
+		"metrics_data": map[string]interface{}{
+			"summary": summary,
+			"checks":  results,
+		},
+		"meta": map[string]interface{}{
+			"timestamp":        timestamp,
+			"last_update_time": timestamp.Format(time.RFC3339),
+			"source":           "checks_monitor",
+			"version":          "1.0",
+		},
+	})
+}
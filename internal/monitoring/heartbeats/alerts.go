@@ -0,0 +1,119 @@
+package heartbeats
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/logger"
+	"fmt"
+	"html"
+	"sync"
+	"time"
+)
+
+// AlertHandler sends notifications for missed and failed heartbeats
+type AlertHandler struct {
+	monitor *Monitor
+	handler *alerts.Handler
+	alerted map[string]bool // Heartbeats that have sent a critical alert and await recovery
+	mutex   sync.Mutex      // Pings arrive from API requests while the monitor loop runs
+}
+
+// NewAlertHandler creates a new alert handler for heartbeats
+func NewAlertHandler(monitor *Monitor) *AlertHandler {
+	return &AlertHandler{
+		monitor: monitor,
+		handler: alerts.NewHandler(monitor, nil),
+		alerted: make(map[string]bool),
+	}
+}
+
+// HandleState sends a critical alert when a heartbeat is missed or fails,
+// and a recovery notice once a successful ping arrives again.
+func (a *AlertHandler) HandleState(state *State, previousStatus string) {
+	if !a.monitor.config.Notifications.Email.Enabled {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	switch state.Status {
+	case "critical":
+		if a.alerted[state.Name] {
+			return
+		}
+		a.alerted[state.Name] = true
+		a.sendAlert(state, alerts.AlertTypeCritical, previousStatus != state.Status)
+	case "normal":
+		if !a.alerted[state.Name] {
+			return
+		}
+		delete(a.alerted, state.Name)
+		a.sendAlert(state, alerts.AlertTypeNormal, true)
+	}
+}
+
+// sendAlert builds and sends the notification email for a heartbeat
+func (a *AlertHandler) sendAlert(state *State, alertType alerts.AlertType, statusChanged bool) {
+	style := a.handler.GetAlertStyle(alertType)
+
+	var title, subject, additionalContent string
+	if alertType == alerts.AlertTypeCritical {
+		title = "HEARTBEAT MISSED"
+		subject = fmt.Sprintf("Heartbeat Down: %s", state.Name)
+		if state.lastOutcomeFail {
+			title = "HEARTBEAT FAILED"
+		}
+		additionalContent = `<p><b>Recommendation:</b> Check the job's schedule and logs to confirm whether it ran and why it did not report success.</p>`
+	} else {
+		title = "HEARTBEAT RECOVERED"
+		subject = fmt.Sprintf("Heartbeat Recovered: %s", state.Name)
+		additionalContent = `<p>A successful ping has been received. No further action is required.</p>`
+	}
+
+	if state.LastMessage != "" {
+		additionalContent += fmt.Sprintf(`
+	<p><b>Last message:</b></p><pre>%s</pre>`, html.EscapeString(state.LastMessage))
+	}
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		title,
+		statusChanged,
+		a.createTableContent(state, style),
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, string(alertType))
+	a.monitor.UpdateLastAlertTime()
+
+	logger.Info("Sent heartbeat notification",
+		logger.String("name", state.Name),
+		logger.String("status", state.Status))
+}
+
+// createTableContent renders the heartbeat details as an HTML table
+func (a *AlertHandler) createTableContent(state *State, style alerts.AlertStyle) string {
+	statusLine := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText)
+
+	rows := []alerts.TableRow{
+		{Label: "Heartbeat", Value: state.Name},
+		{Label: "Reason", Value: state.Reason},
+		{Label: "Expected Every", Value: (time.Duration(state.Interval) * time.Second).String()},
+		{Label: "Grace Period", Value: (time.Duration(state.Grace) * time.Second).String()},
+		{Label: "Last Success", Value: formatTime(state.LastSuccess)},
+		{Label: "Last Failure", Value: formatTime(state.LastFailure)},
+		{Label: "Last Duration", Value: fmt.Sprintf("%.1f seconds", state.LastDuration)},
+	}
+
+	return statusLine + alerts.CreateTable(rows)
+}
+
+// formatTime renders a timestamp for alerts, or "never" when unset
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format(time.RFC1123)
+}
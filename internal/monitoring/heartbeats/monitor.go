@@ -0,0 +1,364 @@
+package heartbeats
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default values used when the configuration leaves them unset
+const (
+	defaultCheckInterval = 30
+	defaultInterval      = 86400
+	defaultGrace         = 3600
+	maxMessageLength     = 10 * 1024
+)
+
+// Monitor tracks heartbeat pings and flags jobs that are late or failing
+type Monitor struct {
+	config        *config.Config
+	cancelJob     func() // Removes the checks from the scheduler
+	stopChan      chan struct{}
+	isRunning     bool
+	mutex         sync.Mutex
+	jobs          map[string]config.HeartbeatConfig
+	states        map[string]*State
+	startedAt     time.Time
+	lastCheck     time.Time // When the last evaluation finished
+	lastAlertTime time.Time
+	emailManager  alerts.NotificationManager
+	alertHandler  *AlertHandler
+	clock         clock.Clock // Time source for pings and deadline checks
+}
+
+// NewMonitor creates a new heartbeat monitor instance
+func NewMonitor(cfg *config.Config) *Monitor {
+	m := &Monitor{
+		config:       cfg,
+		stopChan:     make(chan struct{}),
+		jobs:         make(map[string]config.HeartbeatConfig),
+		states:       make(map[string]*State),
+		startedAt:    time.Now(),
+		emailManager: notifications.NewEmailManager(cfg),
+		clock:        clock.System,
+	}
+
+	for _, job := range cfg.Monitoring.Heartbeats.Jobs {
+		if job.Name == "" {
+			logger.Warn("Skipping heartbeat without a name")
+			continue
+		}
+		if job.Interval <= 0 {
+			job.Interval = defaultInterval
+		}
+		if job.Grace <= 0 {
+			job.Grace = defaultGrace
+		}
+		m.jobs[job.Name] = job
+		m.states[job.Name] = &State{
+			Name:     job.Name,
+			Status:   "unknown",
+			Interval: job.Interval,
+			Grace:    job.Grace,
+		}
+	}
+
+	m.alertHandler = NewAlertHandler(m)
+	return m
+}
+
+// SetClock replaces the time source and restarts the grace period of heartbeats
+// that have not pinged yet from it. Call it before StartMonitoring.
+func (m *Monitor) SetClock(c clock.Clock) {
+	m.clock = c
+	m.startedAt = c.Now()
+}
+
+// Now returns the monitor's current time, which the alert handler uses for cooldowns
+func (m *Monitor) Now() time.Time {
+	return m.clock.Now()
+}
+
+// SetNotificationManager sends heartbeat alerts to manager instead of email
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// StartMonitoring starts evaluating heartbeats for missed deadlines
+func (m *Monitor) StartMonitoring() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isRunning {
+		return fmt.Errorf("heartbeat monitor is already running")
+	}
+
+	if !m.config.Monitoring.Heartbeats.Enabled {
+		return fmt.Errorf("heartbeat monitoring is disabled in configuration")
+	}
+
+	interval := m.checkInterval()
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Delayed:  true,
+		Run: func(context.Context) error {
+			m.evaluateAll()
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule heartbeat checks: %w", err)
+	}
+	m.cancelJob = cancelJob
+	m.isRunning = true
+
+	logger.Info("Starting heartbeat monitoring",
+		logger.Int("heartbeats", len(m.jobs)),
+		logger.Int("interval_seconds", int(interval.Seconds())))
+
+	return nil
+}
+
+// StopMonitoring stops evaluating heartbeats
+func (m *Monitor) StopMonitoring() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+
+	m.cancelJob()
+	close(m.stopChan)
+	m.isRunning = false
+	logger.Info("Heartbeat monitoring stopped")
+}
+
+// Name returns "heartbeats"
+func (m *Monitor) Name() string {
+	return "heartbeats"
+}
+
+// Start evaluates the heartbeat deadlines on the check interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes the deadline checks from the scheduler. Pings are still recorded.
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns a []State with every heartbeat evaluated at the current time
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetStates()
+}
+
+// Health compares the last deadline evaluation with the check interval
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return monitoring.NewHealth(m.isRunning, m.lastCheck, m.checkInterval())
+}
+
+// checkInterval returns the configured time between checks, or the default
+func (m *Monitor) checkInterval() time.Duration {
+	interval := m.config.Monitoring.Heartbeats.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// evaluateAll updates the status of every heartbeat and raises alerts on transitions
+func (m *Monitor) evaluateAll() {
+	now := m.clock.Now()
+
+	m.mutex.Lock()
+	var changed []State
+	previous := make(map[string]string)
+	for name, state := range m.states {
+		oldStatus := state.Status
+		m.evaluate(state, m.jobs[name], now)
+		if state.Status != oldStatus {
+			changed = append(changed, *state)
+			previous[name] = oldStatus
+		}
+	}
+	m.lastCheck = now
+	m.mutex.Unlock()
+
+	for i := range changed {
+		m.alertHandler.HandleState(&changed[i], previous[changed[i].Name])
+	}
+}
+
+// evaluate computes the status of a heartbeat at the given time
+func (m *Monitor) evaluate(state *State, job config.HeartbeatConfig, now time.Time) {
+	interval := time.Duration(job.Interval) * time.Second
+	grace := time.Duration(job.Grace) * time.Second
+
+	// Until the first successful ping, measure from when monitoring started
+	reference := state.LastSuccess
+	if reference.IsZero() {
+		reference = m.startedAt
+	}
+	state.NextExpected = reference.Add(interval)
+
+	switch {
+	case state.lastOutcomeFail:
+		state.Status = "critical"
+		state.Reason = "last run reported failure"
+	case state.Running && job.MaxDuration > 0 &&
+		now.Sub(state.LastStart) > time.Duration(job.MaxDuration)*time.Second+grace:
+		state.Status = "critical"
+		state.Reason = fmt.Sprintf("run started at %s has not finished", state.LastStart.Format(time.RFC3339))
+	case now.After(state.NextExpected.Add(grace)):
+		state.Status = "critical"
+		if state.LastSuccess.IsZero() {
+			state.Reason = "no successful ping received"
+		} else {
+			state.Reason = fmt.Sprintf("no successful ping since %s", state.LastSuccess.Format(time.RFC3339))
+		}
+	case now.After(state.NextExpected):
+		state.Status = "warning"
+		state.Reason = "heartbeat is late, within grace period"
+	case state.LastSuccess.IsZero():
+		state.Status = "unknown"
+		state.Reason = "waiting for first ping"
+	default:
+		state.Status = "normal"
+		state.Reason = ""
+	}
+}
+
+// RecordPing registers a ping of the given kind for a heartbeat
+func (m *Monitor) RecordPing(name, kind, token, message string) (*State, error) {
+	now := m.clock.Now()
+
+	m.mutex.Lock()
+	job, ok := m.jobs[name]
+	if !ok {
+		m.mutex.Unlock()
+		return nil, ErrUnknownHeartbeat
+	}
+	if job.Token != "" && subtle.ConstantTimeCompare([]byte(job.Token), []byte(token)) != 1 {
+		m.mutex.Unlock()
+		return nil, ErrInvalidToken
+	}
+
+	if len(message) > maxMessageLength {
+		message = message[:maxMessageLength]
+	}
+
+	state := m.states[name]
+	previousStatus := state.Status
+	state.LastPing = now
+	state.TotalPings++
+	if message != "" {
+		state.LastMessage = message
+	}
+
+	switch kind {
+	case PingStart:
+		state.Running = true
+		state.LastStart = now
+	case PingFail:
+		m.finishRun(state, now)
+		state.LastFailure = now
+		state.TotalFailures++
+		state.lastOutcomeFail = true
+	default:
+		m.finishRun(state, now)
+		state.LastSuccess = now
+		state.lastOutcomeFail = false
+	}
+
+	m.evaluate(state, job, now)
+	snapshot := *state
+	m.mutex.Unlock()
+
+	logger.Info("Heartbeat received",
+		logger.String("name", name),
+		logger.String("kind", kind),
+		logger.String("status", snapshot.Status))
+
+	if snapshot.Status != previousStatus {
+		m.alertHandler.HandleState(&snapshot, previousStatus)
+	}
+
+	return &snapshot, nil
+}
+
+// finishRun closes a run opened by a start ping and records its duration
+func (m *Monitor) finishRun(state *State, now time.Time) {
+	if state.Running && !state.LastStart.IsZero() {
+		state.LastDuration = now.Sub(state.LastStart).Seconds()
+	}
+	state.Running = false
+}
+
+// GetStates returns a copy of every heartbeat state sorted by name
+func (m *Monitor) GetStates() []State {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.clock.Now()
+	states := make([]State, 0, len(m.states))
+	for name, state := range m.states {
+		// Evaluate a copy so status transitions are still seen (and alerted) by the monitor loop
+		snapshot := *state
+		m.evaluate(&snapshot, m.jobs[name], now)
+		states = append(states, snapshot)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states
+}
+
+// GetConfig returns the monitor's configuration
+// Returns interface{} to match the alerts.ConfigProvider interface
+func (m *Monitor) GetConfig() interface{} {
+	return m.config
+}
+
+// GetConfigPtr returns the monitor's configuration as a concrete type pointer
+func (m *Monitor) GetConfigPtr() *config.Config {
+	return m.config
+}
+
+// UpdateLastAlertTime updates the last alert time. Pings from concurrent API
+// requests may raise alerts at the same time, so it takes the monitor's mutex.
+func (m *Monitor) UpdateLastAlertTime() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = m.clock.Now()
+}
+
+// GetLastAlertTime returns the last alert time
+func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastAlertTime
+}
+
+// GetNotificationManagers returns the notification managers
+func (m *Monitor) GetNotificationManagers() alerts.NotificationManager {
+	return m.emailManager
+}
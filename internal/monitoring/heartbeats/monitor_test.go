@@ -0,0 +1,154 @@
+package heartbeats
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	testsupport.Main(m)
+}
+
+// newMonitor creates a heartbeat monitor for jobs wired to a fake clock and a recorder
+func newMonitor(jobs ...config.HeartbeatConfig) (*Monitor, *testsupport.Env) {
+	cfg := &config.Config{}
+	cfg.Notifications.Email.Enabled = true
+	cfg.Monitoring.Heartbeats.Enabled = true
+	cfg.Monitoring.Heartbeats.Jobs = jobs
+	m := NewMonitor(cfg)
+	return m, testsupport.Wire(m)
+}
+
+func TestEvaluate(t *testing.T) {
+	// Expected hourly with ten minutes of grace; a run may take five minutes
+	job := config.HeartbeatConfig{Name: "backup", Interval: 3600, Grace: 600, MaxDuration: 300}
+	start := testsupport.Start
+
+	tests := []struct {
+		name   string
+		state  State
+		at     time.Duration // Time since monitoring started
+		status string
+		reason string
+	}{
+		{"waiting for first ping", State{}, 10 * time.Minute, "unknown", "waiting for first ping"},
+		{"late before first ping", State{}, 65 * time.Minute, "warning", "within grace period"},
+		{"missed first ping", State{}, 71 * time.Minute, "critical", "no successful ping received"},
+		{"on schedule", State{LastSuccess: start}, 30 * time.Minute, "normal", ""},
+		{"late", State{LastSuccess: start}, 61 * time.Minute, "warning", "within grace period"},
+		{"end of grace", State{LastSuccess: start}, 70 * time.Minute, "warning", "within grace period"},
+		{"past grace", State{LastSuccess: start}, 70*time.Minute + time.Second, "critical", "no successful ping since"},
+		{"running", State{LastSuccess: start, Running: true, LastStart: start.Add(30 * time.Minute)}, 40 * time.Minute, "normal", ""},
+		{"running past max duration", State{LastSuccess: start, Running: true, LastStart: start}, 15*time.Minute + time.Second, "critical", "has not finished"},
+		{"last run failed", State{LastSuccess: start, lastOutcomeFail: true}, time.Minute, "critical", "last run reported failure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newMonitor(job)
+			state := tt.state
+			m.evaluate(&state, job, start.Add(tt.at))
+
+			if state.Status != tt.status {
+				t.Errorf("status = %s, want %s", state.Status, tt.status)
+			}
+			if tt.reason == "" && state.Reason != "" || !strings.Contains(state.Reason, tt.reason) {
+				t.Errorf("reason = %q, want %q", state.Reason, tt.reason)
+			}
+			reference := state.LastSuccess
+			if reference.IsZero() {
+				reference = start
+			}
+			if want := reference.Add(time.Hour); !state.NextExpected.Equal(want) {
+				t.Errorf("next expected = %s, want %s", state.NextExpected, want)
+			}
+		})
+	}
+}
+
+func TestRecordPing(t *testing.T) {
+	m, env := newMonitor(config.HeartbeatConfig{Name: "backup", Interval: 3600, Grace: 600, Token: "secret"})
+
+	if _, err := m.RecordPing("restore", PingSuccess, "", ""); !errors.Is(err, ErrUnknownHeartbeat) {
+		t.Errorf("unknown heartbeat: err = %v, want ErrUnknownHeartbeat", err)
+	}
+	if _, err := m.RecordPing("backup", PingSuccess, "wrong", ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("wrong token: err = %v, want ErrInvalidToken", err)
+	}
+
+	ping := func(kind, message string) *State {
+		t.Helper()
+		state, err := m.RecordPing("backup", kind, "secret", message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state
+	}
+
+	state := ping(PingStart, "")
+	if state.Status != "unknown" || !state.Running || !state.LastStart.Equal(testsupport.Start) {
+		t.Errorf("after start = %+v, want a running job waiting for its first success", state)
+	}
+
+	env.Clock.Advance(2 * time.Minute)
+	state = ping(PingSuccess, "")
+	if state.Status != "normal" || state.Running || state.LastDuration != 120 || state.TotalPings != 2 {
+		t.Errorf("after success = %+v, want a normal job that ran for 120 seconds", state)
+	}
+	if want := testsupport.Start.Add(2 * time.Minute).Add(time.Hour); !state.NextExpected.Equal(want) {
+		t.Errorf("next expected = %s, want %s", state.NextExpected, want)
+	}
+
+	env.Clock.Advance(time.Hour)
+	state = ping(PingFail, "disk full")
+	if state.Status != "critical" || state.TotalFailures != 1 || state.LastMessage != "disk full" || state.LastDuration != 120 {
+		t.Errorf("after fail = %+v, want a critical job with the failure recorded", state)
+	}
+
+	env.Clock.Advance(time.Minute)
+	if state = ping(PingSuccess, ""); state.Status != "normal" {
+		t.Errorf("after recovery = %+v, want normal", state)
+	}
+
+	// Without further pings the monitor loop sees the job late, then missed
+	env.Clock.Advance(61 * time.Minute)
+	m.evaluateAll()
+	env.Clock.Advance(10 * time.Minute)
+	m.evaluateAll()
+	if states := m.GetStates(); states[0].Status != "critical" {
+		t.Errorf("after a missed ping = %+v, want critical", states[0])
+	}
+
+	want := []string{"Heartbeat Down: backup", "Heartbeat Recovered: backup", "Heartbeat Down: backup"}
+	if got := env.Recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+}
+
+func TestRecordPingConcurrently(t *testing.T) {
+	m, _ := newMonitor(config.HeartbeatConfig{Name: "a"}, config.HeartbeatConfig{Name: "b"})
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b"} {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			kind := PingSuccess
+			if i%2 == 0 {
+				kind = PingFail
+			}
+			go func(name, kind string) {
+				defer wg.Done()
+				if _, err := m.RecordPing(name, kind, "", ""); err != nil {
+					t.Error(err)
+				}
+				m.GetLastAlertTime()
+			}(name, kind)
+		}
+	}
+	wg.Wait()
+}
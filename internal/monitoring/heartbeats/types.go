@@ -0,0 +1,39 @@
+package heartbeats
+
+import (
+	"errors"
+	"time"
+)
+
+// Ping kinds accepted by the heartbeat endpoints
+const (
+	PingSuccess = "success"
+	PingStart   = "start"
+	PingFail    = "fail"
+)
+
+// Errors returned when recording a ping
+var (
+	ErrUnknownHeartbeat = errors.New("unknown heartbeat")
+	ErrInvalidToken     = errors.New("invalid heartbeat token")
+)
+
+// State represents the current state of a single heartbeat
+type State struct {
+	Name            string    `json:"name"`
+	Status          string    `json:"status"`           // normal, warning, critical or unknown
+	Reason          string    `json:"reason,omitempty"` // Why the heartbeat is in its current status
+	Interval        int       `json:"interval"`
+	Grace           int       `json:"grace"`
+	Running         bool      `json:"running"`
+	LastPing        time.Time `json:"last_ping,omitempty"`
+	LastStart       time.Time `json:"last_start,omitempty"`
+	LastSuccess     time.Time `json:"last_success,omitempty"`
+	LastFailure     time.Time `json:"last_failure,omitempty"`
+	LastDuration    float64   `json:"last_duration_seconds"` // Duration of the last completed run, when a start ping was sent
+	LastMessage     string    `json:"last_message,omitempty"`
+	NextExpected    time.Time `json:"next_expected,omitempty"`
+	TotalPings      int       `json:"total_pings"`
+	TotalFailures   int       `json:"total_failures"`
+	lastOutcomeFail bool
+}
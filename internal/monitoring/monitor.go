@@ -0,0 +1,103 @@
+// Package monitoring defines what every monitor implements, so the registry can
+// run, serve and report on them without knowing their types.
+package monitoring
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Monitor is a component that checks something periodically
+type Monitor interface {
+	// Name identifies the monitor in routes, topics and logs
+	Name() string
+	// Start begins monitoring in the background. Monitoring ends with Stop or ctx.
+	Start(ctx context.Context) error
+	// Stop ends monitoring
+	Stop()
+	// Snapshot returns the latest results, encoded as JSON by the API
+	Snapshot() interface{}
+	// Health reports whether the monitor is running and checking on schedule
+	Health() Health
+}
+
+// Streamer is a monitor that serves its results on the WebSocket topic named after it
+type Streamer interface {
+	WebSocketHandler(c *gin.Context)
+}
+
+// TopicProvider is a monitor that serves several WebSocket topics, or topics not
+// named after it. It takes precedence over Streamer.
+type TopicProvider interface {
+	WebSocketTopics() []Topic
+}
+
+// Topic is a WebSocket endpoint served below /ws/
+type Topic struct {
+	Path    string
+	Handler gin.HandlerFunc
+}
+
+// Health states
+const (
+	HealthOK       = "ok"       // Running and checking on schedule
+	HealthStarting = "starting" // Running, the first check has not finished yet
+	HealthStale    = "stale"    // Running, but no check finished for several intervals
+	HealthStopped  = "stopped"  // Not running
+	HealthFailed   = "failed"   // Could not be started
+)
+
+// staleIntervals is how many check intervals may pass without a check before a
+// running monitor is reported as stale
+const staleIntervals = 3
+
+// Health is the self-reported state of a monitor
+type Health struct {
+	Status    string     `json:"status"`
+	Running   bool       `json:"running"`
+	LastCheck *time.Time `json:"last_check,omitempty"`
+	LastAlert *time.Time `json:"last_alert,omitempty"`
+	Interval  float64    `json:"interval_seconds,omitempty"` // Expected time between checks
+	Message   string     `json:"message,omitempty"`
+}
+
+// OK reports whether the monitor is running and up to date
+func (h Health) OK() bool {
+	return h.Status == HealthOK || h.Status == HealthStarting
+}
+
+// NewHealth derives the health of a monitor from whether it runs, when its last
+// check finished and how often it checks. A zero interval never goes stale.
+func NewHealth(running bool, lastCheck time.Time, interval time.Duration) Health {
+	health := Health{Running: running, Interval: interval.Seconds()}
+	if !lastCheck.IsZero() {
+		health.LastCheck = &lastCheck
+	}
+
+	switch {
+	case !running:
+		health.Status = HealthStopped
+	case lastCheck.IsZero():
+		health.Status = HealthStarting
+	case interval > 0 && time.Since(lastCheck) > staleIntervals*interval:
+		health.Status = HealthStale
+		health.Message = "no check finished in the last " + (staleIntervals * interval).String()
+	default:
+		health.Status = HealthOK
+	}
+	return health
+}
+
+// StopWhenDone calls stop when ctx ends, unless done is closed first. Monitors
+// with their own stop channel use it to end with the context given to Start.
+func StopWhenDone(ctx context.Context, done <-chan struct{}, stop func()) {
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop()
+		case <-done:
+		}
+	}()
+}
@@ -0,0 +1,245 @@
+// Package registry holds the monitors of the service behind one interface. The
+// registry starts and stops them, hands them the alert notification manager and
+// lists the REST routes and WebSocket topics they serve, so adding a monitor means
+// implementing Monitor and registering it once.
+package registry
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/pkg/logger"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Notifier is a monitor that sends alerts through a notification manager
+type Notifier interface {
+	SetNotificationManager(manager alerts.NotificationManager)
+}
+
+// alertTimer is a monitor that remembers when it last sent an alert
+type alertTimer interface {
+	GetLastAlertTime() time.Time
+}
+
+// RouteFunc registers the REST routes specific to one monitor
+type RouteFunc func(engine *gin.Engine)
+
+// entry is a registered monitor with its lifecycle state
+type entry struct {
+	monitor  monitoring.Monitor
+	routes   []RouteFunc
+	started  bool
+	starting bool // Start is running outside the registry lock
+	startErr error
+}
+
+// Registry keeps the registered monitors in registration order
+type Registry struct {
+	mu       sync.RWMutex
+	entries  []*entry
+	byName   map[string]*entry
+	notifier alerts.NotificationManager
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{byName: make(map[string]*entry)}
+}
+
+// Register adds a monitor, together with the functions that register its own REST
+// routes. Names must be unique.
+func (r *Registry) Register(monitor monitoring.Monitor, routes ...RouteFunc) error {
+	if monitor == nil {
+		return fmt.Errorf("cannot register a nil monitor")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := monitor.Name()
+	if name == "" {
+		return fmt.Errorf("cannot register a monitor without a name")
+	}
+	if _, exists := r.byName[name]; exists {
+		return fmt.Errorf("monitor %q is already registered", name)
+	}
+
+	if notifier, ok := monitor.(Notifier); ok && r.notifier != nil {
+		notifier.SetNotificationManager(r.notifier)
+	}
+
+	e := &entry{monitor: monitor, routes: routes}
+	r.entries = append(r.entries, e)
+	r.byName[name] = e
+	return nil
+}
+
+// SetNotificationManager sets where the alerts of every monitor go, including
+// monitors registered later
+func (r *Registry) SetNotificationManager(manager alerts.NotificationManager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifier = manager
+	for _, e := range r.entries {
+		if notifier, ok := e.monitor.(Notifier); ok {
+			notifier.SetNotificationManager(manager)
+		}
+	}
+}
+
+// Get returns a registered monitor by name
+func (r *Registry) Get(name string) (monitoring.Monitor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.byName[name]
+	if !ok {
+		return nil, false
+	}
+	return e.monitor, true
+}
+
+// Monitors returns the registered monitors in registration order
+func (r *Registry) Monitors() []monitoring.Monitor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	monitors := make([]monitoring.Monitor, 0, len(r.entries))
+	for _, e := range r.entries {
+		monitors = append(monitors, e.monitor)
+	}
+	return monitors
+}
+
+// Names returns the names of the registered monitors, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.entries))
+	for _, e := range r.entries {
+		names = append(names, e.monitor.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartAll starts every monitor that is not running yet. A monitor that fails to
+// start stays registered and reports the error in its health. Monitors are started
+// without holding the registry lock, so a Start that queries the registry or
+// blocks does not stall the API.
+func (r *Registry) StartAll(ctx context.Context) {
+	r.mu.Lock()
+	var pending []*entry
+	for _, e := range r.entries {
+		if !e.started && !e.starting {
+			e.starting = true
+			pending = append(pending, e)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, e := range pending {
+		name := e.monitor.Name()
+		err := e.monitor.Start(ctx)
+
+		r.mu.Lock()
+		e.starting = false
+		e.started = err == nil
+		e.startErr = err
+		r.mu.Unlock()
+
+		if err != nil {
+			logger.Warn("Failed to start monitor",
+				logger.String("monitor", name),
+				logger.String("error", err.Error()))
+			continue
+		}
+		logger.Debug("Started monitor", logger.String("monitor", name))
+	}
+}
+
+// StopAll stops the running monitors in reverse registration order, without
+// holding the registry lock
+func (r *Registry) StopAll() {
+	r.mu.Lock()
+	var running []*entry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		e := r.entries[i]
+		if e.started {
+			e.started = false
+			running = append(running, e)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, e := range running {
+		e.monitor.Stop()
+		logger.Info("Stopped monitor", logger.String("monitor", e.monitor.Name()))
+	}
+}
+
+// Health returns the health of a monitor, with start failures and the last alert
+// filled in by the registry
+func (r *Registry) Health(name string) (monitoring.Health, bool) {
+	r.mu.RLock()
+	e, ok := r.byName[name]
+	var startErr error
+	if ok {
+		startErr = e.startErr
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return monitoring.Health{}, false
+	}
+
+	health := e.monitor.Health()
+	if startErr != nil {
+		health.Status = monitoring.HealthFailed
+		health.Running = false
+		health.Message = startErr.Error()
+	}
+	if timer, ok := e.monitor.(alertTimer); ok {
+		if lastAlert := timer.GetLastAlertTime(); !lastAlert.IsZero() {
+			health.LastAlert = &lastAlert
+		}
+	}
+	return health, true
+}
+
+// RegisterRoutes calls the route functions of every monitor
+func (r *Registry) RegisterRoutes(engine *gin.Engine) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.entries {
+		for _, routes := range e.routes {
+			routes(engine)
+		}
+	}
+}
+
+// WebSocketTopics returns the WebSocket topics of every monitor
+func (r *Registry) WebSocketTopics() []monitoring.Topic {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var topics []monitoring.Topic
+	for _, e := range r.entries {
+		switch monitor := e.monitor.(type) {
+		case monitoring.TopicProvider:
+			topics = append(topics, monitor.WebSocketTopics()...)
+		case monitoring.Streamer:
+			topics = append(topics, monitoring.Topic{Path: e.monitor.Name(), Handler: monitor.WebSocketHandler})
+		}
+	}
+	return topics
+}
@@ -0,0 +1,258 @@
+package registry
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	testsupport.Main(m)
+}
+
+// events records the calls made to fake monitors in order
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.list, ",")
+}
+
+// fakeMonitor is a monitor that records its lifecycle calls
+type fakeMonitor struct {
+	name     string
+	events   *events
+	startErr error
+	onStart  func()
+	running  bool
+}
+
+func (f *fakeMonitor) Name() string { return f.name }
+
+func (f *fakeMonitor) Start(context.Context) error {
+	f.events.add("start " + f.name)
+	if f.onStart != nil {
+		f.onStart()
+	}
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.running = true
+	return nil
+}
+
+func (f *fakeMonitor) Stop() {
+	f.events.add("stop " + f.name)
+	f.running = false
+}
+
+func (f *fakeMonitor) Snapshot() interface{} { return nil }
+
+func (f *fakeMonitor) Health() monitoring.Health {
+	return monitoring.NewHealth(f.running, time.Time{}, time.Minute)
+}
+
+// alertingMonitor is a fake that sends alerts and remembers the last one
+type alertingMonitor struct {
+	fakeMonitor
+	manager   alerts.NotificationManager
+	lastAlert time.Time
+}
+
+func (a *alertingMonitor) SetNotificationManager(manager alerts.NotificationManager) {
+	a.manager = manager
+}
+
+func (a *alertingMonitor) GetLastAlertTime() time.Time { return a.lastAlert }
+
+func TestRegister(t *testing.T) {
+	r := New()
+	log := &events{}
+
+	if err := r.Register(&fakeMonitor{name: "cpu", events: log}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&fakeMonitor{name: "cpu", events: log}); err == nil {
+		t.Error("want an error for a duplicate name")
+	}
+	if err := r.Register(&fakeMonitor{events: log}); err == nil {
+		t.Error("want an error for a monitor without a name")
+	}
+	if err := r.Register(nil); err == nil {
+		t.Error("want an error for a nil monitor")
+	}
+	if err := r.Register(&fakeMonitor{name: "backup", events: log}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(r.Names(), ","); got != "backup,cpu" {
+		t.Errorf("names = %s, want backup,cpu", got)
+	}
+	if monitors := r.Monitors(); len(monitors) != 2 || monitors[0].Name() != "cpu" {
+		t.Errorf("monitors = %v, want cpu then backup", monitors)
+	}
+	if _, ok := r.Get("memory"); ok {
+		t.Error("Get(memory) found an unregistered monitor")
+	}
+}
+
+func TestStartStopOrder(t *testing.T) {
+	r := New()
+	log := &events{}
+	failing := &fakeMonitor{name: "b", events: log, startErr: errors.New("disabled in configuration")}
+	for _, monitor := range []monitoring.Monitor{
+		&fakeMonitor{name: "a", events: log},
+		failing,
+		&fakeMonitor{name: "c", events: log},
+	} {
+		if err := r.Register(monitor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.StartAll(context.Background())
+	r.StartAll(context.Background()) // Only the failed monitor is tried again
+	r.StopAll()
+	r.StopAll()
+
+	if got := log.String(); got != "start a,start b,start c,start b,stop c,stop a" {
+		t.Errorf("calls = %s", got)
+	}
+
+	// A monitor that starts on a later try is stopped like the others
+	failing.startErr = nil
+	r.StartAll(context.Background())
+	r.StopAll()
+	if got := log.String(); !strings.HasSuffix(got, "start a,start b,start c,stop c,stop b,stop a") {
+		t.Errorf("calls = %s", got)
+	}
+}
+
+func TestStartAllWithoutLock(t *testing.T) {
+	r := New()
+	log := &events{}
+
+	// A monitor whose Start reads the registry would deadlock if StartAll held the lock
+	monitor := &fakeMonitor{name: "cpu", events: log}
+	monitor.onStart = func() {
+		r.Health("cpu")
+		r.Names()
+	}
+	if err := r.Register(monitor); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.StartAll(context.Background())
+		r.StopAll()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("StartAll blocked while the monitor read the registry")
+	}
+}
+
+func TestHealth(t *testing.T) {
+	r := New()
+	log := &events{}
+	lastAlert := time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+	monitors := []monitoring.Monitor{
+		&fakeMonitor{name: "quiet", events: log},
+		&alertingMonitor{fakeMonitor: fakeMonitor{name: "alerting", events: log}, lastAlert: lastAlert},
+		&alertingMonitor{fakeMonitor: fakeMonitor{name: "never alerted", events: log}},
+		&fakeMonitor{name: "broken", events: log, startErr: errors.New("disabled in configuration")},
+	}
+	for _, monitor := range monitors {
+		if err := r.Register(monitor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if health, _ := r.Health("quiet"); health.Status != monitoring.HealthStopped {
+		t.Errorf("before start = %+v, want stopped", health)
+	}
+
+	r.StartAll(context.Background())
+	defer r.StopAll()
+
+	tests := []struct {
+		name      string
+		status    string
+		running   bool
+		lastAlert bool
+		message   string
+	}{
+		{"quiet", monitoring.HealthStarting, true, false, ""},
+		{"alerting", monitoring.HealthStarting, true, true, ""},
+		{"never alerted", monitoring.HealthStarting, true, false, ""},
+		{"broken", monitoring.HealthFailed, false, false, "disabled in configuration"},
+	}
+	for _, tt := range tests {
+		health, ok := r.Health(tt.name)
+		if !ok {
+			t.Errorf("Health(%s) found nothing", tt.name)
+			continue
+		}
+		if health.Status != tt.status || health.Running != tt.running || health.Message != tt.message {
+			t.Errorf("Health(%s) = %+v, want status %s, running %v, message %q", tt.name, health, tt.status, tt.running, tt.message)
+		}
+		if (health.LastAlert != nil) != tt.lastAlert || (tt.lastAlert && !health.LastAlert.Equal(lastAlert)) {
+			t.Errorf("Health(%s) last alert = %v, want set %v", tt.name, health.LastAlert, tt.lastAlert)
+		}
+	}
+
+	if _, ok := r.Health("missing"); ok {
+		t.Error("Health(missing) found an unregistered monitor")
+	}
+}
+
+func TestNotifierPropagation(t *testing.T) {
+	r := New()
+	log := &events{}
+	early := &alertingMonitor{fakeMonitor: fakeMonitor{name: "early", events: log}}
+	late := &alertingMonitor{fakeMonitor: fakeMonitor{name: "late", events: log}}
+
+	if err := r.Register(early); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&fakeMonitor{name: "silent", events: log}); err != nil {
+		t.Fatal(err)
+	}
+	if early.manager != nil {
+		t.Error("a manager was set before the registry had one")
+	}
+
+	first := alerts.NewRecorder()
+	r.SetNotificationManager(first)
+	if err := r.Register(late); err != nil {
+		t.Fatal(err)
+	}
+	if early.manager != first || late.manager != first {
+		t.Errorf("managers = %v, %v, want the registry's for monitors registered before and after", early.manager, late.manager)
+	}
+
+	second := alerts.NewRecorder()
+	r.SetNotificationManager(second)
+	if early.manager != second || late.manager != second {
+		t.Error("replacing the manager did not reach every monitor")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/load"
@@ -30,8 +31,21 @@ type AlertHandler struct {
 	warningsSentToday     int           // Counter for warnings sent today
 	lastDayReset          time.Time     // When we last reset the daily counter
 	lastInfo              *CPUInfo      // Last CPU info for comparison
+
+	// Throttling of the rule alerts
+	ruleAlerts            map[string]*ruleAlertState // Last notification per rule
+	ruleWarningsSentToday int                        // Rule warnings sent today, limited by maxWarningsPerDay
+}
+
+// ruleAlertState is the last notification sent for one CPU rule
+type ruleAlertState struct {
+	level    string    // Level last notified, normal before any alert
+	lastSent time.Time // When it was sent
 }
 
+// ruleLevels orders the rule levels by severity
+var ruleLevels = map[string]int{"normal": 0, "warning": 1, "critical": 2}
+
 // NewAlertHandler creates a new alert handler
 func NewAlertHandler(monitor *Monitor) *AlertHandler {
 	// Get config to read throttling settings
@@ -65,15 +79,16 @@ func NewAlertHandler(monitor *Monitor) *AlertHandler {
 		warningEscalation:    warningEscalation, // Only notify after consecutive warnings
 		pendingWarnings:      make([]CPUInfo, 0),
 		aggregationInterval:  aggregationInterval, // Use from config
-		lastAggregationTime:  time.Now(),
+		lastAggregationTime:  monitor.clock.Now(),
 		// Anti-spam settings
 		warningThrottleWindow: warningThrottleWindow,
 		criticalThrottleCount: criticalThrottleCount, // Use from config
 		currentCriticalCount:  0,
 		maxWarningsPerDay:     maxWarningsPerDay, // Use from config
 		warningsSentToday:     0,
-		lastDayReset:          time.Now(),
+		lastDayReset:          monitor.clock.Now(),
 		lastInfo:              nil,
+		ruleAlerts:            make(map[string]*ruleAlertState),
 	}
 }
 
@@ -82,11 +97,7 @@ func (a *AlertHandler) HandleWarningAlert(info *CPUInfo, statusChanged bool) {
 	var counter *int = &a.handler.SuppressedWarningCount
 
 	// Check if we need to reset the daily counter
-	now := time.Now()
-	if now.YearDay() != a.lastDayReset.YearDay() || now.Year() != a.lastDayReset.Year() {
-		a.warningsSentToday = 0
-		a.lastDayReset = now
-	}
+	a.resetDailyCounters(a.monitor.clock.Now())
 
 	// Get config with proper type assertion to determine cooldown
 	configInterface := a.monitor.GetConfig()
@@ -103,8 +114,8 @@ func (a *AlertHandler) HandleWarningAlert(info *CPUInfo, statusChanged bool) {
 		logger.Info("CPU entered warning state",
 			logger.Float64("usage_percent", info.Usage),
 			logger.String("status", info.CPUStatus),
-			logger.String("timestamp", time.Now().Format(time.RFC3339)),
-			logger.Bool("notification_will_be_sent", time.Since(a.lastWarningAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
+			logger.String("timestamp", a.monitor.clock.Now().Format(time.RFC3339)),
+			logger.Bool("notification_will_be_sent", a.monitor.clock.Since(a.lastWarningAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
 	}
 
 	// Reset critical counter when we get a warning
@@ -115,15 +126,17 @@ func (a *AlertHandler) HandleWarningAlert(info *CPUInfo, statusChanged bool) {
 		// This is an improvement, just log it but don't send notification to reduce spam
 		logger.Info("CPU improved from critical to warning state",
 			logger.Float64("usage_percent", info.Usage))
+		a.handler.ReportSuppressed("CPU improved from critical to warning")
 		a.lastInfo = info
 		return
 	}
 
 	// Throttle based on our custom window - only one warning alert per warningThrottleWindow
-	if !a.lastWarningAlertTime.IsZero() && time.Since(a.lastWarningAlertTime) < a.warningThrottleWindow {
+	if !a.lastWarningAlertTime.IsZero() && a.monitor.clock.Since(a.lastWarningAlertTime) < a.warningThrottleWindow {
 		logger.Debug("Suppressing CPU warning notification due to throttle window",
-			logger.Int("minutes_since_last", int(time.Since(a.lastWarningAlertTime).Minutes())),
+			logger.Int("minutes_since_last", int(a.monitor.clock.Since(a.lastWarningAlertTime).Minutes())),
 			logger.Int("throttle_window_minutes", int(a.warningThrottleWindow.Minutes())))
+		a.handler.ReportSuppressed("CPU warning within the throttle window")
 		*counter++
 		return
 	}
@@ -133,6 +146,7 @@ func (a *AlertHandler) HandleWarningAlert(info *CPUInfo, statusChanged bool) {
 		logger.Info("Daily CPU warning notification limit reached",
 			logger.Int("max_warnings_per_day", a.maxWarningsPerDay),
 			logger.Int("warnings_sent_today", a.warningsSentToday))
+		a.handler.ReportSuppressed("Daily CPU warning limit reached")
 		return
 	}
 
@@ -155,7 +169,7 @@ func (a *AlertHandler) HandleWarningAlert(info *CPUInfo, statusChanged bool) {
 	a.pendingWarnings = append(a.pendingWarnings, *info)
 
 	// Only send aggregated alert if enough time has passed
-	if time.Since(a.lastAggregationTime) >= a.aggregationInterval && len(a.pendingWarnings) > 0 {
+	if a.monitor.clock.Since(a.lastAggregationTime) >= a.aggregationInterval && len(a.pendingWarnings) > 0 {
 		// Create an aggregated message
 		a.sendAggregatedWarningAlert()
 		return
@@ -166,6 +180,7 @@ func (a *AlertHandler) HandleWarningAlert(info *CPUInfo, statusChanged bool) {
 		logger.Debug("CPU warning suppressed due to escalation policy",
 			logger.Int("warning_count", a.warningCount),
 			logger.Int("escalation_threshold", a.warningEscalation))
+		a.handler.ReportSuppressed("CPU warning below the escalation threshold")
 		return
 	}
 
@@ -190,7 +205,7 @@ func (a *AlertHandler) sendWarningNotification(info *CPUInfo, statusChanged bool
 	}
 
 	// Record the time we're sending this warning
-	a.lastWarningAlertTime = time.Now()
+	a.lastWarningAlertTime = a.monitor.clock.Now()
 
 	// Get server information using the common utility function
 	serverInfo := alerts.GetServerInfoForAlert()
@@ -212,7 +227,7 @@ func (a *AlertHandler) sendWarningNotification(info *CPUInfo, statusChanged bool
 		a.warningsSentToday, a.maxWarningsPerDay)
 
 	// Add system load information if available
-	if loadAvg, err := getSystemLoadAvg(); err == nil && len(loadAvg) >= 3 {
+	if loadAvg, err := a.monitor.collector.LoadAverage(); err == nil && len(loadAvg) >= 3 {
 		additionalContent += fmt.Sprintf(`
 		<div style="background-color: #f5f5f5; border-left: 5px solid #ddd; padding: 10px; margin: 10px 0;">
 			<p><b>SYSTEM LOAD AVERAGE:</b> 1-min: %.2f, 5-min: %.2f, 15-min: %.2f</p>
@@ -269,10 +284,10 @@ func (a *AlertHandler) HandleCriticalAlert(info *CPUInfo, statusChanged bool) {
 		logger.Info("CPU entered critical state",
 			logger.Float64("usage_percent", info.Usage),
 			logger.String("status", info.CPUStatus),
-			logger.String("timestamp", time.Now().Format(time.RFC3339)),
+			logger.String("timestamp", a.monitor.clock.Now().Format(time.RFC3339)),
 			logger.Int("consecutive_critical_events", a.currentCriticalCount),
 			logger.Int("threshold_for_alert", a.criticalThrottleCount),
-			logger.Bool("notification_will_be_sent", time.Since(a.lastCriticalAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
+			logger.Bool("notification_will_be_sent", a.monitor.clock.Since(a.lastCriticalAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
 	}
 
 	// Store current info for comparison in next cycle
@@ -284,16 +299,18 @@ func (a *AlertHandler) HandleCriticalAlert(info *CPUInfo, statusChanged bool) {
 		logger.Info("Suppressing CPU critical alert until threshold reached",
 			logger.Int("current_count", a.currentCriticalCount),
 			logger.Int("threshold", a.criticalThrottleCount))
+		a.handler.ReportSuppressed("CPU critical below the consecutive event threshold")
 		return
 	}
 
 	// Apply throttling even for status changes
 	if !a.lastCriticalAlertTime.IsZero() {
-		sinceLastCritical := time.Since(a.lastCriticalAlertTime)
+		sinceLastCritical := a.monitor.clock.Since(a.lastCriticalAlertTime)
 		if sinceLastCritical < time.Duration(cooldownPeriod)*time.Second {
 			logger.Debug("Suppressing CPU critical notification due to cooldown",
 				logger.Int("seconds_since_last", int(sinceLastCritical.Seconds())),
 				logger.Int("cooldown_period", cooldownPeriod))
+			a.handler.ReportSuppressed("CPU critical within the cooldown period")
 			*counter++
 			return
 		}
@@ -323,7 +340,7 @@ func (a *AlertHandler) HandleCriticalAlert(info *CPUInfo, statusChanged bool) {
 	</div>`
 
 	// Add load average information
-	if loadAvg, err := getSystemLoadAvg(); err == nil && len(loadAvg) >= 3 {
+	if loadAvg, err := a.monitor.collector.LoadAverage(); err == nil && len(loadAvg) >= 3 {
 		criticalLoad := false
 		processorCount := info.ProcessorCount
 		if processorCount == 0 {
@@ -369,7 +386,7 @@ func (a *AlertHandler) HandleCriticalAlert(info *CPUInfo, statusChanged bool) {
 	// Send notification
 	a.handler.SendNotifications("CRITICAL CPU Alert", message, "critical")
 	a.monitor.UpdateLastAlertTime()
-	a.lastCriticalAlertTime = time.Now()
+	a.lastCriticalAlertTime = a.monitor.clock.Now()
 
 	// Reset the counter after alert is sent
 	a.currentCriticalCount = 0
@@ -384,12 +401,13 @@ func (a *AlertHandler) HandleNormalAlert(info *CPUInfo, statusChanged bool) {
 	a.warningCount = 0
 	a.currentCriticalCount = 0
 
-	// Store current info for comparison in next cycle
+	// Keep the previous info before storing the current one for the next cycle
+	previous := a.lastInfo
 	a.lastInfo = info
 
 	// Only send notification if the status has changed from critical to normal
 	// Don't send notifications for warning->normal transitions to reduce spam
-	if !statusChanged || (a.lastInfo != nil && a.lastInfo.CPUStatus != "critical") {
+	if !statusChanged || previous == nil || previous.CPUStatus != "critical" {
 		return
 	}
 
@@ -407,16 +425,17 @@ func (a *AlertHandler) HandleNormalAlert(info *CPUInfo, statusChanged bool) {
 	logger.Info("CPU returned to normal state",
 		logger.Float64("usage_percent", info.Usage),
 		logger.String("status", info.CPUStatus),
-		logger.String("timestamp", time.Now().Format(time.RFC3339)),
-		logger.Bool("notification_will_be_sent", time.Since(a.lastNormalAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
+		logger.String("timestamp", a.monitor.clock.Now().Format(time.RFC3339)),
+		logger.Bool("notification_will_be_sent", a.monitor.clock.Since(a.lastNormalAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
 
 	// Apply throttling even for normal status
 	if !a.lastNormalAlertTime.IsZero() {
-		sinceLastNormal := time.Since(a.lastNormalAlertTime)
+		sinceLastNormal := a.monitor.clock.Since(a.lastNormalAlertTime)
 		if sinceLastNormal < time.Duration(cooldownPeriod)*time.Second {
 			logger.Debug("Suppressing CPU normal notification due to cooldown",
 				logger.Int("seconds_since_last", int(sinceLastNormal.Seconds())),
 				logger.Int("cooldown_period", cooldownPeriod))
+			a.handler.ReportSuppressed("CPU normal within the cooldown period")
 			return
 		}
 	}
@@ -456,12 +475,162 @@ func (a *AlertHandler) HandleNormalAlert(info *CPUInfo, statusChanged bool) {
 	// Send notification
 	a.handler.SendNotifications("CPU Status Normalized", message, "info")
 	a.monitor.UpdateLastAlertTime()
-	a.lastNormalAlertTime = time.Now()
+	a.lastNormalAlertTime = a.monitor.clock.Now()
 
 	logger.Info("Sent CPU normalized notification",
 		logger.Float64("usage_percent", info.Usage))
 }
 
+// ruleLabels names the CPU rules in notifications
+var ruleLabels = map[string]string{
+	RuleSteal:       "Steal Time",
+	RuleIOWait:      "I/O Wait",
+	RuleCoreHotspot: "Core Hotspot",
+	RuleLoad:        "Normalized Load",
+}
+
+// ruleRecommendations explains what to look at when a CPU rule fires
+var ruleRecommendations = map[string]string{
+	RuleSteal:       "The hypervisor is giving this VM's CPU time to other guests. Adding work here will not help; ask the provider to move or resize the VM.",
+	RuleIOWait:      "CPUs are idle waiting for disk or network I/O. Check 'iostat -x' for saturated devices and the MariaDB slow query log for heavy scans.",
+	RuleCoreHotspot: "A single thread is saturating one core while the average looks low. Use 'top -H' or 'pidstat -t' to find it, e.g. a mysqld purge or replication thread.",
+	RuleLoad:        "More tasks are runnable or blocked than there are logical processors. Check the run queue with 'vmstat 1' and for processes in D state.",
+}
+
+// resetDailyCounters starts the daily warning counters over on a new day
+func (a *AlertHandler) resetDailyCounters(now time.Time) {
+	if now.YearDay() != a.lastDayReset.YearDay() || now.Year() != a.lastDayReset.Year() {
+		a.warningsSentToday = 0
+		a.ruleWarningsSentToday = 0
+		a.lastDayReset = now
+	}
+}
+
+// HandleRuleAlert notifies about a CPU rule that moved to another level. Like the
+// usage alerts, each rule sends at most one notification per throttle window
+// unless it escalates, warnings count against the daily limit, and a recovery is
+// only sent for a level that was notified.
+func (a *AlertHandler) HandleRuleAlert(info *CPUInfo, status RuleStatus) {
+	label := ruleLabels[status.Rule]
+
+	now := a.monitor.clock.Now()
+	a.resetDailyCounters(now)
+
+	state := a.ruleAlerts[status.Rule]
+	if state == nil {
+		state = &ruleAlertState{level: "normal"}
+		a.ruleAlerts[status.Rule] = state
+	}
+	withinWindow := !state.lastSent.IsZero() && now.Sub(state.lastSent) < a.warningThrottleWindow
+
+	suppress := func(reason string) {
+		logger.Debug("Suppressing CPU rule notification",
+			logger.String("rule", status.Rule),
+			logger.String("status", status.Status),
+			logger.String("reason", reason))
+		a.handler.ReportSuppressed(reason)
+	}
+
+	switch {
+	case status.Status == "normal" && state.level == "normal":
+		suppress(fmt.Sprintf("CPU %s recovered before a notification was sent", label))
+		return
+	case status.Status != "normal" && ruleLevels[status.Status] < ruleLevels[state.level]:
+		// An improvement, the recovery is notified once the rule is back to normal
+		suppress(fmt.Sprintf("CPU %s improved from %s to %s", label, state.level, status.Status))
+		return
+	case status.Status != "normal" && (state.level == status.Status || state.level == "normal") && withinWindow:
+		// Back at the notified level, or over a threshold again shortly after recovering
+		suppress(fmt.Sprintf("CPU %s %s within the throttle window", label, status.Status))
+		return
+	case status.Status == "warning" && a.ruleWarningsSentToday >= a.maxWarningsPerDay:
+		suppress("Daily CPU rule warning limit reached")
+		return
+	}
+
+	if status.Status == "warning" {
+		a.ruleWarningsSentToday++
+	}
+	state.level = status.Status
+	state.lastSent = now
+
+	alertType := alerts.AlertTypeNormal
+	subject := fmt.Sprintf("CPU %s Normalized", label)
+	title := fmt.Sprintf("CPU %s NORMALIZED", strings.ToUpper(label))
+	level := "info"
+	switch status.Status {
+	case "warning":
+		alertType = alerts.AlertTypeWarning
+		subject = fmt.Sprintf("CPU %s Warning", label)
+		title = fmt.Sprintf("CPU %s WARNING ALERT", strings.ToUpper(label))
+		level = "warning"
+	case "critical":
+		alertType = alerts.AlertTypeCritical
+		subject = fmt.Sprintf("CRITICAL CPU %s Alert", label)
+		title = fmt.Sprintf("CRITICAL CPU %s ALERT", strings.ToUpper(label))
+		level = "critical"
+	}
+
+	logger.Info("CPU rule changed level",
+		logger.String("rule", status.Rule),
+		logger.String("status", status.Status),
+		logger.Float64("value", status.Value),
+		logger.String("detail", status.Detail))
+
+	style := a.handler.GetAlertStyle(alertType)
+
+	format := "%.1f%%"
+	if status.Rule == RuleLoad {
+		format = "%.2f per logical processor"
+	}
+	threshold := func(value float64) string {
+		if value <= 0 {
+			return "disabled"
+		}
+		return fmt.Sprintf(format, value)
+	}
+
+	tableRows := []alerts.TableRow{
+		{Label: "Triggered By", Value: label},
+		{Label: "Reason", Value: status.Detail},
+		{Label: "Value", Value: fmt.Sprintf(format, status.Value)},
+		{Label: "Warning Threshold", Value: threshold(status.Warning)},
+		{Label: "Critical Threshold", Value: threshold(status.Critical)},
+	}
+	if !status.Since.IsZero() {
+		tableRows = append(tableRows, alerts.TableRow{
+			Label: "Exceeded For",
+			Value: a.monitor.clock.Since(status.Since).Round(time.Second).String(),
+		})
+	}
+	tableRows = append(tableRows,
+		alerts.TableRow{Label: "Overall Usage", Value: fmt.Sprintf("%.2f%%", info.Usage)},
+		alerts.TableRow{Label: "Threads", Value: fmt.Sprintf("%d", info.Threads)},
+	)
+	tableContent := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText) + alerts.CreateTable(tableRows)
+
+	additionalContent := fmt.Sprintf(`
+	<div style="background-color: #f5f5f5; border-left: 5px solid #ddd; padding: 10px; margin: 10px 0;">
+		<p><b>Recommendation:</b> %s</p>
+	</div>`, ruleRecommendations[status.Rule])
+	if status.Status == "normal" {
+		additionalContent = fmt.Sprintf(`
+	<p>%s is back under its thresholds.</p>`, label)
+	}
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		title,
+		true,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, level)
+}
+
 // Helper method to create CPU-specific table content
 func (a *AlertHandler) createCPUTableContent(info *CPUInfo) string {
 	// Get style for alert
@@ -612,11 +781,11 @@ func (a *AlertHandler) sendAggregatedWarningAlert() {
 	a.monitor.UpdateLastAlertTime()
 
 	// Update tracking state
-	a.lastAggregationTime = time.Now()
+	a.lastAggregationTime = a.monitor.clock.Now()
 	a.pendingWarnings = make([]CPUInfo, 0) // Clear pending warnings
 
 	// Record the time we're sending this warning
-	a.lastWarningAlertTime = time.Now()
+	a.lastWarningAlertTime = a.monitor.clock.Now()
 
 	// Reset warning count after sending aggregated alert
 	a.warningCount = 0
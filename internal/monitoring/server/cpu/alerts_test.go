@@ -0,0 +1,279 @@
+package cpu
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	testsupport.Main(m)
+}
+
+// testEnv is a monitor wired to fake sources
+type testEnv struct {
+	*testsupport.Env
+	monitor   *Monitor
+	collector *FakeCollector
+}
+
+// newTestEnv creates a monitor with 70/90 thresholds, optionally adjusting the config
+func newTestEnv(configure func(*config.Config)) *testEnv {
+	cfg := &config.Config{}
+	cfg.Monitoring.CPU.Enabled = true
+	cfg.Monitoring.CPU.CheckInterval = 60
+	cfg.Monitoring.CPU.WarningThreshold = 70
+	cfg.Monitoring.CPU.CriticalThreshold = 90
+	if configure != nil {
+		configure(cfg)
+	}
+
+	env := &testEnv{
+		monitor:   NewMonitor(cfg),
+		collector: NewFakeCollector(),
+	}
+	env.monitor.SetCollector(env.collector)
+	env.Env = testsupport.Wire(env.monitor)
+	return env
+}
+
+// run checks each usage in turn, advancing the clock by step before every check but the first
+func (e *testEnv) run(step time.Duration, usages ...float64) {
+	e.Run(step, func(usage float64) {
+		e.collector.PushUsage(usage)
+		e.monitor.CheckCPU(context.Background())
+	}, usages...)
+}
+
+func TestAlertTransitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*config.Config)
+		step      time.Duration
+		usages    []float64
+		want      []string
+	}{
+		{
+			name:   "normal usage stays silent",
+			step:   time.Minute,
+			usages: []float64{10, 35, 69.9},
+		},
+		{
+			name:   "normal to warning",
+			step:   time.Minute,
+			usages: []float64{10, 70},
+			want:   []string{"CPU Warning"},
+		},
+		{
+			name:   "normal to critical",
+			step:   time.Minute,
+			usages: []float64{10, 95},
+			want:   []string{"CRITICAL CPU Alert"},
+		},
+		{
+			name:   "critical back to normal",
+			step:   time.Minute,
+			usages: []float64{10, 95, 20},
+			want:   []string{"CRITICAL CPU Alert", "CPU Status Normalized"},
+		},
+		{
+			name:   "warning back to normal is silent",
+			step:   time.Minute,
+			usages: []float64{10, 75, 20},
+			want:   []string{"CPU Warning"},
+		},
+		{
+			name:   "critical improving to warning is silent",
+			step:   time.Minute,
+			usages: []float64{10, 95, 75},
+			want:   []string{"CRITICAL CPU Alert"},
+		},
+		{
+			name:   "critical at startup waits for consecutive samples",
+			step:   time.Minute,
+			usages: testsupport.Repeat(95, 2),
+		},
+		{
+			name:   "critical at startup after consecutive samples",
+			step:   time.Minute,
+			usages: testsupport.Repeat(95, 3),
+			want:   []string{"CRITICAL CPU Alert"},
+		},
+		{
+			name:   "sustained warning within the throttle window",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(75, 30)),
+			want:   []string{"CPU Warning"},
+		},
+		{
+			name:   "sustained warning sends a summary after the throttle window",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(75, 31)),
+			want:   []string{"CPU Warning", "CPU Warning Summary"},
+		},
+		{
+			name:   "sustained critical within the cooldown",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(95, 5)),
+			want:   []string{"CRITICAL CPU Alert"},
+		},
+		{
+			name:   "sustained critical repeats after the cooldown",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(95, 6)),
+			want:   []string{"CRITICAL CPU Alert", "CRITICAL CPU Alert"},
+		},
+		{
+			name: "consecutive critical threshold from config",
+			configure: func(cfg *config.Config) {
+				cfg.Notifications.Throttling.Enabled = true
+				cfg.Notifications.Throttling.CooldownPeriod = 60
+				cfg.Notifications.Throttling.CriticalThreshold = 5
+			},
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(95, 5)),
+			want:   []string{"CRITICAL CPU Alert"},
+		},
+		{
+			name: "daily warning limit",
+			configure: func(cfg *config.Config) {
+				cfg.Notifications.Throttling.Enabled = true
+				cfg.Notifications.Throttling.MaxWarningsPerDay = 1
+			},
+			step:   31 * time.Minute,
+			usages: []float64{10, 75, 10, 75},
+			want:   []string{"CPU Warning"},
+		},
+		{
+			name: "daily warning limit resets the next day",
+			configure: func(cfg *config.Config) {
+				cfg.Notifications.Throttling.Enabled = true
+				cfg.Notifications.Throttling.MaxWarningsPerDay = 1
+			},
+			step:   7 * time.Hour,
+			usages: []float64{10, 75, 10, 75},
+			want:   []string{"CPU Warning", "CPU Warning"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(tt.configure)
+			env.run(tt.step, tt.usages...)
+
+			if got := env.Recorder.Subjects(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("notifications = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		load    []float64
+		usages  []float64
+		subject string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "warning",
+			load:    []float64{1.5, 1.25, 1},
+			usages:  []float64{10, 75},
+			subject: "CPU Warning",
+			want: []string{
+				"CPU WARNING ALERT",
+				"75.00%",
+				"This is a status change alert",
+				"This is warning notification 1 of 5 allowed per day",
+				"1-min: 1.50, 5-min: 1.25, 15-min: 1.00",
+			},
+		},
+		{
+			name:    "warning without load average",
+			usages:  []float64{10, 75},
+			subject: "CPU Warning",
+			want:    []string{"CPU WARNING ALERT"},
+			notWant: []string{"SYSTEM LOAD AVERAGE"},
+		},
+		{
+			name:    "critical with saturated load",
+			load:    []float64{12, 8, 4},
+			usages:  []float64{10, 97.5},
+			subject: "CRITICAL CPU Alert",
+			want: []string{
+				"CRITICAL CPU ALERT",
+				"97.50%",
+				"IMMEDIATE ACTION REQUIRED!",
+				"1-min: 12.00, 5-min: 8.00, 15-min: 4.00",
+				"(CRITICAL)",
+			},
+		},
+		{
+			name:    "critical with normal load",
+			load:    []float64{0.5, 0.5, 0.5},
+			usages:  []float64{10, 95},
+			subject: "CRITICAL CPU Alert",
+			want:    []string{"(NORMAL)"},
+		},
+		{
+			name:    "normalized",
+			usages:  []float64{10, 95, 20},
+			subject: "CPU Status Normalized",
+			want: []string{
+				"CPU STATUS NORMALIZED",
+				"20.00%",
+				"System CPU usage has returned to normal parameters.",
+			},
+			notWant: []string{"This is a status change alert"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(nil)
+			if tt.load != nil {
+				env.collector.SetLoadAverage(tt.load[0], tt.load[1], tt.load[2])
+			}
+			env.run(time.Minute, tt.usages...)
+
+			var body string
+			for _, notification := range env.Recorder.Notifications() {
+				if notification.Subject == tt.subject {
+					body = notification.Body
+				}
+			}
+			if body == "" {
+				t.Fatalf("no %q notification in %q", tt.subject, env.Recorder.Subjects())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("body contains %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectorError(t *testing.T) {
+	env := newTestEnv(nil)
+	env.collector.SetError(errors.New("no /proc/stat"))
+	env.monitor.CheckCPU(context.Background())
+
+	if info := env.monitor.GetLastCPUInfo(); info != nil {
+		t.Errorf("last info = %+v, want nil after a failed collection", info)
+	}
+	if got := env.Recorder.Subjects(); len(got) != 0 {
+		t.Errorf("notifications = %q, want none", got)
+	}
+}
@@ -0,0 +1,142 @@
+package cpu
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Collector takes the CPU samples the monitor checks
+type Collector interface {
+	// Collect returns the current CPU information with its status for the thresholds
+	Collect(ctx context.Context, warningThreshold, criticalThreshold float64) (*CPUInfo, error)
+	// LoadAverage returns the 1, 5 and 15 minute load averages
+	LoadAverage() ([]float64, error)
+	// Static returns the static CPU facts, refreshing them first when refresh is set
+	Static(refresh bool) (*StaticInfo, error)
+}
+
+// SystemCollector reads the host through gopsutil
+type SystemCollector struct{}
+
+// Collect implements Collector
+func (SystemCollector) Collect(ctx context.Context, warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
+	return GetCPUInfo(ctx, warningThreshold, criticalThreshold)
+}
+
+// LoadAverage implements Collector
+func (SystemCollector) LoadAverage() ([]float64, error) {
+	return getSystemLoadAvg()
+}
+
+// Static implements Collector
+func (SystemCollector) Static(refresh bool) (*StaticInfo, error) {
+	if refresh {
+		return RefreshStaticInfo()
+	}
+	return GetStaticInfo()
+}
+
+// FakeCollector replays queued samples for tests and simulations. Once the queue
+// is drained the last sample repeats. The status is derived from the thresholds
+// the same way GetCPUInfo does.
+type FakeCollector struct {
+	mu      sync.Mutex
+	samples []CPUInfo
+	last    *CPUInfo
+	err     error
+	load    []float64
+}
+
+// NewFakeCollector creates a fake collector with the given samples queued
+func NewFakeCollector(samples ...CPUInfo) *FakeCollector {
+	f := &FakeCollector{}
+	f.Push(samples...)
+	return f
+}
+
+// Push queues samples
+func (f *FakeCollector) Push(samples ...CPUInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.samples = append(f.samples, samples...)
+}
+
+// PushUsage queues samples that only carry a usage percentage
+func (f *FakeCollector) PushUsage(usages ...float64) {
+	for _, usage := range usages {
+		f.Push(CPUInfo{ModelName: "Fake CPU", Cores: 4, Threads: 8, Usage: usage})
+	}
+}
+
+// SetError makes Collect fail with err until it is cleared with nil
+func (f *FakeCollector) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// SetLoadAverage sets the load averages returned by LoadAverage
+func (f *FakeCollector) SetLoadAverage(load1, load5, load15 float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.load = []float64{load1, load5, load15}
+}
+
+// Collect implements Collector
+func (f *FakeCollector) Collect(ctx context.Context, warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(f.samples) > 0 {
+		sample := f.samples[0]
+		f.samples = f.samples[1:]
+		f.last = &sample
+	}
+	if f.last == nil {
+		return nil, fmt.Errorf("fake CPU collector has no samples")
+	}
+
+	info := *f.last
+	info.CPUStatus = usageStatus(info.Usage, warningThreshold, criticalThreshold)
+	return &info, nil
+}
+
+// LoadAverage implements Collector
+func (f *FakeCollector) LoadAverage() ([]float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.load == nil {
+		return nil, fmt.Errorf("fake CPU collector has no load average")
+	}
+	return append([]float64{}, f.load...), nil
+}
+
+// Static implements Collector. The facts come from the last collected sample, or
+// the next queued one before the first collection.
+func (f *FakeCollector) Static(refresh bool) (*StaticInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sample := f.last
+	if sample == nil && len(f.samples) > 0 {
+		sample = &f.samples[0]
+	}
+	if sample == nil {
+		return nil, fmt.Errorf("fake CPU collector has no samples")
+	}
+	return staticFromInfo(sample), nil
+}
+
+// usageStatus maps a usage percentage to normal, warning or critical
+func usageStatus(usage, warningThreshold, criticalThreshold float64) string {
+	if usage >= criticalThreshold {
+		return "critical"
+	} else if usage >= warningThreshold {
+		return "warning"
+	}
+	return "normal"
+}
@@ -1,8 +1,8 @@
 package cpu
 
 import (
+	"context"
 	"fmt"
-	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -11,35 +11,31 @@ import (
 	"github.com/shirou/gopsutil/host"
 )
 
-// GetCPUInfo retrieves the current CPU information
-func GetCPUInfo(warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
-	// Get CPU stats
-	cpuStats, err := gopsutilCPU.Info()
+// GetCPUInfo retrieves the current CPU information. Static facts come from the
+// cache, only usage, times and frequency are sampled, bounded by ctx.
+func GetCPUInfo(ctx context.Context, warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
+	static, err := GetStaticInfo()
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate if CPU data exists
-	if len(cpuStats) == 0 {
-		return nil, fmt.Errorf("no CPU information available")
+	dynamic, err := GetDynamicInfo(ctx, warningThreshold, criticalThreshold)
+	if err != nil {
+		return nil, err
 	}
 
-	// Start concurrent operations
-	// Get virtualization info in parallel
-	virtChan := make(chan struct {
-		system string
-		role   string
-		err    error
-	})
-	go func() {
-		system, role, err := host.Virtualization()
-		virtChan <- struct {
-			system string
-			role   string
-			err    error
-		}{system, role, err}
-	}()
+	// A different number of logical processors means CPUs were hot-plugged
+	if len(dynamic.CoreUsage) > 0 && len(dynamic.CoreUsage) != static.Threads {
+		if static, err = RefreshStaticInfo(); err != nil {
+			return nil, err
+		}
+	}
 
+	return mergeInfo(static, dynamic), nil
+}
+
+// GetDynamicInfo samples the CPU usage, per-core usage, times and current frequency
+func GetDynamicInfo(ctx context.Context, warningThreshold, criticalThreshold float64) (*DynamicInfo, error) {
 	// Get both total and per-core CPU usage with a single wait period
 	// This reduces the total wait time from 2 seconds to 1 second
 	timeoutDuration := 500 * time.Millisecond // Reduced timeout for faster results
@@ -48,7 +44,7 @@ func GetCPUInfo(warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
 	totalUsageChan := make(chan []float64)
 	totalUsageErrChan := make(chan error)
 	go func() {
-		usage, err := gopsutilCPU.Percent(timeoutDuration, false)
+		usage, err := gopsutilCPU.PercentWithContext(ctx, timeoutDuration, false)
 		totalUsageChan <- usage
 		totalUsageErrChan <- err
 	}()
@@ -57,7 +53,7 @@ func GetCPUInfo(warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
 	perCoreUsageChan := make(chan []float64)
 	perCoreUsageErrChan := make(chan error)
 	go func() {
-		usage, err := gopsutilCPU.Percent(timeoutDuration, true)
+		usage, err := gopsutilCPU.PercentWithContext(ctx, timeoutDuration, true)
 		perCoreUsageChan <- usage
 		perCoreUsageErrChan <- err
 	}()
@@ -66,43 +62,33 @@ func GetCPUInfo(warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
 	cpuTimesChan := make(chan []gopsutilCPU.TimesStat)
 	cpuTimesErrChan := make(chan error)
 	go func() {
-		times, err := gopsutilCPU.Times(false)
+		times, err := gopsutilCPU.TimesWithContext(ctx, false)
 		cpuTimesChan <- times
 		cpuTimesErrChan <- err
 	}()
 
-	// Collect results from concurrent operations
-	// Get virtualization results
-	virtResult := <-virtChan
-	if virtResult.err != nil {
-		return nil, virtResult.err
-	}
-	virtualizationSystem, virtualizationRole := virtResult.system, virtResult.role
-	isVirtual := virtualizationRole == "guest" // If role is "guest", it's a VM
-
 	// Get CPU usage results
 	totalUsage := <-totalUsageChan
-	if err := <-totalUsageErrChan; err != nil {
-		return nil, err
-	}
+	totalUsageErr := <-totalUsageErrChan
 
 	perCoreUsage := <-perCoreUsageChan
-	if err := <-perCoreUsageErrChan; err != nil {
-		return nil, err
-	}
+	perCoreUsageErr := <-perCoreUsageErrChan
 
 	// Get CPU times results
 	cpuTimes := <-cpuTimesChan
-	if err := <-cpuTimesErrChan; err != nil {
-		return nil, err
-	}
+	cpuTimesErr := <-cpuTimesErrChan
 
-	// Determine CPU status based on threshold
-	status := "normal"
-	if totalUsage[0] >= criticalThreshold {
-		status = "critical"
-	} else if totalUsage[0] >= warningThreshold {
-		status = "warning"
+	if totalUsageErr != nil {
+		return nil, totalUsageErr
+	}
+	if perCoreUsageErr != nil {
+		return nil, perCoreUsageErr
+	}
+	if cpuTimesErr != nil {
+		return nil, cpuTimesErr
+	}
+	if len(totalUsage) == 0 {
+		return nil, fmt.Errorf("no CPU usage available")
 	}
 
 	// Convert CPU times to a map - ensure these are properly normalized
@@ -119,102 +105,76 @@ func GetCPUInfo(warningThreshold, criticalThreshold float64) (*CPUInfo, error) {
 		cpuTimeMap["steal"] = cpuTimes[0].Steal
 	}
 
-	// Count unique physical CPU packages
-	physicalIDs := make(map[string]bool)
-	for _, cpu := range cpuStats {
-		physicalIDs[cpu.PhysicalID] = true
-	}
-	processorCount := len(physicalIDs)
-
-	// Get frequency information
-	minFreq := 0.0
-	maxFreq := 0.0
-	// This is a simplified approach - actual implementation might need to read from /sys/devices
-	// on Linux or use other platform-specific methods
-	if cpuStats[0].Mhz > 0 {
-		// If we can't get min/max, at least set max to current
-		maxFreq = cpuStats[0].Mhz / 1000.0
+	// The current frequency changes with scaling; without cpufreq fall back to the
+	// speed reported when the static facts were collected
+	clockSpeed, err := readFrequency("scaling_cur_freq")
+	if err != nil {
+		if static, err := GetStaticInfo(); err == nil {
+			clockSpeed = static.BaseClockSpeed
+		}
 	}
 
-	// Use the first CPU for model, core, cache, and flags info
-	return &CPUInfo{
-		ModelName:  cpuStats[0].ModelName,
-		Cores:      int(cpuStats[0].Cores),
-		Threads:    len(cpuStats),              // Number of threads/logical processors
-		ClockSpeed: cpuStats[0].Mhz / 1000.0,   // Convert MHz to GHz
-		Usage:      totalUsage[0],              // Total CPU usage percentage
-		CoreUsage:  perCoreUsage,               // Per-core CPU usage percentage
-		CacheSize:  int(cpuStats[0].CacheSize), // CPU cache size in KB
-		IsVirtual:  isVirtual,                  // Whether running on a VM
-		Hypervisor: virtualizationSystem,       // Hypervisor name if running on a VM
-		CPUStatus:  status,                     // CPU status based on thresholds
-
-		// New CPU information fields
-		VendorID: cpuStats[0].VendorID,      // CPU vendor (Intel, AMD, etc)
-		Family:   cpuStats[0].Family,        // CPU family information
-		Stepping: int(cpuStats[0].Stepping), // CPU stepping/revision
-
-		// Additional CPU information
-		PhysicalID:     cpuStats[0].PhysicalID,
-		Microcode:      cpuStats[0].Microcode,
-		Architecture:   runtime.GOARCH,
-		MinFrequency:   minFreq,
-		MaxFrequency:   maxFreq,
-		CPUTimes:       cpuTimeMap,
-		Temperature:    0.0, // Would need additional platform-specific code to get temperature
-		ProcessorCount: processorCount,
+	return &DynamicInfo{
+		Usage:       totalUsage[0], // Total CPU usage percentage
+		CoreUsage:   perCoreUsage,  // Per-core CPU usage percentage
+		ClockSpeed:  clockSpeed,
+		CPUTimes:    cpuTimeMap,
+		Temperature: getCPUTemperature(),
+		CPUStatus:   usageStatus(totalUsage[0], warningThreshold, criticalThreshold),
 	}, nil
 }
 
 // GetCPUCoreInfo retrieves CPU core information without usage metrics
 func GetCPUCoreInfo() (*CPUInfo, error) {
-	// Get CPU stats
-	cpuStats, err := gopsutilCPU.Info()
-	if err != nil {
-		return nil, err
-	}
-
-	// Validate if CPU data exists
-	if len(cpuStats) == 0 {
-		return nil, fmt.Errorf("no CPU information available")
-	}
-
-	// Check if system is running on a VM
-	virtualizationSystem, virtualizationRole, err := host.Virtualization()
+	static, err := GetStaticInfo()
 	if err != nil {
 		return nil, err
 	}
 
-	isVirtual := virtualizationRole == "guest" // If role is "guest", it's a VM
-
-	// Count unique physical CPU packages
-	physicalIDs := make(map[string]bool)
-	for _, cpu := range cpuStats {
-		physicalIDs[cpu.PhysicalID] = true
-	}
-	processorCount := len(physicalIDs)
-
-	// Use the first CPU for model, core info
 	return &CPUInfo{
-		ModelName:  cpuStats[0].ModelName,
-		Cores:      int(cpuStats[0].Cores),
-		Threads:    len(cpuStats),        // Number of threads/logical processors
-		IsVirtual:  isVirtual,            // Whether running on a VM
-		Hypervisor: virtualizationSystem, // Hypervisor name if running on a VM
-
-		// New CPU information fields
-		VendorID: cpuStats[0].VendorID,      // CPU vendor (Intel, AMD, etc)
-		Family:   cpuStats[0].Family,        // CPU family information
-		Stepping: int(cpuStats[0].Stepping), // CPU stepping/revision
-
-		// Additional CPU information
-		PhysicalID:     cpuStats[0].PhysicalID,
-		Microcode:      cpuStats[0].Microcode,
-		Architecture:   runtime.GOARCH,
-		ProcessorCount: processorCount,
+		ModelName:      static.ModelName,
+		Cores:          static.Cores,
+		Threads:        static.Threads,
+		IsVirtual:      static.IsVirtual,
+		Hypervisor:     static.Hypervisor,
+		VendorID:       static.VendorID,
+		Family:         static.Family,
+		Stepping:       static.Stepping,
+		PhysicalID:     static.PhysicalID,
+		Microcode:      static.Microcode,
+		Architecture:   static.Architecture,
+		ProcessorCount: static.ProcessorCount,
 	}, nil
 }
 
+// mergeInfo combines the static facts and a dynamic sample into one CPU record
+func mergeInfo(static *StaticInfo, dynamic *DynamicInfo) *CPUInfo {
+	return &CPUInfo{
+		ModelName:      static.ModelName,
+		Cores:          static.Cores,
+		Threads:        static.Threads,
+		ClockSpeed:     dynamic.ClockSpeed,
+		Usage:          dynamic.Usage,
+		CoreUsage:      dynamic.CoreUsage,
+		CacheSize:      static.CacheSize,
+		IsVirtual:      static.IsVirtual,
+		Hypervisor:     static.Hypervisor,
+		CPUStatus:      dynamic.CPUStatus,
+		VendorID:       static.VendorID,
+		Family:         static.Family,
+		Stepping:       static.Stepping,
+		Flags:          static.Flags,
+		PhysicalID:     static.PhysicalID,
+		Microcode:      static.Microcode,
+		Architecture:   static.Architecture,
+		MinFrequency:   static.MinFrequency,
+		MaxFrequency:   static.MaxFrequency,
+		CPUTimes:       dynamic.CPUTimes,
+		Temperature:    dynamic.Temperature,
+		ProcessorCount: static.ProcessorCount,
+	}
+}
+
 // Helper function to detect if running in a virtualized environment
 func detectVirtualization() (bool, string) {
 	info, err := host.Info()
@@ -2,9 +2,12 @@ package cpu
 
 import (
 	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
 	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/clock"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
 	"CheckHealthDO/internal/websocket"
 	"context"
 	"fmt"
@@ -15,16 +18,22 @@ import (
 // Monitor handles periodic CPU monitoring
 type Monitor struct {
 	config          *config.Config
-	ticker          *time.Ticker
+	cancelJob       func() // Removes the checks from the scheduler
 	stopChan        chan struct{}
 	isRunning       bool
 	mutex           sync.Mutex
+	checkMutex      sync.Mutex // Serializes CheckCPU, which the scheduler and WebSocket connects both call
 	lastInfo        *CPUInfo
+	lastCheck       time.Time // When the last check finished
 	lastAlertTime   time.Time
-	emailManager    *notifications.EmailManager
+	emailManager    alerts.NotificationManager
 	checkCount      int // Counter for reducing log frequency
 	alertHandler    *AlertHandler
+	rules           *RuleEvaluator
 	summaryReporter *SummaryReporter
+	collector       Collector   // Source of samples, SystemCollector unless replaced
+	clock           clock.Clock // Time source for checks and alert throttling
+	statusLog       bool        // Write status changes to the status log file
 	// Remove trend-related fields
 }
 
@@ -34,13 +43,51 @@ func NewMonitor(cfg *config.Config) *Monitor {
 		config:       cfg,
 		stopChan:     make(chan struct{}),
 		emailManager: notifications.NewEmailManager(cfg),
+		collector:    SystemCollector{},
+		clock:        clock.System,
+		statusLog:    true,
 		// Remove trend-related initialization
 	}
 	m.alertHandler = NewAlertHandler(m)
+	m.rules = NewRuleEvaluator(m)
 	m.summaryReporter = NewSummaryReporter(m, cfg)
 	return m
 }
 
+// SetCollector replaces the source of CPU samples, e.g. with a FakeCollector.
+// Call it before StartMonitoring.
+func (m *Monitor) SetCollector(collector Collector) {
+	m.collector = collector
+}
+
+// SetClock replaces the time source and restarts the alert and summary state from
+// it, so a fake clock governs throttling from the first check. Call it before
+// StartMonitoring.
+func (m *Monitor) SetClock(c clock.Clock) {
+	m.clock = c
+	m.lastAlertTime = time.Time{}
+	m.alertHandler = NewAlertHandler(m)
+	m.rules = NewRuleEvaluator(m)
+	m.summaryReporter = NewSummaryReporter(m, m.config)
+}
+
+// SetNotificationManager routes CPU alerts and summaries to manager. Call it before
+// StartMonitoring.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// SetStatusLog turns writing status changes to the status log file on or off, so
+// replays do not mix into the host's history
+func (m *Monitor) SetStatusLog(enabled bool) {
+	m.statusLog = enabled
+}
+
+// Now returns the monitor's current time, which the alert handler uses for cooldowns
+func (m *Monitor) Now() time.Time {
+	return m.clock.Now()
+}
+
 // StartMonitoring begins the CPU monitoring process
 func (m *Monitor) StartMonitoring() error {
 	m.mutex.Lock()
@@ -56,7 +103,17 @@ func (m *Monitor) StartMonitoring() error {
 	}
 
 	interval := time.Duration(m.config.Monitoring.CPU.CheckInterval) * time.Second
-	m.ticker = time.NewTicker(interval)
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return m.CheckCPU(ctx)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule CPU checks: %w", err)
+	}
+	m.cancelJob = cancelJob
 	m.isRunning = true
 
 	logger.Info("Starting CPU monitor",
@@ -64,21 +121,6 @@ func (m *Monitor) StartMonitoring() error {
 		logger.Float64("warning_threshold", m.config.Monitoring.CPU.WarningThreshold),
 		logger.Float64("critical_threshold", m.config.Monitoring.CPU.CriticalThreshold))
 
-	// Run the first check immediately, then continue at intervals
-	go func() {
-		m.CheckCPU() // Update this call
-
-		for {
-			select {
-			case <-m.ticker.C:
-				m.CheckCPU() // Update this call
-			case <-m.stopChan:
-				m.ticker.Stop()
-				return
-			}
-		}
-	}()
-
 	return nil
 }
 
@@ -91,14 +133,52 @@ func (m *Monitor) StopMonitoring() {
 		return
 	}
 
+	m.cancelJob()
 	close(m.stopChan)
 	m.isRunning = false
 	logger.Info("CPU monitor stopped")
 }
 
-// CheckCPU performs a single CPU check
-func (m *Monitor) CheckCPU() {
-	info, err := GetCPUInfo(
+// Name returns "cpu"
+func (m *Monitor) Name() string {
+	return "cpu"
+}
+
+// Start schedules CheckCPU on the configured interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes CheckCPU from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns the *CPUInfo of the last CheckCPU, nil before the first one
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetLastCPUInfo()
+}
+
+// Health compares the last CheckCPU with monitoring.cpu.check_interval
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	interval := time.Duration(m.config.Monitoring.CPU.CheckInterval) * time.Second
+	return monitoring.NewHealth(m.isRunning, m.lastCheck, interval)
+}
+
+// CheckCPU performs a single CPU check, bounded by ctx. Checks run one at a time,
+// as the rule evaluator and alert handler keep state between them.
+func (m *Monitor) CheckCPU(ctx context.Context) error {
+	m.checkMutex.Lock()
+	defer m.checkMutex.Unlock()
+
+	info, err := m.collector.Collect(ctx,
 		m.config.Monitoring.CPU.WarningThreshold,
 		m.config.Monitoring.CPU.CriticalThreshold,
 	)
@@ -106,72 +186,40 @@ func (m *Monitor) CheckCPU() {
 	if err != nil {
 		logger.Error("Failed to get CPU info",
 			logger.String("error", err.Error()))
-		return
+		return err
 	}
 
+	// Evaluate the steal, iowait, core hotspot and load rules
+	rules, changedRules := m.rules.Evaluate(info)
+	info.Rules = rules
+
 	// Check if status changed from the last check
 	statusChanged := false
 	m.mutex.Lock()
 	if m.lastInfo != nil && m.lastInfo.CPUStatus != info.CPUStatus {
 		statusChanged = true
 		// Log the status change using the dedicated status logger
-		GetStatusLogger().LogStatusChange(m.lastInfo.CPUStatus, info.CPUStatus, info.Usage)
+		if m.statusLog {
+			GetStatusLogger().LogStatusChange(m.lastInfo.CPUStatus, info.CPUStatus, info.Usage)
+		}
 	}
 
 	// Store the latest metrics
+	checkedAt := m.clock.Now()
 	m.lastInfo = info
+	m.lastCheck = checkedAt
 	m.mutex.Unlock()
 
 	// Only log detailed information if not a status change but at a lower frequency
 	m.checkCount++
 
-	// Format timestamp consistently for all messages
-	timestamp := time.Now()
-	formattedTime := timestamp.Format(time.RFC3339)
-
-	// Create a completely separate metrics structure to ensure no overlap with memory data
-	// Using a deeply nested structure with explicit metric type identification
-	combinedMsg := map[string]interface{}{
-		"metric_type": "cpu", // Explicit identifier for the metric type
-		"metrics_data": map[string]interface{}{
-			"cpu_info": map[string]interface{}{
-				"model_name":      info.ModelName,
-				"cores":           info.Cores,
-				"threads":         info.Threads,
-				"clock_speed":     info.ClockSpeed,
-				"usage":           info.Usage,
-				"core_usage":      info.CoreUsage,
-				"cache_size":      info.CacheSize,
-				"cpu_status":      info.CPUStatus,
-				"is_virtual":      info.IsVirtual,
-				"hypervisor":      info.Hypervisor,
-				"status":          info.CPUStatus,
-				"vendor_id":       info.VendorID,
-				"family":          info.Family,
-				"stepping":        info.Stepping,
-				"physical_id":     info.PhysicalID,
-				"microcode":       info.Microcode,
-				"architecture":    info.Architecture,
-				"min_frequency":   info.MinFrequency,
-				"max_frequency":   info.MaxFrequency,
-				"processor_count": info.ProcessorCount,
-				"cpu_times":       info.CPUTimes,
-				"temperature":     info.Temperature,
-			},
-		},
-		"meta": map[string]interface{}{
-			"timestamp":        timestamp,
-			"last_update_time": formattedTime,
-			"source":           "cpu_monitor",
-			"version":          "1.0",
-		},
-	}
+	combinedMsg := m.cpuMessage(info, checkedAt)
 
 	// Get the registry
 	registry := websocket.GetRegistry()
 
 	// Broadcast to CPU-specific WebSocket with the dedicated CPU structure
-	if handler := registry.GetCPUHandler(); handler != nil {
+	if handler := registry.GetHandler(websocket.TopicCPU); handler != nil {
 		registry.BroadcastCPU(combinedMsg)
 	}
 
@@ -180,9 +228,10 @@ func (m *Monitor) CheckCPU() {
 
 	// Only process alerts if status changed or a significant amount of time has passed
 	// since the last alert to avoid excessive checks
+	sinceLastAlert := m.clock.Since(m.GetLastAlertTime())
 	shouldProcessAlerts := statusChanged ||
-		(time.Since(m.lastAlertTime) >= 5*time.Minute) ||
-		(info.CPUStatus == "critical" && time.Since(m.lastAlertTime) >= 1*time.Minute)
+		(sinceLastAlert >= 5*time.Minute) ||
+		(info.CPUStatus == "critical" && sinceLastAlert >= 1*time.Minute)
 
 	if shouldProcessAlerts {
 		// Process alerts based on status
@@ -194,7 +243,18 @@ func (m *Monitor) CheckCPU() {
 		case "critical":
 			m.alertHandler.HandleCriticalAlert(info, statusChanged)
 		}
+	} else {
+		logger.Debug("Skipping CPU alert evaluation until the re-check interval has passed",
+			logger.String("status", info.CPUStatus))
+		m.alertHandler.handler.ReportSuppressed("CPU alert re-check interval has not passed")
+	}
+
+	// Rules alert on every change of level, naming the dimension that triggered
+	for _, status := range changedRules {
+		m.alertHandler.HandleRuleAlert(info, status)
 	}
+
+	return nil
 }
 
 // GetLastCPUInfo returns the most recently captured CPU information
@@ -204,6 +264,11 @@ func (m *Monitor) GetLastCPUInfo() *CPUInfo {
 	return m.lastInfo
 }
 
+// GetStaticInfo returns the static CPU facts, collecting them again when refresh is set
+func (m *Monitor) GetStaticInfo(refresh bool) (*StaticInfo, error) {
+	return m.collector.Static(refresh)
+}
+
 // GetConfig returns the monitor's configuration
 // Modified to return interface{} to match the alerts.ConfigProvider interface
 func (m *Monitor) GetConfig() interface{} {
@@ -218,11 +283,15 @@ func (m *Monitor) GetConfigPtr() *config.Config {
 
 // UpdateLastAlertTime updates the last alert time
 func (m *Monitor) UpdateLastAlertTime() {
-	m.lastAlertTime = time.Now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = m.clock.Now()
 }
 
 // GetLastAlertTime returns the last alert time
 func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.lastAlertTime
 }
 
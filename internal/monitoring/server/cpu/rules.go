@@ -0,0 +1,187 @@
+package cpu
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"fmt"
+	"time"
+)
+
+// Rule names
+const (
+	RuleSteal       = "steal"
+	RuleIOWait      = "iowait"
+	RuleCoreHotspot = "core_hotspot"
+	RuleLoad        = "load"
+)
+
+// RuleStatus is the state of one CPU alert rule after a check
+type RuleStatus struct {
+	Rule     string    `json:"rule"`            // steal, iowait, core_hotspot or load
+	Value    float64   `json:"value"`           // Percent, or load per logical processor for load
+	Warning  float64   `json:"warning"`         // Threshold, 0 when disabled
+	Critical float64   `json:"critical"`        // Threshold, 0 when disabled
+	Status   string    `json:"status"`          // normal, warning or critical
+	Since    time.Time `json:"since,omitempty"` // When the value went over the warning threshold
+	Detail   string    `json:"detail"`          // What was measured, e.g. which core
+}
+
+// ruleState tracks how long a rule's thresholds have been exceeded
+type ruleState struct {
+	warningSince  time.Time // Zero while under the warning threshold
+	criticalSince time.Time // Zero while under the critical threshold
+	status        string    // Level last alerted, normal before any alert
+}
+
+// RuleEvaluator checks the steal, iowait, core hotspot and load rules on every
+// CPU sample and reports the rules whose alerted level changed
+type RuleEvaluator struct {
+	monitor  *Monitor
+	previous map[string]float64 // CPU times of the previous sample
+	states   map[string]*ruleState
+}
+
+// NewRuleEvaluator creates a rule evaluator for the monitor
+func NewRuleEvaluator(monitor *Monitor) *RuleEvaluator {
+	return &RuleEvaluator{
+		monitor: monitor,
+		states:  make(map[string]*ruleState),
+	}
+}
+
+// Evaluate measures every enabled rule on the sample. It returns the status of all
+// measured rules and, separately, those that moved to another level.
+func (e *RuleEvaluator) Evaluate(info *CPUInfo) (statuses []RuleStatus, changed []RuleStatus) {
+	rules := e.monitor.config.Monitoring.CPU.Rules
+	now := e.monitor.clock.Now()
+
+	measure := func(name string, rule config.CPURuleConfig, value float64, detail string) {
+		state := e.states[name]
+		if state == nil {
+			state = &ruleState{status: "normal"}
+			e.states[name] = state
+		}
+
+		status := RuleStatus{
+			Rule:     name,
+			Value:    value,
+			Warning:  rule.Warning,
+			Critical: rule.Critical,
+			Status:   state.update(value, rule, now),
+			Since:    state.warningSince,
+			Detail:   detail,
+		}
+		statuses = append(statuses, status)
+
+		if status.Status != state.status {
+			state.status = status.Status
+			changed = append(changed, status)
+		}
+	}
+
+	// Steal and iowait are shares of the CPU time spent since the previous sample
+	if len(info.CPUTimes) > 0 {
+		if e.previous != nil {
+			if shares, ok := timeShares(e.previous, info.CPUTimes); ok {
+				if enabled(rules.Steal) {
+					measure(RuleSteal, rules.Steal, shares["steal"],
+						fmt.Sprintf("%.1f%% of CPU time was taken by the hypervisor", shares["steal"]))
+				}
+				if enabled(rules.IOWait) {
+					measure(RuleIOWait, rules.IOWait, shares["iowait"],
+						fmt.Sprintf("%.1f%% of CPU time was spent waiting for I/O", shares["iowait"]))
+				}
+			}
+		}
+		e.previous = info.CPUTimes
+	}
+
+	// The busiest core, which a low average can hide
+	if enabled(rules.CoreHotspot) && len(info.CoreUsage) > 0 {
+		core, usage := busiestCore(info.CoreUsage)
+		measure(RuleCoreHotspot, rules.CoreHotspot, usage,
+			fmt.Sprintf("Core %d at %.1f%% while the average is %.1f%%", core, usage, info.Usage))
+	}
+
+	// Load average per logical processor
+	if enabled(rules.Load) {
+		processors := info.Threads
+		if processors == 0 {
+			processors = len(info.CoreUsage)
+		}
+		if loadAvg, err := e.monitor.collector.LoadAverage(); err == nil && len(loadAvg) > 0 && processors > 0 {
+			measure(RuleLoad, rules.Load, loadAvg[0]/float64(processors),
+				fmt.Sprintf("1-minute load %.2f on %d logical processors", loadAvg[0], processors))
+		}
+	}
+
+	return statuses, changed
+}
+
+// update records the value and returns the level whose threshold has been
+// exceeded for the rule's duration
+func (s *ruleState) update(value float64, rule config.CPURuleConfig, now time.Time) string {
+	duration := time.Duration(rule.Duration) * time.Second
+
+	s.warningSince = exceededSince(s.warningSince, value, rule.Warning, now)
+	s.criticalSince = exceededSince(s.criticalSince, value, rule.Critical, now)
+
+	switch {
+	case !s.criticalSince.IsZero() && now.Sub(s.criticalSince) >= duration:
+		return "critical"
+	case !s.warningSince.IsZero() && now.Sub(s.warningSince) >= duration:
+		return "warning"
+	}
+	return "normal"
+}
+
+// exceededSince returns when value went over threshold, zero when it is under it
+// or the threshold is disabled
+func exceededSince(since time.Time, value, threshold float64, now time.Time) time.Time {
+	if threshold <= 0 || value < threshold {
+		return time.Time{}
+	}
+	if since.IsZero() {
+		return now
+	}
+	return since
+}
+
+// enabled reports whether a rule has at least one threshold
+func enabled(rule config.CPURuleConfig) bool {
+	return rule.Warning > 0 || rule.Critical > 0
+}
+
+// timeShares returns the percent of CPU time spent in each state between two
+// samples of cumulative CPU times. It fails when no time passed or the counters
+// went backwards.
+func timeShares(previous, current map[string]float64) (map[string]float64, bool) {
+	deltas := make(map[string]float64, len(current))
+	total := 0.0
+	for name, value := range current {
+		delta := value - previous[name]
+		if delta < 0 {
+			return nil, false
+		}
+		deltas[name] = delta
+		total += delta
+	}
+	if total <= 0 {
+		return nil, false
+	}
+
+	for name, delta := range deltas {
+		deltas[name] = delta / total * 100
+	}
+	return deltas, true
+}
+
+// busiestCore returns the index and usage of the core with the highest usage
+func busiestCore(coreUsage []float64) (int, float64) {
+	core := 0
+	for i, usage := range coreUsage {
+		if usage > coreUsage[core] {
+			core = i
+		}
+	}
+	return core, coreUsage[core]
+}
@@ -0,0 +1,292 @@
+package cpu
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// timesSample returns a 10% usage sample whose cumulative CPU times advanced by
+// 100 seconds since previous, stealSeconds and iowaitSeconds of them in those states
+func timesSample(previous map[string]float64, stealSeconds, iowaitSeconds float64) CPUInfo {
+	times := map[string]float64{
+		"user":   previous["user"] + 10,
+		"idle":   previous["idle"] + 90 - stealSeconds - iowaitSeconds,
+		"steal":  previous["steal"] + stealSeconds,
+		"iowait": previous["iowait"] + iowaitSeconds,
+	}
+	return CPUInfo{Threads: 4, Usage: 10, CoreUsage: []float64{10, 10, 10, 10}, CPUTimes: times}
+}
+
+// timesSeries returns n samples whose CPU times advance by the given seconds of
+// steal and iowait out of every 100
+func timesSeries(n int, stealSeconds, iowaitSeconds float64) []CPUInfo {
+	samples := make([]CPUInfo, 0, n)
+	previous := map[string]float64{}
+	for i := 0; i < n; i++ {
+		sample := timesSample(previous, stealSeconds, iowaitSeconds)
+		samples = append(samples, sample)
+		previous = sample.CPUTimes
+	}
+	return samples
+}
+
+func TestRuleAlerts(t *testing.T) {
+	// Ten-second checks; the critical threshold of each rule is twice the warning
+	rule := config.CPURuleConfig{Warning: 20, Critical: 40, Duration: 30}
+
+	tests := []struct {
+		name    string
+		rules   config.CPURulesConfig
+		load    []float64
+		samples func() []CPUInfo
+		want    []string
+	}{
+		{
+			name:  "steal under the threshold stays silent",
+			rules: config.CPURulesConfig{Steal: rule},
+			samples: func() []CPUInfo {
+				return timesSeries(8, 10, 0)
+			},
+		},
+		{
+			name:  "steal alerts once sustained for the duration",
+			rules: config.CPURulesConfig{Steal: rule},
+			samples: func() []CPUInfo {
+				return timesSeries(5, 25, 0)
+			},
+			want: []string{"CPU Steal Time Warning"},
+		},
+		{
+			name:  "short steal spike stays silent",
+			rules: config.CPURulesConfig{Steal: rule},
+			samples: func() []CPUInfo {
+				return timesSeries(3, 25, 0)
+			},
+		},
+		{
+			name:  "steal escalates and recovers",
+			rules: config.CPURulesConfig{Steal: rule},
+			samples: func() []CPUInfo {
+				samples := timesSeries(5, 50, 0)
+				return append(samples, timesSample(samples[len(samples)-1].CPUTimes, 0, 0))
+			},
+			want: []string{"CRITICAL CPU Steal Time Alert", "CPU Steal Time Normalized"},
+		},
+		{
+			name:  "iowait",
+			rules: config.CPURulesConfig{IOWait: rule},
+			samples: func() []CPUInfo {
+				return timesSeries(5, 0, 25)
+			},
+			want: []string{"CPU I/O Wait Warning"},
+		},
+		{
+			name:  "one pegged core behind a low average",
+			rules: config.CPURulesConfig{CoreHotspot: config.CPURuleConfig{Warning: 90, Duration: 30}},
+			samples: func() []CPUInfo {
+				sample := CPUInfo{Threads: 4, Usage: 25, CoreUsage: []float64{0, 100, 0, 0}}
+				return []CPUInfo{sample, sample, sample, sample}
+			},
+			want: []string{"CPU Core Hotspot Warning"},
+		},
+		{
+			name:  "load normalized by logical processors",
+			rules: config.CPURulesConfig{Load: config.CPURuleConfig{Warning: 1, Critical: 2}},
+			load:  []float64{6, 4, 2},
+			samples: func() []CPUInfo {
+				return []CPUInfo{{Threads: 4, Usage: 10}}
+			},
+			want: []string{"CPU Normalized Load Warning"},
+		},
+		{
+			name:  "load under the processor count stays silent",
+			rules: config.CPURulesConfig{Load: config.CPURuleConfig{Warning: 1, Critical: 2}},
+			load:  []float64{3, 4, 2},
+			samples: func() []CPUInfo {
+				return []CPUInfo{{Threads: 4, Usage: 10}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(func(cfg *config.Config) {
+				cfg.Monitoring.CPU.Rules = tt.rules
+			})
+			if tt.load != nil {
+				env.collector.SetLoadAverage(tt.load[0], tt.load[1], tt.load[2])
+			}
+			for i, sample := range tt.samples() {
+				if i > 0 {
+					env.Clock.Advance(10 * time.Second)
+				}
+				env.collector.Push(sample)
+				env.monitor.CheckCPU(context.Background())
+			}
+
+			if got := env.Recorder.Subjects(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("notifications = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleNotificationContent(t *testing.T) {
+	env := newTestEnv(func(cfg *config.Config) {
+		cfg.Monitoring.CPU.Rules.CoreHotspot = config.CPURuleConfig{Warning: 90, Critical: 99}
+	})
+	env.collector.Push(CPUInfo{Threads: 4, Usage: 25, CoreUsage: []float64{0, 0, 100, 0}})
+	env.monitor.CheckCPU(context.Background())
+
+	notifications := env.Recorder.Notifications()
+	if len(notifications) != 1 {
+		t.Fatalf("notifications = %q, want one", env.Recorder.Subjects())
+	}
+	for _, want := range []string{
+		"CRITICAL CPU CORE HOTSPOT ALERT",
+		"Core Hotspot",
+		"Core 2 at 100.0% while the average is 25.0%",
+		"99.0%",
+		"pidstat -t",
+	} {
+		if !strings.Contains(notifications[0].Body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+
+	info := env.monitor.GetLastCPUInfo()
+	if len(info.Rules) != 1 || info.Rules[0].Rule != RuleCoreHotspot || info.Rules[0].Status != "critical" {
+		t.Errorf("rules = %+v, want a critical core hotspot", info.Rules)
+	}
+}
+
+// stealEnv returns a test env with a steal rule that fires on the first sample
+// over a threshold, and a function checking one sample with the given steal
+func stealEnv() (*testEnv, func(step time.Duration, steal float64)) {
+	env := newTestEnv(func(cfg *config.Config) {
+		cfg.Monitoring.CPU.Rules.Steal = config.CPURuleConfig{Warning: 20, Critical: 40}
+	})
+
+	previous := map[string]float64{}
+	env.collector.Push(timesSample(previous, 0, 0))
+	env.monitor.CheckCPU(context.Background())
+	previous = env.monitor.GetLastCPUInfo().CPUTimes
+
+	check := func(step time.Duration, steal float64) {
+		env.Clock.Advance(step)
+		sample := timesSample(previous, steal, 0)
+		previous = sample.CPUTimes
+		env.collector.Push(sample)
+		env.monitor.CheckCPU(context.Background())
+	}
+	return env, check
+}
+
+// ruleSuppressions returns the suppression reasons recorded for the steal rule
+func ruleSuppressions(env *testEnv) []string {
+	var reasons []string
+	for _, outcome := range env.Recorder.Outcomes() {
+		if strings.Contains(outcome.Reason, "Steal") || strings.Contains(outcome.Reason, "rule") {
+			reasons = append(reasons, outcome.Reason)
+		}
+	}
+	return reasons
+}
+
+func TestRuleAlertThrottling(t *testing.T) {
+	t.Run("flapping rule notifies once per throttle window", func(t *testing.T) {
+		env, check := stealEnv()
+		for _, steal := range []float64{25, 0, 25, 0, 25, 0} {
+			check(time.Minute, steal)
+		}
+
+		want := []string{"CPU Steal Time Warning", "CPU Steal Time Normalized"}
+		if got := env.Recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("notifications = %q, want %q", got, want)
+		}
+		wantReasons := []string{
+			"CPU Steal Time warning within the throttle window",
+			"CPU Steal Time recovered before a notification was sent",
+			"CPU Steal Time warning within the throttle window",
+			"CPU Steal Time recovered before a notification was sent",
+		}
+		if got := ruleSuppressions(env); strings.Join(got, "|") != strings.Join(wantReasons, "|") {
+			t.Errorf("suppressed = %q, want %q", got, wantReasons)
+		}
+	})
+
+	t.Run("escalation passes the throttle window", func(t *testing.T) {
+		env, check := stealEnv()
+		check(time.Minute, 25)    // warning
+		check(time.Minute, 50)    // critical, an escalation
+		check(time.Minute, 25)    // improvement, held until normal
+		check(time.Minute, 50)    // critical again within the window
+		check(time.Minute, 0)     // recovery
+		check(31*time.Minute, 25) // warning after the window
+
+		want := []string{
+			"CPU Steal Time Warning",
+			"CRITICAL CPU Steal Time Alert",
+			"CPU Steal Time Normalized",
+			"CPU Steal Time Warning",
+		}
+		if got := env.Recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("notifications = %q, want %q", got, want)
+		}
+		wantReasons := []string{
+			"CPU Steal Time improved from critical to warning",
+			"CPU Steal Time critical within the throttle window",
+		}
+		if got := ruleSuppressions(env); strings.Join(got, "|") != strings.Join(wantReasons, "|") {
+			t.Errorf("suppressed = %q, want %q", got, wantReasons)
+		}
+	})
+
+	t.Run("daily warning limit", func(t *testing.T) {
+		env, check := stealEnv()
+		for i := 0; i < 6; i++ {
+			check(31*time.Minute, 25)
+			check(time.Minute, 0)
+		}
+
+		warnings := 0
+		for _, subject := range env.Recorder.Subjects() {
+			if subject == "CPU Steal Time Warning" {
+				warnings++
+			}
+		}
+		if warnings != 5 {
+			t.Errorf("sent %d warnings, want the daily limit of 5", warnings)
+		}
+		if got := ruleSuppressions(env); len(got) == 0 || got[0] != "Daily CPU rule warning limit reached" {
+			t.Errorf("suppressed = %q, want the daily limit first", got)
+		}
+	})
+}
+
+func TestCheckCPUConcurrently(t *testing.T) {
+	env := newTestEnv(func(cfg *config.Config) {
+		cfg.Monitoring.CPU.Rules.CoreHotspot = config.CPURuleConfig{Warning: 90}
+		cfg.Monitoring.CPU.Rules.Steal = config.CPURuleConfig{Warning: 20}
+	})
+	env.collector.Push(timesSeries(2, 25, 0)...)
+
+	// The scheduler and WebSocket connects check at the same time; run with -race
+	done := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			env.monitor.CheckCPU(context.Background())
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		<-done
+	}
+
+	if got := env.Recorder.Subjects(); len(got) != 1 || got[0] != "CPU Steal Time Warning" {
+		t.Errorf("notifications = %q, want one steal warning", got)
+	}
+}
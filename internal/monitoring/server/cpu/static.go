@@ -0,0 +1,173 @@
+package cpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopsutilCPU "github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/host"
+)
+
+// cpufreqDir holds the frequency limits and current frequency of the first CPU on Linux
+const cpufreqDir = "/sys/devices/system/cpu/cpu0/cpufreq"
+
+// StaticInfo holds the CPU facts that do not change while the host runs. They are
+// collected once and cached, see GetStaticInfo and RefreshStaticInfo.
+type StaticInfo struct {
+	ModelName      string    `json:"model_name"`
+	Cores          int       `json:"cores"`      // Physical cores
+	Threads        int       `json:"threads"`    // Logical processors
+	CacheSize      int       `json:"cache_size"` // In KB
+	IsVirtual      bool      `json:"is_virtual"`
+	Hypervisor     string    `json:"hypervisor"`
+	VendorID       string    `json:"vendor_id"`
+	Family         string    `json:"family"`
+	Stepping       int       `json:"stepping"`
+	Flags          []string  `json:"flags"`
+	PhysicalID     string    `json:"physical_id"`
+	Microcode      string    `json:"microcode"`
+	Architecture   string    `json:"architecture"`
+	MinFrequency   float64   `json:"min_frequency"`    // In GHz
+	MaxFrequency   float64   `json:"max_frequency"`    // In GHz
+	BaseClockSpeed float64   `json:"base_clock_speed"` // Clock speed when collected, used when the current one cannot be read
+	ProcessorCount int       `json:"processor_count"`  // Physical processor packages
+	CollectedAt    time.Time `json:"collected_at"`
+}
+
+var staticCache struct {
+	mu   sync.Mutex
+	info *StaticInfo
+}
+
+// GetStaticInfo returns the cached static CPU facts, collecting them on first use
+func GetStaticInfo() (*StaticInfo, error) {
+	staticCache.mu.Lock()
+	defer staticCache.mu.Unlock()
+
+	if staticCache.info == nil {
+		info, err := collectStaticInfo()
+		if err != nil {
+			return nil, err
+		}
+		staticCache.info = info
+	}
+
+	info := *staticCache.info
+	return &info, nil
+}
+
+// RefreshStaticInfo collects the static CPU facts again, e.g. after CPUs were added
+// to a VM or the VM moved to another host
+func RefreshStaticInfo() (*StaticInfo, error) {
+	info, err := collectStaticInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	staticCache.mu.Lock()
+	staticCache.info = info
+	staticCache.mu.Unlock()
+
+	refreshed := *info
+	return &refreshed, nil
+}
+
+// collectStaticInfo reads the CPU model, topology and virtualization
+func collectStaticInfo() (*StaticInfo, error) {
+	cpuStats, err := gopsutilCPU.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate if CPU data exists
+	if len(cpuStats) == 0 {
+		return nil, fmt.Errorf("no CPU information available")
+	}
+
+	// Check if system is running on a VM
+	virtualizationSystem, virtualizationRole, err := host.Virtualization()
+	if err != nil {
+		return nil, err
+	}
+
+	// Count unique physical CPU packages
+	physicalIDs := make(map[string]bool)
+	for _, cpu := range cpuStats {
+		physicalIDs[cpu.PhysicalID] = true
+	}
+
+	// Use the first CPU for model, core, cache, and flags info
+	first := cpuStats[0]
+	info := &StaticInfo{
+		ModelName:      first.ModelName,
+		Cores:          int(first.Cores),
+		Threads:        len(cpuStats),
+		CacheSize:      int(first.CacheSize),
+		IsVirtual:      virtualizationRole == "guest", // If role is "guest", it's a VM
+		Hypervisor:     virtualizationSystem,
+		VendorID:       first.VendorID,
+		Family:         first.Family,
+		Stepping:       int(first.Stepping),
+		Flags:          first.Flags,
+		PhysicalID:     first.PhysicalID,
+		Microcode:      first.Microcode,
+		Architecture:   runtime.GOARCH,
+		BaseClockSpeed: first.Mhz / 1000.0, // Convert MHz to GHz
+		ProcessorCount: len(physicalIDs),
+		CollectedAt:    time.Now(),
+	}
+
+	// Frequency limits come from cpufreq where available. Without it, at least set
+	// the maximum to the current speed.
+	info.MinFrequency, _ = readFrequency("cpuinfo_min_freq")
+	if maxFrequency, err := readFrequency("cpuinfo_max_freq"); err == nil {
+		info.MaxFrequency = maxFrequency
+	} else {
+		info.MaxFrequency = info.BaseClockSpeed
+	}
+
+	return info, nil
+}
+
+// readFrequency reads a cpufreq value of the first CPU in GHz
+func readFrequency(name string) (float64, error) {
+	data, err := os.ReadFile(filepath.Join(cpufreqDir, name))
+	if err != nil {
+		return 0, err
+	}
+
+	khz, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return khz / 1000000.0, nil
+}
+
+// staticFromInfo returns the static part of a combined CPU record
+func staticFromInfo(info *CPUInfo) *StaticInfo {
+	return &StaticInfo{
+		ModelName:      info.ModelName,
+		Cores:          info.Cores,
+		Threads:        info.Threads,
+		CacheSize:      info.CacheSize,
+		IsVirtual:      info.IsVirtual,
+		Hypervisor:     info.Hypervisor,
+		VendorID:       info.VendorID,
+		Family:         info.Family,
+		Stepping:       info.Stepping,
+		Flags:          info.Flags,
+		PhysicalID:     info.PhysicalID,
+		Microcode:      info.Microcode,
+		Architecture:   info.Architecture,
+		MinFrequency:   info.MinFrequency,
+		MaxFrequency:   info.MaxFrequency,
+		BaseClockSpeed: info.ClockSpeed,
+		ProcessorCount: info.ProcessorCount,
+	}
+}
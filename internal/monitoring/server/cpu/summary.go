@@ -35,10 +35,10 @@ func NewSummaryReporter(monitor *Monitor, cfg *config.Config) *SummaryReporter {
 		warningEvents:      0,
 		criticalEvents:     0,
 		peakCPUUsage:       0,
-		lastReportTime:     time.Now(),
+		lastReportTime:     monitor.clock.Now(),
 		reportingInterval:  interval,
 		highUsageDurations: make(map[string]time.Duration),
-		lastCheckTime:      time.Now(),
+		lastCheckTime:      monitor.clock.Now(),
 	}
 }
 
@@ -53,7 +53,7 @@ func (s *SummaryReporter) RecordEvent(info *CPUInfo) {
 	}
 
 	// Track durations in different states
-	now := time.Now()
+	now := s.monitor.clock.Now()
 	if !s.lastCheckTime.IsZero() && s.lastStatus != "" {
 		duration := now.Sub(s.lastCheckTime)
 		s.highUsageDurations[s.lastStatus] += duration
@@ -70,7 +70,7 @@ func (s *SummaryReporter) RecordEvent(info *CPUInfo) {
 	}
 
 	// Check if it's time to send a report
-	if time.Since(s.lastReportTime) >= s.reportingInterval {
+	if s.monitor.clock.Since(s.lastReportTime) >= s.reportingInterval {
 		s.sendSummaryReport()
 	}
 }
@@ -78,7 +78,7 @@ func (s *SummaryReporter) RecordEvent(info *CPUInfo) {
 // sendSummaryReport sends a summary report via email
 func (s *SummaryReporter) sendSummaryReport() {
 	// Reset last report time first to prevent duplicate reports
-	s.lastReportTime = time.Now()
+	s.lastReportTime = s.monitor.clock.Now()
 
 	// Create summary content
 	serverInfo := alerts.GetServerInfoForAlert()
@@ -32,6 +32,19 @@ type CPUInfo struct {
 	CPUTimes       map[string]float64 `json:"cpu_times"`       // CPU time breakdown (user, system, idle)
 	Temperature    float64            `json:"temperature"`     // CPU temperature if available
 	ProcessorCount int                `json:"processor_count"` // Number of physical processor packages
+
+	// Steal, iowait, core hotspot and load rules, set by the monitor's checks
+	Rules []RuleStatus `json:"rules,omitempty"`
+}
+
+// DynamicInfo holds the CPU values that are sampled on every collection
+type DynamicInfo struct {
+	Usage       float64            `json:"usage"`       // Total CPU usage percentage
+	CoreUsage   []float64          `json:"core_usage"`  // Per-core usage percentage
+	ClockSpeed  float64            `json:"clock_speed"` // Current frequency in GHz
+	CPUTimes    map[string]float64 `json:"cpu_times"`   // CPU time breakdown (user, system, idle)
+	Temperature float64            `json:"temperature"` // CPU temperature if available
+	CPUStatus   string             `json:"cpu_status"`  // Status CPU: normal, warning, critical
 }
 
 // CPUMetricsMsg is the message structure for WebSocket updates
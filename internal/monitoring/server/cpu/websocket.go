@@ -3,28 +3,106 @@ package cpu
 import (
 	"CheckHealthDO/internal/pkg/logger"
 	"CheckHealthDO/internal/websocket"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// WebSocketHandler handles WebSocket connections for CPU monitoring
+// WebSocketHandler handles WebSocket connections for CPU monitoring. A new client
+// gets the static facts and the sample of the last scheduled check; collection
+// stays with the scheduler, since every check also runs the rules and alerts.
 func (m *Monitor) WebSocketHandler(c *gin.Context) {
 	// Initialize the WebSocket registry if needed
 	registry := websocket.GetRegistry()
-	handler := registry.GetCPUHandler()
+	handler := registry.HandlerFor(websocket.TopicCPU)
 
-	// Create a new handler if one doesn't exist
-	if handler == nil {
-		handler = websocket.NewHandler()
-		registry.RegisterCPUHandler(handler)
+	// Let the central registry handle the WebSocket connection, starting with the
+	// static facts that are not repeated in the per-check messages
+	handler.ServeWithInitialMessage(c.Writer, c.Request, m.staticMessage(), m.latestMessage())
+
+	logger.Info("New WebSocket client connected for CPU monitoring",
+		logger.String("client_ip", c.ClientIP()))
+}
+
+// latestMessage encodes the sample of the last check for a new client, nil before the first check
+func (m *Monitor) latestMessage() []byte {
+	m.mutex.Lock()
+	info, checkedAt := m.lastInfo, m.lastCheck
+	m.mutex.Unlock()
+
+	if info == nil {
+		return nil
+	}
+	data, err := websocket.CPUMessage(m.cpuMessage(info, checkedAt))
+	if err != nil {
+		logger.Error("Failed to marshal CPU info for WebSocket",
+			logger.String("error", err.Error()))
+		return nil
 	}
+	return data
+}
 
-	// Force an immediate CPU check to get fresh data
-	m.CheckCPU()
+// cpuMessage builds the per-check CPU message. Static facts are left out; clients
+// get them once on connect or from the static REST endpoint and match them by
+// collection time.
+func (m *Monitor) cpuMessage(info *CPUInfo, timestamp time.Time) map[string]interface{} {
+	var staticCollectedAt time.Time
+	if static, err := m.collector.Static(false); err == nil {
+		staticCollectedAt = static.CollectedAt
+	}
 
-	// Let the central registry handle the WebSocket connection
-	handler.ServeHTTP(c.Writer, c.Request)
+	// Create a completely separate metrics structure to ensure no overlap with memory data
+	// Using a deeply nested structure with explicit metric type identification
+	return map[string]interface{}{
+		"metric_type": "cpu", // Explicit identifier for the metric type
+		"metrics_data": map[string]interface{}{
+			"cpu_info": map[string]interface{}{
+				"clock_speed":         info.ClockSpeed,
+				"usage":               info.Usage,
+				"core_usage":          info.CoreUsage,
+				"cpu_status":          info.CPUStatus,
+				"status":              info.CPUStatus,
+				"cpu_times":           info.CPUTimes,
+				"temperature":         info.Temperature,
+				"rules":               info.Rules,
+				"static_collected_at": staticCollectedAt,
+			},
+		},
+		"meta": cpuMessageMeta(timestamp, timestamp.Format(time.RFC3339)),
+	}
+}
 
-	logger.Info("New WebSocket client connected for CPU monitoring",
-		logger.String("client_ip", c.ClientIP()))
+// staticMessage encodes the static CPU facts for a new client, nil if they are unavailable
+func (m *Monitor) staticMessage() []byte {
+	static, err := m.collector.Static(false)
+	if err != nil {
+		logger.Error("Failed to get static CPU info",
+			logger.String("error", err.Error()))
+		return nil
+	}
+
+	timestamp := m.clock.Now()
+	data, err := websocket.CPUMessage(map[string]interface{}{
+		"metric_type": "cpu_static",
+		"metrics_data": map[string]interface{}{
+			"cpu_static": static,
+		},
+		"meta": cpuMessageMeta(timestamp, timestamp.Format(time.RFC3339)),
+	})
+	if err != nil {
+		logger.Error("Failed to marshal static CPU info for WebSocket",
+			logger.String("error", err.Error()))
+		return nil
+	}
+	return data
+}
+
+// cpuMessageMeta returns the meta block of CPU WebSocket messages
+func cpuMessageMeta(timestamp time.Time, formattedTime string) map[string]interface{} {
+	return map[string]interface{}{
+		"timestamp":        timestamp,
+		"last_update_time": formattedTime,
+		"source":           "cpu_monitor",
+		"version":          "1.0",
+	}
 }
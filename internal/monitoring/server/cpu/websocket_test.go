@@ -0,0 +1,93 @@
+package cpu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// connect serves the monitor's WebSocket handler and dials it
+func connect(t *testing.T, m *Monitor) *gorilla.Conn {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/ws/cpu", m.WebSocketHandler)
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+
+	conn, _, err := gorilla.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/cpu", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readMetricType reads one message and returns its metric type and CPU data
+func readMetricType(t *testing.T, conn *gorilla.Conn) (string, map[string]interface{}) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message struct {
+		CPU struct {
+			MetricType  string                 `json:"metric_type"`
+			MetricsData map[string]interface{} `json:"metrics_data"`
+		} `json:"cpu"`
+	}
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatal(err)
+	}
+	return message.CPU.MetricType, message.CPU.MetricsData
+}
+
+func TestWebSocketSendsCachedSample(t *testing.T) {
+	env := newTestEnv(nil)
+	env.collector.PushUsage(50)
+	if err := env.monitor.CheckCPU(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	env.collector.PushUsage(95) // Would raise a critical alert if a connect collected
+
+	conn := connect(t, env.monitor)
+	if metricType, _ := readMetricType(t, conn); metricType != "cpu_static" {
+		t.Fatalf("first message = %s, want cpu_static", metricType)
+	}
+	metricType, data := readMetricType(t, conn)
+	if metricType != "cpu" {
+		t.Fatalf("second message = %s, want cpu", metricType)
+	}
+	encoded, _ := json.Marshal(data["cpu_info"])
+	if !strings.Contains(string(encoded), `"usage":50`) {
+		t.Errorf("cpu_info = %s, want the cached sample", encoded)
+	}
+
+	if info := env.monitor.GetLastCPUInfo(); info.Usage != 50 {
+		t.Errorf("last usage = %.0f, want the connect not to collect", info.Usage)
+	}
+	if subjects := env.Recorder.Subjects(); len(subjects) != 0 {
+		t.Errorf("notifications = %q, want none from connecting", subjects)
+	}
+}
+
+func TestWebSocketBeforeFirstCheck(t *testing.T) {
+	env := newTestEnv(nil)
+	env.collector.PushUsage(95)
+
+	conn := connect(t, env.monitor)
+	if metricType, _ := readMetricType(t, conn); metricType != "cpu_static" {
+		t.Fatalf("first message = %s, want cpu_static", metricType)
+	}
+
+	// No sample is cached yet, so nothing follows until the scheduler checks
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, message, err := conn.ReadMessage(); err == nil {
+		t.Errorf("unexpected message %s before the first check", message)
+	}
+	if info := env.monitor.GetLastCPUInfo(); info != nil {
+		t.Errorf("last info = %+v, want no collection on connect", info)
+	}
+}
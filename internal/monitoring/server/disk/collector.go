@@ -0,0 +1,72 @@
+package disk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Collector takes the storage samples the monitor checks
+type Collector interface {
+	Collect(ctx context.Context) ([]StorageInfo, *TotalStorage, error)
+}
+
+// SystemCollector reads the mounted partitions through gopsutil
+type SystemCollector struct{}
+
+// Collect implements Collector
+func (SystemCollector) Collect(ctx context.Context) ([]StorageInfo, *TotalStorage, error) {
+	return GetStorageInfo(ctx)
+}
+
+// FakeCollector replays queued samples for tests and simulations. Once the queue
+// is drained the last sample repeats.
+type FakeCollector struct {
+	mu      sync.Mutex
+	samples [][]StorageInfo
+	last    []StorageInfo
+	err     error
+}
+
+// NewFakeCollector creates a fake collector with one sample of the given disks queued
+func NewFakeCollector(disks ...StorageInfo) *FakeCollector {
+	f := &FakeCollector{}
+	if len(disks) > 0 {
+		f.Push(disks...)
+	}
+	return f
+}
+
+// Push queues one sample made of the given disks
+func (f *FakeCollector) Push(disks ...StorageInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.samples = append(f.samples, append([]StorageInfo{}, disks...))
+}
+
+// SetError makes Collect fail with err until it is cleared with nil
+func (f *FakeCollector) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// Collect implements Collector. The totals are computed from the disks.
+func (f *FakeCollector) Collect(ctx context.Context) ([]StorageInfo, *TotalStorage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	if len(f.samples) > 0 {
+		f.last = f.samples[0]
+		f.samples = f.samples[1:]
+	}
+	if f.last == nil {
+		return nil, nil, fmt.Errorf("fake disk collector has no samples")
+	}
+
+	disks := append([]StorageInfo{}, f.last...)
+	return disks, totalStorage(disks), nil
+}
@@ -1,6 +1,7 @@
 package disk
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,13 +12,13 @@ import (
 var getStorageInfoFunc = getStorageInfo
 
 // GetStorageInfo is a wrapper around getStorageInfo for easy mocking in tests
-func GetStorageInfo() ([]StorageInfo, *TotalStorage, error) {
-	return getStorageInfoFunc()
+func GetStorageInfo(ctx context.Context) ([]StorageInfo, *TotalStorage, error) {
+	return getStorageInfoFunc(ctx)
 }
 
 // Function to get disk I/O information
-func getDiskIO(deviceName string) (*DiskIOInfo, error) {
-	ioCounters, err := disk.IOCounters()
+func getDiskIO(ctx context.Context, deviceName string) (*DiskIOInfo, error) {
+	ioCounters, err := disk.IOCountersWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -68,24 +69,24 @@ func getDiskIO(deviceName string) (*DiskIOInfo, error) {
 	return nil, nil
 }
 
-// getStorageInfo is the actual implementation of storage info retrieval
-func getStorageInfo() ([]StorageInfo, *TotalStorage, error) {
+// getStorageInfo is the actual implementation of storage info retrieval. It gives
+// up once ctx ends, so a hung network mount cannot hold the check past its deadline.
+func getStorageInfo(ctx context.Context) ([]StorageInfo, *TotalStorage, error) {
 	// Ambil daftar partisi
-	partitions, err := disk.Partitions(true)
+	partitions, err := disk.PartitionsWithContext(ctx, true)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var storageInfos []StorageInfo
-	var totalCapacity, totalUsed, totalFree uint64
-	var totalCapacityInternal, totalUsedInternal, totalFreeInternal uint64
-	var totalCapacityExternal, totalUsedExternal, totalFreeExternal uint64
-	var totalDeviceInternal, totalDeviceExternal int
-	var hasInternalStorage bool = false
 
 	// Iterasi setiap partisi untuk mendapatkan informasi detail
 	for _, partition := range partitions {
-		usageStat, err := disk.Usage(partition.Mountpoint)
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		usageStat, err := disk.UsageWithContext(ctx, partition.Mountpoint)
 		if err != nil {
 			// Jika ada error pada partisi tertentu, lanjutkan ke partisi berikutnya
 			fmt.Printf("Error getting usage for partition %s: %v\n", partition.Mountpoint, err)
@@ -93,7 +94,7 @@ func getStorageInfo() ([]StorageInfo, *TotalStorage, error) {
 		}
 
 		// Get I/O information for this device
-		ioInfo, err := getDiskIO(partition.Device)
+		ioInfo, err := getDiskIO(ctx, partition.Device)
 		if err != nil {
 			fmt.Printf("Error getting I/O stats for device %s: %v\n", partition.Device, err)
 			// Continue with nil I/O info
@@ -116,27 +117,37 @@ func getStorageInfo() ([]StorageInfo, *TotalStorage, error) {
 				IsExternal: isExternal,             // Set whether this is an external storage
 				IO:         ioInfo,                 // Add I/O information
 			})
+		}
+	}
 
-			// Count and track storage by type (internal vs external)
-			if isExternal {
-				totalCapacityExternal += usageStat.Total
-				totalUsedExternal += usageStat.Used
-				totalFreeExternal += usageStat.Free
-				totalDeviceExternal++
-			} else {
-				totalCapacityInternal += usageStat.Total
-				totalUsedInternal += usageStat.Used
-				totalFreeInternal += usageStat.Free
-				totalDeviceInternal++
-				hasInternalStorage = true
-			}
+	return storageInfos, totalStorage(storageInfos), nil
+}
+
+// totalStorage sums the partitions into internal, external and combined totals.
+// It returns nil when there is no internal storage.
+func totalStorage(storageInfos []StorageInfo) *TotalStorage {
+	var totalCapacityInternal, totalUsedInternal, totalFreeInternal uint64
+	var totalCapacityExternal, totalUsedExternal, totalFreeExternal uint64
+	var totalDeviceInternal, totalDeviceExternal int
+	var hasInternalStorage bool = false
+
+	for _, info := range storageInfos {
+		if info.Total == 0 {
+			continue
 		}
 
-		// Existing code for tracking total (internal only) is still needed for backward compatibility
-		if !isExternal && usageStat.Total > 0 {
-			totalCapacity += usageStat.Total
-			totalUsed += usageStat.Used
-			totalFree += usageStat.Free
+		// Count and track storage by type (internal vs external)
+		if info.IsExternal {
+			totalCapacityExternal += info.Total
+			totalUsedExternal += info.Used
+			totalFreeExternal += info.Free
+			totalDeviceExternal++
+		} else {
+			totalCapacityInternal += info.Total
+			totalUsedInternal += info.Used
+			totalFreeInternal += info.Free
+			totalDeviceInternal++
+			hasInternalStorage = true
 		}
 	}
 
@@ -152,8 +163,8 @@ func getStorageInfo() ([]StorageInfo, *TotalStorage, error) {
 	}
 
 	// Jika tidak ada storage internal, return nil untuk TotalStorage
-	if !hasInternalStorage || totalCapacity == 0 {
-		return storageInfos, nil, nil
+	if !hasInternalStorage || totalCapacityInternal == 0 {
+		return nil
 	}
 
 	// Calculate usage percentages for internal and external storage
@@ -165,8 +176,8 @@ func getStorageInfo() ([]StorageInfo, *TotalStorage, error) {
 		totalUsagePercentExternal = (float64(totalUsedExternal) / float64(totalCapacityExternal)) * 100
 	}
 
-	// Kembalikan informasi storage dan total storage (removed monitoredPathInfos)
-	return storageInfos, &TotalStorage{
+	// Kembalikan total storage
+	return &TotalStorage{
 		TotalCapacity: combinedTotalCapacity,
 		TotalUsed:     combinedTotalUsed,
 		TotalFree:     combinedTotalFree,
@@ -185,5 +196,5 @@ func getStorageInfo() ([]StorageInfo, *TotalStorage, error) {
 		TotalFreeExternal:         totalFreeExternal,
 		TotalDeviceExternal:       totalDeviceExternal,
 		TotalUsagePercentExternal: totalUsagePercentExternal,
-	}, nil
+	}
 }
@@ -1,8 +1,11 @@
 package disk
 
 import (
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/pkg/clock"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
 	"CheckHealthDO/internal/websocket"
 	"context"
 	"fmt"
@@ -13,22 +16,38 @@ import (
 // Monitor handles periodic storage monitoring
 type Monitor struct {
 	config    *config.Config
-	ticker    *time.Ticker
+	cancelJob func() // Removes the checks from the scheduler
 	stopChan  chan struct{}
 	isRunning bool
 	mutex     sync.Mutex
 	lastInfo  []StorageInfo // Changed from *StorageInfo to []StorageInfo
+	lastCheck time.Time     // When the last check finished
+	collector Collector     // Source of samples, SystemCollector unless replaced
+	clock     clock.Clock   // Time source for sample timestamps
 }
 
 // NewMonitor creates a new storage monitor instance
 func NewMonitor(cfg *config.Config) *Monitor {
 	m := &Monitor{
-		config:   cfg,
-		stopChan: make(chan struct{}),
+		config:    cfg,
+		stopChan:  make(chan struct{}),
+		collector: SystemCollector{},
+		clock:     clock.System,
 	}
 	return m
 }
 
+// SetCollector replaces the source of storage samples, e.g. with a FakeCollector.
+// Call it before StartMonitoring.
+func (m *Monitor) SetCollector(collector Collector) {
+	m.collector = collector
+}
+
+// SetClock replaces the clock used to timestamp samples. Call it before StartMonitoring.
+func (m *Monitor) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
 // StartMonitoring begins the storage monitoring process
 func (m *Monitor) StartMonitoring() error {
 	m.mutex.Lock()
@@ -44,7 +63,17 @@ func (m *Monitor) StartMonitoring() error {
 	}
 
 	interval := time.Duration(m.config.Monitoring.Disk.CheckInterval) * time.Second
-	m.ticker = time.NewTicker(interval)
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return m.CheckStorage(ctx)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule disk checks: %w", err)
+	}
+	m.cancelJob = cancelJob
 	m.isRunning = true
 
 	logger.Info("Starting disk monitor",
@@ -52,21 +81,6 @@ func (m *Monitor) StartMonitoring() error {
 		logger.Float64("warning_threshold", m.config.Monitoring.Disk.WarningThreshold),
 		logger.Float64("critical_threshold", m.config.Monitoring.Disk.CriticalThreshold))
 
-	// Run the first check immediately, then continue at intervals
-	go func() {
-		m.checkStorageInfo()
-
-		for {
-			select {
-			case <-m.ticker.C:
-				m.checkStorageInfo()
-			case <-m.stopChan:
-				m.ticker.Stop()
-				return
-			}
-		}
-	}()
-
 	return nil
 }
 
@@ -79,11 +93,44 @@ func (m *Monitor) StopMonitoring() {
 		return
 	}
 
+	m.cancelJob()
 	close(m.stopChan)
 	m.isRunning = false
 	logger.Info("Disk monitor stopped")
 }
 
+// Name returns "disk"
+func (m *Monitor) Name() string {
+	return "disk"
+}
+
+// Start samples the partitions on the configured interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes the disk checks from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns the []StorageInfo of the latest sample, one entry per partition
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetLastStorageInfo()
+}
+
+// Health compares the last check with monitoring.disk.check_interval
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	interval := time.Duration(m.config.Monitoring.Disk.CheckInterval) * time.Second
+	return monitoring.NewHealth(m.isRunning, m.lastCheck, interval)
+}
+
 // StartBackgroundMonitor creates and starts a Disk monitor in a background goroutine
 // Returns a function to stop monitoring
 func StartBackgroundMonitor(ctx context.Context, cfg *config.Config) (func(), error) {
@@ -104,6 +151,18 @@ func (m *Monitor) GetConfig() *config.Config {
 	return m.config
 }
 
+// GetLastStorageInfo returns the disks seen by the most recent check
+func (m *Monitor) GetLastStorageInfo() []StorageInfo {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastInfo
+}
+
+// DiskStatus returns normal, warning or critical for a disk under the configured thresholds
+func (m *Monitor) DiskStatus(info StorageInfo) string {
+	return determineDiskStatus(info.Usage, m.config)
+}
+
 // formatBytes converts bytes to a human-readable string
 func formatBytes(bytes uint64) string {
 	const unit = 1024
@@ -118,24 +177,30 @@ func formatBytes(bytes uint64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-// checkStorageInfo performs a single storage check
-func (m *Monitor) checkStorageInfo() {
+// CheckStorage performs a single storage check, bounded by ctx
+func (m *Monitor) CheckStorage(ctx context.Context) error {
 	// Get storage information with monitored paths analysis
-	infoSlice, totalStorage, err := GetStorageInfo()
+	infoSlice, totalStorage, err := m.collector.Collect(ctx)
 	if err != nil {
 		logger.Error("Failed to get storage info",
 			logger.String("error", err.Error()))
-		return
+		return err
+	}
+
+	// Hosts with only external storage have no totals
+	if totalStorage == nil {
+		totalStorage = &TotalStorage{}
 	}
 
 	// Lock before modifying shared data
 	m.mutex.Lock()
 	// Store the latest metrics
 	m.lastInfo = infoSlice
+	m.lastCheck = m.clock.Now()
 	m.mutex.Unlock()
 
 	// Format timestamp consistently for all messages
-	timestamp := time.Now()
+	timestamp := m.clock.Now()
 	formattedTime := timestamp.Format(time.RFC3339)
 
 	// Create a slice to hold disk information
@@ -211,9 +276,11 @@ func (m *Monitor) checkStorageInfo() {
 
 	// Broadcast to disk-specific WebSocket
 	registry := websocket.GetRegistry()
-	if handler := registry.GetDiskHandler(); handler != nil {
+	if handler := registry.GetHandler(websocket.TopicDisk); handler != nil {
 		registry.BroadcastDisk(combinedMsg)
 	}
+
+	return nil
 }
 
 // determineDiskStatus determines the status of a disk based on usage percentage
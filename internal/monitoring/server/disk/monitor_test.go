@@ -0,0 +1,108 @@
+package disk
+
+import (
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	testsupport.Main(m)
+}
+
+// partition returns a partition of total bytes with used bytes in use
+func partition(mount string, total, used uint64, external bool) StorageInfo {
+	return StorageInfo{
+		Device:     "/dev/vda",
+		MountPoint: mount,
+		FileSystem: "ext4",
+		Total:      total,
+		Used:       used,
+		Free:       total - used,
+		Usage:      float64(used) / float64(total) * 100,
+		IsExternal: external,
+	}
+}
+
+func TestFakeCollector(t *testing.T) {
+	root := partition("/", 100, 40, false)
+	backup := partition("/mnt/backup", 300, 240, true)
+
+	fake := NewFakeCollector(root)
+	fake.Push(root, backup)
+
+	disks, total, err := fake.Collect(context.Background())
+	if err != nil || len(disks) != 1 || total.UsagePercent != 40 {
+		t.Fatalf("first sample = %v, %+v, %v, want the root disk at 40%%", disks, total, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		disks, total, err = fake.Collect(context.Background())
+		if err != nil || len(disks) != 2 {
+			t.Fatalf("sample %d = %v, %v, want the last sample repeated", i+2, disks, err)
+		}
+		if total.TotalCapacity != 400 || total.UsagePercent != 70 || total.TotalDeviceExternal != 1 || total.TotalUsagePercentExternal != 80 {
+			t.Errorf("sample %d totals = %+v, want 400 bytes at 70%% with one external disk at 80%%", i+2, total)
+		}
+	}
+
+	failure := errors.New("statfs failed")
+	fake.SetError(failure)
+	if _, _, err := fake.Collect(context.Background()); !errors.Is(err, failure) {
+		t.Errorf("err = %v, want the injected error", err)
+	}
+	fake.SetError(nil)
+	if _, _, err := fake.Collect(context.Background()); err != nil {
+		t.Errorf("err = %v, want the samples back after the error is cleared", err)
+	}
+
+	if _, _, err := NewFakeCollector().Collect(context.Background()); err == nil {
+		t.Error("want an error from an empty fake")
+	}
+}
+
+func TestCheckStorageUsesCollector(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Monitoring.Disk.CheckInterval = 60
+	cfg.Monitoring.Disk.WarningThreshold = 80
+	cfg.Monitoring.Disk.CriticalThreshold = 90
+
+	fake := NewFakeCollector(partition("/", 100, 95, false))
+	clk := clock.NewFake(testsupport.Start)
+	monitor := NewMonitor(cfg)
+	monitor.SetCollector(fake)
+	monitor.SetClock(clk)
+
+	if err := monitor.CheckStorage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	last := monitor.GetLastStorageInfo()
+	if len(last) != 1 || monitor.DiskStatus(last[0]) != "critical" {
+		t.Errorf("last sample = %+v, want the root disk critical", last)
+	}
+
+	fake.Push(partition("/", 100, 50, false))
+	clk.Advance(time.Minute)
+	if err := monitor.CheckStorage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if last := monitor.GetLastStorageInfo(); monitor.DiskStatus(last[0]) != "normal" {
+		t.Errorf("last sample = %+v, want the root disk back to normal", last)
+	}
+
+	fake.SetError(errors.New("statfs failed"))
+	clk.Advance(time.Minute)
+	if err := monitor.CheckStorage(context.Background()); err == nil {
+		t.Error("want the collector error returned")
+	}
+	if last := monitor.GetLastStorageInfo(); len(last) != 1 || last[0].Used != 50 {
+		t.Errorf("last sample = %+v, want the previous sample kept after a failed check", last)
+	}
+	if got := monitor.lastCheck; !got.Equal(testsupport.Start.Add(time.Minute)) {
+		t.Errorf("lastCheck = %v, want the time of the last successful check", got)
+	}
+}
@@ -18,16 +18,10 @@ func (m *Monitor) WebSocketHandler(c *gin.Context) {
 
 	// Initialize the WebSocket registry if needed
 	registry := websocket.GetRegistry()
-	handler := registry.GetDiskHandler()
-
-	// Create a new handler if one doesn't exist
-	if handler == nil {
-		handler = websocket.NewHandler()
-		registry.RegisterDiskHandler(handler)
-	}
+	handler := registry.HandlerFor(websocket.TopicDisk)
 
 	// Force an immediate status check to get fresh data
-	m.checkStorageInfo()
+	m.CheckStorage(c.Request.Context())
 
 	// Let the central registry handle the WebSocket connection
 	handler.ServeHTTP(c.Writer, c.Request)
@@ -4,11 +4,10 @@ import (
 	"CheckHealthDO/internal/alerts"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/runner"
 	"CheckHealthDO/internal/services/mariadb"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -76,14 +75,14 @@ func NewAlertHandler(monitor *Monitor) *AlertHandler {
 		warningEscalation:    warningEscalation, // Only notify after consecutive warnings
 		pendingWarnings:      make([]MemoryInfo, 0),
 		aggregationInterval:  aggregationInterval, // Use from config
-		lastAggregationTime:  time.Now(),
+		lastAggregationTime:  monitor.clock.Now(),
 		// Anti-spam settings
 		warningThrottleWindow: warningThrottleWindow,
 		criticalThrottleCount: criticalThrottleCount, // Use from config
 		currentCriticalCount:  0,
 		maxWarningsPerDay:     maxWarningsPerDay, // Use from config
 		warningsSentToday:     0,
-		lastDayReset:          time.Now(),
+		lastDayReset:          monitor.clock.Now(),
 		lastInfo:              nil,
 	}
 }
@@ -93,7 +92,7 @@ func (a *AlertHandler) HandleWarningAlert(info *MemoryInfo, statusChanged bool)
 	var counter *int = &a.handler.SuppressedWarningCount
 
 	// Check if we need to reset the daily counter
-	now := time.Now()
+	now := a.monitor.clock.Now()
 	if now.YearDay() != a.lastDayReset.YearDay() || now.Year() != a.lastDayReset.Year() {
 		a.warningsSentToday = 0
 		a.lastDayReset = now
@@ -104,7 +103,7 @@ func (a *AlertHandler) HandleWarningAlert(info *MemoryInfo, statusChanged bool)
 		logger.Info("Memory entered warning state",
 			logger.Float64("usage_percent", info.UsedMemoryPercentage),
 			logger.String("status", info.MemoryStatus),
-			logger.String("timestamp", time.Now().Format(time.RFC3339)),
+			logger.String("timestamp", a.monitor.clock.Now().Format(time.RFC3339)),
 			logger.Bool("notification_will_be_sent", false))
 	}
 
@@ -116,14 +115,16 @@ func (a *AlertHandler) HandleWarningAlert(info *MemoryInfo, statusChanged bool)
 		// This is an improvement, just log it but don't send notification
 		logger.Info("Memory improved from critical to warning state",
 			logger.Float64("usage_percent", info.UsedMemoryPercentage))
+		a.handler.ReportSuppressed("Memory improved from critical to warning")
 		return
 	}
 
 	// Throttle based on our custom window - only one warning alert per warningThrottleWindow
-	if !a.lastWarningAlertTime.IsZero() && time.Since(a.lastWarningAlertTime) < a.warningThrottleWindow {
+	if !a.lastWarningAlertTime.IsZero() && a.monitor.clock.Since(a.lastWarningAlertTime) < a.warningThrottleWindow {
 		// logger.Debug("Suppressing memory warning notification due to throttle window",
-		// 	logger.Int("minutes_since_last", int(time.Since(a.lastWarningAlertTime).Minutes())),
+		// 	logger.Int("minutes_since_last", int(a.monitor.clock.Since(a.lastWarningAlertTime).Minutes())),
 		// 	logger.Int("throttle_window_minutes", int(a.warningThrottleWindow.Minutes())))
+		a.handler.ReportSuppressed("Memory warning within the throttle window")
 		*counter++
 		return
 	}
@@ -133,6 +134,7 @@ func (a *AlertHandler) HandleWarningAlert(info *MemoryInfo, statusChanged bool)
 		logger.Info("Daily warning notification limit reached",
 			logger.Int("max_warnings_per_day", a.maxWarningsPerDay),
 			logger.Int("warnings_sent_today", a.warningsSentToday))
+		a.handler.ReportSuppressed("Daily memory warning limit reached")
 		return
 	}
 
@@ -144,7 +146,7 @@ func (a *AlertHandler) HandleWarningAlert(info *MemoryInfo, statusChanged bool)
 		a.pendingWarnings = append(a.pendingWarnings, *info)
 
 		// Only send aggregated alert if enough time has passed
-		if time.Since(a.lastAggregationTime) >= a.aggregationInterval && len(a.pendingWarnings) > 0 {
+		if a.monitor.clock.Since(a.lastAggregationTime) >= a.aggregationInterval && len(a.pendingWarnings) > 0 {
 			// Create an aggregated message
 			a.sendAggregatedWarningAlert()
 			return
@@ -155,6 +157,7 @@ func (a *AlertHandler) HandleWarningAlert(info *MemoryInfo, statusChanged bool)
 			logger.Debug("Memory warning suppressed due to escalation policy",
 				logger.Int("warning_count", a.warningCount),
 				logger.Int("escalation_threshold", a.warningEscalation))
+			a.handler.ReportSuppressed("Memory warning below the escalation threshold")
 			return
 		}
 	}
@@ -177,7 +180,7 @@ func (a *AlertHandler) sendWarningNotification(info *MemoryInfo, statusChanged b
 	a.warningsSentToday++
 
 	// Record the time we're sending this warning
-	a.lastWarningAlertTime = time.Now()
+	a.lastWarningAlertTime = a.monitor.clock.Now()
 
 	// Get server information using the common utility function
 	serverInfo := alerts.GetServerInfoForAlert()
@@ -226,7 +229,7 @@ func (a *AlertHandler) sendWarningNotification(info *MemoryInfo, statusChanged b
 }
 
 // HandleCriticalAlert handles critical level memory alerts
-func (a *AlertHandler) HandleCriticalAlert(info *MemoryInfo, statusChanged bool) {
+func (a *AlertHandler) HandleCriticalAlert(ctx context.Context, info *MemoryInfo, statusChanged bool) {
 	// Increment critical event counter
 	a.currentCriticalCount++
 
@@ -235,7 +238,7 @@ func (a *AlertHandler) HandleCriticalAlert(info *MemoryInfo, statusChanged bool)
 		logger.Info("Memory entered critical state",
 			logger.Float64("usage_percent", info.UsedMemoryPercentage),
 			logger.String("status", info.MemoryStatus),
-			logger.String("timestamp", time.Now().Format(time.RFC3339)),
+			logger.String("timestamp", a.monitor.clock.Now().Format(time.RFC3339)),
 			logger.Int("consecutive_critical_events", a.currentCriticalCount),
 			logger.Int("threshold_for_alert", a.criticalThrottleCount))
 	}
@@ -280,7 +283,7 @@ func (a *AlertHandler) HandleCriticalAlert(info *MemoryInfo, statusChanged bool)
 		// Send notification
 		a.handler.SendNotifications("CRITICAL Memory Alert", message, "critical")
 		a.monitor.UpdateLastAlertTime()
-		a.lastCriticalAlertTime = time.Now()
+		a.lastCriticalAlertTime = a.monitor.clock.Now()
 		return
 	}
 
@@ -301,7 +304,7 @@ func (a *AlertHandler) HandleCriticalAlert(info *MemoryInfo, statusChanged bool)
 	}
 
 	// Add system load information if available
-	if loadAvg, err := getSystemLoadAvg(); err == nil && len(loadAvg) >= 3 {
+	if loadAvg, err := a.monitor.collector.LoadAverage(); err == nil && len(loadAvg) >= 3 {
 		additionalContent += fmt.Sprintf(`
 		<div style="background-color: #f2dede; border-left: 5px solid #d9534f; padding: 10px; margin: 10px 0;">
 			<p><b>SYSTEM LOAD:</b> 1-min: %.2f, 5-min: %.2f, 15-min: %.2f</p>
@@ -329,11 +332,11 @@ func (a *AlertHandler) HandleCriticalAlert(info *MemoryInfo, statusChanged bool)
 
 	// Perform recovery actions if configured to do so
 	if cfg.Monitoring.MariaDB.RestartOnThreshold.Enabled {
-		a.performRecoveryActions(info)
+		a.performRecoveryActions(ctx, info)
 	}
 
 	// Update the last critical alert time
-	a.lastCriticalAlertTime = time.Now()
+	a.lastCriticalAlertTime = a.monitor.clock.Now()
 
 	// Reset the counter after alert is sent
 	a.currentCriticalCount = 0
@@ -348,12 +351,15 @@ func (a *AlertHandler) HandleNormalAlert(info *MemoryInfo, statusChanged bool) {
 	a.warningCount = 0
 	a.currentCriticalCount = 0
 
-	// Store current info for comparison in next cycle
+	// Compare with the previous info before storing the current one for the next cycle.
+	// An improvement from critical to warning keeps the critical info, so the notice
+	// is also sent when memory recovers through the warning range.
+	previous := a.lastInfo
 	a.lastInfo = info
 
 	// Only send notification if the status has changed from critical to normal
 	// Don't send notifications for warning->normal transitions to reduce spam
-	if !statusChanged || (a.lastInfo != nil && a.lastInfo.MemoryStatus != "critical") {
+	if !statusChanged || previous == nil || previous.MemoryStatus != "critical" {
 		return
 	}
 
@@ -371,16 +377,17 @@ func (a *AlertHandler) HandleNormalAlert(info *MemoryInfo, statusChanged bool) {
 	logger.Info("Memory returned to normal state",
 		logger.Float64("usage_percent", info.UsedMemoryPercentage),
 		logger.String("status", info.MemoryStatus),
-		logger.String("timestamp", time.Now().Format(time.RFC3339)),
-		logger.Bool("notification_will_be_sent", time.Since(a.lastNormalAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
+		logger.String("timestamp", a.monitor.clock.Now().Format(time.RFC3339)),
+		logger.Bool("notification_will_be_sent", a.monitor.clock.Since(a.lastNormalAlertTime) >= time.Duration(cooldownPeriod)*time.Second))
 
 	// Apply throttling even for normal status
 	if !a.lastNormalAlertTime.IsZero() {
-		sinceLastNormal := time.Since(a.lastNormalAlertTime)
+		sinceLastNormal := a.monitor.clock.Since(a.lastNormalAlertTime)
 		if sinceLastNormal < time.Duration(cooldownPeriod)*time.Second {
 			logger.Debug("Suppressing memory normal notification due to cooldown",
 				logger.Int("seconds_since_last", int(sinceLastNormal.Seconds())),
 				logger.Int("cooldown_period", cooldownPeriod))
+			a.handler.ReportSuppressed("Memory normal within the cooldown period")
 			return
 		}
 	}
@@ -416,7 +423,7 @@ func (a *AlertHandler) HandleNormalAlert(info *MemoryInfo, statusChanged bool) {
 	a.monitor.UpdateLastAlertTime()
 
 	// Update the last normal alert time
-	a.lastNormalAlertTime = time.Now()
+	a.lastNormalAlertTime = a.monitor.clock.Now()
 
 	logger.Info("Sent memory normalized notification",
 		logger.Float64("usage_percent", info.UsedMemoryPercentage))
@@ -447,7 +454,7 @@ func (a *AlertHandler) createMemoryTableContent(info *MemoryInfo) string {
 	}
 
 	// Add any available memory info if present
-	availableMemory := GetAvailableMemory()
+	availableMemory := info.AvailableMemory
 	if availableMemory > 0 {
 		availableMemoryGB := float64(availableMemory) / 1024 / 1024 / 1024
 		tableRows = append(tableRows, alerts.TableRow{
@@ -498,8 +505,9 @@ func (a *AlertHandler) createMemoryTableContent(info *MemoryInfo) string {
 }
 
 // performRecoveryActions takes steps to reduce memory usage
-func (a *AlertHandler) performRecoveryActions(info *MemoryInfo) {
+func (a *AlertHandler) performRecoveryActions(ctx context.Context, info *MemoryInfo) {
 	logger.Info("Performing memory recovery actions due to critical memory usage")
+	a.handler.ReportAction("Memory recovery actions")
 	logger.Info("This is a critical situation that requires immediate attention. The system is attempting automatic recovery.")
 
 	// Get config with proper type assertion
@@ -517,75 +525,17 @@ func (a *AlertHandler) performRecoveryActions(info *MemoryInfo) {
 		serviceName := cfg.Monitoring.MariaDB.ServiceName
 
 		// Check if service is running first
-		isRunning, _ := mariadb.CheckServiceStatus(serviceName, nil)
+		isRunning, _ := mariadb.CheckServiceStatus(ctx, serviceName, nil)
 		if isRunning {
-			logger.Info("Attempting to restart MariaDB service to free memory",
-				logger.String("service", serviceName),
-				logger.Float64("memory_usage", info.UsedMemoryPercentage))
-
-			// Instead of creating temporary files, we'll:
-			// 1. Log a very distinctive message to system journal that we can search for later
-			// 2. Record the PID of MariaDB before restart to compare after
-
-			// Get current PID of MariaDB to detect actual process restart later
-			pidCmd := exec.Command("bash", "-c", "pgrep -f mysqld")
-			pidOutput, _ := pidCmd.CombinedOutput()
-			oldPid := strings.TrimSpace(string(pidOutput))
-
-			// Create persistent log directory if it doesn't exist
-			logDir := "logs"
-			os.MkdirAll(logDir, 0755)
-			logFile := filepath.Join(logDir, "mariadb_restarts.log")
-
-			// Log the event to a persistent location in the app logs directory
-			logEntry := fmt.Sprintf("[%s] Memory Critical Auto-Recovery: Memory usage was %.2f%% - PID before restart: %s\n",
-				time.Now().Format(time.RFC3339), info.UsedMemoryPercentage, oldPid)
-
-			// Append to log file (create if doesn't exist)
-			f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-			if err == nil {
-				f.WriteString(logEntry)
-				f.Close()
-			}
-
-			// Log to system journal with unique identifier
-			restartMsg := fmt.Sprintf("CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_%s: Restarting MariaDB due to critical memory usage (%.2f%%)",
-				time.Now().Format("20060102_150405"), info.UsedMemoryPercentage)
-			journalCmd := exec.Command("logger", "-t", "CheckHealthDO", restartMsg)
-			journalCmd.Run()
-
-			// Perform the actual restart
-			err = mariadb.RestartMariaDBService(serviceName)
-			if err != nil {
-				logger.Error("Failed to restart MariaDB service",
-					logger.String("error", err.Error()))
+			// The restart policy protects against restarting the service in a loop
+			decision := mariadb.GetRestartPolicy(cfg).Allow("Memory Critical Auto-Recovery")
+			if decision.Allowed {
+				a.restartMariaDB(ctx, serviceName, info)
 			} else {
-				logger.Info("Successfully restarted MariaDB service due to memory conditions")
-
-				// Verify the restart by checking if the PID changed
-				time.Sleep(2 * time.Second) // Give it a moment to restart
-
-				newPidCmd := exec.Command("bash", "-c", "pgrep -f mysqld")
-				newPidOutput, _ := newPidCmd.CombinedOutput()
-				newPid := strings.TrimSpace(string(newPidOutput))
-
-				if oldPid != newPid {
-					restartCompletedMsg := fmt.Sprintf("CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_COMPLETED_%s: PID before: %s, PID after: %s",
-						time.Now().Format("20060102_150405"), oldPid, newPid)
-					logger.Info(restartCompletedMsg)
-
-					// Log completion to system journal too
-					journalCmd = exec.Command("logger", "-t", "CheckHealthDO", restartCompletedMsg)
-					journalCmd.Run()
-
-					// Update our log file with completion info
-					f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-					if err == nil {
-						f.WriteString(fmt.Sprintf("[%s] Restart completed - PID after restart: %s\n",
-							time.Now().Format(time.RFC3339), newPid))
-						f.Close()
-					}
-				}
+				logger.Warn("Skipping MariaDB restart to free memory",
+					logger.String("service", serviceName),
+					logger.String("reason", decision.Reason))
+				a.handler.ReportAction("MariaDB restart skipped: " + decision.Reason)
 			}
 		} else {
 			logger.Warn("MariaDB service is not running, no restart performed",
@@ -597,6 +547,68 @@ func (a *AlertHandler) performRecoveryActions(info *MemoryInfo) {
 	}
 }
 
+// restartMariaDB restarts the service to free memory and records the restart in the
+// application log and the system journal
+func (a *AlertHandler) restartMariaDB(ctx context.Context, serviceName string, info *MemoryInfo) {
+	// A restart that has begun is seen through when the check passes its deadline,
+	// the runner bounds each command on its own
+	ctx = context.WithoutCancel(ctx)
+
+	logger.Info("Attempting to restart MariaDB service to free memory",
+		logger.String("service", serviceName),
+		logger.Float64("memory_usage", info.UsedMemoryPercentage))
+	a.handler.ReportAction("Restart of MariaDB service " + serviceName + " to free memory")
+
+	// Instead of creating temporary files, we'll:
+	// 1. Log a very distinctive message to system journal that we can search for later
+	// 2. Record the PID of MariaDB before restart to compare after
+
+	// Get current PID of MariaDB to detect actual process restart later
+	pidOutput, _ := runner.Output(ctx, "pgrep", "-f", "mysqld")
+	oldPid := strings.TrimSpace(string(pidOutput))
+
+	// Log the event to the restart log in the app logs directory
+	logEntry := fmt.Sprintf("Memory Critical Auto-Recovery (%s): Memory usage was %.2f%% - PID before restart: %s",
+		serviceName, info.UsedMemoryPercentage, oldPid)
+	if err := mariadb.RecordRestart(a.monitor.config, a.monitor.clock.Now(), logEntry); err != nil {
+		logger.Warn("Failed to record MariaDB restart", logger.String("error", err.Error()))
+	}
+
+	// Log to system journal with unique identifier
+	restartMsg := fmt.Sprintf("CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_%s: Restarting MariaDB (%s) due to critical memory usage (%.2f%%)",
+		a.monitor.clock.Now().Format("20060102_150405"), serviceName, info.UsedMemoryPercentage)
+	runner.Output(ctx, "logger", "-t", "CheckHealthDO", restartMsg)
+
+	// Perform the actual restart
+	if err := mariadb.RestartMariaDBService(ctx, serviceName); err != nil {
+		logger.Error("Failed to restart MariaDB service",
+			logger.String("error", err.Error()))
+	} else {
+		logger.Info("Successfully restarted MariaDB service due to memory conditions")
+
+		// Verify the restart by checking if the PID changed
+		time.Sleep(2 * time.Second) // Give it a moment to restart
+
+		newPidOutput, _ := runner.Output(ctx, "pgrep", "-f", "mysqld")
+		newPid := strings.TrimSpace(string(newPidOutput))
+
+		if oldPid != newPid {
+			restartCompletedMsg := fmt.Sprintf("CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_COMPLETED_%s: Restarted MariaDB (%s), PID before: %s, PID after: %s",
+				a.monitor.clock.Now().Format("20060102_150405"), serviceName, oldPid, newPid)
+			logger.Info(restartCompletedMsg)
+
+			// Log completion to system journal too
+			runner.Output(ctx, "logger", "-t", "CheckHealthDO", restartCompletedMsg)
+
+			// Update our log file with completion info
+			if err := mariadb.RecordRestart(a.monitor.config, a.monitor.clock.Now(),
+				fmt.Sprintf("Restart completed (%s) - PID after restart: %s", serviceName, newPid)); err != nil {
+				logger.Warn("Failed to record MariaDB restart", logger.String("error", err.Error()))
+			}
+		}
+	}
+}
+
 // sendAggregatedWarningAlert sends a single warning alert that summarizes multiple warnings
 func (a *AlertHandler) sendAggregatedWarningAlert() {
 	if len(a.pendingWarnings) == 0 {
@@ -653,11 +665,11 @@ func (a *AlertHandler) sendAggregatedWarningAlert() {
 	a.monitor.UpdateLastAlertTime()
 
 	// Update tracking state
-	a.lastAggregationTime = time.Now()
+	a.lastAggregationTime = a.monitor.clock.Now()
 	a.pendingWarnings = make([]MemoryInfo, 0) // Clear pending warnings
 
 	// Record the time we're sending this warning
-	a.lastWarningAlertTime = time.Now()
+	a.lastWarningAlertTime = a.monitor.clock.Now()
 
 	// Reset warning count after sending aggregated alert
 	a.warningCount = 0
@@ -0,0 +1,274 @@
+package memory
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	testsupport.Main(m)
+}
+
+// testEnv is a monitor wired to fake sources
+type testEnv struct {
+	*testsupport.Env
+	monitor   *Monitor
+	collector *FakeCollector
+}
+
+// newTestEnv creates a monitor with 80/90 thresholds, optionally adjusting the config.
+// MariaDB recovery stays disabled so critical alerts never restart anything.
+func newTestEnv(configure func(*config.Config)) *testEnv {
+	cfg := &config.Config{}
+	cfg.Monitoring.Memory.Enabled = true
+	cfg.Monitoring.Memory.CheckInterval = 60
+	cfg.Monitoring.Memory.WarningThreshold = 80
+	cfg.Monitoring.Memory.CriticalThreshold = 90
+	if configure != nil {
+		configure(cfg)
+	}
+
+	env := &testEnv{
+		monitor:   NewMonitor(cfg),
+		collector: NewFakeCollector(),
+	}
+	env.monitor.SetCollector(env.collector)
+	env.Env = testsupport.Wire(env.monitor)
+	return env
+}
+
+// run checks each usage in turn, advancing the clock by step before every check but the first
+func (e *testEnv) run(step time.Duration, usages ...float64) {
+	e.Run(step, func(usage float64) {
+		e.collector.PushUsage(usage)
+		e.monitor.CheckMemory(context.Background())
+	}, usages...)
+}
+
+func TestAlertTransitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*config.Config)
+		step      time.Duration
+		usages    []float64
+		want      []string
+	}{
+		{
+			name:   "normal usage stays silent",
+			step:   time.Minute,
+			usages: []float64{10, 50, 79.9},
+		},
+		{
+			name:   "normal to warning",
+			step:   time.Minute,
+			usages: []float64{10, 80},
+			want:   []string{"Memory Warning"},
+		},
+		{
+			name:   "normal to critical",
+			step:   time.Minute,
+			usages: []float64{10, 95},
+			want:   []string{"CRITICAL Memory Alert"},
+		},
+		{
+			name:   "critical at startup",
+			step:   time.Minute,
+			usages: []float64{95},
+			want:   []string{"CRITICAL Memory Alert"},
+		},
+		{
+			name:   "critical back to normal",
+			step:   time.Minute,
+			usages: []float64{10, 95, 20},
+			want:   []string{"CRITICAL Memory Alert", "Memory Status Normalized"},
+		},
+		{
+			name:   "warning back to normal is silent",
+			step:   time.Minute,
+			usages: []float64{10, 85, 20},
+			want:   []string{"Memory Warning"},
+		},
+		{
+			name:   "critical improving to warning is silent",
+			step:   time.Minute,
+			usages: []float64{10, 95, 85},
+			want:   []string{"CRITICAL Memory Alert"},
+		},
+		{
+			name:   "critical improving through warning to normal",
+			step:   time.Minute,
+			usages: []float64{10, 95, 85, 20},
+			want:   []string{"CRITICAL Memory Alert", "Memory Status Normalized"},
+		},
+		{
+			name:   "sustained critical is never throttled",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(95, 3)),
+			want:   []string{"CRITICAL Memory Alert", "CRITICAL Memory Alert", "CRITICAL Memory Alert"},
+		},
+		{
+			name:   "sustained warning within the throttle window",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(85, 30)),
+			want:   []string{"Memory Warning"},
+		},
+		{
+			name:   "sustained warning sends a summary after the throttle window",
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(85, 31)),
+			want:   []string{"Memory Warning", "Memory Warning Summary"},
+		},
+		{
+			name: "warning window and aggregation period from config",
+			configure: func(cfg *config.Config) {
+				cfg.Notifications.Throttling.Enabled = true
+				cfg.Notifications.Throttling.WarningWindow = 10
+				cfg.Notifications.Throttling.AggregationPeriod = 5
+			},
+			step:   time.Minute,
+			usages: testsupport.Concat([]float64{10}, testsupport.Repeat(85, 11)),
+			want:   []string{"Memory Warning", "Memory Warning Summary"},
+		},
+		{
+			name: "daily warning limit",
+			configure: func(cfg *config.Config) {
+				cfg.Notifications.Throttling.Enabled = true
+				cfg.Notifications.Throttling.MaxWarningsPerDay = 1
+			},
+			step:   31 * time.Minute,
+			usages: []float64{10, 85, 10, 85},
+			want:   []string{"Memory Warning"},
+		},
+		{
+			name: "daily warning limit resets the next day",
+			configure: func(cfg *config.Config) {
+				cfg.Notifications.Throttling.Enabled = true
+				cfg.Notifications.Throttling.MaxWarningsPerDay = 1
+			},
+			step:   7 * time.Hour,
+			usages: []float64{10, 85, 10, 85},
+			want:   []string{"Memory Warning", "Memory Warning"},
+		},
+		{
+			name:   "normalized notice has its own cooldown",
+			step:   time.Minute,
+			usages: []float64{10, 95, 20, 95, 20},
+			want:   []string{"CRITICAL Memory Alert", "Memory Status Normalized", "CRITICAL Memory Alert"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(tt.configure)
+			env.run(tt.step, tt.usages...)
+
+			if got := env.Recorder.Subjects(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("notifications = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		load    []float64
+		usages  []float64
+		subject string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "warning",
+			usages:  []float64{10, 85},
+			subject: "Memory Warning",
+			want: []string{
+				"MEMORY WARNING ALERT",
+				"13.60 GB (85.00%)",
+				"16.00 GB",
+				"Available Memory",
+				"This is a status change alert",
+				"This is warning notification 1 of 5 allowed per day",
+			},
+		},
+		{
+			name:    "critical",
+			load:    []float64{2, 1.5, 1},
+			usages:  []float64{10, 95},
+			subject: "CRITICAL Memory Alert",
+			want: []string{
+				"CRITICAL MEMORY ALERT",
+				"95.00%",
+				"IMMEDIATE ACTION REQUIRED!",
+				"1-min: 2.00, 5-min: 1.50, 15-min: 1.00",
+			},
+			notWant: []string{"AUTOMATIC RECOVERY ACTION"},
+		},
+		{
+			name:    "critical without load average",
+			usages:  []float64{10, 95},
+			subject: "CRITICAL Memory Alert",
+			want:    []string{"CRITICAL MEMORY ALERT"},
+			notWant: []string{"SYSTEM LOAD"},
+		},
+		{
+			name:    "normalized",
+			usages:  []float64{10, 95, 20},
+			subject: "Memory Status Normalized",
+			want: []string{
+				"MEMORY STATUS NORMALIZED",
+				"20.00%",
+				"System is now operating within normal parameters.",
+			},
+			notWant: []string{"This is a status change alert"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(nil)
+			if tt.load != nil {
+				env.collector.SetLoadAverage(tt.load[0], tt.load[1], tt.load[2])
+			}
+			env.run(time.Minute, tt.usages...)
+
+			var body string
+			for _, notification := range env.Recorder.Notifications() {
+				if notification.Subject == tt.subject {
+					body = notification.Body
+				}
+			}
+			if body == "" {
+				t.Fatalf("no %q notification in %q", tt.subject, env.Recorder.Subjects())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("body contains %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectorError(t *testing.T) {
+	env := newTestEnv(nil)
+	env.collector.SetError(errors.New("no /proc/meminfo"))
+	env.monitor.CheckMemory(context.Background())
+
+	if info := env.monitor.GetLastMemoryInfo(); info != nil {
+		t.Errorf("last info = %+v, want nil after a failed collection", info)
+	}
+	if got := env.Recorder.Subjects(); len(got) != 0 {
+		t.Errorf("notifications = %q, want none", got)
+	}
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// fakeTotalMemory is the size of the machine PushUsage pretends to be, 16 GiB
+const fakeTotalMemory = 16 * 1024 * 1024 * 1024
+
+// Collector takes the memory samples the monitor checks
+type Collector interface {
+	// Collect returns the current memory information with its status for the thresholds
+	Collect(ctx context.Context, warningThreshold, criticalThreshold float64) (*MemoryInfo, error)
+	// LoadAverage returns the 1, 5 and 15 minute load averages
+	LoadAverage() ([]float64, error)
+}
+
+// SystemCollector reads the host through gopsutil
+type SystemCollector struct{}
+
+// Collect implements Collector
+func (SystemCollector) Collect(ctx context.Context, warningThreshold, criticalThreshold float64) (*MemoryInfo, error) {
+	return GetMemoryInfo(ctx, warningThreshold, criticalThreshold)
+}
+
+// LoadAverage implements Collector
+func (SystemCollector) LoadAverage() ([]float64, error) {
+	return getSystemLoadAvg()
+}
+
+// FakeCollector replays queued samples for tests and simulations. Once the queue
+// is drained the last sample repeats. The status is derived from the thresholds
+// the same way GetMemoryInfo does.
+type FakeCollector struct {
+	mu      sync.Mutex
+	samples []MemoryInfo
+	last    *MemoryInfo
+	err     error
+	load    []float64
+}
+
+// NewFakeCollector creates a fake collector with the given samples queued
+func NewFakeCollector(samples ...MemoryInfo) *FakeCollector {
+	f := &FakeCollector{}
+	f.Push(samples...)
+	return f
+}
+
+// Push queues samples
+func (f *FakeCollector) Push(samples ...MemoryInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.samples = append(f.samples, samples...)
+}
+
+// PushUsage queues samples of a 16 GiB machine at the given usage percentages
+func (f *FakeCollector) PushUsage(usages ...float64) {
+	for _, usage := range usages {
+		used := uint64(float64(fakeTotalMemory) * usage / 100)
+		f.Push(MemoryInfo{
+			TotalMemory:          fakeTotalMemory,
+			UsedMemory:           used,
+			FreeMemory:           fakeTotalMemory - used,
+			AvailableMemory:      fakeTotalMemory - used,
+			UsedMemoryPercentage: usage,
+			FreeMemoryPercentage: 100 - usage,
+		})
+	}
+}
+
+// SetError makes Collect fail with err until it is cleared with nil
+func (f *FakeCollector) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// SetLoadAverage sets the load averages returned by LoadAverage
+func (f *FakeCollector) SetLoadAverage(load1, load5, load15 float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.load = []float64{load1, load5, load15}
+}
+
+// Collect implements Collector
+func (f *FakeCollector) Collect(ctx context.Context, warningThreshold, criticalThreshold float64) (*MemoryInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(f.samples) > 0 {
+		sample := f.samples[0]
+		f.samples = f.samples[1:]
+		f.last = &sample
+	}
+	if f.last == nil {
+		return nil, fmt.Errorf("fake memory collector has no samples")
+	}
+
+	info := *f.last
+	info.MemoryStatus = usageStatus(info.UsedMemoryPercentage, warningThreshold, criticalThreshold)
+	return &info, nil
+}
+
+// LoadAverage implements Collector
+func (f *FakeCollector) LoadAverage() ([]float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.load == nil {
+		return nil, fmt.Errorf("fake memory collector has no load average")
+	}
+	return append([]float64{}, f.load...), nil
+}
+
+// usageStatus maps a usage percentage to normal, warning or critical
+func usageStatus(usage, warningThreshold, criticalThreshold float64) string {
+	if usage >= criticalThreshold {
+		return "critical"
+	} else if usage >= warningThreshold {
+		return "warning"
+	}
+	return "normal"
+}
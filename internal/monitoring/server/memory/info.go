@@ -1,13 +1,15 @@
 package memory
 
 import (
+	"context"
+
 	"github.com/shirou/gopsutil/mem"
 )
 
 // GetMemoryInfo retrieves the current memory information
-func GetMemoryInfo(warningThreshold, criticalThreshold float64) (*MemoryInfo, error) {
+func GetMemoryInfo(ctx context.Context, warningThreshold, criticalThreshold float64) (*MemoryInfo, error) {
 	// Ambil statistik memory menggunakan gopsutil
-	vmStat, err := mem.VirtualMemory()
+	vmStat, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -17,12 +19,7 @@ func GetMemoryInfo(warningThreshold, criticalThreshold float64) (*MemoryInfo, er
 	freePercentage := 100 - usedPercentage
 
 	// Tentukan status memory berdasarkan threshold
-	status := "normal"
-	if usedPercentage >= criticalThreshold {
-		status = "critical"
-	} else if usedPercentage >= warningThreshold {
-		status = "warning"
-	}
+	status := usageStatus(usedPercentage, warningThreshold, criticalThreshold)
 
 	memInfo := &MemoryInfo{
 		TotalMemory:          vmStat.Total,
@@ -42,7 +39,7 @@ func GetMemoryInfo(warningThreshold, criticalThreshold float64) (*MemoryInfo, er
 	}
 
 	// Try to get swap information
-	swapStat, err := mem.SwapMemory()
+	swapStat, err := mem.SwapMemoryWithContext(ctx)
 	if err == nil && swapStat != nil {
 		memInfo.SwapTotal = swapStat.Total
 		memInfo.SwapUsed = swapStat.Used
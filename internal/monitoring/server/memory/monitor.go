@@ -2,9 +2,12 @@ package memory
 
 import (
 	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
 	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/clock"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
 	"CheckHealthDO/internal/websocket"
 	"context"
 	"fmt"
@@ -15,16 +18,20 @@ import (
 // Monitor handles periodic memory monitoring
 type Monitor struct {
 	config          *config.Config
-	ticker          *time.Ticker
+	cancelJob       func() // Removes the checks from the scheduler
 	stopChan        chan struct{}
 	isRunning       bool
 	mutex           sync.Mutex
 	lastInfo        *MemoryInfo
+	lastCheck       time.Time // When the last check finished
 	lastAlertTime   time.Time
-	emailManager    *notifications.EmailManager
+	emailManager    alerts.NotificationManager
 	checkCount      int // Counter for reducing log frequency
 	alertHandler    *AlertHandler
 	summaryReporter *SummaryReporter // Add this field
+	collector       Collector        // Source of samples, SystemCollector unless replaced
+	clock           clock.Clock      // Time source for checks and alert throttling
+	statusLog       bool             // Write status changes to the status log file
 	// Remove trend-related fields
 }
 
@@ -34,6 +41,9 @@ func NewMonitor(cfg *config.Config) *Monitor {
 		config:       cfg,
 		stopChan:     make(chan struct{}),
 		emailManager: notifications.NewEmailManager(cfg),
+		collector:    SystemCollector{},
+		clock:        clock.System,
+		statusLog:    true,
 		// Remove trend-related initialization
 	}
 	m.alertHandler = NewAlertHandler(m)
@@ -41,6 +51,39 @@ func NewMonitor(cfg *config.Config) *Monitor {
 	return m
 }
 
+// SetCollector replaces the source of memory samples, e.g. with a FakeCollector.
+// Call it before StartMonitoring.
+func (m *Monitor) SetCollector(collector Collector) {
+	m.collector = collector
+}
+
+// SetClock replaces the time source and restarts the alert and summary state from
+// it, so a fake clock governs throttling from the first check. Call it before
+// StartMonitoring.
+func (m *Monitor) SetClock(c clock.Clock) {
+	m.clock = c
+	m.lastAlertTime = time.Time{}
+	m.alertHandler = NewAlertHandler(m)
+	m.summaryReporter = NewSummaryReporter(m, m.config)
+}
+
+// SetNotificationManager routes memory alerts to manager. Call it before
+// StartMonitoring.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// SetStatusLog turns writing status changes to the status log file on or off, so
+// replays do not mix into the host's history
+func (m *Monitor) SetStatusLog(enabled bool) {
+	m.statusLog = enabled
+}
+
+// Now returns the monitor's current time, which the alert handler uses for cooldowns
+func (m *Monitor) Now() time.Time {
+	return m.clock.Now()
+}
+
 // StartMonitoring begins the memory monitoring process
 func (m *Monitor) StartMonitoring() error {
 	m.mutex.Lock()
@@ -56,7 +99,17 @@ func (m *Monitor) StartMonitoring() error {
 	}
 
 	interval := time.Duration(m.config.Monitoring.Memory.CheckInterval) * time.Second
-	m.ticker = time.NewTicker(interval)
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return m.CheckMemory(ctx)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule memory checks: %w", err)
+	}
+	m.cancelJob = cancelJob
 	m.isRunning = true
 
 	logger.Info("Starting memory monitor",
@@ -64,21 +117,6 @@ func (m *Monitor) StartMonitoring() error {
 		logger.Float64("warning_threshold", m.config.Monitoring.Memory.WarningThreshold),
 		logger.Float64("critical_threshold", m.config.Monitoring.Memory.CriticalThreshold))
 
-	// Run the first check immediately, then continue at intervals
-	go func() {
-		m.checkMemory()
-
-		for {
-			select {
-			case <-m.ticker.C:
-				m.checkMemory()
-			case <-m.stopChan:
-				m.ticker.Stop()
-				return
-			}
-		}
-	}()
-
 	return nil
 }
 
@@ -91,14 +129,48 @@ func (m *Monitor) StopMonitoring() {
 		return
 	}
 
+	m.cancelJob()
 	close(m.stopChan)
 	m.isRunning = false
 	logger.Info("Memory monitor stopped")
 }
 
-// checkMemory performs a single memory check
-func (m *Monitor) checkMemory() {
-	info, err := GetMemoryInfo(
+// Name returns "memory"
+func (m *Monitor) Name() string {
+	return "memory"
+}
+
+// Start schedules CheckMemory on the configured interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes CheckMemory from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns the *MemoryInfo of the last CheckMemory, nil before the first one
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetLastMemoryInfo()
+}
+
+// Health compares the last CheckMemory with monitoring.memory.check_interval
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	interval := time.Duration(m.config.Monitoring.Memory.CheckInterval) * time.Second
+	return monitoring.NewHealth(m.isRunning, m.lastCheck, interval)
+}
+
+// CheckMemory performs a single memory check, bounded by ctx
+func (m *Monitor) CheckMemory(ctx context.Context) error {
+	info, err := m.collector.Collect(ctx,
 		m.config.Monitoring.Memory.WarningThreshold,
 		m.config.Monitoring.Memory.CriticalThreshold,
 	)
@@ -106,7 +178,7 @@ func (m *Monitor) checkMemory() {
 	if err != nil {
 		logger.Error("Failed to get memory info",
 			logger.String("error", err.Error()))
-		return
+		return err
 	}
 
 	// Check if status changed from the last check
@@ -115,11 +187,14 @@ func (m *Monitor) checkMemory() {
 	if m.lastInfo != nil && m.lastInfo.MemoryStatus != info.MemoryStatus {
 		statusChanged = true
 		// Log the status change using the dedicated status logger
-		GetStatusLogger().LogStatusChange(m.lastInfo.MemoryStatus, info.MemoryStatus, info.UsedMemoryPercentage)
+		if m.statusLog {
+			GetStatusLogger().LogStatusChange(m.lastInfo.MemoryStatus, info.MemoryStatus, info.UsedMemoryPercentage)
+		}
 	}
 
 	// Store the latest metrics
 	m.lastInfo = info
+	m.lastCheck = m.clock.Now()
 	m.mutex.Unlock()
 
 	// Only log detailed information if not a status change but at a lower frequency
@@ -134,7 +209,7 @@ func (m *Monitor) checkMemory() {
 	}
 
 	// Format timestamp consistently for all messages
-	timestamp := time.Now()
+	timestamp := m.clock.Now()
 	formattedTime := timestamp.Format(time.RFC3339)
 
 	// Create a completely separate metrics structure to ensure no overlap with CPU data
@@ -164,7 +239,7 @@ func (m *Monitor) checkMemory() {
 
 	// Also broadcast to memory-specific WebSocket
 	registry := websocket.GetRegistry()
-	if handler := registry.GetMemoryHandler(); handler != nil {
+	if handler := registry.GetHandler(websocket.TopicMemory); handler != nil {
 		registry.BroadcastMemory(combinedMsg)
 	}
 
@@ -180,8 +255,10 @@ func (m *Monitor) checkMemory() {
 	case "warning":
 		m.alertHandler.HandleWarningAlert(info, statusChanged)
 	case "critical":
-		m.alertHandler.HandleCriticalAlert(info, statusChanged)
+		m.alertHandler.HandleCriticalAlert(ctx, info, statusChanged)
 	}
+
+	return nil
 }
 
 // GetLastMemoryInfo returns the most recently captured memory information
@@ -205,11 +282,15 @@ func (m *Monitor) GetConfigPtr() *config.Config {
 
 // UpdateLastAlertTime updates the last alert time
 func (m *Monitor) UpdateLastAlertTime() {
-	m.lastAlertTime = time.Now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = m.clock.Now()
 }
 
 // GetLastAlertTime returns the last alert time
 func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.lastAlertTime
 }
 
@@ -32,7 +32,7 @@ func NewSummaryReporter(monitor *Monitor, cfg *config.Config) *SummaryReporter {
 		warningEvents:     0,
 		criticalEvents:    0,
 		peakMemoryUsage:   0,
-		lastReportTime:    time.Now(),
+		lastReportTime:    monitor.clock.Now(),
 		reportingInterval: interval,
 	}
 }
@@ -55,7 +55,7 @@ func (s *SummaryReporter) RecordEvent(info *MemoryInfo) {
 	}
 
 	// Check if it's time to send a report
-	if time.Since(s.lastReportTime) >= s.reportingInterval {
+	if s.monitor.clock.Since(s.lastReportTime) >= s.reportingInterval {
 		s.sendSummaryReport()
 	}
 }
@@ -63,7 +63,7 @@ func (s *SummaryReporter) RecordEvent(info *MemoryInfo) {
 // sendSummaryReport sends a summary report via email
 func (s *SummaryReporter) sendSummaryReport() {
 	// Reset last report time first to prevent duplicate reports
-	s.lastReportTime = time.Now()
+	s.lastReportTime = s.monitor.clock.Now()
 
 	// Create summary content
 	serverInfo := alerts.GetServerInfoForAlert()
@@ -11,14 +11,10 @@ import (
 func (m *Monitor) WebSocketHandler(c *gin.Context) {
 	// Initialize the WebSocket registry if needed
 	registry := websocket.GetRegistry()
-	handler := registry.GetMemoryHandler()
-	if handler == nil {
-		handler = websocket.NewHandler()
-		registry.RegisterMemoryHandler(handler)
-	}
+	handler := registry.HandlerFor(websocket.TopicMemory)
 
 	// Force an immediate memory check to get fresh data
-	m.checkMemory()
+	m.CheckMemory(c.Request.Context())
 
 	// Let the central registry handle the WebSocket connection
 	handler.ServeHTTP(c.Writer, c.Request)
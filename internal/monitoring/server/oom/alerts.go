@@ -0,0 +1,131 @@
+package oom
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring/server/memory"
+	"CheckHealthDO/internal/pkg/logger"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AlertHandler sends a notification for every process the OOM killer kills
+type AlertHandler struct {
+	monitor *Monitor
+	handler *alerts.Handler
+}
+
+// NewAlertHandler creates a new alert handler for OOM kills
+func NewAlertHandler(monitor *Monitor) *AlertHandler {
+	return &AlertHandler{
+		monitor: monitor,
+		handler: alerts.NewHandler(monitor, nil),
+	}
+}
+
+// HandleKill sends a critical notification for a kill. Kills are not throttled;
+// each one is a process that is gone.
+func (a *AlertHandler) HandleKill(event *Event) {
+	alertType := alerts.AlertTypeCritical
+	style := a.handler.GetAlertStyle(alertType)
+	kill := event.Kill
+
+	subject := fmt.Sprintf("CRITICAL OOM Kill: %s (pid %d)", kill.Process, kill.PID)
+	additionalContent := fmt.Sprintf(`
+		<p>The kernel's Out-of-Memory killer terminated <b>%s</b> to free memory.</p>
+		<p><b>Kernel message:</b> <code>%s</code></p>
+		<p><b>Recommendation:</b> %s</p>`,
+		kill.Process, kill.Message, recommendation(kill))
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		"CRITICAL OOM KILL ALERT",
+		true,
+		a.createTableContent(event, style),
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, string(alertType))
+	a.monitor.UpdateLastAlertTime()
+
+	logger.Info("Sent OOM kill notification",
+		logger.String("process", kill.Process),
+		logger.Int("pid", kill.PID))
+}
+
+// createTableContent renders the victim and the memory snapshot as an HTML table
+func (a *AlertHandler) createTableContent(event *Event, style alerts.AlertStyle) string {
+	statusLine := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText)
+	kill := event.Kill
+
+	uid := "Not reported"
+	if kill.UID >= 0 {
+		uid = strconv.Itoa(kill.UID)
+	}
+	cgroup := kill.Cgroup
+	if cgroup == "" {
+		cgroup = "Not reported"
+	}
+
+	rows := []alerts.TableRow{
+		{Label: "Process", Value: kill.Process},
+		{Label: "PID", Value: strconv.Itoa(kill.PID)},
+		{Label: "UID", Value: uid},
+		{Label: "Cgroup", Value: cgroup},
+		{Label: "Constraint", Value: constraintLabel(kill.Constraint)},
+		{Label: "RSS", Value: fmt.Sprintf("%s (anon %s, file %s, shmem %s)",
+			memory.FormatBytes(kill.RSS()), memory.FormatBytes(kill.AnonRSS),
+			memory.FormatBytes(kill.FileRSS), memory.FormatBytes(kill.ShmemRSS))},
+		{Label: "Total VM", Value: memory.FormatBytes(kill.TotalVM)},
+		{Label: "Page Tables", Value: memory.FormatBytes(kill.PageTables)},
+		{Label: "oom_score_adj", Value: strconv.Itoa(kill.OOMScoreAdj)},
+		{Label: "Killed At", Value: kill.Time.Format("2006-01-02 15:04:05")},
+	}
+
+	if snapshot := event.Snapshot; snapshot != nil {
+		if info := snapshot.Memory; info != nil {
+			rows = append(rows,
+				alerts.TableRow{Label: "Memory Used", Value: fmt.Sprintf("%.2f%% of %s",
+					info.UsedMemoryPercentage, memory.FormatBytes(info.TotalMemory))},
+				alerts.TableRow{Label: "Memory Available", Value: memory.FormatBytes(info.AvailableMemory)},
+				alerts.TableRow{Label: "Swap Used", Value: fmt.Sprintf("%s (%.2f%%)",
+					memory.FormatBytes(info.SwapUsed), info.SwapUsedPercentage)},
+			)
+		}
+		if snapshot.PressureSome != nil {
+			pressure := fmt.Sprintf("some %.2f%%", snapshot.PressureSome.Avg10)
+			if snapshot.PressureFull != nil {
+				pressure += fmt.Sprintf(", full %.2f%%", snapshot.PressureFull.Avg10)
+			}
+			rows = append(rows, alerts.TableRow{Label: "Memory Pressure (avg10)", Value: pressure})
+		}
+	}
+
+	return statusLine + alerts.CreateTable(rows)
+}
+
+// constraintLabel describes what ran out of memory
+func constraintLabel(constraint string) string {
+	switch constraint {
+	case "CONSTRAINT_NONE":
+		return "System-wide"
+	case "CONSTRAINT_MEMCG":
+		return "Cgroup memory limit"
+	case "CONSTRAINT_CPUSET", "CONSTRAINT_MEMORY_POLICY":
+		return "NUMA memory policy"
+	case "":
+		return "Not reported"
+	default:
+		return constraint
+	}
+}
+
+// recommendation suggests where to look depending on what ran out of memory
+func recommendation(kill Kill) string {
+	if kill.Constraint == "CONSTRAINT_MEMCG" || strings.HasPrefix(kill.Message, "Memory cgroup") {
+		return "The process hit the memory limit of its cgroup, not the host's memory. Check MemoryMax of the unit or container limit against what the process needs."
+	}
+	return "The host ran out of memory. Check the largest processes, the MariaDB buffer pool size against the available memory, and whether swap is configured. A negative oom_score_adj protects a process from being chosen."
+}
@@ -0,0 +1,61 @@
+package oom
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// kmsgPath is the kernel log device; every read returns one record
+const kmsgPath = "/dev/kmsg"
+
+// kmsgRecordSize is larger than any record the kernel writes
+const kmsgRecordSize = 8192
+
+// parseKmsgRecord splits a /dev/kmsg record such as
+//
+//	6,1234,5678901234,-;Out of memory: Killed process 1234 (mariadbd) ...
+//	 SUBSYSTEM=...
+//
+// into its message and the time it was logged. The timestamp is in microseconds
+// since boot.
+func parseKmsgRecord(record string, bootTime time.Time) (string, time.Time, bool) {
+	header, message, ok := strings.Cut(record, ";")
+	if !ok {
+		return "", time.Time{}, false
+	}
+
+	// Continuation lines carry dictionary fields, not message text
+	message, _, _ = strings.Cut(message, "\n")
+
+	fields := strings.Split(header, ",")
+	if len(fields) < 3 {
+		return "", time.Time{}, false
+	}
+	usec, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return message, bootTime.Add(time.Duration(usec) * time.Microsecond), true
+}
+
+// readKmsg reads records until r fails or is closed, starting with those still in
+// the kernel's buffer
+func readKmsg(r io.Reader, handle func(record string)) error {
+	buf := make([]byte, kmsgRecordSize)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			// Records were overwritten before they could be read; reading continues
+			// with the oldest one left
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+			return err
+		}
+		handle(string(buf[:n]))
+	}
+}
@@ -0,0 +1,415 @@
+package oom
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/monitoring/server/memory"
+	"CheckHealthDO/internal/monitoring/server/pressure"
+	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/journal"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/host"
+)
+
+// Default values used when the configuration leaves them unset
+const (
+	defaultCheckInterval = 10
+	defaultHistory       = 100
+)
+
+// backlogTolerance allows for the second resolution of the boot time that kmsg
+// timestamps are relative to. Kills logged longer before the watcher started are
+// recorded without alerting, and kills this close together with the same pid are
+// the same kill.
+const backlogTolerance = 2 * time.Second
+
+// snapshotTimeout bounds reading the memory usage for the snapshot of a kill
+const snapshotTimeout = 5 * time.Second
+
+// Monitor watches the kernel log for processes killed by the OOM killer, alerting
+// on every kill and keeping a history of them
+type Monitor struct {
+	config        *config.Config
+	source        string   // kmsg or journal, chosen when started
+	kmsg          *os.File // Open kernel log device while following kmsg
+	cancelJob     func()   // Removes the journal reads from the scheduler
+	stopChan      chan struct{}
+	isRunning     bool
+	mutex         sync.Mutex
+	startedAt     time.Time
+	lastCheck     time.Time // When kernel messages were last read
+	readerErr     error     // Why following kmsg ended, nil while it runs
+	parser        *parser
+	journalSince  time.Time // Time of the newest journal entry read
+	events        []Event   // Most recent last
+	nextID        int
+	historyLimit  int
+	lastAlertTime time.Time
+	emailManager  alerts.NotificationManager
+	alertHandler  *AlertHandler
+}
+
+// NewMonitor creates a new OOM kill monitor instance
+func NewMonitor(cfg *config.Config) *Monitor {
+	historyLimit := cfg.Monitoring.OOM.History
+	if historyLimit <= 0 {
+		historyLimit = defaultHistory
+	}
+
+	m := &Monitor{
+		config:       cfg,
+		stopChan:     make(chan struct{}),
+		parser:       newParser(),
+		nextID:       1,
+		historyLimit: historyLimit,
+		emailManager: notifications.NewEmailManager(cfg),
+	}
+	m.alertHandler = NewAlertHandler(m)
+	return m
+}
+
+// SetNotificationManager routes OOM kill alerts to manager. Call it before
+// StartMonitoring.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// StartMonitoring starts following the kernel log
+func (m *Monitor) StartMonitoring() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isRunning {
+		return fmt.Errorf("OOM monitor is already running")
+	}
+
+	if !m.config.Monitoring.OOM.Enabled {
+		return fmt.Errorf("OOM monitoring is disabled in configuration")
+	}
+
+	source, kmsg, err := m.openSource()
+	if err != nil {
+		return err
+	}
+	m.source = source
+	m.startedAt = time.Now()
+
+	if source == SourceKmsg {
+		m.kmsg = kmsg
+		go m.followKmsg(kmsg)
+	} else {
+		cancelJob, err := scheduler.Schedule(scheduler.Job{
+			Name:     m.Name(),
+			Interval: m.checkInterval(),
+			Run: func(ctx context.Context) error {
+				return m.readJournal(ctx)
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to schedule journal reads: %w", err)
+		}
+		m.cancelJob = cancelJob
+	}
+	m.isRunning = true
+
+	logger.Info("Starting OOM kill monitoring",
+		logger.String("source", source))
+
+	return nil
+}
+
+// openSource picks where kernel messages are read from, opening /dev/kmsg when it is used
+func (m *Monitor) openSource() (string, *os.File, error) {
+	source := strings.ToLower(m.config.Monitoring.OOM.Source)
+
+	switch source {
+	case SourceJournal:
+		return SourceJournal, nil, nil
+	case SourceKmsg:
+		kmsg, err := os.Open(kmsgPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to open %s: %w", kmsgPath, err)
+		}
+		return SourceKmsg, kmsg, nil
+	case "", SourceAuto:
+		kmsg, err := os.Open(kmsgPath)
+		if err != nil {
+			logger.Info("Kernel log device not readable, reading kernel messages from the journal",
+				logger.String("error", err.Error()))
+			return SourceJournal, nil, nil
+		}
+		return SourceKmsg, kmsg, nil
+	default:
+		return "", nil, fmt.Errorf("unknown OOM source %q, use kmsg, journal or auto", source)
+	}
+}
+
+// StopMonitoring stops following the kernel log
+func (m *Monitor) StopMonitoring() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+
+	if m.cancelJob != nil {
+		m.cancelJob()
+	}
+	if m.kmsg != nil {
+		m.kmsg.Close()
+	}
+	close(m.stopChan)
+	m.isRunning = false
+	logger.Info("OOM kill monitoring stopped")
+}
+
+// Name returns "oom"
+func (m *Monitor) Name() string {
+	return "oom"
+}
+
+// Start follows kmsg, or polls the journal, until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop ends the kmsg reader or the journal polling
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns the kernel message source and the recorded OOM kill events
+func (m *Monitor) Snapshot() interface{} {
+	return map[string]interface{}{
+		"source": m.Source(),
+		"events": m.GetEvents(),
+	}
+}
+
+// Health only goes stale when the journal is polled. kmsg is followed rather than
+// polled, so it never goes stale, but stops being healthy when reading it fails.
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var interval time.Duration
+	if m.source == SourceJournal {
+		interval = m.checkInterval()
+	}
+
+	health := monitoring.NewHealth(m.isRunning && m.readerErr == nil, m.lastCheck, interval)
+	if m.readerErr != nil {
+		health.Message = "reading kernel messages failed: " + m.readerErr.Error()
+	}
+	return health
+}
+
+// checkInterval returns the configured time between journal reads, or the default
+func (m *Monitor) checkInterval() time.Duration {
+	interval := m.config.Monitoring.OOM.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// followKmsg reads kernel messages until the device is closed by StopMonitoring
+func (m *Monitor) followKmsg(kmsg *os.File) {
+	var bootTime time.Time
+	if seconds, err := host.BootTime(); err == nil {
+		bootTime = time.Unix(int64(seconds), 0)
+	}
+
+	// kmsg is followed outside the scheduler, so only takeSnapshot bounds the work
+	// done for each kill
+	err := readKmsg(kmsg, func(record string) {
+		if message, at, ok := parseKmsgRecord(record, bootTime); ok {
+			m.handleMessage(context.Background(), message, at, SourceKmsg)
+		}
+	})
+	if errors.Is(err, os.ErrClosed) {
+		return
+	}
+
+	logger.Error("Stopped reading kernel messages",
+		logger.String("error", err.Error()))
+	m.mutex.Lock()
+	m.readerErr = err
+	m.mutex.Unlock()
+}
+
+// readJournal reads the kernel messages logged since the previous read. The first
+// read covers the current boot.
+func (m *Monitor) readJournal(ctx context.Context) error {
+	entries, err := journal.Read(ctx, journal.Query{Kernel: true, CurrentBoot: true, Since: m.journalSince})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		m.handleMessage(ctx, entry.Message, entry.Time, SourceJournal)
+		if entry.Time.After(m.journalSince) {
+			m.journalSince = entry.Time
+		}
+	}
+
+	m.mutex.Lock()
+	m.lastCheck = time.Now()
+	m.mutex.Unlock()
+	return nil
+}
+
+// handleMessage parses one kernel message and records the kill it completes
+func (m *Monitor) handleMessage(ctx context.Context, message string, at time.Time, source string) {
+	m.mutex.Lock()
+	m.lastCheck = time.Now()
+	kill, ok := m.parser.feed(message, at)
+	m.mutex.Unlock()
+
+	if ok {
+		m.record(ctx, *kill, source)
+	}
+}
+
+// record adds a kill to the event history and alerts on it, unless it happened
+// before the watcher started or is already recorded
+func (m *Monitor) record(ctx context.Context, kill Kill, source string) {
+	live := !kill.Time.Before(m.startedAt.Add(-backlogTolerance))
+
+	var snapshot *MemorySnapshot
+	if live {
+		snapshot = m.takeSnapshot(ctx)
+	}
+
+	m.mutex.Lock()
+	for _, event := range m.events {
+		if event.Kill.PID == kill.PID && absDuration(event.Kill.Time.Sub(kill.Time)) < backlogTolerance {
+			m.mutex.Unlock()
+			return
+		}
+	}
+
+	event := Event{
+		ID:         m.nextID,
+		CapturedAt: time.Now(),
+		Source:     source,
+		Kill:       kill,
+		Snapshot:   snapshot,
+	}
+	m.nextID++
+	m.events = append(m.events, event)
+	if len(m.events) > m.historyLimit {
+		m.events = append(m.events[:0], m.events[len(m.events)-m.historyLimit:]...)
+	}
+	m.mutex.Unlock()
+
+	logger.Warn("OOM killer killed a process",
+		logger.String("process", kill.Process),
+		logger.Int("pid", kill.PID),
+		logger.String("rss", memory.FormatBytes(kill.RSS())),
+		logger.Int("oom_score_adj", kill.OOMScoreAdj),
+		logger.String("cgroup", kill.Cgroup),
+		logger.Bool("before_start", !live))
+
+	if live {
+		m.alertHandler.HandleKill(&event)
+	}
+}
+
+// takeSnapshot captures the memory usage and, where available, memory pressure.
+// It gives up on the memory usage after snapshotTimeout.
+func (m *Monitor) takeSnapshot(ctx context.Context) *MemorySnapshot {
+	snapshot := &MemorySnapshot{}
+
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	info, err := memory.GetMemoryInfo(ctx,
+		m.config.Monitoring.Memory.WarningThreshold,
+		m.config.Monitoring.Memory.CriticalThreshold,
+	)
+	if err != nil {
+		logger.Warn("Failed to take memory snapshot for OOM kill",
+			logger.String("error", err.Error()))
+	} else {
+		snapshot.Memory = info
+	}
+
+	if psi, err := pressure.ReadResource(pressure.DefaultDir, pressure.ResourceMemory); err == nil {
+		snapshot.PressureSome = &psi.Some
+		snapshot.PressureFull = psi.Full
+	}
+	return snapshot
+}
+
+// absDuration returns the absolute value of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// Source returns where kernel messages are read from, empty before the monitor started
+func (m *Monitor) Source() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.source
+}
+
+// GetEvents returns the recorded OOM kills, newest first
+func (m *Monitor) GetEvents() []Event {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	events := make([]Event, 0, len(m.events))
+	for i := len(m.events) - 1; i >= 0; i-- {
+		events = append(events, m.events[i])
+	}
+	return events
+}
+
+// GetConfig returns the monitor's configuration
+// Returns interface{} to match the alerts.ConfigProvider interface
+func (m *Monitor) GetConfig() interface{} {
+	return m.config
+}
+
+// GetConfigPtr returns the monitor's configuration as a concrete type pointer
+func (m *Monitor) GetConfigPtr() *config.Config {
+	return m.config
+}
+
+// UpdateLastAlertTime updates the last alert time
+func (m *Monitor) UpdateLastAlertTime() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = time.Now()
+}
+
+// GetLastAlertTime returns the last alert time
+func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastAlertTime
+}
+
+// GetNotificationManagers returns the notification managers
+func (m *Monitor) GetNotificationManagers() alerts.NotificationManager {
+	return m.emailManager
+}
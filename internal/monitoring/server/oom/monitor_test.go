@@ -0,0 +1,147 @@
+package oom
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+const (
+	summaryLine = "oom-kill:constraint=CONSTRAINT_NONE,nodemask=(null),cpuset=/,mems_allowed=0,global_oom,task_memcg=/system.slice/mariadb.service,task=mariadbd,pid=1234,uid=27"
+	killedLine  = "Out of memory: Killed process 1234 (mariadbd) total-vm:4000000kB, anon-rss:3000000kB, file-rss:2048kB, shmem-rss:0kB, UID:27 pgtables:6500kB oom_score_adj:-600"
+)
+
+// newTestMonitor creates a monitor that started now and records its notifications
+func newTestMonitor(history int) (*Monitor, *alerts.Recorder) {
+	cfg := &config.Config{}
+	cfg.Monitoring.OOM.Enabled = true
+	cfg.Monitoring.OOM.History = history
+
+	recorder := alerts.NewRecorder()
+	monitor := NewMonitor(cfg)
+	monitor.SetNotificationManager(recorder)
+	monitor.startedAt = time.Now()
+	return monitor, recorder
+}
+
+func TestParseKill(t *testing.T) {
+	p := newParser()
+	at := time.Now()
+
+	if _, ok := p.feed(summaryLine, at); ok {
+		t.Fatal("summary alone should not complete a kill")
+	}
+	kill, ok := p.feed(killedLine, at)
+	if !ok {
+		t.Fatal("killed line should complete a kill")
+	}
+
+	want := Kill{
+		Time:        at,
+		PID:         1234,
+		Process:     "mariadbd",
+		UID:         27,
+		TotalVM:     4000000 * 1024,
+		AnonRSS:     3000000 * 1024,
+		FileRSS:     2048 * 1024,
+		PageTables:  6500 * 1024,
+		OOMScoreAdj: -600,
+		Cgroup:      "/system.slice/mariadb.service",
+		Constraint:  "CONSTRAINT_NONE",
+		Message:     killedLine,
+	}
+	if *kill != want {
+		t.Errorf("kill = %+v, want %+v", *kill, want)
+	}
+	if len(p.pending) != 0 {
+		t.Errorf("pending = %v, want the summary consumed", p.pending)
+	}
+}
+
+func TestParseKillOldKernel(t *testing.T) {
+	kill, ok := newParser().feed("Killed process 99 (php-fpm: pool www) total-vm:512000kB, anon-rss:256000kB, file-rss:0kB", time.Now())
+	if !ok {
+		t.Fatal("killed line should complete a kill")
+	}
+	if kill.PID != 99 || kill.Process != "php-fpm: pool www" || kill.AnonRSS != 256000*1024 || kill.UID != -1 || kill.Cgroup != "" {
+		t.Errorf("kill = %+v", *kill)
+	}
+}
+
+func TestParseKmsgRecord(t *testing.T) {
+	boot := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	message, at, ok := parseKmsgRecord("3,812,5000000,-;"+killedLine+"\n SUBSYSTEM=memory\n", boot)
+	if !ok || message != killedLine || !at.Equal(boot.Add(5*time.Second)) {
+		t.Errorf("got %q at %v (%v)", message, at, ok)
+	}
+
+	if _, _, ok := parseKmsgRecord("no header", boot); ok {
+		t.Error("want a record without a header rejected")
+	}
+}
+
+func TestKillAlerts(t *testing.T) {
+	monitor, recorder := newTestMonitor(0)
+
+	// A kill from before the watcher started is recorded without alerting
+	before := monitor.startedAt.Add(-time.Hour)
+	monitor.handleMessage(context.Background(), "Out of memory: Killed process 10 (java) total-vm:100kB, anon-rss:50kB, file-rss:0kB, shmem-rss:0kB, UID:0 pgtables:4kB oom_score_adj:0", before, SourceKmsg)
+
+	now := time.Now()
+	monitor.handleMessage(context.Background(), summaryLine, now, SourceKmsg)
+	monitor.handleMessage(context.Background(), killedLine, now, SourceKmsg)
+	// The journal repeats the newest entry of the previous read
+	monitor.handleMessage(context.Background(), killedLine, now, SourceJournal)
+
+	if got := recorder.Subjects(); len(got) != 1 || got[0] != "CRITICAL OOM Kill: mariadbd (pid 1234)" {
+		t.Fatalf("notifications = %q, want one for mariadbd", got)
+	}
+
+	events := monitor.GetEvents()
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if events[0].Kill.PID != 1234 || events[0].ID != 2 || events[0].Snapshot == nil {
+		t.Errorf("newest event = %+v, want mariadbd with a snapshot", events[0])
+	}
+	if events[1].Kill.PID != 10 || events[1].Snapshot != nil {
+		t.Errorf("oldest event = %+v, want java without a snapshot", events[1])
+	}
+
+	body := recorder.Notifications()[0].Body
+	for _, want := range []string{"mariadbd", "/system.slice/mariadb.service", "System-wide", "-600", "Killed process 1234"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	monitor, recorder := newTestMonitor(3)
+
+	now := time.Now()
+	for pid := 1; pid <= 5; pid++ {
+		monitor.handleMessage(context.Background(), "Killed process "+strconv.Itoa(pid)+" (worker) total-vm:0kB, anon-rss:0kB, file-rss:0kB", now, SourceKmsg)
+	}
+
+	if got := len(recorder.Subjects()); got != 5 {
+		t.Errorf("notifications = %d, want one per kill", got)
+	}
+	events := monitor.GetEvents()
+	if len(events) != 3 || events[0].Kill.PID != 5 || events[2].Kill.PID != 3 {
+		t.Errorf("events = %+v, want the newest three", events)
+	}
+}
@@ -0,0 +1,108 @@
+package oom
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPending bounds the oom-kill summaries waiting for their "Killed process" line
+const maxPending = 16
+
+var (
+	// killedPattern matches the line naming the victim, e.g.
+	//
+	//	Out of memory: Killed process 1234 (mariadbd) total-vm:4000000kB, anon-rss:3000000kB, file-rss:0kB, shmem-rss:0kB, UID:27 pgtables:6500kB oom_score_adj:0
+	//	Memory cgroup out of memory: Killed process 1234 (mariadbd) ...
+	//
+	// Kernels before 5.0 only report the sizes up to file-rss.
+	killedPattern = regexp.MustCompile(`Killed process (\d+) \((.*)\)(.*)$`)
+	// killedFieldPattern matches the name:value fields after the process name
+	killedFieldPattern = regexp.MustCompile(`([A-Za-z_-]+):(-?\d+)(kB)?`)
+)
+
+// parser turns kernel messages into kills. Since Linux 4.19 the kernel logs an
+// oom-kill summary with the cgroup before the "Killed process" line; the two are
+// joined by pid.
+type parser struct {
+	pending map[int]map[string]string // oom-kill summary fields by pid
+}
+
+// newParser creates a kernel message parser
+func newParser() *parser {
+	return &parser{pending: make(map[int]map[string]string)}
+}
+
+// feed parses one kernel message and returns the kill it completes, if any
+func (p *parser) feed(message string, at time.Time) (*Kill, bool) {
+	message = strings.TrimSpace(message)
+
+	if summary, ok := strings.CutPrefix(message, "oom-kill:"); ok {
+		fields := parseSummary(summary)
+		if pid, err := strconv.Atoi(fields["pid"]); err == nil {
+			if len(p.pending) >= maxPending {
+				p.pending = make(map[int]map[string]string)
+			}
+			p.pending[pid] = fields
+		}
+		return nil, false
+	}
+
+	match := killedPattern.FindStringSubmatch(message)
+	if match == nil {
+		return nil, false
+	}
+
+	pid, _ := strconv.Atoi(match[1])
+	kill := &Kill{Time: at, PID: pid, Process: match[2], UID: -1, Message: message}
+
+	for _, field := range killedFieldPattern.FindAllStringSubmatch(match[3], -1) {
+		value, err := strconv.ParseInt(field[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		if field[3] == "kB" {
+			value *= 1024
+		}
+
+		switch field[1] {
+		case "total-vm":
+			kill.TotalVM = uint64(value)
+		case "anon-rss":
+			kill.AnonRSS = uint64(value)
+		case "file-rss":
+			kill.FileRSS = uint64(value)
+		case "shmem-rss":
+			kill.ShmemRSS = uint64(value)
+		case "pgtables":
+			kill.PageTables = uint64(value)
+		case "UID":
+			kill.UID = int(value)
+		case "oom_score_adj":
+			kill.OOMScoreAdj = int(value)
+		}
+	}
+
+	if fields, ok := p.pending[pid]; ok {
+		delete(p.pending, pid)
+		kill.Cgroup = fields["task_memcg"]
+		kill.Constraint = fields["constraint"]
+		if uid, err := strconv.Atoi(fields["uid"]); err == nil && kill.UID < 0 {
+			kill.UID = uid
+		}
+	}
+	return kill, true
+}
+
+// parseSummary parses the comma separated key=value fields of an oom-kill summary,
+// e.g. constraint=CONSTRAINT_NONE,nodemask=(null),cpuset=/,mems_allowed=0,global_oom,task_memcg=/system.slice/mariadb.service,task=mariadbd,pid=1234,uid=27
+func parseSummary(summary string) map[string]string {
+	fields := make(map[string]string)
+	for _, part := range strings.Split(summary, ",") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
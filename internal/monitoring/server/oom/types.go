@@ -0,0 +1,52 @@
+package oom
+
+import (
+	"CheckHealthDO/internal/monitoring/server/memory"
+	"CheckHealthDO/internal/monitoring/server/pressure"
+	"time"
+)
+
+// Sources kernel messages are read from
+const (
+	SourceKmsg    = "kmsg"
+	SourceJournal = "journal"
+	SourceAuto    = "auto"
+)
+
+// Kill is a process killed by the OOM killer, as reported by the kernel
+type Kill struct {
+	Time        time.Time `json:"time"`
+	PID         int       `json:"pid"`
+	Process     string    `json:"process"`
+	UID         int       `json:"uid"`         // -1 when not reported
+	TotalVM     uint64    `json:"total_vm"`    // In bytes
+	AnonRSS     uint64    `json:"anon_rss"`    // In bytes
+	FileRSS     uint64    `json:"file_rss"`    // In bytes
+	ShmemRSS    uint64    `json:"shmem_rss"`   // In bytes
+	PageTables  uint64    `json:"page_tables"` // In bytes
+	OOMScoreAdj int       `json:"oom_score_adj"`
+	Cgroup      string    `json:"cgroup,omitempty"`     // Memory cgroup of the victim
+	Constraint  string    `json:"constraint,omitempty"` // CONSTRAINT_NONE for a system-wide OOM, CONSTRAINT_MEMCG for a cgroup limit
+	Message     string    `json:"message"`              // The kernel's "Killed process" line
+}
+
+// RSS returns the resident memory of the killed process in bytes
+func (k Kill) RSS() uint64 {
+	return k.AnonRSS + k.FileRSS + k.ShmemRSS
+}
+
+// MemorySnapshot is the state of the host's memory when a kill was captured
+type MemorySnapshot struct {
+	Memory       *memory.MemoryInfo `json:"memory,omitempty"`
+	PressureSome *pressure.Stall    `json:"pressure_some,omitempty"` // Memory stall, where PSI is available
+	PressureFull *pressure.Stall    `json:"pressure_full,omitempty"`
+}
+
+// Event is an OOM kill in the event history
+type Event struct {
+	ID         int             `json:"id"`
+	CapturedAt time.Time       `json:"captured_at"`
+	Source     string          `json:"source"` // kmsg or journal
+	Kill       Kill            `json:"kill"`
+	Snapshot   *MemorySnapshot `json:"snapshot,omitempty"` // nil for kills from before the watcher started
+}
@@ -0,0 +1,180 @@
+package pressure
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/logger"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// resourceLabels names the resources in notifications
+var resourceLabels = map[string]string{
+	ResourceCPU:    "CPU",
+	ResourceMemory: "Memory",
+	ResourceIO:     "I/O",
+}
+
+// resourceRecommendations explains what a stall on each resource usually means
+var resourceRecommendations = map[string]string{
+	ResourceCPU:    "Runnable tasks are waiting for a CPU. Check 'top' for the busiest processes and consider more cores or moving work off this host.",
+	ResourceMemory: "Tasks are waiting for memory to be reclaimed or swapped in. Check for swapping with 'vmstat 1', the largest processes, and the MariaDB buffer pool size against the available memory.",
+	ResourceIO:     "Tasks are waiting for disk I/O. Check 'iostat -x' for saturated devices and look for large scans, backups or flushes running now.",
+}
+
+// defaultCooldown is the time between notifications for a resource when
+// throttling is not configured
+const defaultCooldown = 5 * time.Minute
+
+// AlertHandler sends notifications when a resource's stall time crosses a threshold
+type AlertHandler struct {
+	monitor *Monitor
+	handler *alerts.Handler
+	states  map[string]*resourceState
+}
+
+// resourceState tracks how long a resource has been at a level and what was last
+// notified for it
+type resourceState struct {
+	level    string    // Level of the latest samples
+	since    time.Time // When the samples reached it
+	notified string    // Level last notified, normal before any alert and after a recovery
+	lastSent time.Time // When the last notification was sent
+}
+
+// NewAlertHandler creates a new alert handler for pressure stalls
+func NewAlertHandler(monitor *Monitor) *AlertHandler {
+	return &AlertHandler{
+		monitor: monitor,
+		handler: alerts.NewHandler(monitor, nil),
+		states:  make(map[string]*resourceState),
+	}
+}
+
+// HandleResource sends a notification when a resource enters warning or critical
+// state or changes between them, and a recovery notice once it is back to normal.
+// A level must hold for the resource's duration before it is notified, and apart
+// from critical alerts and recoveries a resource is notified at most once per
+// cooldown.
+func (a *AlertHandler) HandleResource(pressure *ResourcePressure) {
+	now := a.monitor.Now()
+	label := resourceLabels[pressure.Resource]
+
+	state := a.states[pressure.Resource]
+	if state == nil {
+		state = &resourceState{level: "normal", notified: "normal"}
+		a.states[pressure.Resource] = state
+	}
+	if pressure.Status != state.level {
+		state.level = pressure.Status
+		state.since = now
+	}
+	if state.level == state.notified {
+		return
+	}
+
+	duration := time.Duration(a.monitor.resourceConfig(pressure.Resource).Duration) * time.Second
+	if now.Sub(state.since) < duration {
+		a.suppress(pressure, fmt.Sprintf("%s pressure %s has not held for %s", label, state.level, duration))
+		return
+	}
+
+	if state.level == "warning" && !state.lastSent.IsZero() && now.Sub(state.lastSent) < a.cooldown() {
+		a.suppress(pressure, fmt.Sprintf("%s pressure warning notifications are in the cooldown period", label))
+		return
+	}
+
+	state.notified = state.level
+	state.lastSent = now
+	if state.level == "normal" {
+		a.sendAlert(pressure, alerts.AlertTypeNormal)
+		return
+	}
+	a.sendAlert(pressure, alerts.AlertType(state.level))
+}
+
+// suppress logs and reports a notification that was held back
+func (a *AlertHandler) suppress(pressure *ResourcePressure, reason string) {
+	logger.Debug("Suppressing pressure notification",
+		logger.String("resource", pressure.Resource),
+		logger.String("status", pressure.Status),
+		logger.String("reason", reason))
+	a.handler.ReportSuppressed(reason)
+}
+
+// cooldown returns the time between notifications for a resource, the throttling
+// cooldown when configured
+func (a *AlertHandler) cooldown() time.Duration {
+	throttling := a.monitor.config.Notifications.Throttling
+	if throttling.Enabled && throttling.CooldownPeriod > 0 {
+		return time.Duration(throttling.CooldownPeriod) * time.Second
+	}
+	return defaultCooldown
+}
+
+// sendAlert builds and sends the notification email for a resource
+func (a *AlertHandler) sendAlert(pressure *ResourcePressure, alertType alerts.AlertType) {
+	style := a.handler.GetAlertStyle(alertType)
+	label := resourceLabels[pressure.Resource]
+
+	var title, subject, additionalContent string
+	switch alertType {
+	case alerts.AlertTypeNormal:
+		title = fmt.Sprintf("%s PRESSURE NORMALIZED", strings.ToUpper(label))
+		subject = fmt.Sprintf("%s Pressure Normalized", label)
+		additionalContent = fmt.Sprintf(`<p>Tasks are no longer stalling on %s beyond the configured thresholds.</p>`, label)
+	case alerts.AlertTypeCritical:
+		title = fmt.Sprintf("CRITICAL %s PRESSURE ALERT", strings.ToUpper(label))
+		subject = fmt.Sprintf("CRITICAL %s Pressure Alert", label)
+		additionalContent = fmt.Sprintf(`<p><b>Recommendation:</b> %s</p>`, resourceRecommendations[pressure.Resource])
+	default:
+		title = fmt.Sprintf("%s PRESSURE WARNING ALERT", strings.ToUpper(label))
+		subject = fmt.Sprintf("%s Pressure Warning", label)
+		additionalContent = fmt.Sprintf(`<p><b>Recommendation:</b> %s</p>`, resourceRecommendations[pressure.Resource])
+	}
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		title,
+		true,
+		a.createTableContent(pressure, style),
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, string(alertType))
+	a.monitor.UpdateLastAlertTime()
+
+	logger.Info("Sent pressure notification",
+		logger.String("resource", pressure.Resource),
+		logger.String("status", pressure.Status),
+		logger.String("reason", pressure.Reason))
+}
+
+// createTableContent renders the stall averages as an HTML table
+func (a *AlertHandler) createTableContent(pressure *ResourcePressure, style alerts.AlertStyle) string {
+	statusLine := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText)
+
+	reason := pressure.Reason
+	if reason == "" {
+		reason = "All averages are under their thresholds"
+	}
+
+	rows := []alerts.TableRow{
+		{Label: "Resource", Value: resourceLabels[pressure.Resource]},
+		{Label: "Reason", Value: reason},
+		{Label: "Some (avg10 / avg60 / avg300)", Value: formatStall(pressure.Some)},
+	}
+	if pressure.Full != nil {
+		rows = append(rows, alerts.TableRow{Label: "Full (avg10 / avg60 / avg300)", Value: formatStall(*pressure.Full)})
+	}
+	rows = append(rows, alerts.TableRow{Label: "Window", Value: fmt.Sprintf("avg%d", pressure.Window)})
+
+	return statusLine + alerts.CreateTable(rows)
+}
+
+// formatStall formats the averages of a pressure line
+func formatStall(stall Stall) string {
+	return fmt.Sprintf("%.2f%% / %.2f%% / %.2f%%", stall.Avg10, stall.Avg60, stall.Avg300)
+}
@@ -0,0 +1,270 @@
+package pressure
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// checkInterval is the time the test clock advances after every check
+const checkInterval = 10 * time.Second
+
+// testEnv is a monitor reading pressure files from a temporary directory
+type testEnv struct {
+	t        *testing.T
+	monitor  *Monitor
+	dir      string
+	recorder *alerts.Recorder
+	clock    *clock.Fake
+}
+
+// newTestEnv creates a monitor with memory thresholds of some 10/25 and full 5/10 over avg10
+func newTestEnv(t *testing.T) *testEnv {
+	cfg := &config.Config{}
+	cfg.Monitoring.Pressure.Enabled = true
+	cfg.Monitoring.Pressure.Memory = config.PressureResourceConfig{
+		Window:       10,
+		SomeWarning:  10,
+		SomeCritical: 25,
+		FullWarning:  5,
+		FullCritical: 10,
+	}
+
+	env := &testEnv{
+		t:        t,
+		monitor:  NewMonitor(cfg),
+		dir:      t.TempDir(),
+		recorder: alerts.NewRecorder(),
+		clock:    clock.NewFake(testsupport.Start),
+	}
+	env.monitor.SetDir(env.dir)
+	env.monitor.SetNotificationManager(env.recorder)
+	env.monitor.SetClock(env.clock)
+	return env
+}
+
+// check writes the avg10 values of the memory pressure file, with idle cpu and io
+// files, runs one check and advances the clock by checkInterval
+func (e *testEnv) check(some, full float64) {
+	files := map[string]string{
+		ResourceCPU:    "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+		ResourceIO:     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+		ResourceMemory: fmt.Sprintf("some avg10=%.2f avg60=0.00 avg300=0.00 total=100\nfull avg10=%.2f avg60=0.00 avg300=0.00 total=50\n", some, full),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(e.dir, name), []byte(content), 0o644); err != nil {
+			e.t.Fatal(err)
+		}
+	}
+	if err := e.monitor.CheckPressure(context.Background()); err != nil {
+		e.t.Fatalf("CheckPressure: %v", err)
+	}
+	e.clock.Advance(checkInterval)
+}
+
+// suppressed returns the reasons of the notifications held back so far
+func (e *testEnv) suppressed() []string {
+	var reasons []string
+	for _, outcome := range e.recorder.Outcomes() {
+		if outcome.Kind == alerts.OutcomeSuppressed {
+			reasons = append(reasons, outcome.Reason)
+		}
+	}
+	return reasons
+}
+
+func TestPressureAlerts(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples [][2]float64 // some and full avg10 of memory
+		want    []string
+	}{
+		{
+			name:    "under the thresholds stays silent",
+			samples: [][2]float64{{5, 1}, {9.9, 4.9}},
+		},
+		{
+			name:    "some over the warning threshold",
+			samples: [][2]float64{{5, 0}, {12, 0}, {15, 0}},
+			want:    []string{"Memory Pressure Warning"},
+		},
+		{
+			name:    "full over the critical threshold",
+			samples: [][2]float64{{12, 0}, {20, 11}},
+			want:    []string{"Memory Pressure Warning", "CRITICAL Memory Pressure Alert"},
+		},
+		{
+			name:    "recovery",
+			samples: [][2]float64{{30, 0}, {2, 0}, {1, 0}},
+			want:    []string{"CRITICAL Memory Pressure Alert", "Memory Pressure Normalized"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			for _, sample := range tt.samples {
+				env.check(sample[0], sample[1])
+			}
+
+			if got := env.recorder.Subjects(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("notifications = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPressureDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples [][2]float64 // some and full avg10 of memory, one check interval apart
+		want    []string
+	}{
+		{
+			name:    "short spike stays silent",
+			samples: [][2]float64{{12, 0}, {12, 0}, {12, 0}, {2, 0}, {2, 0}},
+		},
+		{
+			name:    "warning once held for the duration",
+			samples: [][2]float64{{12, 0}, {12, 0}, {12, 0}, {12, 0}},
+			want:    []string{"Memory Pressure Warning"},
+		},
+		{
+			name:    "brief dip does not recover",
+			samples: [][2]float64{{12, 0}, {12, 0}, {12, 0}, {12, 0}, {2, 0}, {12, 0}, {12, 0}},
+			want:    []string{"Memory Pressure Warning"},
+		},
+		{
+			name:    "recovery once normal held for the duration",
+			samples: [][2]float64{{12, 0}, {12, 0}, {12, 0}, {12, 0}, {2, 0}, {2, 0}, {2, 0}, {2, 0}},
+			want:    []string{"Memory Pressure Warning", "Memory Pressure Normalized"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.monitor.config.Monitoring.Pressure.Memory.Duration = 30
+			for _, sample := range tt.samples {
+				env.check(sample[0], sample[1])
+			}
+
+			if got := env.recorder.Subjects(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("notifications = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	env := newTestEnv(t)
+	env.monitor.config.Monitoring.Pressure.Memory.Duration = 30
+	env.check(12, 0)
+	if got := env.suppressed(); len(got) != 1 || got[0] != "Memory pressure warning has not held for 30s" {
+		t.Errorf("suppressed = %q, want the warning held back", got)
+	}
+}
+
+func TestPressureCooldown(t *testing.T) {
+	env := newTestEnv(t)
+	env.check(12, 0) // warning
+	env.check(2, 0)  // recovery
+	env.check(12, 0) // warning again within the cooldown
+	env.check(2, 0)  // nothing to recover from
+	env.check(30, 0) // critical passes the cooldown
+	env.check(12, 0) // back to warning within the cooldown
+	env.clock.Advance(defaultCooldown)
+	env.check(12, 0) // still warning after the cooldown
+
+	want := []string{
+		"Memory Pressure Warning",
+		"Memory Pressure Normalized",
+		"CRITICAL Memory Pressure Alert",
+		"Memory Pressure Warning",
+	}
+	if got := env.recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+	wantSuppressed := []string{
+		"Memory pressure warning notifications are in the cooldown period",
+		"Memory pressure warning notifications are in the cooldown period",
+	}
+	if got := env.suppressed(); strings.Join(got, "|") != strings.Join(wantSuppressed, "|") {
+		t.Errorf("suppressed = %q, want %q", got, wantSuppressed)
+	}
+
+	// The configured throttling cooldown replaces the default
+	env = newTestEnv(t)
+	env.monitor.config.Notifications.Throttling.Enabled = true
+	env.monitor.config.Notifications.Throttling.CooldownPeriod = 30
+	env.check(12, 0)
+	env.check(2, 0)
+	env.check(2, 0)
+	env.check(2, 0)
+	env.check(12, 0)
+	if got := env.recorder.Subjects(); len(got) != 3 {
+		t.Errorf("notifications = %q, want the warning again after 30s", got)
+	}
+}
+
+func TestPressureReason(t *testing.T) {
+	env := newTestEnv(t)
+	env.check(12, 11)
+
+	info := env.monitor.GetLastInfo()
+	if info.Status != "critical" || len(info.Resources) != 3 {
+		t.Fatalf("info = %+v, want three resources and critical", info)
+	}
+
+	var memory ResourcePressure
+	for _, resource := range info.Resources {
+		if resource.Resource == ResourceMemory {
+			memory = resource
+		}
+	}
+	want := "some avg10 12.00% >= warning threshold 10.00%; full avg10 11.00% >= critical threshold 10.00%"
+	if memory.Reason != want {
+		t.Errorf("reason = %q, want %q", memory.Reason, want)
+	}
+
+	body := env.recorder.Notifications()[0].Body
+	for _, want := range []string{"CRITICAL MEMORY PRESSURE ALERT", want, "12.00% / 0.00% / 0.00%", "vmstat 1"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+}
+
+func TestParsePressure(t *testing.T) {
+	some, full, err := parsePressure(strings.NewReader("some avg10=9.03 avg60=5.01 avg300=3.04 total=137531442\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if some.Avg10 != 9.03 || some.Avg60 != 5.01 || some.Avg300 != 3.04 || some.Total != 137531442 {
+		t.Errorf("some = %+v", some)
+	}
+	if full != nil {
+		t.Errorf("full = %+v, want nil without a full line", full)
+	}
+
+	if _, _, err := parsePressure(strings.NewReader("some avg10=abc\n")); err == nil {
+		t.Error("want an error for a malformed value")
+	}
+	if _, _, err := parsePressure(strings.NewReader("")); err == nil {
+		t.Error("want an error for an empty file")
+	}
+}
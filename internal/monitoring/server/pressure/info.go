@@ -0,0 +1,101 @@
+package pressure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultDir is where the kernel exposes pressure stall information
+const DefaultDir = "/proc/pressure"
+
+// ReadResource reads the pressure file of one resource from dir
+func ReadResource(dir, resource string) (*ResourcePressure, error) {
+	file, err := os.Open(filepath.Join(dir, resource))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	some, full, err := parsePressure(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s pressure: %w", resource, err)
+	}
+	return &ResourcePressure{Resource: resource, Some: some, Full: full}, nil
+}
+
+// Available reports whether the kernel exposes pressure stall information in dir.
+// It needs Linux 4.20 or later built with CONFIG_PSI and not booted with psi=0.
+func Available(dir string) error {
+	if _, err := ReadResource(dir, ResourceCPU); err != nil {
+		return fmt.Errorf("pressure stall information is not available: %w", err)
+	}
+	return nil
+}
+
+// parsePressure parses the some and full lines of a pressure file, e.g.
+//
+//	some avg10=0.23 avg60=0.26 avg300=0.08 total=7575007
+//	full avg10=0.13 avg60=0.07 avg300=0.01 total=4014169
+//
+// full is nil when the file has no full line, as for cpu before Linux 5.13.
+func parsePressure(r io.Reader) (some Stall, full *Stall, err error) {
+	foundSome := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		stall, err := parseStall(fields[1:])
+		if err != nil {
+			return Stall{}, nil, err
+		}
+		switch fields[0] {
+		case "some":
+			some = stall
+			foundSome = true
+		case "full":
+			full = &stall
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Stall{}, nil, err
+	}
+	if !foundSome {
+		return Stall{}, nil, fmt.Errorf("no some line")
+	}
+	return some, full, nil
+}
+
+// parseStall parses the key=value fields of a pressure line
+func parseStall(fields []string) (Stall, error) {
+	var stall Stall
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return Stall{}, fmt.Errorf("malformed field %q", field)
+		}
+
+		var err error
+		switch key {
+		case "avg10":
+			stall.Avg10, err = strconv.ParseFloat(value, 64)
+		case "avg60":
+			stall.Avg60, err = strconv.ParseFloat(value, 64)
+		case "avg300":
+			stall.Avg300, err = strconv.ParseFloat(value, 64)
+		case "total":
+			stall.Total, err = strconv.ParseUint(value, 10, 64)
+		}
+		if err != nil {
+			return Stall{}, fmt.Errorf("malformed field %q: %w", field, err)
+		}
+	}
+	return stall, nil
+}
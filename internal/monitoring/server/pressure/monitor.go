@@ -0,0 +1,315 @@
+package pressure
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default values used when the configuration leaves them unset
+const (
+	defaultCheckInterval = 10
+	defaultWindow        = 60
+)
+
+// Monitor periodically reads pressure stall information and alerts when tasks
+// spend too much time waiting for CPU, memory or I/O
+type Monitor struct {
+	config        *config.Config
+	dir           string // Directory holding the pressure files, DefaultDir unless replaced
+	cancelJob     func() // Removes the checks from the scheduler
+	stopChan      chan struct{}
+	isRunning     bool
+	mutex         sync.Mutex
+	lastInfo      *PressureInfo
+	lastAlertTime time.Time
+	emailManager  alerts.NotificationManager
+	alertHandler  *AlertHandler
+	clock         clock.Clock // Time source for checks and alert cooldowns
+}
+
+// NewMonitor creates a new pressure monitor instance
+func NewMonitor(cfg *config.Config) *Monitor {
+	m := &Monitor{
+		config:       cfg,
+		dir:          DefaultDir,
+		stopChan:     make(chan struct{}),
+		emailManager: notifications.NewEmailManager(cfg),
+		clock:        clock.System,
+	}
+	m.alertHandler = NewAlertHandler(m)
+	return m
+}
+
+// SetClock replaces the time source and restarts the alert state from it, so a
+// fake clock governs the durations and cooldowns from the first check. Call it
+// before StartMonitoring.
+func (m *Monitor) SetClock(c clock.Clock) {
+	m.clock = c
+	m.lastAlertTime = time.Time{}
+	m.alertHandler = NewAlertHandler(m)
+}
+
+// Now returns the monitor's current time, which the alert handler uses for cooldowns
+func (m *Monitor) Now() time.Time {
+	return m.clock.Now()
+}
+
+// SetDir replaces the directory the pressure files are read from. Call it before
+// StartMonitoring.
+func (m *Monitor) SetDir(dir string) {
+	m.dir = dir
+}
+
+// SetNotificationManager routes pressure alerts to manager.
+// Call it before StartMonitoring.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.emailManager = manager
+}
+
+// StartMonitoring starts the pressure monitoring process
+func (m *Monitor) StartMonitoring() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isRunning {
+		return fmt.Errorf("pressure monitor is already running")
+	}
+
+	if !m.config.Monitoring.Pressure.Enabled {
+		return fmt.Errorf("pressure monitoring is disabled in configuration")
+	}
+
+	if err := Available(m.dir); err != nil {
+		return err
+	}
+
+	interval := m.checkInterval()
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return m.CheckPressure(ctx)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule pressure checks: %w", err)
+	}
+	m.cancelJob = cancelJob
+	m.isRunning = true
+
+	logger.Info("Starting pressure monitoring",
+		logger.Int("interval_seconds", int(interval.Seconds())))
+
+	return nil
+}
+
+// StopMonitoring stops the pressure monitoring process
+func (m *Monitor) StopMonitoring() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+
+	m.cancelJob()
+	close(m.stopChan)
+	m.isRunning = false
+	logger.Info("Pressure monitoring stopped")
+}
+
+// Name returns "pressure"
+func (m *Monitor) Name() string {
+	return "pressure"
+}
+
+// Start reads the PSI files on the configured interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes the pressure checks from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns the latest *PressureInfo, nil before the first check
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetLastInfo()
+}
+
+// Health measures staleness from the LastCheck of the latest sample
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var lastCheck time.Time
+	if m.lastInfo != nil {
+		lastCheck = m.lastInfo.LastCheck
+	}
+	return monitoring.NewHealth(m.isRunning, lastCheck, m.checkInterval())
+}
+
+// checkInterval returns the configured time between checks, or the default
+func (m *Monitor) checkInterval() time.Duration {
+	interval := m.config.Monitoring.Pressure.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// CheckPressure reads every resource, evaluates its thresholds and raises alerts
+// on status changes. It stops reading once ctx ends.
+func (m *Monitor) CheckPressure(ctx context.Context) error {
+	info := &PressureInfo{Status: "normal", LastCheck: m.clock.Now()}
+
+	for _, resource := range []string{ResourceCPU, ResourceMemory, ResourceIO} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pressure, err := ReadResource(m.dir, resource)
+		if err != nil {
+			logger.Warn("Failed to read pressure stall information",
+				logger.String("resource", resource),
+				logger.String("error", err.Error()))
+			continue
+		}
+
+		evaluate(pressure, m.resourceConfig(resource))
+		if severity(pressure.Status) > severity(info.Status) {
+			info.Status = pressure.Status
+		}
+		info.Resources = append(info.Resources, *pressure)
+	}
+
+	if len(info.Resources) == 0 {
+		return fmt.Errorf("no pressure stall information could be read from %s", m.dir)
+	}
+
+	m.mutex.Lock()
+	m.lastInfo = info
+	m.mutex.Unlock()
+
+	m.broadcast(info)
+
+	for i := range info.Resources {
+		m.alertHandler.HandleResource(&info.Resources[i])
+	}
+	return nil
+}
+
+// resourceConfig returns the thresholds configured for a resource
+func (m *Monitor) resourceConfig(resource string) config.PressureResourceConfig {
+	switch resource {
+	case ResourceCPU:
+		return m.config.Monitoring.Pressure.CPU
+	case ResourceMemory:
+		return m.config.Monitoring.Pressure.Memory
+	default:
+		return m.config.Monitoring.Pressure.IO
+	}
+}
+
+// evaluate sets the status of a resource from the averages over its window and
+// explains which line exceeded which threshold
+func evaluate(pressure *ResourcePressure, cfg config.PressureResourceConfig) {
+	window := cfg.Window
+	if window != 10 && window != 300 {
+		window = defaultWindow
+	}
+	pressure.Window = window
+	pressure.Status = "normal"
+
+	var reasons []string
+	check := func(line string, stall *Stall, warning, critical float64) {
+		if stall == nil {
+			return
+		}
+
+		value := stall.Average(window)
+		status, threshold := "normal", 0.0
+		switch {
+		case critical > 0 && value >= critical:
+			status, threshold = "critical", critical
+		case warning > 0 && value >= warning:
+			status, threshold = "warning", warning
+		default:
+			return
+		}
+
+		reasons = append(reasons, fmt.Sprintf("%s avg%d %.2f%% >= %s threshold %.2f%%",
+			line, window, value, status, threshold))
+		if severity(status) > severity(pressure.Status) {
+			pressure.Status = status
+		}
+	}
+
+	check("some", &pressure.Some, cfg.SomeWarning, cfg.SomeCritical)
+	check("full", pressure.Full, cfg.FullWarning, cfg.FullCritical)
+	pressure.Reason = strings.Join(reasons, "; ")
+}
+
+// severity orders statuses from normal to critical
+func severity(status string) int {
+	switch status {
+	case "critical":
+		return 2
+	case "warning":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// GetLastInfo returns the most recent pressure sample, nil before the first check
+func (m *Monitor) GetLastInfo() *PressureInfo {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastInfo
+}
+
+// GetConfig returns the monitor's configuration
+// Returns interface{} to match the alerts.ConfigProvider interface
+func (m *Monitor) GetConfig() interface{} {
+	return m.config
+}
+
+// GetConfigPtr returns the monitor's configuration as a concrete type pointer
+func (m *Monitor) GetConfigPtr() *config.Config {
+	return m.config
+}
+
+// UpdateLastAlertTime updates the last alert time
+func (m *Monitor) UpdateLastAlertTime() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = m.clock.Now()
+}
+
+// GetLastAlertTime returns the last alert time
+func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastAlertTime
+}
+
+// GetNotificationManagers returns the notification managers
+func (m *Monitor) GetNotificationManagers() alerts.NotificationManager {
+	return m.emailManager
+}
@@ -0,0 +1,47 @@
+package pressure
+
+import "time"
+
+// Resources reported by the kernel below /proc/pressure
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourceIO     = "io"
+)
+
+// Stall is one line of a pressure file
+type Stall struct {
+	Avg10  float64 `json:"avg10"`  // Percent of time stalled over the last 10 seconds
+	Avg60  float64 `json:"avg60"`  // Percent of time stalled over the last 60 seconds
+	Avg300 float64 `json:"avg300"` // Percent of time stalled over the last 300 seconds
+	Total  uint64  `json:"total"`  // Cumulative stall time in microseconds
+}
+
+// Average returns the average over the given window in seconds, avg60 for unknown windows
+func (s Stall) Average(window int) float64 {
+	switch window {
+	case 10:
+		return s.Avg10
+	case 300:
+		return s.Avg300
+	default:
+		return s.Avg60
+	}
+}
+
+// ResourcePressure holds the stall information of one resource
+type ResourcePressure struct {
+	Resource string `json:"resource"`       // cpu, memory or io
+	Some     Stall  `json:"some"`           // At least one task stalled on the resource
+	Full     *Stall `json:"full,omitempty"` // All non-idle tasks stalled at once, nil where not reported
+	Window   int    `json:"window"`         // Average in seconds compared with the thresholds
+	Status   string `json:"status"`         // normal, warning or critical
+	Reason   string `json:"reason,omitempty"`
+}
+
+// PressureInfo holds the stall information of every resource
+type PressureInfo struct {
+	Resources []ResourcePressure `json:"resources"`
+	Status    string             `json:"status"` // Worst status of the resources
+	LastCheck time.Time          `json:"last_check"`
+}
@@ -0,0 +1,42 @@
+package pressure
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/websocket"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketHandler handles WebSocket connections for pressure monitoring
+func (m *Monitor) WebSocketHandler(c *gin.Context) {
+	registry := websocket.GetRegistry()
+	handler := registry.HandlerFor(websocket.TopicPressure)
+
+	logger.Info("New WebSocket client connected for pressure monitoring",
+		logger.String("client_ip", c.ClientIP()))
+
+	// Let the central registry handle the WebSocket connection
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// broadcast pushes a pressure sample to WebSocket clients
+func (m *Monitor) broadcast(info *PressureInfo) {
+	registry := websocket.GetRegistry()
+	if registry.GetHandler(websocket.TopicPressure) == nil {
+		return
+	}
+
+	registry.Broadcast(websocket.TopicPressure, map[string]interface{}{
+		"metric_type": "pressure",
+		"metrics_data": map[string]interface{}{
+			"pressure": info,
+		},
+		"meta": map[string]interface{}{
+			"timestamp":        info.LastCheck,
+			"last_update_time": info.LastCheck.Format(time.RFC3339),
+			"source":           "pressure_monitor",
+			"version":          "1.0",
+		},
+	})
+}
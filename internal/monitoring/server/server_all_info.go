@@ -6,6 +6,7 @@ import (
 	"CheckHealthDO/internal/monitoring/server/memory"
 	"CheckHealthDO/internal/monitoring/server/sysinfo"
 	"CheckHealthDO/internal/pkg/config"
+	"context"
 	"time"
 )
 
@@ -25,25 +26,25 @@ type DiskMetrics struct {
 }
 
 // GetAllServerMetrics collects all server metrics
-func GetServerAllInfo(cfg *config.Config) (*ServerMetrics, error) {
+func GetServerAllInfo(ctx context.Context, cfg *config.Config) (*ServerMetrics, error) {
 	metrics := &ServerMetrics{
 		Timestamp: time.Now(),
 	}
 
 	// Get CPU info
-	cpuInfo, err := cpu.GetCPUInfo(cfg.Monitoring.CPU.WarningThreshold, cfg.Monitoring.CPU.CriticalThreshold)
+	cpuInfo, err := cpu.GetCPUInfo(ctx, cfg.Monitoring.CPU.WarningThreshold, cfg.Monitoring.CPU.CriticalThreshold)
 	if err == nil && cpuInfo != nil {
 		metrics.CPU = cpuInfo
 	}
 
 	// Get Memory info
-	memInfo, err := memory.GetMemoryInfo(cfg.Monitoring.Memory.WarningThreshold, cfg.Monitoring.Memory.CriticalThreshold)
+	memInfo, err := memory.GetMemoryInfo(ctx, cfg.Monitoring.Memory.WarningThreshold, cfg.Monitoring.Memory.CriticalThreshold)
 	if err == nil && memInfo != nil {
 		metrics.Memory = memInfo
 	}
 
 	// Get Disk info including monitored paths
-	diskPartitions, totalStorage, err := disk.GetStorageInfo()
+	diskPartitions, totalStorage, err := disk.GetStorageInfo(ctx)
 	if err == nil {
 		metrics.Disk = &DiskMetrics{
 			Partitions:   diskPartitions,
@@ -1,8 +1,10 @@
 package sysinfo
 
 import (
+	"CheckHealthDO/internal/monitoring"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
 	"CheckHealthDO/internal/websocket"
 	"context"
 	"fmt"
@@ -13,11 +15,12 @@ import (
 // Monitor handles periodic memory monitoring
 type Monitor struct {
 	config    *config.Config
-	ticker    *time.Ticker
+	cancelJob func() // Removes the checks from the scheduler
 	stopChan  chan struct{}
 	isRunning bool
 	mutex     sync.Mutex
 	lastInfo  *SystemInfo
+	lastCheck time.Time // When the last check finished
 }
 
 // NewMonitor creates a new memory monitor instance
@@ -39,24 +42,19 @@ func (m *Monitor) StartMonitoring() error {
 	}
 
 	interval := 1 * time.Second
-	m.ticker = time.NewTicker(interval)
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(context.Context) error {
+			return m.checkSysInfo()
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule system information checks: %w", err)
+	}
+	m.cancelJob = cancelJob
 	m.isRunning = true
 
-	// Run the first check immediately, then continue at intervals
-	go func() {
-		m.checkSysInfo()
-
-		for {
-			select {
-			case <-m.ticker.C:
-				m.checkSysInfo()
-			case <-m.stopChan:
-				m.ticker.Stop()
-				return
-			}
-		}
-	}()
-
 	return nil
 }
 
@@ -69,11 +67,43 @@ func (m *Monitor) StopMonitoring() {
 		return
 	}
 
+	m.cancelJob()
 	close(m.stopChan)
 	m.isRunning = false
 	logger.Info("SysInfo monitor stopped")
 }
 
+// Name returns "sysinfo"
+func (m *Monitor) Name() string {
+	return "sysinfo"
+}
+
+// Start collects system information every second until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes the collection job from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns the latest *SystemInfo, nil before the first collection
+func (m *Monitor) Snapshot() interface{} {
+	return m.getLastInfo()
+}
+
+// Health goes stale when no collection finished in the last three seconds
+func (m *Monitor) Health() monitoring.Health {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return monitoring.NewHealth(m.isRunning, m.lastCheck, time.Second)
+}
+
 // StartBackgroundMonitor creates and starts a memory monitor in a background goroutine
 // Returns a function to stop monitoring
 func StartBackgroundMonitor(ctx context.Context, cfg *config.Config) (func(), error) {
@@ -94,20 +124,28 @@ func (m *Monitor) GetConfig() *config.Config {
 	return m.config
 }
 
+// getLastInfo returns the most recently captured system information
+func (m *Monitor) getLastInfo() *SystemInfo {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastInfo
+}
+
 // checkMemory performs a single memory check
-func (m *Monitor) checkSysInfo() {
+func (m *Monitor) checkSysInfo() error {
 	info, err := GetSystemInfo()
 
 	if err != nil {
 		logger.Error("Failed to get system info",
 			logger.String("error", err.Error()))
-		return
+		return err
 	}
 
 	// Lock before modifying shared data
 	m.mutex.Lock()
 	// Store the latest metrics
 	m.lastInfo = info
+	m.lastCheck = time.Now()
 	m.mutex.Unlock()
 
 	// Format timestamp consistently for all messages
@@ -141,7 +179,9 @@ func (m *Monitor) checkSysInfo() {
 
 	// Also broadcast to memory-specific WebSocket
 	registry := websocket.GetRegistry()
-	if handler := registry.GetSysHandler(); handler != nil {
+	if handler := registry.GetHandler(websocket.TopicSysInfo); handler != nil {
 		registry.BroadcastSysInfo(combinedMsg)
 	}
+
+	return nil
 }
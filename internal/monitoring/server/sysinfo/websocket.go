@@ -11,13 +11,7 @@ import (
 func (m *Monitor) WebSocketHandler(c *gin.Context) {
 	// Initialize the WebSocket registry if needed
 	registry := websocket.GetRegistry()
-	handler := registry.GetSysHandler()
-
-	// Create a new handler if one doesn't exist
-	if handler == nil {
-		handler = websocket.NewHandler()
-		registry.RegisterSysHandler(handler)
-	}
+	handler := registry.HandlerFor(websocket.TopicSysInfo)
 
 	// Force an immediate CPU check to get fresh data
 	m.checkSysInfo()
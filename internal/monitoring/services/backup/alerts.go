@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/logger"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertHandler sends notifications for missing, stale and truncated backups
+type AlertHandler struct {
+	monitor  *Monitor
+	handler  *alerts.Handler
+	notified map[string]string // Last status a notification was sent for, by target name
+}
+
+// NewAlertHandler creates a new alert handler for backups
+func NewAlertHandler(monitor *Monitor) *AlertHandler {
+	return &AlertHandler{
+		monitor:  monitor,
+		handler:  alerts.NewHandler(monitor, nil),
+		notified: make(map[string]string),
+	}
+}
+
+// HandleStatus sends a notification when a backup target becomes unhealthy
+// and a recovery notice once a good backup is found again.
+func (a *AlertHandler) HandleStatus(status *BackupStatus, previousStatus string) {
+	if !a.monitor.config.Notifications.Email.Enabled {
+		return
+	}
+
+	lastNotified := a.notified[status.Name]
+
+	switch status.Status {
+	case "warning", "critical":
+		if lastNotified == status.Status {
+			return
+		}
+		a.notified[status.Name] = status.Status
+		a.sendAlert(status, alerts.AlertType(status.Status), previousStatus != status.Status)
+	case "normal":
+		if lastNotified == "" {
+			return
+		}
+		delete(a.notified, status.Name)
+		a.sendAlert(status, alerts.AlertTypeNormal, true)
+	}
+}
+
+// sendAlert builds and sends the notification email for a backup target
+func (a *AlertHandler) sendAlert(status *BackupStatus, alertType alerts.AlertType, statusChanged bool) {
+	style := a.handler.GetAlertStyle(alertType)
+
+	var title, subject, additionalContent string
+	switch alertType {
+	case alerts.AlertTypeNormal:
+		title = "BACKUP RECOVERED"
+		subject = fmt.Sprintf("Backup OK: %s", status.Name)
+		additionalContent = `<p>A fresh and complete backup has been found. No further action is required.</p>`
+	case alerts.AlertTypeCritical:
+		title = "BACKUP CRITICAL ALERT"
+		subject = fmt.Sprintf("Backup Problem: %s", status.Name)
+		additionalContent = `<p><b>Recommendation:</b> Check the backup job logs and available disk space, then run a new backup as soon as possible.</p>`
+	default:
+		title = "BACKUP WARNING ALERT"
+		subject = fmt.Sprintf("Backup Warning: %s", status.Name)
+		additionalContent = `<p><b>Recommendation:</b> Verify that the backup contains all expected databases.</p>`
+	}
+
+	if len(status.Problems) > 0 {
+		additionalContent += fmt.Sprintf(`
+	<p><b>Problems:</b></p><ul><li>%s</li></ul>`, strings.Join(status.Problems, "</li><li>"))
+	}
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		title,
+		statusChanged,
+		a.createTableContent(status, style),
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	a.handler.SendNotifications(subject, message, string(alertType))
+	a.monitor.UpdateLastAlertTime()
+
+	logger.Info("Sent backup notification",
+		logger.String("name", status.Name),
+		logger.String("status", status.Status))
+}
+
+// createTableContent renders the backup details as an HTML table
+func (a *AlertHandler) createTableContent(status *BackupStatus, style alerts.AlertStyle) string {
+	statusLine := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText)
+
+	rows := []alerts.TableRow{
+		{Label: "Target", Value: status.Name},
+		{Label: "Directory", Value: status.Path},
+		{Label: "Type", Value: status.Type},
+		{Label: "Backups Found", Value: fmt.Sprintf("%d", status.BackupCount)},
+	}
+
+	if status.Latest != nil {
+		rows = append(rows,
+			alerts.TableRow{Label: "Newest Backup", Value: status.Latest.Path},
+			alerts.TableRow{Label: "Created", Value: status.Latest.ModTime.Format(time.RFC1123)},
+			alerts.TableRow{Label: "Age", Value: fmt.Sprintf("%.1f hours", status.AgeHours)},
+			alerts.TableRow{Label: "Size", Value: status.Latest.FormattedSize},
+		)
+	}
+	if status.AverageSize > 0 {
+		rows = append(rows, alerts.TableRow{
+			Label: "Previous Median Size",
+			Value: fmt.Sprintf("%s (%+.1f%%)", formatBytes(status.AverageSize), status.SizeDeviation),
+		})
+	}
+	rows = append(rows, alerts.TableRow{Label: "Integrity", Value: status.IntegrityStatus})
+
+	return statusLine + alerts.CreateTable(rows)
+}
@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Names of the checkpoints file written by mariabackup and older xtrabackup releases
+var checkpointFiles = []string{"mariadb_backup_checkpoints", "xtrabackup_checkpoints"}
+
+// inProgressSuffixes mark files that backup scripts write before renaming them into place
+var inProgressSuffixes = []string{".tmp", ".part", ".partial", ".inprogress", ".incomplete"}
+
+// findBackups lists the backup entries of a target sorted from newest to oldest
+func findBackups(path, backupType, pattern string) ([]BackupEntry, error) {
+	if pattern == "" {
+		if backupType == TypeMysqldump {
+			pattern = "*.sql*"
+		} else {
+			pattern = "*"
+		}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("backup directory is not accessible: %w", err)
+	}
+
+	// A mariabackup target may point directly at a single backup directory
+	if backupType == TypeMariabackup && findCheckpointsFile(path) != "" {
+		entry, err := newEntry(path)
+		if err != nil {
+			return nil, err
+		}
+		return []BackupEntry{entry}, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(path, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup pattern %q: %w", pattern, err)
+	}
+
+	entries := make([]BackupEntry, 0, len(matches))
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		// mysqldump output is always a file; mariabackup produces directories or streamed archives
+		if backupType == TypeMysqldump && info.IsDir() {
+			continue
+		}
+		if backupType == TypeMariabackup && !info.IsDir() && !isArchive(match) {
+			continue
+		}
+
+		entry, err := newEntry(match)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime.After(entries[j].ModTime)
+	})
+
+	return entries, nil
+}
+
+// newEntry builds a BackupEntry, summing the size of directories
+func newEntry(path string) (BackupEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return BackupEntry{}, err
+	}
+
+	entry := BackupEntry{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+	}
+
+	if info.IsDir() {
+		var total int64
+		var lastWrite time.Time
+		filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() {
+				if fi, err := d.Info(); err == nil {
+					total += fi.Size()
+					if fi.ModTime().After(lastWrite) {
+						lastWrite = fi.ModTime()
+					}
+				}
+			}
+			return nil
+		})
+		entry.Size = total
+
+		// The checkpoints file is written last, so its mtime marks completion. Until
+		// it exists the directory was last touched by the newest file written into it.
+		if checkpoints := findCheckpointsFile(path); checkpoints != "" {
+			if cpInfo, err := os.Stat(checkpoints); err == nil {
+				entry.ModTime = cpInfo.ModTime()
+			}
+		} else if lastWrite.After(entry.ModTime) {
+			entry.ModTime = lastWrite
+		}
+	}
+
+	entry.FormattedSize = formatBytes(entry.Size)
+	return entry, nil
+}
+
+// findCheckpointsFile returns the path of the checkpoints file in a backup directory
+func findCheckpointsFile(dir string) string {
+	for _, name := range checkpointFiles {
+		candidate := filepath.Join(dir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// inProgress reports whether a backup is probably still being written: it was
+// modified within the settle time or carries a temporary suffix
+func inProgress(entry BackupEntry, settle time.Duration, now time.Time) bool {
+	if now.Sub(entry.ModTime) < settle {
+		return true
+	}
+	lower := strings.ToLower(entry.Path)
+	for _, suffix := range inProgressSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isArchive reports whether a file looks like a compressed or streamed backup
+func isArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, suffix := range []string{".gz", ".xbstream", ".tar", ".tgz", ".zst", ".xz", ".bz2"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatBytes converts bytes to a human-readable format
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
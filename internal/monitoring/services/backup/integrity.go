@@ -0,0 +1,168 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// dumpTrailer is the comment mysqldump and mariadb-dump write after a complete dump
+const dumpTrailer = "-- Dump completed"
+
+// tailSize is how much of the end of a dump is inspected for the trailer
+const tailSize = 4096
+
+// Integrity statuses
+const (
+	IntegrityOK         = "ok"
+	IntegrityTruncated  = "truncated"
+	IntegrityCorrupt    = "corrupt"
+	IntegrityIncomplete = "incomplete"
+	IntegrityUnchecked  = "unchecked"
+)
+
+// tailBuffer keeps the last n bytes written to it
+type tailBuffer struct {
+	data []byte
+	size int
+}
+
+// Write implements io.Writer
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.data = append(t.data, p...)
+	if len(t.data) > t.size {
+		t.data = t.data[len(t.data)-t.size:]
+	}
+	return len(p), nil
+}
+
+// verifyEntry checks the completeness of a backup entry
+func verifyEntry(entry BackupEntry, backupType string) integrityResult {
+	if entry.IsDir {
+		return verifyMariabackupDir(entry.Path)
+	}
+
+	lower := strings.ToLower(entry.Path)
+	isGzip := strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".tgz")
+
+	if backupType == TypeMysqldump {
+		return verifyDumpFile(entry.Path, isGzip)
+	}
+
+	if isGzip {
+		if err := verifyGzip(entry.Path, io.Discard); err != nil {
+			return integrityResult{status: IntegrityCorrupt, detail: err.Error()}
+		}
+		return integrityResult{status: IntegrityOK, detail: "gzip stream is complete"}
+	}
+
+	return integrityResult{status: IntegrityUnchecked, detail: "archive format is not verified"}
+}
+
+// verifyDumpFile checks that a (optionally gzipped) SQL dump ends with the mysqldump trailer
+func verifyDumpFile(path string, isGzip bool) integrityResult {
+	tail := &tailBuffer{size: tailSize}
+
+	if isGzip {
+		if err := verifyGzip(path, tail); err != nil {
+			return integrityResult{status: IntegrityCorrupt, detail: err.Error()}
+		}
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return integrityResult{status: IntegrityUnchecked, detail: err.Error()}
+		}
+		defer file.Close()
+
+		if info, err := file.Stat(); err == nil && info.Size() > tailSize {
+			if _, err := file.Seek(-tailSize, io.SeekEnd); err != nil {
+				return integrityResult{status: IntegrityUnchecked, detail: err.Error()}
+			}
+		}
+		if _, err := io.Copy(tail, file); err != nil {
+			return integrityResult{status: IntegrityUnchecked, detail: err.Error()}
+		}
+	}
+
+	if !bytes.Contains(tail.data, []byte(dumpTrailer)) {
+		return integrityResult{status: IntegrityTruncated, detail: "dump does not end with \"" + dumpTrailer + "\""}
+	}
+
+	return integrityResult{status: IntegrityOK, detail: "dump trailer found"}
+}
+
+// verifyGzip decompresses the whole file, which validates the CRC and length of every member
+func verifyGzip(path string, w io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return fmt.Errorf("invalid gzip header: %w", err)
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(w, reader); err != nil {
+		return fmt.Errorf("gzip stream is damaged or truncated: %w", err)
+	}
+
+	return nil
+}
+
+// verifyMariabackupDir checks the checkpoints file of a mariabackup directory
+func verifyMariabackupDir(dir string) integrityResult {
+	path := findCheckpointsFile(dir)
+	if path == "" {
+		return integrityResult{status: IntegrityIncomplete, detail: "checkpoints file not found, backup may not have finished"}
+	}
+
+	checkpoints, err := parseCheckpoints(path)
+	if err != nil {
+		return integrityResult{status: IntegrityCorrupt, detail: err.Error()}
+	}
+
+	backupType := checkpoints["backup_type"]
+	if backupType == "" {
+		return integrityResult{status: IntegrityIncomplete, detail: "checkpoints file has no backup_type", checkpoints: checkpoints}
+	}
+	if toLSN := checkpoints["to_lsn"]; toLSN == "" || toLSN == "0" {
+		return integrityResult{status: IntegrityIncomplete, detail: "checkpoints file has no to_lsn", checkpoints: checkpoints}
+	}
+
+	return integrityResult{
+		status:      IntegrityOK,
+		detail:      fmt.Sprintf("backup_type %s, to_lsn %s", backupType, checkpoints["to_lsn"]),
+		checkpoints: checkpoints,
+	}
+}
+
+// parseCheckpoints reads the key = value pairs from a checkpoints file
+func parseCheckpoints(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoints file: %w", err)
+	}
+	defer file.Close()
+
+	checkpoints := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		checkpoints[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints file: %w", err)
+	}
+
+	return checkpoints, nil
+}
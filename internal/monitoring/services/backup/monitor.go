@@ -0,0 +1,395 @@
+package backup
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/notifications"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default values used when the configuration leaves them unset
+const (
+	defaultCheckInterval = 900
+	defaultMaxAge        = 26
+	defaultSizeDeviation = 50.0
+	defaultHistory       = 5
+	defaultSettleTime    = 10
+)
+
+// Monitor verifies the freshness and integrity of MariaDB backups
+type Monitor struct {
+	config        *config.Config
+	cancelJob     func() // Removes the checks from the scheduler
+	stopChan      chan struct{}
+	isRunning     bool
+	mutex         sync.Mutex
+	statuses      map[string]*BackupStatus
+	cache         map[cacheKey]integrityResult
+	lastAlertTime time.Time
+	emailManager  *notifications.EmailManager
+	alertHandler  *AlertHandler
+}
+
+// NewMonitor creates a new backup monitor instance
+func NewMonitor(cfg *config.Config) *Monitor {
+	m := &Monitor{
+		config:       cfg,
+		stopChan:     make(chan struct{}),
+		statuses:     make(map[string]*BackupStatus),
+		cache:        make(map[cacheKey]integrityResult),
+		emailManager: notifications.NewEmailManager(cfg),
+	}
+	m.alertHandler = NewAlertHandler(m)
+	return m
+}
+
+// StartMonitoring starts the backup verification process
+func (m *Monitor) StartMonitoring() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.isRunning {
+		return fmt.Errorf("backup monitor is already running")
+	}
+
+	if !m.config.Monitoring.Backup.Enabled {
+		return fmt.Errorf("backup monitoring is disabled in configuration")
+	}
+
+	interval := m.checkInterval()
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: interval,
+		Run: func(context.Context) error {
+			m.checkAll()
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule backup checks: %w", err)
+	}
+	m.cancelJob = cancelJob
+	m.isRunning = true
+
+	logger.Info("Starting backup monitoring",
+		logger.Int("targets", len(m.config.Monitoring.Backup.Targets)),
+		logger.Int("interval_seconds", int(interval.Seconds())))
+
+	return nil
+}
+
+// StopMonitoring stops the backup verification process
+func (m *Monitor) StopMonitoring() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+
+	m.cancelJob()
+	close(m.stopChan)
+	m.isRunning = false
+	logger.Info("Backup monitoring stopped")
+}
+
+// Name returns "backup"
+func (m *Monitor) Name() string {
+	return "backup"
+}
+
+// Start checks every backup target on the configured interval until Stop or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	if err := m.StartMonitoring(); err != nil {
+		return err
+	}
+	monitoring.StopWhenDone(ctx, m.stopChan, m.StopMonitoring)
+	return nil
+}
+
+// Stop removes the backup checks from the scheduler
+func (m *Monitor) Stop() {
+	m.StopMonitoring()
+}
+
+// Snapshot returns a []BackupStatus with one entry per configured target
+func (m *Monitor) Snapshot() interface{} {
+	return m.GetStatuses()
+}
+
+// Health measures staleness from the most recently checked target
+func (m *Monitor) Health() monitoring.Health {
+	statuses := m.GetStatuses()
+
+	var lastCheck time.Time
+	for _, status := range statuses {
+		if status.LastCheck.After(lastCheck) {
+			lastCheck = status.LastCheck
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return monitoring.NewHealth(m.isRunning, lastCheck, m.checkInterval())
+}
+
+// checkInterval returns the configured time between checks, or the default
+func (m *Monitor) checkInterval() time.Duration {
+	interval := m.config.Monitoring.Backup.CheckInterval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// checkAll verifies every configured backup target
+func (m *Monitor) checkAll() {
+	for _, target := range m.config.Monitoring.Backup.Targets {
+		status := m.checkTarget(applyDefaults(target))
+
+		m.mutex.Lock()
+		previousStatus := "unknown"
+		if previous, ok := m.statuses[status.Name]; ok {
+			previousStatus = previous.Status
+		}
+		m.statuses[status.Name] = status
+		m.mutex.Unlock()
+
+		if status.Status != previousStatus {
+			logger.Info("Backup status changed",
+				logger.String("name", status.Name),
+				logger.String("previous", previousStatus),
+				logger.String("current", status.Status),
+				logger.String("problems", strings.Join(status.Problems, "; ")))
+		}
+
+		m.alertHandler.HandleStatus(status, previousStatus)
+	}
+}
+
+// checkTarget evaluates a single backup target
+func (m *Monitor) checkTarget(target config.BackupTargetConfig) *BackupStatus {
+	now := time.Now()
+	status := &BackupStatus{
+		Name:            target.Name,
+		Path:            target.Path,
+		Type:            target.Type,
+		IntegrityStatus: IntegrityUnchecked,
+		LastCheck:       now,
+	}
+
+	entries, err := findBackups(target.Path, target.Type, target.Pattern)
+	if err != nil {
+		status.Problems = append(status.Problems, err.Error())
+		status.Status = "critical"
+		return status
+	}
+
+	// A backup still being written would fail the size and trailer checks, so only
+	// finished backups are evaluated
+	settle := time.Duration(target.SettleTime) * time.Minute
+	finished := make([]BackupEntry, 0, len(entries))
+	for _, entry := range entries {
+		if inProgress(entry, settle, now) {
+			if status.InProgress == nil {
+				running := entry
+				status.InProgress = &running
+			}
+			continue
+		}
+		finished = append(finished, entry)
+	}
+	entries = finished
+
+	status.BackupCount = len(entries)
+	if len(entries) == 0 {
+		if status.InProgress != nil {
+			status.Problems = append(status.Problems, "no finished backups yet, the first one is still being written")
+			status.Status = "warning"
+			return status
+		}
+		status.Problems = append(status.Problems, "no backups found")
+		status.Status = "critical"
+		return status
+	}
+
+	latest := entries[0]
+	status.Latest = &latest
+	status.AgeHours = math.Round(now.Sub(latest.ModTime).Hours()*10) / 10
+
+	critical := false
+	warning := false
+
+	// Freshness
+	if status.AgeHours > float64(target.MaxAge) {
+		status.Problems = append(status.Problems,
+			fmt.Sprintf("newest backup is %.1f hours old (max %d)", status.AgeHours, target.MaxAge))
+		critical = true
+	}
+
+	// Minimum size
+	if target.MinSize > 0 && latest.Size < target.MinSize*1024*1024 {
+		status.Problems = append(status.Problems,
+			fmt.Sprintf("newest backup is %s, below the minimum of %d MB", latest.FormattedSize, target.MinSize))
+		critical = true
+	}
+
+	// Size anomaly against previous backups
+	previous := entries[1:]
+	if len(previous) > target.History {
+		previous = previous[:target.History]
+	}
+	if len(previous) > 0 {
+		status.AverageSize = medianSize(previous)
+		if status.AverageSize > 0 {
+			deviation := (float64(latest.Size) - float64(status.AverageSize)) / float64(status.AverageSize) * 100
+			status.SizeDeviation = math.Round(deviation*10) / 10
+			if math.Abs(deviation) > target.SizeDeviation {
+				status.Problems = append(status.Problems,
+					fmt.Sprintf("newest backup size deviates %.1f%% from the median of the previous %d backups", deviation, len(previous)))
+				warning = true
+			}
+		}
+	}
+
+	// Integrity, cached per backup version since verifying large dumps is expensive
+	integrity := m.verify(latest, target.Type)
+	status.IntegrityStatus = integrity.status
+	status.IntegrityDetail = integrity.detail
+	status.Checkpoints = integrity.checkpoints
+	switch integrity.status {
+	case IntegrityTruncated, IntegrityCorrupt, IntegrityIncomplete:
+		status.Problems = append(status.Problems, fmt.Sprintf("backup is %s: %s", integrity.status, integrity.detail))
+		critical = true
+	}
+
+	switch {
+	case critical:
+		status.Status = "critical"
+	case warning:
+		status.Status = "warning"
+	default:
+		status.Status = "normal"
+	}
+
+	return status
+}
+
+// verify returns the integrity result for an entry, using the cache when it has not changed
+func (m *Monitor) verify(entry BackupEntry, backupType string) integrityResult {
+	key := cacheKey{path: entry.Path, modTime: entry.ModTime.UnixNano(), size: entry.Size}
+
+	m.mutex.Lock()
+	result, ok := m.cache[key]
+	m.mutex.Unlock()
+	if ok {
+		return result
+	}
+
+	result = verifyEntry(entry, backupType)
+
+	// Only the newest version of each path needs to be remembered
+	m.mutex.Lock()
+	for existing := range m.cache {
+		if existing.path == entry.Path {
+			delete(m.cache, existing)
+		}
+	}
+	m.cache[key] = result
+	m.mutex.Unlock()
+
+	return result
+}
+
+// medianSize returns the median size of the given entries
+func medianSize(entries []BackupEntry) int64 {
+	sizes := make([]int64, len(entries))
+	for i, entry := range entries {
+		sizes[i] = entry.Size
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+
+	mid := len(sizes) / 2
+	if len(sizes)%2 == 0 {
+		return (sizes[mid-1] + sizes[mid]) / 2
+	}
+	return sizes[mid]
+}
+
+// applyDefaults fills unset target fields with sensible defaults
+func applyDefaults(target config.BackupTargetConfig) config.BackupTargetConfig {
+	target.Type = strings.ToLower(target.Type)
+	if target.Type != TypeMysqldump {
+		target.Type = TypeMariabackup
+	}
+	if target.Name == "" {
+		target.Name = target.Path
+	}
+	if target.MaxAge <= 0 {
+		target.MaxAge = defaultMaxAge
+	}
+	if target.SizeDeviation <= 0 {
+		target.SizeDeviation = defaultSizeDeviation
+	}
+	if target.History <= 0 {
+		target.History = defaultHistory
+	}
+	if target.SettleTime <= 0 {
+		target.SettleTime = defaultSettleTime
+	}
+	return target
+}
+
+// GetStatuses returns the latest status of every backup target sorted by name
+func (m *Monitor) GetStatuses() []BackupStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	statuses := make([]BackupStatus, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// GetConfig returns the monitor's configuration
+// Returns interface{} to match the alerts.ConfigProvider interface
+func (m *Monitor) GetConfig() interface{} {
+	return m.config
+}
+
+// GetConfigPtr returns the monitor's configuration as a concrete type pointer
+func (m *Monitor) GetConfigPtr() *config.Config {
+	return m.config
+}
+
+// UpdateLastAlertTime updates the last alert time
+func (m *Monitor) UpdateLastAlertTime() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastAlertTime = time.Now()
+}
+
+// GetLastAlertTime returns the last alert time
+func (m *Monitor) GetLastAlertTime() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lastAlertTime
+}
+
+// GetNotificationManagers returns the notification managers
+func (m *Monitor) GetNotificationManagers() alerts.NotificationManager {
+	return m.emailManager
+}
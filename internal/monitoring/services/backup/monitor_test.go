@@ -0,0 +1,91 @@
+package backup
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// writeDump writes a gzipped dump modified at the given time, with the trailer when complete
+func writeDump(t *testing.T, path string, complete bool, modTime time.Time) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := gzip.NewWriter(file)
+	writer.Write([]byte(strings.Repeat("INSERT INTO t VALUES (1);\n", 1000)))
+	if complete {
+		writer.Write([]byte(dumpTrailer + " on 2024-01-01  2:00:00\n"))
+		writer.Close()
+	} else {
+		// A dump still being written has no gzip trailer yet
+		writer.Flush()
+	}
+	file.Close()
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInProgressBackupSkipped(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeDump(t, filepath.Join(dir, "db-1.sql.gz"), true, now.Add(-26*time.Hour))
+	writeDump(t, filepath.Join(dir, "db-2.sql.gz"), true, now.Add(-2*time.Hour))
+	writeDump(t, filepath.Join(dir, "db-3.sql.gz"), false, now.Add(-time.Minute))
+
+	monitor := NewMonitor(&config.Config{})
+	status := monitor.checkTarget(applyDefaults(config.BackupTargetConfig{Path: dir, Type: TypeMysqldump}))
+
+	if status.Status != "normal" || len(status.Problems) > 0 {
+		t.Errorf("status = %s %q, want normal", status.Status, status.Problems)
+	}
+	if status.Latest == nil || filepath.Base(status.Latest.Path) != "db-2.sql.gz" {
+		t.Errorf("latest = %+v, want the newest finished dump", status.Latest)
+	}
+	if status.InProgress == nil || filepath.Base(status.InProgress.Path) != "db-3.sql.gz" {
+		t.Errorf("in progress = %+v, want the dump being written", status.InProgress)
+	}
+	if status.BackupCount != 2 {
+		t.Errorf("backup count = %d, want 2", status.BackupCount)
+	}
+}
+
+func TestTruncatedBackupAfterSettling(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeDump(t, filepath.Join(dir, "db-1.sql.gz"), true, now.Add(-26*time.Hour))
+	writeDump(t, filepath.Join(dir, "db-2.sql.gz"), false, now.Add(-time.Hour))
+
+	monitor := NewMonitor(&config.Config{})
+	status := monitor.checkTarget(applyDefaults(config.BackupTargetConfig{Path: dir, Type: TypeMysqldump}))
+
+	if status.Status != "critical" || status.IntegrityStatus != IntegrityCorrupt {
+		t.Errorf("status = %s, integrity = %s, want a critical corrupt backup", status.Status, status.IntegrityStatus)
+	}
+}
+
+func TestFirstBackupInProgress(t *testing.T) {
+	dir := t.TempDir()
+	writeDump(t, filepath.Join(dir, "db-1.sql.gz.part"), false, time.Now().Add(-time.Hour))
+
+	monitor := NewMonitor(&config.Config{})
+	status := monitor.checkTarget(applyDefaults(config.BackupTargetConfig{Path: dir, Type: TypeMysqldump, Pattern: "*.sql.gz*"}))
+
+	if status.Status != "warning" || status.InProgress == nil {
+		t.Errorf("status = %s %q, want a warning while the first backup is written", status.Status, status.Problems)
+	}
+}
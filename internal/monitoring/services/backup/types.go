@@ -0,0 +1,51 @@
+package backup
+
+import "time"
+
+// Backup types supported by the monitor
+const (
+	TypeMariabackup = "mariabackup"
+	TypeMysqldump   = "mysqldump"
+)
+
+// BackupStatus represents the verification result of a single backup target
+type BackupStatus struct {
+	Name            string            `json:"name"`
+	Path            string            `json:"path"`
+	Type            string            `json:"type"`
+	Status          string            `json:"status"` // normal, warning or critical
+	Problems        []string          `json:"problems,omitempty"`
+	BackupCount     int               `json:"backup_count"`          // Finished backups
+	Latest          *BackupEntry      `json:"latest,omitempty"`      // Newest finished backup
+	InProgress      *BackupEntry      `json:"in_progress,omitempty"` // Newest backup still being written, not yet evaluated
+	AgeHours        float64           `json:"age_hours"`
+	AverageSize     int64             `json:"average_size"` // Median size of the previous backups compared against
+	SizeDeviation   float64           `json:"size_deviation_percent"`
+	Checkpoints     map[string]string `json:"checkpoints,omitempty"` // Parsed mariabackup checkpoints file
+	IntegrityStatus string            `json:"integrity_status"`      // ok, truncated, corrupt, incomplete or unchecked
+	IntegrityDetail string            `json:"integrity_detail,omitempty"`
+	LastCheck       time.Time         `json:"last_check"`
+}
+
+// BackupEntry describes a single backup file or directory
+type BackupEntry struct {
+	Path          string    `json:"path"`
+	Size          int64     `json:"size"`
+	FormattedSize string    `json:"formatted_size"`
+	ModTime       time.Time `json:"mod_time"`
+	IsDir         bool      `json:"is_dir"`
+}
+
+// integrityResult is the cached outcome of verifying a backup entry
+type integrityResult struct {
+	status      string
+	detail      string
+	checkpoints map[string]string
+}
+
+// cacheKey identifies a backup entry version so unchanged backups are not re-verified
+type cacheKey struct {
+	path    string
+	modTime int64
+	size    int64
+}
@@ -4,7 +4,9 @@ import (
 	"CheckHealthDO/internal/alerts"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
 	"fmt"
+	"html"
 	"strings"
 	"time"
 )
@@ -23,6 +25,15 @@ func NewNotifier(cfg *config.Config) *Notifier {
 	}
 }
 
+// sendEmail sends an alert email, naming the instance in the subject when
+// several MariaDB instances are monitored
+func (n *Notifier) sendEmail(subject, message string) error {
+	if name := n.config.Monitoring.MariaDB.Name; name != "" {
+		subject = fmt.Sprintf("[%s] %s", name, subject)
+	}
+	return n.emailManager.SendEmail(subject, message)
+}
+
 // SendStatusChangeNotification sends notifications about MariaDB status changes
 func (n *Notifier) SendStatusChangeNotification(status *Status, reason string) {
 	// Get server information
@@ -32,9 +43,15 @@ func (n *Notifier) SendStatusChangeNotification(status *Status, reason string) {
 	var alertType alerts.AlertType
 	var subject string
 
-	if status.Status == "stopped" {
+	if status.Status == "unresponsive" {
+		alertType = alerts.AlertTypeCritical
+		subject = "CRITICAL: MariaDB Service Unresponsive"
+	} else if status.Status == "stopped" {
 		// Customize based on stop reason for more specific alerts
-		if strings.Contains(status.StopReason, "Manual Systemctl Stop") {
+		if strings.Contains(status.StopReason, "Liveness Auto-Recovery") {
+			alertType = alerts.AlertTypeWarning
+			subject = "NOTICE: MariaDB Service Restarted Because It Was Unresponsive"
+		} else if strings.Contains(status.StopReason, "Manual Systemctl Stop") {
 			alertType = alerts.AlertTypeWarning // It's not critical if manually stopped
 			subject = "NOTICE: MariaDB Service Manually Stopped"
 		} else if strings.Contains(status.StopReason, "Memory Critical Auto-Recovery") {
@@ -49,7 +66,10 @@ func (n *Notifier) SendStatusChangeNotification(status *Status, reason string) {
 		}
 	} else { // running
 		// Customize based on start reason
-		if strings.Contains(reason, "manually started") {
+		if strings.Contains(reason, "answering queries again") {
+			alertType = alerts.AlertTypeNormal
+			subject = "INFO: MariaDB Service Responsive Again"
+		} else if strings.Contains(reason, "manually started") {
 			alertType = alerts.AlertTypeNormal
 			subject = "INFO: MariaDB Service Manually Started"
 		} else if strings.Contains(reason, "system startup") || strings.Contains(reason, "boot process") {
@@ -69,7 +89,23 @@ func (n *Notifier) SendStatusChangeNotification(status *Status, reason string) {
 
 	// Create additional content based on status
 	var additionalContent string
-	if status.Status == "stopped" && status.StopReason != "" {
+	if status.Status == "unresponsive" {
+		additionalContent = fmt.Sprintf(`
+		<div style="background-color: #f2dede; border-left: 5px solid #d9534f; padding: 10px; margin: 10px 0;">
+			<h3 style="color: #a94442; margin-top: 0;">MariaDB Is Not Answering Queries</h3>
+			<p>The mysqld process is still running but the SQL liveness probe keeps failing.</p>
+			<p>Recommendations:</p>
+			<ul>
+				<li>Check for lock contention or long running queries with <code>SHOW PROCESSLIST</code></li>
+				<li>Check disk space and I/O latency on the data directory</li>
+				<li>Check the MariaDB error log for semaphore waits or storage errors</li>
+			</ul>
+		</div>
+		<div style="background-color: #f5f5f5; border-left: 5px solid #777; padding: 10px; margin: 10px 0;">
+			<h3 style="margin-top: 0;">Probe Error</h3>
+			<pre style="background-color: #eee; padding: 10px; border-radius: 4px; overflow-x: auto;">%s</pre>
+		</div>`, html.EscapeString(formatErrorDetails(reason)))
+	} else if status.Status == "stopped" && status.StopReason != "" {
 		additionalContent = n.createErrorDetailsContent(status)
 	} else if status.Status == "running" {
 		additionalContent = `
@@ -95,7 +131,7 @@ func (n *Notifier) SendStatusChangeNotification(status *Status, reason string) {
 
 	// Send email notification if enabled
 	if n.config.Notifications.Email.Enabled {
-		err := n.emailManager.SendEmail(subject, message)
+		err := n.sendEmail(subject, message)
 		if err != nil {
 			logger.Error("Failed to send email notification for MariaDB status change",
 				logger.String("error", err.Error()))
@@ -114,6 +150,9 @@ func (n *Notifier) createMariaDBStatusTable(status *Status, reason string) strin
 	if status.Status == "stopped" {
 		statusClass = "critical-text"
 		statusText = "STOPPED"
+	} else if status.Status == "unresponsive" {
+		statusClass = "critical-text"
+		statusText = "UNRESPONSIVE"
 	}
 
 	statusLine := alerts.CreateStatusLine(statusClass, statusText)
@@ -125,6 +164,11 @@ func (n *Notifier) createMariaDBStatusTable(status *Status, reason string) strin
 		{Label: "Timestamp", Value: status.Timestamp.Format(time.RFC3339)},
 	}
 
+	// Name the instance when several are monitored on this host
+	if name := n.config.Monitoring.MariaDB.Name; name != "" {
+		tableRows = append([]alerts.TableRow{{Label: "Instance", Value: name}}, tableRows...)
+	}
+
 	// Add reason for status change if available
 	if reason != "" {
 		// Format reason to highlight it better depending on the content
@@ -169,6 +213,20 @@ func (n *Notifier) createMariaDBStatusTable(status *Status, reason string) strin
 		})
 	}
 
+	// Include the liveness probe state when it is in use
+	if status.Liveness != nil {
+		tableRows = append(tableRows,
+			alerts.TableRow{Label: "Liveness Probe", Value: fmt.Sprintf("%d consecutive failures (threshold %d)",
+				status.Liveness.ConsecutiveFailures, status.Liveness.FailureThreshold)},
+			alerts.TableRow{Label: "Probe Latency", Value: fmt.Sprintf("%.2f ms", status.Liveness.LatencyMs)})
+		if !status.Liveness.LastSuccess.IsZero() {
+			tableRows = append(tableRows, alerts.TableRow{
+				Label: "Last Successful Probe",
+				Value: status.Liveness.LastSuccess.Format(time.RFC3339),
+			})
+		}
+	}
+
 	// Create the table HTML
 	tableHTML := alerts.CreateTable(tableRows)
 
@@ -189,6 +247,20 @@ func (n *Notifier) createErrorDetailsContent(status *Status) string {
             <p><strong>This is an expected event if you or another administrator initiated the stop.</strong></p>
         </div>
         `
+	} else if strings.Contains(status.StopReason, "Liveness Auto-Recovery") {
+		content = `
+        <div style="background-color: #fcf8e3; border-left: 5px solid #f0ad4e; padding: 10px; margin: 10px 0;">
+            <h3 style="color: #8a6d3b; margin-top: 0;">Automatic Liveness Recovery Action</h3>
+            <p>The MariaDB process was running but stopped answering queries, so CheckHealthDO <strong>automatically restarted</strong> the service.</p>
+            <p><strong>This is an AUTOMATIC action initiated by the liveness probe - not a manual service stop.</strong></p>
+            <p>Recommendations:</p>
+            <ul>
+                <li>Check the MariaDB error log for long semaphore waits or storage errors</li>
+                <li>Review disk I/O latency and free space on the data directory</li>
+                <li>Look for long running transactions or lock contention after the restart</li>
+            </ul>
+        </div>
+        `
 	} else if strings.Contains(status.StopReason, "Memory Critical Auto-Recovery") {
 		content = `
         <div style="background-color: #fcf8e3; border-left: 5px solid #f0ad4e; padding: 10px; margin: 10px 0;">
@@ -289,3 +361,340 @@ func formatErrorDetails(details string) string {
 
 	return details
 }
+
+// SendLogEventNotification sends a notification for classified error log events of one category
+func (n *Notifier) SendLogEventNotification(category string, events []mariadb.LogEvent, suppressed int) {
+	if !n.config.Notifications.Email.Enabled || len(events) == 0 {
+		return
+	}
+
+	severity := events[0].Severity
+	alertType := alerts.AlertTypeWarning
+	if severity == mariadb.SeverityCritical {
+		alertType = alerts.AlertTypeCritical
+	}
+	style := alerts.DefaultStyles()[alertType]
+
+	label := strings.ToUpper(strings.ReplaceAll(category, "_", " "))
+	subject := fmt.Sprintf("%s: MariaDB Error Log Reports %s", strings.ToUpper(severity), label)
+
+	tableRows := []alerts.TableRow{
+		{Label: "Category", Value: category},
+		{Label: "Severity", Value: severity},
+		{Label: "Events", Value: fmt.Sprintf("%d", len(events))},
+		{Label: "First Seen", Value: events[0].Time.Format(time.RFC3339)},
+	}
+	if suppressed > 0 {
+		tableRows = append(tableRows, alerts.TableRow{
+			Label: "Suppressed Since Last Alert",
+			Value: fmt.Sprintf("%d", suppressed),
+		})
+	}
+	tableContent := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText) + alerts.CreateTable(tableRows)
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, event.Line)
+	}
+	additionalContent := fmt.Sprintf(`
+		<div style="background-color: #f5f5f5; border-left: 5px solid #777; padding: 10px; margin: 10px 0;">
+			<h3 style="margin-top: 0;">Log Lines</h3>
+			<pre style="background-color: #eee; padding: 10px; border-radius: 4px; overflow-x: auto;">%s</pre>
+		</div>
+		`, html.EscapeString(formatErrorDetails(strings.Join(lines, "\n"))))
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		fmt.Sprintf("MariaDB Error Log: %s", label),
+		true,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	if err := n.sendEmail(subject, message); err != nil {
+		logger.Error("Failed to send email notification for MariaDB log event",
+			logger.String("category", category),
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Sent email notification for MariaDB log event",
+		logger.String("category", category),
+		logger.Int("events", len(events)))
+}
+
+// SendSlowQueryReport sends the periodic slow query digest report
+func (n *Notifier) SendSlowQueryReport(digests []mariadb.QueryDigestStats, totalQueries int, from, to time.Time) {
+	if !n.config.Notifications.Email.Enabled {
+		return
+	}
+
+	style := alerts.DefaultStyles()[alerts.AlertTypeNormal]
+	subject := fmt.Sprintf("REPORT: MariaDB Slow Query Digest (%d queries)", totalQueries)
+
+	tableContent := alerts.CreateTable([]alerts.TableRow{
+		{Label: "Period", Value: fmt.Sprintf("%s - %s", from.Format(time.RFC1123), to.Format(time.RFC1123))},
+		{Label: "Slow Queries", Value: fmt.Sprintf("%d", totalQueries)},
+		{Label: "Digests Shown", Value: fmt.Sprintf("%d", len(digests))},
+	})
+
+	var rows strings.Builder
+	for i, d := range digests {
+		rows.WriteString(fmt.Sprintf(`
+			<tr>
+				<td style="padding: 6px; border: 1px solid #ddd;">%d</td>
+				<td style="padding: 6px; border: 1px solid #ddd;"><code>%s</code><br><small>%s</small></td>
+				<td style="padding: 6px; border: 1px solid #ddd; text-align: right;">%d</td>
+				<td style="padding: 6px; border: 1px solid #ddd; text-align: right;">%.2fs (%.1f%%)</td>
+				<td style="padding: 6px; border: 1px solid #ddd; text-align: right;">%.3fs</td>
+				<td style="padding: 6px; border: 1px solid #ddd; text-align: right;">%.3fs</td>
+				<td style="padding: 6px; border: 1px solid #ddd; text-align: right;">%d</td>
+				<td style="padding: 6px; border: 1px solid #ddd; text-align: right;">%d</td>
+			</tr>`,
+			i+1, d.Digest, html.EscapeString(truncateQuery(d.Fingerprint, 300)), d.Count,
+			d.TotalTime, d.ResponseTimeShare, d.AvgTime, d.P95Time, d.RowsExamined, d.RowsSent))
+	}
+
+	additionalContent := fmt.Sprintf(`
+		<h3>Top Queries by Total Time</h3>
+		<table style="border-collapse: collapse; width: 100%%; font-size: 12px;">
+			<tr style="background-color: #f5f5f5;">
+				<th style="padding: 6px; border: 1px solid #ddd;">#</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">Digest / Fingerprint</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">Count</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">Total</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">Avg</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">P95</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">Rows Examined</th>
+				<th style="padding: 6px; border: 1px solid #ddd;">Rows Sent</th>
+			</tr>%s
+		</table>`, rows.String())
+
+	message := alerts.CreateAlertHTML(
+		alerts.AlertTypeNormal,
+		style,
+		"MariaDB Slow Query Digest",
+		false,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	if err := n.sendEmail(subject, message); err != nil {
+		logger.Error("Failed to send MariaDB slow query report",
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Sent MariaDB slow query report",
+		logger.Int("queries", totalQueries),
+		logger.Int("digests", len(digests)))
+}
+
+// truncateQuery shortens long query text for display
+func truncateQuery(query string, limit int) string {
+	if len(query) <= limit {
+		return query
+	}
+	return query[:limit] + "..."
+}
+
+// SendDeadlockRateNotification sends an alert when InnoDB deadlocks exceed the hourly threshold
+func (n *Notifier) SendDeadlockRateNotification(count, threshold int, latest *mariadb.InnoDBDeadlock) {
+	if !n.config.Notifications.Email.Enabled {
+		return
+	}
+
+	style := alerts.DefaultStyles()[alerts.AlertTypeWarning]
+	subject := fmt.Sprintf("WARNING: %d MariaDB Deadlocks in the Last Hour", count)
+
+	tableRows := []alerts.TableRow{
+		{Label: "Deadlocks (last hour)", Value: fmt.Sprintf("%d", count)},
+		{Label: "Threshold", Value: fmt.Sprintf("%d per hour", threshold)},
+	}
+	if latest != nil {
+		tableRows = append(tableRows,
+			alerts.TableRow{Label: "Latest Deadlock", Value: latest.Time.Format(time.RFC3339)},
+			alerts.TableRow{Label: "Rolled Back", Value: fmt.Sprintf("Transaction (%d)", latest.RolledBack)})
+	}
+	tableContent := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText) + alerts.CreateTable(tableRows)
+
+	var additionalContent string
+	if latest != nil {
+		var queries strings.Builder
+		for _, trx := range latest.Transactions {
+			queries.WriteString(fmt.Sprintf("(%d) thread %d %s@%s\n%s\n\n",
+				trx.Number, trx.ThreadID, trx.User, trx.Host, trx.Query))
+		}
+		additionalContent = fmt.Sprintf(`
+		<div style="background-color: #f5f5f5; border-left: 5px solid #777; padding: 10px; margin: 10px 0;">
+			<h3 style="margin-top: 0;">Latest Deadlock Transactions</h3>
+			<pre style="background-color: #eee; padding: 10px; border-radius: 4px; overflow-x: auto;">%s</pre>
+		</div>
+		`, html.EscapeString(formatErrorDetails(strings.TrimSpace(queries.String()))))
+	}
+
+	message := alerts.CreateAlertHTML(
+		alerts.AlertTypeWarning,
+		style,
+		"MariaDB Deadlock Rate",
+		true,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	if err := n.sendEmail(subject, message); err != nil {
+		logger.Error("Failed to send MariaDB deadlock notification",
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Sent MariaDB deadlock notification", logger.Int("deadlocks", count))
+}
+
+// SendSemaphoreWaitNotification sends an alert when an InnoDB semaphore wait exceeds the threshold
+func (n *Notifier) SendSemaphoreWaitNotification(semaphores mariadb.InnoDBSemaphores, threshold int) {
+	if !n.config.Notifications.Email.Enabled {
+		return
+	}
+
+	style := alerts.DefaultStyles()[alerts.AlertTypeCritical]
+	subject := fmt.Sprintf("CRITICAL: MariaDB InnoDB Semaphore Wait of %.0f Seconds", semaphores.MaxWaitSeconds)
+
+	tableRows := []alerts.TableRow{
+		{Label: "Longest Wait", Value: fmt.Sprintf("%.0f seconds", semaphores.MaxWaitSeconds)},
+		{Label: "Threshold", Value: fmt.Sprintf("%d seconds", threshold)},
+		{Label: "Waiting Threads", Value: fmt.Sprintf("%d", len(semaphores.Waits))},
+	}
+	for _, wait := range semaphores.Waits {
+		tableRows = append(tableRows, alerts.TableRow{
+			Label: fmt.Sprintf("Thread %s", wait.Thread),
+			Value: fmt.Sprintf("%.0fs at %s line %d", wait.Seconds, wait.File, wait.Line),
+		})
+	}
+	tableContent := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText) + alerts.CreateTable(tableRows)
+
+	additionalContent := `
+		<div style="background-color: #f2dede; border-left: 5px solid #d9534f; padding: 10px; margin: 10px 0;">
+			<p>Long semaphore waits usually indicate heavy internal contention or stalled I/O.
+			InnoDB intentionally crashes the server when a wait exceeds 600 seconds.</p>
+			<p>Check <code>SHOW ENGINE INNODB STATUS</code>, disk latency and long running transactions.</p>
+		</div>`
+
+	message := alerts.CreateAlertHTML(
+		alerts.AlertTypeCritical,
+		style,
+		"MariaDB InnoDB Semaphore Wait",
+		true,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	if err := n.sendEmail(subject, message); err != nil {
+		logger.Error("Failed to send MariaDB semaphore wait notification",
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Sent MariaDB semaphore wait notification",
+		logger.Float64("max_wait_seconds", semaphores.MaxWaitSeconds))
+}
+
+// SendGaleraNotification sends a notification about Galera cluster health
+func (n *Notifier) SendGaleraNotification(alertType alerts.AlertType, subject, reason string, status *mariadb.GaleraStatus) {
+	if !n.config.Notifications.Email.Enabled {
+		return
+	}
+
+	style := alerts.DefaultStyles()[alertType]
+
+	tableContent := alerts.CreateStatusLine(style.StatusColorClass, style.StatusText) + alerts.CreateTable([]alerts.TableRow{
+		{Label: "Reason", Value: reason},
+		{Label: "Cluster Status", Value: status.ClusterStatus},
+		{Label: "Cluster Size", Value: fmt.Sprintf("%d", status.ClusterSize)},
+		{Label: "Local State", Value: status.LocalState},
+		{Label: "Ready", Value: fmt.Sprintf("%t", status.Ready)},
+		{Label: "Flow Control Paused", Value: fmt.Sprintf("%.1f%%", status.FlowControlPaused*100)},
+		{Label: "Recv Queue", Value: fmt.Sprintf("%d (avg %.2f)", status.RecvQueue, status.RecvQueueAvg)},
+		{Label: "Send Queue", Value: fmt.Sprintf("%d (avg %.2f)", status.SendQueue, status.SendQueueAvg)},
+		{Label: "Cert Failures", Value: fmt.Sprintf("%d", status.CertFailures)},
+		{Label: "Incoming Addresses", Value: status.IncomingAddresses},
+	})
+
+	message := alerts.CreateAlertHTML(
+		alertType,
+		style,
+		"MariaDB Galera Cluster",
+		true,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		"",
+	)
+
+	if err := n.sendEmail(subject, message); err != nil {
+		logger.Error("Failed to send MariaDB Galera notification",
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Sent MariaDB Galera notification", logger.String("subject", subject))
+}
+
+// SendRestartInhibitedNotification pages when the restart policy stops automatic restarts
+func (n *Notifier) SendRestartInhibitedNotification(status mariadb.RestartPolicyStatus) {
+	if !n.config.Notifications.Email.Enabled {
+		return
+	}
+
+	style := alerts.DefaultStyles()[alerts.AlertTypeCritical]
+
+	rows := []alerts.TableRow{
+		{Label: "Service Name", Value: status.ServiceName},
+		{Label: "Reason", Value: status.InhibitReason},
+		{Label: "Restarts In Window", Value: fmt.Sprintf("%d of %d in %s", status.RestartsInWindow, status.MaxRestarts,
+			time.Duration(status.WindowSeconds)*time.Second)},
+		{Label: "Inhibited At", Value: status.InhibitedAt.Format(time.RFC3339)},
+	}
+	if status.LastTrigger != "" {
+		rows = append(rows, alerts.TableRow{Label: "Last Restart Trigger", Value: status.LastTrigger})
+	}
+	if !status.LastRestart.IsZero() {
+		rows = append(rows, alerts.TableRow{Label: "Last Restart", Value: status.LastRestart.Format(time.RFC3339)})
+	}
+
+	tableContent := alerts.CreateStatusLine(style.StatusColorClass, "RESTART INHIBITED") + alerts.CreateTable(rows)
+
+	additionalContent := `
+		<div style="background-color: #f2dede; border-left: 5px solid #d9534f; padding: 10px; margin: 10px 0;">
+			<h3 style="color: #a94442; margin-top: 0;">Automatic Restarts Stopped - Manual Action Required</h3>
+			<p>CheckHealthDO will not restart MariaDB automatically until the restart policy is reset.</p>
+			<p>Recommendations:</p>
+			<ul>
+				<li>Check the MariaDB error log for crash or corruption messages before starting the service again</li>
+				<li>Check free space on the data directory with <code>df -h</code></li>
+				<li>Reset the policy with <code>POST /api/mariadb/restart-policy/reset</code> once the cause is fixed</li>
+			</ul>
+		</div>`
+
+	message := alerts.CreateAlertHTML(
+		alerts.AlertTypeCritical,
+		style,
+		"MariaDB Automatic Restarts Inhibited",
+		true,
+		tableContent,
+		alerts.GetServerInfoForAlert(),
+		additionalContent,
+	)
+
+	if err := n.sendEmail("CRITICAL: MariaDB Automatic Restarts Inhibited", message); err != nil {
+		logger.Error("Failed to send MariaDB restart inhibited notification",
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Sent MariaDB restart inhibited notification")
+}
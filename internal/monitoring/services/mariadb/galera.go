@@ -0,0 +1,233 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default values for Galera monitoring
+const (
+	defaultGaleraInterval       = 10
+	defaultFlowControlThreshold = 0.1
+	defaultFlowControlSamples   = 3
+	defaultSizeSettleTime       = 3600
+)
+
+// GaleraCollector samples wsrep status and alerts on cluster health problems
+type GaleraCollector struct {
+	config         *config.Config
+	notifier       *Notifier
+	mu             sync.RWMutex
+	latest         *mariadb.GaleraStatus
+	sampledAt      time.Time
+	maxSize        int       // Largest cluster size seen, used to detect nodes leaving
+	smallerSince   time.Time // When the cluster size last changed while below maxSize
+	smallerSize    int       // Size the cluster has had since smallerSince
+	flowSamples    int       // Consecutive samples over the flow control threshold
+	quorumAlerted  bool      // A lost quorum alert is outstanding
+	sizeAlerted    bool      // A node-left alert is outstanding
+	flowAlerted    bool      // A flow control alert is outstanding
+	notGaleraNoted bool
+}
+
+// NewGaleraCollector creates a new Galera collector
+func NewGaleraCollector(cfg *config.Config, notifier *Notifier) *GaleraCollector {
+	return &GaleraCollector{
+		config:   cfg,
+		notifier: notifier,
+	}
+}
+
+// Run samples Galera status until the context is cancelled or stop is closed
+func (g *GaleraCollector) Run(ctx context.Context, stop <-chan struct{}, isRunning func() bool) {
+	interval := g.config.Monitoring.MariaDB.Galera.CheckInterval
+	if interval <= 0 {
+		interval = defaultGaleraInterval
+	}
+
+	err := scheduler.RunUntil(ctx, stop, scheduler.Job{
+		Name:     monitorName(g.config) + "/galera",
+		Interval: time.Duration(interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			g.sample(ctx, isRunning)
+			return nil
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to schedule Galera collection", logger.String("error", err.Error()))
+	}
+}
+
+// sample reads wsrep status and evaluates alert conditions
+func (g *GaleraCollector) sample(ctx context.Context, isRunning func() bool) {
+	if !isRunning() {
+		g.mu.Lock()
+		g.latest = nil
+		g.mu.Unlock()
+		return
+	}
+
+	status, err := mariadb.GetGaleraStatus(ctx, mariadb.GetDBConfigFromConfig(g.config))
+	if err != nil {
+		logger.Warn("Failed to read Galera status", logger.String("error", err.Error()))
+		return
+	}
+
+	if !status.Enabled {
+		if !g.notGaleraNoted {
+			logger.Warn("Galera monitoring is enabled but the server is not a Galera node")
+			g.notGaleraNoted = true
+		}
+		g.mu.Lock()
+		g.latest = status
+		g.mu.Unlock()
+		return
+	}
+	g.notGaleraNoted = false
+
+	now := time.Now()
+
+	g.mu.Lock()
+	if g.latest != nil && g.latest.Enabled {
+		mariadb.ComputeFlowControlPaused(g.latest, status, now.Sub(g.sampledAt).Nanoseconds())
+	} else {
+		mariadb.ComputeFlowControlPaused(nil, status, 0)
+	}
+	g.latest = status
+	g.sampledAt = now
+	g.mu.Unlock()
+
+	g.evaluate(status, now)
+}
+
+// evaluate sends alerts for lost quorum, departed nodes and sustained flow control
+func (g *GaleraCollector) evaluate(status *mariadb.GaleraStatus, now time.Time) {
+	galeraCfg := g.config.Monitoring.MariaDB.Galera
+
+	// Quorum
+	if status.ClusterStatus != "Primary" {
+		if !g.quorumAlerted {
+			g.quorumAlerted = true
+			g.notifier.SendGaleraNotification(alerts.AlertTypeCritical,
+				"CRITICAL: MariaDB Galera Node Lost Quorum",
+				fmt.Sprintf("Cluster status is %s; this node no longer accepts writes", status.ClusterStatus),
+				status)
+		}
+	} else if g.quorumAlerted {
+		g.quorumAlerted = false
+		g.notifier.SendGaleraNotification(alerts.AlertTypeNormal,
+			"INFO: MariaDB Galera Quorum Restored",
+			"Cluster status is Primary again", status)
+	}
+
+	// Cluster size
+	expected := galeraCfg.ExpectedSize
+	if expected <= 0 {
+		g.trackMaxSize(status, now)
+		expected = g.maxSize
+	}
+	if status.ClusterSize < expected {
+		if !g.sizeAlerted {
+			g.sizeAlerted = true
+			g.notifier.SendGaleraNotification(alerts.AlertTypeWarning,
+				"WARNING: MariaDB Galera Node Left the Cluster",
+				fmt.Sprintf("Cluster size dropped to %d of %d nodes", status.ClusterSize, expected),
+				status)
+		}
+	} else if g.sizeAlerted {
+		g.sizeAlerted = false
+		g.notifier.SendGaleraNotification(alerts.AlertTypeNormal,
+			"INFO: MariaDB Galera Cluster Size Restored",
+			fmt.Sprintf("Cluster size is back to %d nodes", status.ClusterSize), status)
+	}
+
+	// Flow control
+	threshold := galeraCfg.FlowControlThreshold
+	if threshold <= 0 {
+		threshold = defaultFlowControlThreshold
+	}
+	samples := galeraCfg.FlowControlSamples
+	if samples <= 0 {
+		samples = defaultFlowControlSamples
+	}
+	if status.FlowControlPaused >= threshold {
+		g.flowSamples++
+	} else {
+		g.flowSamples = 0
+	}
+	if g.flowSamples >= samples {
+		if !g.flowAlerted {
+			g.flowAlerted = true
+			g.notifier.SendGaleraNotification(alerts.AlertTypeWarning,
+				"WARNING: MariaDB Galera Sustained Flow Control",
+				fmt.Sprintf("Replication was paused %.0f%% of the time for %d consecutive samples",
+					status.FlowControlPaused*100, g.flowSamples),
+				status)
+		}
+	} else if g.flowSamples == 0 && g.flowAlerted {
+		g.flowAlerted = false
+		g.notifier.SendGaleraNotification(alerts.AlertTypeNormal,
+			"INFO: MariaDB Galera Flow Control Cleared",
+			"Replication is no longer throttled by flow control", status)
+	}
+}
+
+// trackMaxSize follows the largest cluster size seen. Once a smaller cluster
+// has been stable for the settle time it becomes the new baseline, so a node
+// that was removed on purpose does not keep the alert raised forever.
+func (g *GaleraCollector) trackMaxSize(status *mariadb.GaleraStatus, now time.Time) {
+	if status.ClusterSize >= g.maxSize {
+		g.maxSize = status.ClusterSize
+		g.smallerSince = time.Time{}
+		return
+	}
+
+	if g.smallerSince.IsZero() || status.ClusterSize != g.smallerSize {
+		g.smallerSince = now
+		g.smallerSize = status.ClusterSize
+		return
+	}
+
+	settle := g.config.Monitoring.MariaDB.Galera.SizeSettleTime
+	if settle <= 0 {
+		settle = defaultSizeSettleTime
+	}
+	if now.Sub(g.smallerSince) < time.Duration(settle)*time.Second {
+		return
+	}
+
+	logger.Info("Galera cluster size settled at a smaller size",
+		logger.Int("previous_size", g.maxSize),
+		logger.Int("cluster_size", status.ClusterSize))
+	g.maxSize = status.ClusterSize
+	g.smallerSince = time.Time{}
+	if g.sizeAlerted {
+		g.sizeAlerted = false
+		g.notifier.SendGaleraNotification(alerts.AlertTypeNormal,
+			"INFO: MariaDB Galera Cluster Size Accepted",
+			fmt.Sprintf("Cluster has run with %d nodes for %s; this is now the expected size",
+				status.ClusterSize, time.Duration(settle)*time.Second), status)
+	}
+}
+
+// Latest returns the most recent Galera status
+func (g *GaleraCollector) Latest() *mariadb.GaleraStatus {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.latest
+}
+
+// GetGaleraStatus returns the latest Galera status, or false when Galera monitoring is disabled
+func (m *Monitor) GetGaleraStatus() (*mariadb.GaleraStatus, bool) {
+	if m.galera == nil {
+		return nil, false
+	}
+	return m.galera.Latest(), true
+}
@@ -0,0 +1,82 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// newTestNotifier creates a notifier that records its notifications
+func newTestNotifier(cfg *config.Config) (*Notifier, *alerts.Recorder) {
+	cfg.Notifications.Email.Enabled = true
+	recorder := alerts.NewRecorder()
+	return &Notifier{config: cfg, emailManager: recorder}, recorder
+}
+
+func TestGaleraSizeBaselineSettles(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Monitoring.MariaDB.Galera.SizeSettleTime = 600
+	notifier, recorder := newTestNotifier(cfg)
+	collector := NewGaleraCollector(cfg, notifier)
+
+	start := time.Now()
+	sizes := []struct {
+		size   int
+		offset time.Duration
+	}{
+		{3, 0},
+		{2, time.Minute},      // A node leaves
+		{2, 5 * time.Minute},  // Still waiting for it to come back
+		{1, 6 * time.Minute},  // Another one leaves, the settle time restarts
+		{1, 15 * time.Minute}, // Not stable for long enough yet
+		{1, 17 * time.Minute}, // Stable for ten minutes, now the baseline
+		{1, 20 * time.Minute},
+		{2, 25 * time.Minute}, // A node joins and raises the baseline again
+		{1, 26 * time.Minute},
+	}
+	for _, s := range sizes {
+		collector.evaluate(&mariadb.GaleraStatus{Enabled: true, ClusterStatus: "Primary", ClusterSize: s.size}, start.Add(s.offset))
+	}
+
+	want := []string{
+		"WARNING: MariaDB Galera Node Left the Cluster",
+		"INFO: MariaDB Galera Cluster Size Accepted",
+		"WARNING: MariaDB Galera Node Left the Cluster",
+	}
+	if got := recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+	if collector.maxSize != 2 {
+		t.Errorf("baseline = %d, want 2", collector.maxSize)
+	}
+}
+
+func TestGaleraExpectedSizeDoesNotSettle(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Monitoring.MariaDB.Galera.ExpectedSize = 3
+	cfg.Monitoring.MariaDB.Galera.SizeSettleTime = 60
+	notifier, recorder := newTestNotifier(cfg)
+	collector := NewGaleraCollector(cfg, notifier)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		collector.evaluate(&mariadb.GaleraStatus{Enabled: true, ClusterStatus: "Primary", ClusterSize: 2}, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	want := []string{"WARNING: MariaDB Galera Node Left the Cluster"}
+	if got := recorder.Subjects(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("notifications = %q, want %q", got, want)
+	}
+}
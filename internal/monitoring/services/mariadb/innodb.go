@@ -0,0 +1,256 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"sync"
+	"time"
+)
+
+// Default values for InnoDB status sampling
+const (
+	defaultInnoDBInterval      = 60
+	defaultDeadlockHistory     = 100
+	defaultDeadlockThreshold   = 5
+	defaultSemaphoreWait       = 60
+	defaultInnoDBAlertCooldown = 900
+)
+
+// DeadlockRecord is a deadlock captured from the InnoDB status output
+type DeadlockRecord struct {
+	ID         int                    `json:"id"`
+	CapturedAt time.Time              `json:"captured_at"`
+	Deadlock   mariadb.InnoDBDeadlock `json:"deadlock"`
+}
+
+// deadlockSample is an increase of the Innodb_deadlocks counter
+type deadlockSample struct {
+	at    time.Time
+	count int64
+}
+
+// InnoDBCollector samples SHOW ENGINE INNODB STATUS and records deadlocks
+type InnoDBCollector struct {
+	config       *config.Config
+	notifier     *Notifier
+	mu           sync.RWMutex
+	latest       *mariadb.InnoDBStatus
+	deadlocks    []DeadlockRecord // Most recent last
+	nextID       int
+	lastRaw      string // Raw text of the last recorded deadlock, to skip repeats
+	sampled      bool   // Whether a first snapshot has been taken
+	lastCounter  int64
+	haveCounter  bool
+	samples      []deadlockSample // Counter increases within the last hour
+	lastAlerts   map[string]time.Time
+	historyLimit int
+}
+
+// NewInnoDBCollector creates a new InnoDB status collector
+func NewInnoDBCollector(cfg *config.Config, notifier *Notifier) *InnoDBCollector {
+	historyLimit := cfg.Monitoring.MariaDB.InnoDB.DeadlockHistory
+	if historyLimit <= 0 {
+		historyLimit = defaultDeadlockHistory
+	}
+
+	return &InnoDBCollector{
+		config:       cfg,
+		notifier:     notifier,
+		nextID:       1,
+		lastAlerts:   make(map[string]time.Time),
+		historyLimit: historyLimit,
+	}
+}
+
+// Run samples the InnoDB status until the context is cancelled or stop is closed
+func (c *InnoDBCollector) Run(ctx context.Context, stop <-chan struct{}, isRunning func() bool) {
+	interval := c.config.Monitoring.MariaDB.InnoDB.CheckInterval
+	if interval <= 0 {
+		interval = defaultInnoDBInterval
+	}
+
+	err := scheduler.RunUntil(ctx, stop, scheduler.Job{
+		Name:     monitorName(c.config) + "/innodb",
+		Interval: time.Duration(interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			c.sample(ctx, isRunning)
+			return nil
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to schedule InnoDB collection", logger.String("error", err.Error()))
+	}
+}
+
+// sample collects and parses one InnoDB status snapshot
+func (c *InnoDBCollector) sample(ctx context.Context, isRunning func() bool) {
+	if !isRunning() {
+		// The deadlock counter restarts with the server
+		c.mu.Lock()
+		c.haveCounter = false
+		c.mu.Unlock()
+		return
+	}
+
+	dbConfig := mariadb.GetDBConfigFromConfig(c.config)
+
+	text, err := mariadb.GetInnoDBStatus(ctx, dbConfig)
+	if err != nil {
+		logger.Warn("Failed to sample InnoDB status", logger.String("error", err.Error()))
+		return
+	}
+	status := mariadb.ParseInnoDBStatus(text)
+
+	counter, counterErr := mariadb.GetDeadlockCount(ctx, dbConfig)
+	if counterErr != nil {
+		logger.Debug("Failed to read Innodb_deadlocks counter", logger.String("error", counterErr.Error()))
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	c.latest = status
+
+	newDeadlock := false
+	if status.LatestDeadlock != nil && status.LatestDeadlock.Raw != c.lastRaw {
+		// A deadlock already present in the first sample happened before startup
+		newDeadlock = c.sampled
+		c.lastRaw = status.LatestDeadlock.Raw
+		c.recordDeadlock(*status.LatestDeadlock, now)
+	}
+
+	// Several deadlocks may occur between samples, the counter catches those
+	increase := int64(0)
+	if counterErr == nil {
+		if c.haveCounter && counter >= c.lastCounter {
+			increase = counter - c.lastCounter
+		}
+		c.lastCounter = counter
+		c.haveCounter = true
+	}
+	if increase == 0 && newDeadlock {
+		increase = 1
+	}
+	if increase > 0 {
+		c.samples = append(c.samples, deadlockSample{at: now, count: increase})
+	}
+
+	deadlocksLastHour := c.countRecent(now)
+	c.sampled = true
+	c.mu.Unlock()
+
+	if newDeadlock {
+		logger.Warn("New InnoDB deadlock detected",
+			logger.Int("transactions", len(status.LatestDeadlock.Transactions)),
+			logger.Int("rolled_back", status.LatestDeadlock.RolledBack))
+	}
+
+	c.checkThresholds(status, deadlocksLastHour)
+}
+
+// recordDeadlock appends a deadlock to the bounded history. Must be called with the lock held.
+func (c *InnoDBCollector) recordDeadlock(deadlock mariadb.InnoDBDeadlock, now time.Time) {
+	c.deadlocks = append(c.deadlocks, DeadlockRecord{
+		ID:         c.nextID,
+		CapturedAt: now,
+		Deadlock:   deadlock,
+	})
+	c.nextID++
+
+	if len(c.deadlocks) > c.historyLimit {
+		c.deadlocks = append(c.deadlocks[:0], c.deadlocks[len(c.deadlocks)-c.historyLimit:]...)
+	}
+}
+
+// countRecent drops samples older than an hour and returns the remaining total. Must be called with the lock held.
+func (c *InnoDBCollector) countRecent(now time.Time) int {
+	cutoff := now.Add(-time.Hour)
+	start := 0
+	for start < len(c.samples) && c.samples[start].at.Before(cutoff) {
+		start++
+	}
+	c.samples = append(c.samples[:0], c.samples[start:]...)
+
+	total := int64(0)
+	for _, s := range c.samples {
+		total += s.count
+	}
+	return int(total)
+}
+
+// checkThresholds raises alerts for a high deadlock rate or long semaphore waits
+func (c *InnoDBCollector) checkThresholds(status *mariadb.InnoDBStatus, deadlocksLastHour int) {
+	innodbCfg := c.config.Monitoring.MariaDB.InnoDB
+
+	deadlockThreshold := innodbCfg.DeadlockThreshold
+	if deadlockThreshold <= 0 {
+		deadlockThreshold = defaultDeadlockThreshold
+	}
+	if deadlocksLastHour >= deadlockThreshold && c.shouldAlert("deadlock") {
+		c.notifier.SendDeadlockRateNotification(deadlocksLastHour, deadlockThreshold, status.LatestDeadlock)
+	}
+
+	semaphoreWait := innodbCfg.SemaphoreWait
+	if semaphoreWait <= 0 {
+		semaphoreWait = defaultSemaphoreWait
+	}
+	if status.Semaphores.MaxWaitSeconds >= float64(semaphoreWait) && c.shouldAlert("semaphore") {
+		c.notifier.SendSemaphoreWaitNotification(status.Semaphores, semaphoreWait)
+	}
+}
+
+// shouldAlert applies the alert cooldown per alert kind
+func (c *InnoDBCollector) shouldAlert(kind string) bool {
+	cooldown := time.Duration(c.config.Monitoring.MariaDB.InnoDB.AlertCooldown) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultInnoDBAlertCooldown * time.Second
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last, ok := c.lastAlerts[kind]; ok && time.Since(last) < cooldown {
+		return false
+	}
+	c.lastAlerts[kind] = time.Now()
+	return true
+}
+
+// Latest returns the most recent parsed InnoDB status
+func (c *InnoDBCollector) Latest() *mariadb.InnoDBStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.latest
+}
+
+// Deadlocks returns the recorded deadlocks, newest first, and the number seen in the last hour
+func (c *InnoDBCollector) Deadlocks() ([]DeadlockRecord, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	records := make([]DeadlockRecord, 0, len(c.deadlocks))
+	for i := len(c.deadlocks) - 1; i >= 0; i-- {
+		records = append(records, c.deadlocks[i])
+	}
+	return records, c.countRecent(time.Now())
+}
+
+// GetInnoDBStatus returns the latest parsed InnoDB status, or false when sampling is disabled
+func (m *Monitor) GetInnoDBStatus() (*mariadb.InnoDBStatus, bool) {
+	if m.innodb == nil {
+		return nil, false
+	}
+	return m.innodb.Latest(), true
+}
+
+// GetDeadlocks returns the deadlock history, or false when sampling is disabled
+func (m *Monitor) GetDeadlocks() ([]DeadlockRecord, int, bool) {
+	if m.innodb == nil {
+		return nil, 0, false
+	}
+	records, lastHour := m.innodb.Deadlocks()
+	return records, lastHour, true
+}
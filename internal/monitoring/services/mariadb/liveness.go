@@ -0,0 +1,166 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/runner"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Default values for the SQL liveness probe
+const (
+	defaultProbeTimeout          = 5
+	defaultProbeFailureThreshold = 3
+	defaultHeartbeatTable        = "checkhealth.heartbeat"
+	defaultRestartCooldown       = 600
+)
+
+// LivenessStatus holds the outcome of the SQL liveness probe
+type LivenessStatus struct {
+	Healthy             bool      `json:"healthy"`
+	LatencyMs           float64   `json:"latency_ms"`
+	ReadOnly            bool      `json:"read_only,omitempty"` // Write mode fell back to a read on a read-only server
+	Stage               string    `json:"stage"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailureThreshold    int       `json:"failure_threshold"`
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+	LastCheck           time.Time `json:"last_check"`
+}
+
+// runLivenessProbe runs the configured SQL probe against the server
+func (m *Monitor) runLivenessProbe(ctx context.Context) mariadb.ProbeResult {
+	livenessCfg := m.config.Monitoring.MariaDB.Liveness
+
+	timeout := livenessCfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	table := livenessCfg.HeartbeatTable
+	if table == "" {
+		table = defaultHeartbeatTable
+	}
+
+	return mariadb.ProbeLiveness(ctx, mariadb.GetDBConfigFromConfig(m.config),
+		strings.ToLower(livenessCfg.Mode), table, time.Duration(timeout)*time.Second)
+}
+
+// recordProbeResult updates the liveness state and reports whether the
+// failure threshold has been reached. Must be called with the lock held.
+func (m *Monitor) recordProbeResult(result mariadb.ProbeResult, now time.Time) bool {
+	threshold := m.config.Monitoring.MariaDB.Liveness.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultProbeFailureThreshold
+	}
+
+	liveness := m.status.Liveness
+	if liveness == nil {
+		liveness = &LivenessStatus{}
+		m.status.Liveness = liveness
+	}
+
+	liveness.Healthy = result.Success
+	liveness.LatencyMs = float64(result.Latency.Microseconds()) / 1000
+	liveness.Stage = result.Stage
+	liveness.ReadOnly = result.ReadOnly
+	liveness.FailureThreshold = threshold
+	liveness.LastCheck = now
+
+	if result.Success {
+		liveness.ConsecutiveFailures = 0
+		liveness.LastError = ""
+		liveness.LastSuccess = now
+		return false
+	}
+
+	liveness.ConsecutiveFailures++
+	liveness.LastError = result.Error
+
+	logger.Warn("MariaDB liveness probe failed",
+		logger.String("stage", result.Stage),
+		logger.String("error", result.Error),
+		logger.Int("consecutive_failures", liveness.ConsecutiveFailures))
+
+	return liveness.ConsecutiveFailures >= threshold
+}
+
+// livenessReason describes why the service is considered unresponsive
+func livenessReason(liveness *LivenessStatus) string {
+	if liveness == nil {
+		return "SQL liveness probe failed"
+	}
+	return fmt.Sprintf("SQL liveness probe failed %d consecutive times at %s stage: %s",
+		liveness.ConsecutiveFailures, liveness.Stage, liveness.LastError)
+}
+
+// shouldAutoRestart reports whether an unresponsive server should be restarted now.
+// Must be called with the lock held.
+func (m *Monitor) shouldAutoRestart() bool {
+	livenessCfg := m.config.Monitoring.MariaDB.Liveness
+	if livenessCfg.AutoRestartAfter <= 0 || m.status.Liveness == nil || m.restarting {
+		return false
+	}
+	if m.status.Liveness.ConsecutiveFailures < livenessCfg.AutoRestartAfter {
+		return false
+	}
+
+	cooldown := livenessCfg.RestartCooldown
+	if cooldown <= 0 {
+		cooldown = defaultRestartCooldown
+	}
+	if !m.lastAutoRestart.IsZero() && m.clock.Since(m.lastAutoRestart) < time.Duration(cooldown)*time.Second {
+		return false
+	}
+
+	m.restarting = true
+	m.lastAutoRestart = m.clock.Now()
+	return true
+}
+
+// restartUnresponsive restarts a server that stopped answering queries. It outlives
+// the check that saw the failed probes, so ctx carries no deadline.
+func (m *Monitor) restartUnresponsive(ctx context.Context, reason string) {
+	defer func() {
+		m.mu.Lock()
+		m.restarting = false
+		m.mu.Unlock()
+	}()
+
+	serviceName := m.config.Monitoring.MariaDB.ServiceName
+
+	decision := mariadb.GetRestartPolicy(m.config).Allow("Liveness Auto-Recovery")
+	if !decision.Allowed {
+		logger.Warn("Skipping restart of unresponsive MariaDB service",
+			logger.String("service", serviceName),
+			logger.String("reason", decision.Reason))
+		return
+	}
+
+	logger.Warn("Restarting unresponsive MariaDB service",
+		logger.String("service", serviceName),
+		logger.String("reason", reason))
+
+	// Record the restart where ClassifyStopReason looks for auto-recovery evidence
+	now := m.clock.Now()
+	entry := fmt.Sprintf("%s (%s): %s", livenessRecoveryTag, serviceName, reason)
+	if err := mariadb.RecordRestart(m.config, now, entry); err != nil {
+		logger.Warn("Failed to record MariaDB restart", logger.String("error", err.Error()))
+	}
+
+	restartMsg := fmt.Sprintf("CHECKHEALTHDO_LIVENESS_AUTO_RECOVERY_%s: Restarting unresponsive MariaDB (%s)",
+		now.Format("20060102_150405"), reason)
+	if _, err := runner.Output(ctx, "logger", "-t", "CheckHealthDO", restartMsg); err != nil {
+		logger.Debug("Failed to write MariaDB restart marker to the journal", logger.String("error", err.Error()))
+	}
+
+	if err := mariadb.RestartMariaDBService(ctx, serviceName); err != nil {
+		logger.Error("Failed to restart unresponsive MariaDB service",
+			logger.String("error", err.Error()))
+		return
+	}
+
+	logger.Info("Restarted unresponsive MariaDB service")
+}
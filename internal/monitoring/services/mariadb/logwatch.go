@@ -0,0 +1,230 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"CheckHealthDO/internal/services/mariadb"
+	"CheckHealthDO/internal/websocket"
+	"context"
+	"sync"
+	"time"
+)
+
+// Default values for the error log watcher
+const (
+	defaultLogPollInterval = 1
+	defaultLogBufferSize   = 1000
+	defaultLogAlertCool    = 300
+)
+
+// LogWatcher follows the MariaDB error log and keeps recent classified events
+type LogWatcher struct {
+	config     *config.Config
+	notifier   *Notifier
+	follower   *mariadb.LogFollower
+	mu         sync.RWMutex
+	events     []mariadb.LogEvent // Ring buffer of the most recent events
+	nextID     uint64
+	bufferSize int
+	alertOn    map[string]bool
+	lastAlert  map[string]time.Time // Last alert time per category
+	suppressed map[string]int       // Events not alerted per category due to cooldown
+}
+
+// NewLogWatcher creates a new error log watcher
+func NewLogWatcher(cfg *config.Config, notifier *Notifier) *LogWatcher {
+	logCfg := cfg.Monitoring.MariaDB.ErrorLog
+
+	bufferSize := logCfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultLogBufferSize
+	}
+
+	severities := logCfg.AlertSeverities
+	if len(severities) == 0 {
+		severities = []string{mariadb.SeverityCritical}
+	}
+	alertOn := make(map[string]bool, len(severities))
+	for _, severity := range severities {
+		alertOn[severity] = true
+	}
+
+	return &LogWatcher{
+		config:     cfg,
+		notifier:   notifier,
+		follower:   mariadb.NewLogFollower(mariadb.ResolveLogPath(cfg.Monitoring.MariaDB.LogPath), true),
+		events:     make([]mariadb.LogEvent, 0, bufferSize),
+		nextID:     1,
+		bufferSize: bufferSize,
+		alertOn:    alertOn,
+		lastAlert:  make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Run polls the error log until the context is cancelled or stop is closed
+func (w *LogWatcher) Run(ctx context.Context, stop <-chan struct{}) {
+	interval := w.config.Monitoring.MariaDB.ErrorLog.PollInterval
+	if interval <= 0 {
+		interval = defaultLogPollInterval
+	}
+
+	defer w.follower.Close()
+
+	logger.Info("Following MariaDB error log",
+		logger.String("path", w.follower.Path()),
+		logger.Int("poll_interval", interval))
+
+	var lastErr string
+	err := scheduler.RunUntil(ctx, stop, scheduler.Job{
+		Name:     monitorName(w.config) + "/errorlog",
+		Interval: time.Duration(interval) * time.Second,
+		Delayed:  true,
+		Run: func(context.Context) error {
+			lines, err := w.follower.Poll()
+			if err != nil {
+				// Only log when the error changes to avoid flooding while the file is missing
+				if err.Error() != lastErr {
+					logger.Warn("Failed to read MariaDB error log",
+						logger.String("path", w.follower.Path()),
+						logger.String("error", err.Error()))
+					lastErr = err.Error()
+				}
+			} else {
+				lastErr = ""
+			}
+			if len(lines) > 0 {
+				w.process(lines)
+			}
+			return err
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to schedule MariaDB error log polling", logger.String("error", err.Error()))
+	}
+}
+
+// process classifies new lines, stores, broadcasts and alerts on them
+func (w *LogWatcher) process(lines []string) {
+	var events []mariadb.LogEvent
+
+	w.mu.Lock()
+	for _, line := range lines {
+		event, ok := mariadb.ClassifyLogLine(line)
+		if !ok {
+			continue
+		}
+		event.ID = w.nextID
+		w.nextID++
+
+		if len(w.events) >= w.bufferSize {
+			w.events = append(w.events[:0], w.events[1:]...)
+		}
+		w.events = append(w.events, event)
+		events = append(events, event)
+	}
+	w.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	w.broadcast(events)
+	w.alert(events)
+}
+
+// broadcast pushes new events to WebSocket clients
+func (w *LogWatcher) broadcast(events []mariadb.LogEvent) {
+	instance := w.config.Monitoring.MariaDB.Name
+	if logsHandler(instance, false) == nil {
+		return
+	}
+
+	timestamp := time.Now()
+	meta := map[string]interface{}{
+		"timestamp":        timestamp,
+		"last_update_time": timestamp.Format(time.RFC3339),
+		"source":           "mariadb_log_watcher",
+		"version":          "1.0",
+	}
+	message := map[string]interface{}{
+		"metric_type": "mariadb_logs",
+		"metrics_data": map[string]interface{}{
+			"events": events,
+		},
+		"meta": meta,
+	}
+
+	if instance != "" {
+		meta["instance"] = instance
+	}
+	websocket.GetRegistry().Broadcast(logsTopic(instance), message)
+}
+
+// alert sends one notification per category for events with an alerting severity,
+// respecting a per-category cooldown
+func (w *LogWatcher) alert(events []mariadb.LogEvent) {
+	cooldown := time.Duration(w.config.Monitoring.MariaDB.ErrorLog.AlertCooldown) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultLogAlertCool * time.Second
+	}
+
+	byCategory := make(map[string][]mariadb.LogEvent)
+	var order []string
+	for _, event := range events {
+		if !w.alertOn[event.Severity] {
+			continue
+		}
+		if _, ok := byCategory[event.Category]; !ok {
+			order = append(order, event.Category)
+		}
+		byCategory[event.Category] = append(byCategory[event.Category], event)
+	}
+
+	for _, category := range order {
+		categoryEvents := byCategory[category]
+		if last, ok := w.lastAlert[category]; ok && time.Since(last) < cooldown {
+			w.suppressed[category] += len(categoryEvents)
+			logger.Debug("Suppressing MariaDB log alert due to cooldown",
+				logger.String("category", category),
+				logger.Int("suppressed", w.suppressed[category]))
+			continue
+		}
+
+		w.notifier.SendLogEventNotification(category, categoryEvents, w.suppressed[category])
+		w.lastAlert[category] = time.Now()
+		w.suppressed[category] = 0
+	}
+}
+
+// Events returns buffered events newer than since and with an ID greater than afterID
+func (w *LogWatcher) Events(since time.Time, afterID uint64) []mariadb.LogEvent {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	events := make([]mariadb.LogEvent, 0)
+	for _, event := range w.events {
+		if event.ID <= afterID {
+			continue
+		}
+		if !since.IsZero() && !event.Time.After(since) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// GetLogEvents returns classified error log events, or nil when the log watcher is disabled
+func (m *Monitor) GetLogEvents(since time.Time, afterID uint64) []mariadb.LogEvent {
+	if m.logWatcher == nil {
+		return nil
+	}
+	return m.logWatcher.Events(since, afterID)
+}
+
+// LogWatcherEnabled reports whether the error log is being followed
+func (m *Monitor) LogWatcherEnabled() bool {
+	return m.logWatcher != nil
+}
@@ -1,17 +1,16 @@
 package mariadb
 
 import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring"
+	"CheckHealthDO/internal/pkg/clock"
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
 	"CheckHealthDO/internal/services/mariadb"
 	"CheckHealthDO/internal/websocket"
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,21 +18,22 @@ import (
 
 // Status represents the current status of the MariaDB service
 type Status struct {
-	Status            string    `json:"status"`            // "running" or "stopped"
-	ServiceName       string    `json:"service_name"`      // Service name (e.g., "mariadb")
-	Timestamp         time.Time `json:"timestamp"`         // Time of status check
-	Version           string    `json:"version,omitempty"` // MariaDB version (if running)
-	UptimeSeconds     int64     `json:"uptime_seconds,omitempty"`
-	MemoryUsed        int64     `json:"memory_used,omitempty"`         // Memory used by MariaDB in bytes
-	MemoryUsedPercent float64   `json:"memory_used_percent,omitempty"` // Percentage of system memory used by MariaDB
-	ConnectionsActive int       `json:"connections_active,omitempty"`  // Active connections count
-	Message           string    `json:"message,omitempty"`             // Additional status message
-	LastUpdateTime    time.Time `json:"last_update_time"`              // Last time the status was updated
-	StatusChanged     bool      `json:"-"`                             // Indicates if the status has changed (not sent to clients)
-	LastStatus        string    `json:"-"`                             // Last known status (not sent to clients)
-	PreviousStatus    string    `json:"previous_status,omitempty"`     // Previous status for reference
-	StopReason        string    `json:"stop_reason,omitempty"`         // Reason why MariaDB stopped
-	StopErrorDetails  string    `json:"stop_error_details,omitempty"`  // Detailed error information
+	Status            string          `json:"status"`            // "running", "unresponsive" or "stopped"
+	ServiceName       string          `json:"service_name"`      // Service name (e.g., "mariadb")
+	Timestamp         time.Time       `json:"timestamp"`         // Time of status check
+	Version           string          `json:"version,omitempty"` // MariaDB version (if running)
+	UptimeSeconds     int64           `json:"uptime_seconds,omitempty"`
+	MemoryUsed        int64           `json:"memory_used,omitempty"`         // Memory used by MariaDB in bytes
+	MemoryUsedPercent float64         `json:"memory_used_percent,omitempty"` // Percentage of system memory used by MariaDB
+	ConnectionsActive int             `json:"connections_active,omitempty"`  // Active connections count
+	Message           string          `json:"message,omitempty"`             // Additional status message
+	LastUpdateTime    time.Time       `json:"last_update_time"`              // Last time the status was updated
+	StatusChanged     bool            `json:"-"`                             // Indicates if the status has changed (not sent to clients)
+	LastStatus        string          `json:"-"`                             // Last known status (not sent to clients)
+	PreviousStatus    string          `json:"previous_status,omitempty"`     // Previous status for reference
+	StopReason        string          `json:"stop_reason,omitempty"`         // Reason why MariaDB stopped
+	StopErrorDetails  string          `json:"stop_error_details,omitempty"`  // Detailed error information
+	Liveness          *LivenessStatus `json:"liveness,omitempty"`            // SQL liveness probe state, when enabled
 }
 
 // Monitor handles MariaDB service monitoring
@@ -44,10 +44,29 @@ type Monitor struct {
 	stopCh             chan struct{}
 	statusChanged      bool
 	notifier           *Notifier
-	apiInitiatedChange bool         // Tracks if a change was initiated by the API
-	apiActionTime      time.Time    // When the API action was initiated
-	apiActionType      string       // Type of API action (start/stop/restart)
-	apiActionMu        sync.RWMutex // Mutex for API action tracking
+	apiInitiatedChange bool                 // Tracks if a change was initiated by the API
+	apiActionTime      time.Time            // When the API action was initiated
+	apiActionType      string               // Type of API action (start/stop/restart)
+	apiActionMu        sync.RWMutex         // Mutex for API action tracking
+	logWatcher         *LogWatcher          // Error log follower, nil when disabled
+	slowLogWatcher     *SlowLogWatcher      // Slow query log follower, nil when disabled
+	topQueries         *TopQueriesCollector // performance_schema sampler, nil when disabled
+	innodb             *InnoDBCollector     // InnoDB status sampler, nil when disabled
+	galera             *GaleraCollector     // Galera cluster sampler, nil when disabled
+	restarting         bool                 // An automatic restart of an unresponsive server is in progress
+	lastAutoRestart    time.Time            // When the liveness probe last restarted the server
+	clock              clock.Clock          // Time source for status checks and API action windows
+	replay             bool                 // Observations are recorded: no queries, journal reads or restarts
+	running            bool                 // Start was called and Stop was not
+	stopOnce           sync.Once            // Guards closing stopCh, Stop runs on shutdown and when ctx ends
+}
+
+// Observation is what one status check saw of the server
+type Observation struct {
+	Running bool                 // The service or process is up
+	Probe   *mariadb.ProbeResult // Liveness probe outcome, nil when the probe did not run
+	Reason  string               // Stop or start reason, looked up from the journal when empty
+	Details string               // Details for Reason
 }
 
 // NewMonitor creates a new MariaDB monitor
@@ -57,12 +76,34 @@ func NewMonitor(cfg *config.Config) (*Monitor, error) {
 		return nil, fmt.Errorf("invalid configuration: nil config")
 	}
 
-	return &Monitor{
+	monitor := &Monitor{
 		config:   cfg,
 		status:   &Status{LastStatus: "unknown"},
 		stopCh:   make(chan struct{}),
 		notifier: NewNotifier(cfg),
-	}, nil
+		clock:    clock.System,
+	}
+
+	// Page humans when the restart policy stops automatic restarts
+	mariadb.GetRestartPolicy(cfg).SetInhibitHandler(monitor.notifier.SendRestartInhibitedNotification)
+
+	if cfg.Monitoring.MariaDB.ErrorLog.Enabled {
+		monitor.logWatcher = NewLogWatcher(cfg, monitor.notifier)
+	}
+	if cfg.Monitoring.MariaDB.SlowLog.Enabled {
+		monitor.slowLogWatcher = NewSlowLogWatcher(cfg, monitor.notifier)
+	}
+	if cfg.Monitoring.MariaDB.TopQueries.Enabled {
+		monitor.topQueries = NewTopQueriesCollector(cfg)
+	}
+	if cfg.Monitoring.MariaDB.InnoDB.Enabled {
+		monitor.innodb = NewInnoDBCollector(cfg, monitor.notifier)
+	}
+	if cfg.Monitoring.MariaDB.Galera.Enabled {
+		monitor.galera = NewGaleraCollector(cfg, monitor.notifier)
+	}
+
+	return monitor, nil
 }
 
 // StartBackgroundMonitor starts the monitoring process in the background
@@ -78,14 +119,11 @@ func StartBackgroundMonitor(ctx context.Context, cfg *config.Config) (func(), er
 	}
 
 	// Register with the WebSocket registry handler using MariaDB-specific handler
-	registry := websocket.GetRegistry()
-	handler := registry.GetMariaDBHandler() // Use MariaDB handler instead of metrics handler
-	if handler == nil {
-		handler = websocket.NewHandler()
-		registry.RegisterMariaDBHandler(handler)
-	}
+	statusHandler(monitor.Instance(), true)
 
-	go monitor.Start(ctx)
+	if err := monitor.Start(ctx); err != nil {
+		return nil, err
+	}
 
 	logger.Info("Started MariaDB monitoring service")
 
@@ -95,31 +133,93 @@ func StartBackgroundMonitor(ctx context.Context, cfg *config.Config) (func(), er
 	}, nil
 }
 
-// Start begins the monitoring process
-func (m *Monitor) Start(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(m.config.Monitoring.MariaDB.CheckInterval) * time.Second)
-	defer ticker.Stop()
-
-	// Run immediately at start
-	m.checkStatus()
-
-	for {
-		select {
-		case <-ticker.C:
-			m.checkStatus()
-		case <-ctx.Done():
-			m.Stop()
-			return
-		case <-m.stopCh:
-			return
-		}
+// SetClock replaces the time source. Call it before Start.
+func (m *Monitor) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// SetNotificationManager routes the alerts of this instance and its collectors to
+// manager. Call it before Start.
+func (m *Monitor) SetNotificationManager(manager alerts.NotificationManager) {
+	m.notifier.emailManager = manager
+}
+
+// SetReplay marks the monitor as fed with recorded observations. It then skips the
+// metric queries, journal lookups and automatic restarts a live check performs.
+func (m *Monitor) SetReplay(replay bool) {
+	m.replay = replay
+}
+
+// Start begins monitoring in the background until Stop is called or ctx ends
+func (m *Monitor) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		return fmt.Errorf("MariaDB monitor is already running")
+	}
+	select {
+	case <-m.stopCh:
+		return fmt.Errorf("MariaDB monitor was stopped and cannot be restarted")
+	default:
+	}
+
+	cancelJob, err := scheduler.Schedule(scheduler.Job{
+		Name:     m.Name(),
+		Interval: time.Duration(m.config.Monitoring.MariaDB.CheckInterval) * time.Second,
+		Run: func(ctx context.Context) error {
+			m.checkStatus(ctx)
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule MariaDB checks: %w", err)
+	}
+
+	m.running = true
+	go m.run(ctx, cancelJob)
+	return nil
+}
+
+// run follows the logs and collections alongside the scheduled status checks until
+// the monitor stops
+func (m *Monitor) run(ctx context.Context, cancelJob func()) {
+	defer cancelJob()
+
+	// Follow the error log alongside the status checks
+	if m.logWatcher != nil {
+		go m.logWatcher.Run(ctx, m.stopCh)
+	}
+	if m.slowLogWatcher != nil {
+		go m.slowLogWatcher.Run(ctx, m.stopCh)
+	}
+	if m.topQueries != nil {
+		go m.topQueries.Run(ctx, m.stopCh, m.isServiceRunning)
+	}
+	if m.innodb != nil {
+		go m.innodb.Run(ctx, m.stopCh, m.isServiceRunning)
+	}
+	if m.galera != nil {
+		go m.galera.Run(ctx, m.stopCh, m.isServiceRunning)
+	}
+
+	select {
+	case <-ctx.Done():
+		m.Stop()
+	case <-m.stopCh:
 	}
 }
 
-// Stop stops the monitoring process
+// Stop stops the monitoring process. It is safe to call more than once.
 func (m *Monitor) Stop() {
-	close(m.stopCh)
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
 	// No need to close WebSocket clients, as we're using the central registry
+
+	m.mu.Lock()
+	m.running = false
+	m.mu.Unlock()
 }
 
 // GetStatus returns the current MariaDB status
@@ -129,6 +229,42 @@ func (m *Monitor) GetStatus() *Status {
 	return m.status
 }
 
+// Instance returns the instance name, empty for the default instance
+func (m *Monitor) Instance() string {
+	return m.config.Monitoring.MariaDB.Name
+}
+
+// Name returns "mariadb" for the default instance and "mariadb-<instance>" for
+// additional ones
+func (m *Monitor) Name() string {
+	return monitorName(m.config)
+}
+
+// monitorName names the monitor of a MariaDB configuration. Its collections are
+// scheduled below this name.
+func monitorName(cfg *config.Config) string {
+	if instance := cfg.Monitoring.MariaDB.Name; instance != "" {
+		return "mariadb-" + instance
+	}
+	return "mariadb"
+}
+
+// Snapshot returns a copy of the instance's *Status taken under the status lock
+func (m *Monitor) Snapshot() interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status := *m.status
+	return &status
+}
+
+// Health compares the last status update with monitoring.mariadb.check_interval
+func (m *Monitor) Health() monitoring.Health {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	interval := time.Duration(m.config.Monitoring.MariaDB.CheckInterval) * time.Second
+	return monitoring.NewHealth(m.running, m.status.LastUpdateTime, interval)
+}
+
 // GetConfig returns the monitor's configuration
 func (m *Monitor) GetConfig() *config.Config {
 	return m.config
@@ -141,7 +277,7 @@ func (m *Monitor) MarkAPIAction(actionType string) {
 	defer m.apiActionMu.Unlock()
 
 	m.apiInitiatedChange = true
-	m.apiActionTime = time.Now()
+	m.apiActionTime = m.clock.Now()
 	m.apiActionType = actionType
 
 	logger.Info("API-initiated MariaDB action flagged",
@@ -156,7 +292,7 @@ func (m *Monitor) ClearAPIAction() {
 	defer m.apiActionMu.Unlock()
 
 	// If it's been more than 30 seconds since the API action, clear the flag
-	if m.apiInitiatedChange && time.Since(m.apiActionTime) > 30*time.Second {
+	if m.apiInitiatedChange && m.clock.Since(m.apiActionTime) > 30*time.Second {
 		logger.Debug("Clearing API-initiated action flag",
 			logger.String("action", m.apiActionType),
 			logger.Any("initiated_at", m.apiActionTime))
@@ -172,7 +308,7 @@ func (m *Monitor) wasChangeInitiatedByAPI() bool {
 	defer m.apiActionMu.RUnlock()
 
 	// If the flag is set and the API action was recent (within 30 seconds)
-	if m.apiInitiatedChange && time.Since(m.apiActionTime) <= 30*time.Second {
+	if m.apiInitiatedChange && m.clock.Since(m.apiActionTime) <= 30*time.Second {
 		// For restart, we expect a stop followed by a start, so keep the flag
 		if m.apiActionType == "restart" {
 			logger.Info("Status change was initiated by API restart operation")
@@ -194,348 +330,50 @@ func (m *Monitor) wasChangeInitiatedByAPI() bool {
 }
 
 // getDatabaseStopReason attempts to determine why MariaDB service stopped
-func (m *Monitor) getDatabaseStopReason() (string, string) {
-	serviceName := m.config.Monitoring.MariaDB.ServiceName
-
-	// FIRST: Check for memory auto-recovery using the log messages and journal
-	// This check has the highest priority
-	
-	// Check our internal log file first
-	logDir := "logs"
-	logFile := filepath.Join(logDir, "mariadb_restarts.log")
-	
-	// First check if the log file exists
-	if _, err := os.Stat(logFile); err == nil {
-		// Check for recent entries (within last 5 minutes)
-		recentLogCmd := exec.Command("bash", "-c", 
-			fmt.Sprintf("grep -a 'Memory Critical Auto-Recovery' %s | tail -n 10", logFile))
-		logOutput, err := recentLogCmd.CombinedOutput()
-		
-		if err == nil && len(logOutput) > 0 {
-			logEntries := strings.Split(string(logOutput), "\n")
-			for _, entry := range logEntries {
-				// Skip empty entries
-				if entry == "" {
-					continue
-				}
-				
-				// Try to parse the timestamp from the log entry
-				timestampPattern := regexp.MustCompile(`\[(.*?)\]`)
-				matches := timestampPattern.FindStringSubmatch(entry)
-				
-				if len(matches) > 1 {
-					timestampStr := matches[1]
-					timestamp, parseErr := time.Parse(time.RFC3339, timestampStr)
-					
-					// If we found a recent entry (within last 5 minutes)
-					if parseErr == nil && time.Since(timestamp) < 5*time.Minute {
-						logger.Info("Found evidence of recent auto-recovery in logs",
-							logger.String("log_entry", entry))
-						return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions: %s", entry)
-					}
-				}
-			}
-		}
-	}
-	
-	// Check system journal for our specific auto-recovery message
-	journalCmd := exec.Command("bash", "-c", 
-		"journalctl --since='5 minutes ago' | grep -i 'CHECKHEALTHDO_MEMORY_AUTO_RECOVERY' | tail -n 5")
-	journalOutput, _ := journalCmd.CombinedOutput()
-	
-	if len(journalOutput) > 0 {
-		logger.Info("Found evidence of memory-triggered restart in system logs",
-			logger.String("output", string(journalOutput)))
-		return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions (from journal): %s", string(journalOutput))
-	}
-	
-	// SECOND: Check for any restart/memory related entries in recent system logs
-	memoryRestartCmd := exec.Command("bash", "-c", 
-		fmt.Sprintf("journalctl -u %s --since='5 minutes ago' | grep -i 'restart.*memory\\|memory.*restart\\|critical memory' | tail -n 5", 
-			serviceName))
-	memoryOutput, _ := memoryRestartCmd.CombinedOutput()
-	
-	if len(memoryOutput) > 0 {
-		logger.Info("Found evidence of memory-related restart in MariaDB logs",
-			logger.String("output", string(memoryOutput)))
-		return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions: %s", string(memoryOutput))
-	}
-	
-	// THIRD: Check for OOM kills in kernel logs
-	oomCmd := exec.Command("bash", "-c", "journalctl -k -b | grep -i 'killed process' | grep -i 'mysqld\\|mariadb' | tail -n 5")
-	oomOutput, err := oomCmd.CombinedOutput()
-	if err == nil && len(oomOutput) > 0 {
-		logger.Info("Found OOM kill evidence in logs",
-			logger.String("service", serviceName),
-			logger.String("logs", string(oomOutput)))
-		return "Out of Memory Kill", string(oomOutput)
-	}
-
-	// FOURTH: Check for manual systemctl stop
-	manualStopCmd := exec.Command("bash", "-c",
-		fmt.Sprintf("journalctl -u %s -n 50 --no-pager | grep -i 'systemctl stop\\|Stopped.*mariadb\\|Stopping.*mariadb' | tail -n 5",
-			serviceName))
-	manualOutput, _ := manualStopCmd.CombinedOutput()
-
-	if len(manualOutput) > 0 {
-		outputStr := string(manualOutput)
-
-		// Don't identify as manual stop if it appears to be a memory-related restart
-		if strings.Contains(outputStr, "memory") || strings.Contains(outputStr, "auto-recovery") {
-			return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions (detected in stop logs): %s", outputStr)
-		}
-
-		// Look for systemctl stop command
-		if strings.Contains(outputStr, "systemctl stop") || strings.Contains(outputStr, "systemd[1]: Stopped") {
-			// Try to extract who stopped it
-			userPattern := regexp.MustCompile(`by\s+(\w+)`)
-			matches := userPattern.FindStringSubmatch(outputStr)
-
-			if len(matches) > 1 {
-				// Found specific user who ran the command
-				user := matches[1]
-
-				// Check if this is a system user that might be involved in automated tasks
-				if user == "root" || user == "system" || user == "systemd" {
-					// Double-check recent activity for memory-related actions
-					recentCmd := exec.Command("bash", "-c",
-						fmt.Sprintf("journalctl -n 100 --since='5 minutes ago' | grep -i 'memory\\|restart\\|critical'"))
-					recentOutput, _ := recentCmd.CombinedOutput()
-
-					if len(recentOutput) > 0 && (strings.Contains(string(recentOutput), "memory") ||
-						strings.Contains(string(recentOutput), "critical") ||
-						strings.Contains(string(recentOutput), "auto-recovery")) {
-						return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions (system-initiated): %s", string(recentOutput))
-					}
-				}
-
-				// If we get here, it's likely a genuine manual stop
-				return "Manual Systemctl Stop", fmt.Sprintf("MariaDB was manually stopped by user '%s' via systemctl command: %s", user, outputStr)
-			}
-
-			// We can see it was systemctl but not who ran it
-			// Do one more check for memory-related restart
-			recentCmd := exec.Command("bash", "-c",
-				fmt.Sprintf("journalctl -n 100 --since='5 minutes ago' | grep -i 'memory\\|restart\\|critical'"))
-			recentOutput, _ := recentCmd.CombinedOutput()
-
-			if len(recentOutput) > 0 && (strings.Contains(string(recentOutput), "memory") ||
-				strings.Contains(string(recentOutput), "critical") ||
-				strings.Contains(string(recentOutput), "auto-recovery")) {
-				return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions (system-initiated): %s", string(recentOutput))
-			}
-
-			return "Manual Systemctl Stop", fmt.Sprintf("MariaDB was manually stopped via systemctl command: %s", outputStr)
-		}
-	}
-
-	// FIFTH: Check for explicit manual stop via service status
-	statusCmd := exec.Command("bash", "-c", fmt.Sprintf("systemctl status %s | grep -i 'inactive\\|failed\\|stopped'", serviceName))
-	statusOutput, err := statusCmd.CombinedOutput()
-	if err == nil && len(statusOutput) > 0 {
-		outputStr := string(statusOutput)
-
-		// Don't identify as manual stop if it appears to be a memory-related restart
-		if strings.Contains(outputStr, "memory") || strings.Contains(outputStr, "auto-recovery") {
-			return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions (detected in status): %s", outputStr)
-		}
-
-		// Check for manual stop
-		if strings.Contains(outputStr, "deactivated") || strings.Contains(outputStr, "stop") {
-			// Try to extract who stopped it
-			userPattern := regexp.MustCompile(`by\s+(\w+)`)
-			matches := userPattern.FindStringSubmatch(outputStr)
-			if len(matches) > 1 {
-				return "Manual Stop", fmt.Sprintf("Service was manually stopped by user %s: %s", matches[1], outputStr)
-			}
-			return "Manual Stop", fmt.Sprintf("Service was manually stopped: %s", outputStr)
-		}
-	}
-
-	// SIXTH: Check systemd journal logs for service failures
-	journalCmd = exec.Command("bash", "-c", fmt.Sprintf("journalctl -u %s --no-pager -n 50 | grep -i 'fail\\|error\\|terminate\\|abort\\|denied\\|shutdown'", serviceName))
-	journalOutput, err = journalCmd.CombinedOutput()
-	if err == nil && len(journalOutput) > 0 {
-		// Look for specific patterns in the output
-		outputStr := string(journalOutput)
-
-		// Check for normal shutdown
-		if strings.Contains(outputStr, "shutdown") || strings.Contains(outputStr, "Shutdown") {
-			return "Shutdown Normal", outputStr
-		}
-
-		// Check for permission/access denied issues
-		if strings.Contains(outputStr, "denied") || strings.Contains(outputStr, "permission") {
-			return "Permission Error", outputStr
-		}
-
-		// Check for configuration errors
-		if strings.Contains(outputStr, "configuration") || strings.Contains(outputStr, "config") {
-			return "Configuration Error", outputStr
-		}
-
-		// Check for disk space issues
-		if strings.Contains(outputStr, "disk space") || strings.Contains(outputStr, "no space") {
-			return "Disk Space Error", outputStr
-		}
-
-		// If we found errors but couldn't categorize them, return generic error
-		return "Service Error", outputStr
-	}
-
-	// Check MySQL error logs as a last resort
-	logCmd := exec.Command("bash", "-c", "tail -n 50 /var/log/mysql/error.log 2>/dev/null || tail -n 50 /var/log/mysqld.log 2>/dev/null")
-	logOutput, err := logCmd.CombinedOutput()
-	if err == nil && len(logOutput) > 0 {
-		return "Database Error", string(logOutput)
-	}
-
-	// If we couldn't determine a specific reason
-	return "Unknown Failure", "Could not determine the specific reason for service failure"
+func (m *Monitor) getDatabaseStopReason(ctx context.Context) (string, string) {
+	return ClassifyStopReason(m.reasonEvidence(ctx))
 }
 
 // getStartReason attempts to determine why MariaDB service started
-func (m *Monitor) getStartReason() (string, string) {
-	serviceName := m.config.Monitoring.MariaDB.ServiceName
-
-	// First check for manual service start via systemctl
-	syslogCmd := exec.Command("bash", "-c",
-		fmt.Sprintf("journalctl -u %s -n 100 --no-pager | grep -i 'Starting\\|Started.*mariadb\\|systemctl start' | tail -n 10",
-			serviceName))
-	syslogOutput, _ := syslogCmd.CombinedOutput()
-
-	if len(syslogOutput) > 0 {
-		syslogStr := string(syslogOutput)
-
-		// Check for manual systemctl start command
-		if strings.Contains(syslogStr, "systemctl start") || strings.Contains(syslogStr, "systemd[1]: Started") {
-			// Try to extract the user who ran the command
-			userPattern := regexp.MustCompile(`by\s+(\w+)`)
-			matches := userPattern.FindStringSubmatch(syslogStr)
-
-			if len(matches) > 1 {
-				// Found specific user who ran the command
-				user := matches[1]
-				return fmt.Sprintf("MariaDB manually started by user '%s' via systemctl", user), syslogStr
-			}
-
-			// We can see it was systemctl but not who ran it
-			return "MariaDB manually started via systemctl command", syslogStr
-		}
-	}
-
-	// Check if it's a system boot
-	bootCmd := exec.Command("bash", "-c",
-		"journalctl -b -n 100 | grep -i 'system startup\\|boot\\|reboot'")
-	bootOutput, bootErr := bootCmd.CombinedOutput()
-
-	if bootErr == nil && len(bootOutput) > 0 &&
-		time.Since(m.status.Timestamp) < 10*time.Minute {
-		return "System startup detected - MariaDB service started during boot process", string(bootOutput)
-	}
-
-	// Check for memory marker file indicating recovery restart
-	logFile := filepath.Join("logs", "mariadb_restarts.log")
-	if _, err := os.Stat(logFile); err == nil {
-		// Check for recent entries (within last 5 minutes)
-		recentLogCmd := exec.Command("bash", "-c", 
-			fmt.Sprintf("grep -a 'Memory Critical Auto-Recovery' %s | tail -n 10", logFile))
-		logOutput, err := recentLogCmd.CombinedOutput()
-		
-		if err == nil && len(logOutput) > 0 {
-			return "Service restarted after memory-related shutdown", string(logOutput)
-		}
-	}
-
-	// Check system journal for our specific auto-recovery message
-	journalCmd := exec.Command("bash", "-c", 
-		"journalctl --since='5 minutes ago' | grep -i 'CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_COMPLETED' | tail -n 5")
-	journalOutput, _ := journalCmd.CombinedOutput()
-	
-	if len(journalOutput) > 0 {
-		return "Service restarted after memory-related shutdown", string(journalOutput)
-	}
-
-	// Check for MySQL startup messages in logs
-	logCmd := exec.Command("bash", "-c",
-		"tail -n 100 /var/log/mysql/error.log 2>/dev/null || tail -n 100 /var/log/mysqld.log 2>/dev/null")
-	logOutput, _ := logCmd.CombinedOutput()
-
-	if len(logOutput) > 0 {
-		logStr := string(logOutput)
-		if strings.Contains(logStr, "starting") || strings.Contains(logStr, "started") {
-			return "MariaDB service started (found startup messages in logs)", logStr
-		}
-	}
-
-	// Check process start time
-	processCmd := exec.Command("bash", "-c", "ps -o lstart= -C mysqld")
-	processOutput, _ := processCmd.CombinedOutput()
-
-	if len(processOutput) > 0 {
-		startTimeStr := strings.TrimSpace(string(processOutput))
-		return fmt.Sprintf("MariaDB service started at %s", startTimeStr),
-			"Process start time detected from system"
-	}
-
-	// Default message with more context
-	uptime, _ := getSystemUptime()
-	loadAvg, _ := getSystemLoadAverage()
-
-	details := fmt.Sprintf("System uptime: %s, Load average: %.2f, %.2f, %.2f",
-		uptime, loadAvg[0], loadAvg[1], loadAvg[2])
-
-	return "MariaDB service started (unable to determine specific trigger)", details
-}
-
-// Helper function to get system uptime
-func getSystemUptime() (string, error) {
-	cmd := exec.Command("bash", "-c", "uptime -p")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(output)), nil
+func (m *Monitor) getStartReason(ctx context.Context) (string, string) {
+	return ClassifyStartReason(m.reasonEvidence(ctx))
 }
 
-// Helper function to get system load average
-func getSystemLoadAverage() ([]float64, error) {
-	cmd := exec.Command("bash", "-c", "cat /proc/loadavg")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return []float64{0, 0, 0}, err
-	}
-
-	parts := strings.Fields(string(output))
-	if len(parts) < 3 {
-		return []float64{0, 0, 0}, fmt.Errorf("invalid load average format")
+// reasonEvidence gathers the evidence for the reason lookups. A replay has none
+// beyond what the recording says, so nothing is read from this host.
+func (m *Monitor) reasonEvidence(ctx context.Context) *ReasonEvidence {
+	if m.replay {
+		return &ReasonEvidence{
+			ServiceName: m.config.Monitoring.MariaDB.ServiceName,
+			Now:         m.clock.Now(),
+			LoadAverage: []float64{0, 0, 0},
+		}
 	}
-
-	load1, _ := strconv.ParseFloat(parts[0], 64)
-	load5, _ := strconv.ParseFloat(parts[1], 64)
-	load15, _ := strconv.ParseFloat(parts[2], 64)
-
-	return []float64{load1, load5, load15}, nil
+	return m.collectReasonEvidence(ctx)
 }
 
 // broadcastMetrics sends the current status to all WebSocket clients using the registry
 func (m *Monitor) broadcastMetrics() {
 	wsMsg := MariaDBMetricsMsg{
-		Timestamp:      time.Now(),
+		Instance:       m.Instance(),
+		Timestamp:      m.clock.Now(),
 		Status:         m.status,
-		LastUpdateTime: time.Now().Format(time.RFC3339),
+		LastUpdateTime: m.clock.Now().Format(time.RFC3339),
+	}
+	if m.topQueries != nil {
+		wsMsg.TopQueries = m.topQueries.Latest()
 	}
 
-	// Use the MariaDB-specific broadcast method
-	websocket.GetRegistry().BroadcastMariaDB(wsMsg)
+	// Named instances have their own topic
+	websocket.GetRegistry().Broadcast(statusTopic(m.Instance()), wsMsg)
 }
 
 // populateAdditionalInfo adds additional metrics when MariaDB is running
-func (m *Monitor) populateAdditionalInfo() {
+func (m *Monitor) populateAdditionalInfo(ctx context.Context) {
 	dbConfig := mariadb.GetDBConfigFromConfig(m.config)
 
 	// Get MariaDB version
-	version, err := mariadb.GetVersion(dbConfig)
+	version, err := mariadb.GetVersion(ctx, dbConfig)
 	if err != nil {
 		logger.Warn("Failed to get MariaDB version",
 			logger.String("error", err.Error()))
@@ -544,7 +382,7 @@ func (m *Monitor) populateAdditionalInfo() {
 	}
 
 	// Get MariaDB uptime - refresh this every time to ensure it's real-time
-	uptime, err := mariadb.GetUptime(dbConfig)
+	uptime, err := mariadb.GetUptime(ctx, dbConfig)
 	if err != nil {
 		logger.Warn("Failed to get MariaDB uptime",
 			logger.String("error", err.Error()))
@@ -555,7 +393,7 @@ func (m *Monitor) populateAdditionalInfo() {
 	}
 
 	// Get active connections
-	connections, err := mariadb.GetActiveConnections(dbConfig)
+	connections, err := mariadb.GetActiveConnections(ctx, dbConfig)
 	if err != nil {
 		logger.Warn("Failed to get MariaDB connections",
 			logger.String("error", err.Error()))
@@ -564,7 +402,7 @@ func (m *Monitor) populateAdditionalInfo() {
 	}
 
 	// Get memory usage
-	memUsed, memUsedPercent, err := mariadb.GetMariaDBMemoryUsage()
+	memUsed, memUsedPercent, err := mariadb.GetMariaDBMemoryUsage(ctx)
 	if err != nil {
 		logger.Warn("Failed to get MariaDB memory usage",
 			logger.String("error", err.Error()))
@@ -575,18 +413,49 @@ func (m *Monitor) populateAdditionalInfo() {
 }
 
 // checkStatus checks the MariaDB service status and updates internal state
-func (m *Monitor) checkStatus() error {
+func (m *Monitor) checkStatus(ctx context.Context) error {
+	serviceName := m.config.Monitoring.MariaDB.ServiceName
+
+	// With the liveness probe enabled the process state and SQL responsiveness
+	// are checked separately, so a hung server is not reported as stopped
+	var isRunning bool
+	var probe *mariadb.ProbeResult
+	if m.config.Monitoring.MariaDB.Liveness.Enabled {
+		isRunning = mariadb.CheckProcessStatus(ctx, serviceName)
+		if isRunning {
+			result := m.runLivenessProbe(ctx)
+			probe = &result
+		}
+	} else {
+		// Pass config to enable connection verification
+		var err error
+		isRunning, err = mariadb.CheckServiceStatus(ctx, serviceName, m.config)
+		if err != nil {
+			logger.Error("Failed to check MariaDB service status",
+				logger.String("error", err.Error()))
+			return err
+		}
+	}
+
+	m.observe(ctx, Observation{Running: isRunning, Probe: probe})
+	return nil
+}
+
+// Observe updates the status from one observation and sends a notification when
+// the status changes. Replays feed it recorded observations and read nothing from
+// this host, so they need no context.
+func (m *Monitor) Observe(obs Observation) {
+	m.observe(context.Background(), obs)
+}
+
+// observe implements Observe for checkStatus, which bounds the metric queries and
+// reason lookups by ctx
+func (m *Monitor) observe(ctx context.Context, obs Observation) {
 	// Clear any expired API action flags
 	m.ClearAPIAction()
 
 	serviceName := m.config.Monitoring.MariaDB.ServiceName
-	// Pass config to enable connection verification
-	isRunning, err := mariadb.CheckServiceStatus(serviceName, m.config)
-	if err != nil {
-		logger.Error("Failed to check MariaDB service status",
-			logger.String("error", err.Error()))
-		return err
-	}
+	isRunning, probe := obs.Running, obs.Probe
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -595,15 +464,32 @@ func (m *Monitor) checkStatus() error {
 	previousStatus := m.status.Status
 
 	// Create new status with current time
-	now := time.Now()
+	now := m.clock.Now()
 	m.status.Timestamp = now
 	m.status.LastUpdateTime = now
 	m.status.ServiceName = serviceName
 
-	if isRunning {
+	unresponsive := false
+	if probe != nil {
+		unresponsive = m.recordProbeResult(*probe, now)
+	} else if !isRunning && m.status.Liveness != nil {
+		m.status.Liveness.ConsecutiveFailures = 0
+	}
+
+	if unresponsive {
+		m.status.Status = "unresponsive"
+		m.status.Message = "MariaDB process is running but not answering queries"
+	} else if isRunning {
 		m.status.Status = "running"
-		m.populateAdditionalInfo()
-		m.status.Message = "MariaDB service is running normally"
+		if probe == nil || probe.Success {
+			if !m.replay {
+				m.populateAdditionalInfo(ctx)
+			}
+			m.status.Message = "MariaDB service is running normally"
+		} else {
+			// Skip the metric queries, they would only wait for the same timeout
+			m.status.Message = "MariaDB service is running but the last liveness probe failed"
+		}
 	} else {
 		m.status.Status = "stopped"
 		m.status.Message = "MariaDB service is currently stopped"
@@ -632,9 +518,19 @@ func (m *Monitor) checkStatus() error {
 			m.status.StopReason = ""
 			m.status.StopErrorDetails = ""
 
-			if previousStatus == "running" && m.status.Status == "stopped" {
+			if m.status.Status == "unresponsive" {
+				reason = livenessReason(m.status.Liveness)
+
+				logger.Error("MariaDB service is unresponsive",
+					logger.String("reason", reason))
+			} else if previousStatus == "unresponsive" && m.status.Status == "running" {
+				reason = "MariaDB is answering queries again"
+			} else if (previousStatus == "running" || previousStatus == "unresponsive") && m.status.Status == "stopped" {
 				// Get detailed information about why the service stopped
-				stopReason, errorDetails := m.getDatabaseStopReason()
+				stopReason, errorDetails := obs.Reason, obs.Details
+				if stopReason == "" {
+					stopReason, errorDetails = m.getDatabaseStopReason(ctx)
+				}
 
 				m.status.StopReason = stopReason
 				m.status.StopErrorDetails = errorDetails
@@ -649,9 +545,18 @@ func (m *Monitor) checkStatus() error {
 				logger.Error("MariaDB service stopped",
 					logger.String("reason", stopReason),
 					logger.String("details", errorDetails))
+
+				// Bring the service back unless someone stopped it on purpose
+				if m.config.Monitoring.MariaDB.AutoRestart && !strings.Contains(stopReason, "Manual Systemctl Stop") && !m.restarting && !m.replay {
+					m.restarting = true
+					go m.autoStart(context.WithoutCancel(ctx), stopReason)
+				}
 			} else if previousStatus == "stopped" && m.status.Status == "running" {
 				// Get more detailed information about the service start
-				startReason, startDetails := m.getStartReason()
+				startReason, startDetails := obs.Reason, obs.Details
+				if startReason == "" {
+					startReason, startDetails = m.getStartReason(ctx)
+				}
 
 				// Use the start reason directly, it's already formatted well
 				reason = startReason
@@ -668,8 +573,10 @@ func (m *Monitor) checkStatus() error {
 		m.status.StatusChanged = false
 	}
 
+	if m.status.Status == "unresponsive" && !m.replay && m.shouldAutoRestart() {
+		go m.restartUnresponsive(context.WithoutCancel(ctx), livenessReason(m.status.Liveness))
+	}
+
 	// Broadcast metrics via WebSocket after each status check
 	m.broadcastMetrics()
-
-	return nil
 }
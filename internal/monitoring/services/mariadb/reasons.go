@@ -0,0 +1,345 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/journal"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/load"
+	"github.com/shirou/gopsutil/process"
+)
+
+const (
+	reasonWindow      = 5 * time.Minute  // How far back stop and start evidence is looked for
+	bootStartWindow   = 10 * time.Minute // A start this soon after boot is reported as part of the boot
+	unitJournalLines  = 100              // Recent unit entries read from the journal
+	errorLogLines     = 100              // Error log lines read when nothing else explains a change
+	agentIdentifier   = "CheckHealthDO"  // Syslog identifier of the recovery markers we write with logger -t
+	sudoIdentifier    = "sudo"
+	memoryRecoveryTag = "Memory Critical Auto-Recovery"
+
+	// livenessRecoveryTag marks restarts by the liveness probe in the restart log
+	livenessRecoveryTag = "Liveness Auto-Recovery"
+)
+
+var (
+	// byUserPattern extracts the user from messages such as "Stopped by admin"
+	byUserPattern = regexp.MustCompile(`by\s+(\w+)`)
+	// memoryRestartPattern matches unit messages about restarts caused by memory pressure
+	memoryRestartPattern = regexp.MustCompile(`(?i)restart.*memory|memory.*restart|critical memory`)
+	// failurePattern matches unit messages describing a failed or aborted service
+	failurePattern = regexp.MustCompile(`(?i)fail|error|terminate|abort|denied|shutdown`)
+)
+
+// ReasonEvidence is everything stop and start reasons are derived from. It is
+// collected once per status change so the classification itself needs no I/O.
+type ReasonEvidence struct {
+	ServiceName  string
+	Now          time.Time
+	RestartLog   []string        // Lines of the restart log written by the auto-recoveries
+	Agent        []journal.Entry // Recovery markers written by CheckHealthDO within the window
+	Unit         []journal.Entry // Recent entries of the service unit, including those logged by systemd
+	Kernel       []journal.Entry // Kernel messages of the current boot within the window
+	Sudo         []journal.Entry // Commands run through sudo within the window
+	ErrorLog     []string        // Tail of the MariaDB error log
+	BootTime     time.Time
+	ProcessStart time.Time // Start time of the mysqld or mariadbd process, zero when not running
+	Uptime       time.Duration
+	LoadAverage  []float64
+}
+
+// collectReasonEvidence reads the journal, the restart log and the error log for the
+// configured service. Sources that cannot be read are left empty.
+func (m *Monitor) collectReasonEvidence(ctx context.Context) *ReasonEvidence {
+	serviceName := m.config.Monitoring.MariaDB.ServiceName
+	now := m.clock.Now()
+	since := now.Add(-reasonWindow)
+
+	evidence := &ReasonEvidence{
+		ServiceName: serviceName,
+		Now:         now,
+		LoadAverage: []float64{0, 0, 0},
+	}
+
+	if data, err := os.ReadFile(mariadb.RestartLogPath(m.config)); err == nil {
+		evidence.RestartLog = strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	evidence.Agent = readJournal(ctx, journal.Query{Identifiers: []string{agentIdentifier}, Since: since})
+	evidence.Unit = readJournal(ctx, journal.Query{Units: []string{serviceName}, Since: since, Lines: unitJournalLines})
+	evidence.Kernel = readJournal(ctx, journal.Query{Kernel: true, CurrentBoot: true, Since: since})
+	evidence.Sudo = readJournal(ctx, journal.Query{Identifiers: []string{sudoIdentifier}, Since: since})
+
+	if logPath := mariadb.ResolveLogPath(m.config.Monitoring.MariaDB.LogPath); logPath != "" {
+		if _, err := os.Stat(logPath); err == nil {
+			evidence.ErrorLog, _ = mariadb.GetLatestMariaDBLogs(logPath, errorLogLines)
+		}
+	}
+
+	if bootTime, err := host.BootTimeWithContext(ctx); err == nil {
+		evidence.BootTime = time.Unix(int64(bootTime), 0)
+	}
+	if uptime, err := host.UptimeWithContext(ctx); err == nil {
+		evidence.Uptime = time.Duration(uptime) * time.Second
+	}
+	if avg, err := load.AvgWithContext(ctx); err == nil {
+		evidence.LoadAverage = []float64{avg.Load1, avg.Load5, avg.Load15}
+	}
+	evidence.ProcessStart = serverProcessStart()
+
+	return evidence
+}
+
+// readJournal runs a journal query, logging failures at debug level since the
+// journal is not available on every host
+func readJournal(ctx context.Context, q journal.Query) []journal.Entry {
+	entries, err := journal.Read(ctx, q)
+	if err != nil {
+		logger.Debug("Failed to read journal for MariaDB status reason",
+			logger.String("error", err.Error()))
+	}
+	return entries
+}
+
+// serverProcessStart returns when the running mysqld or mariadbd process started
+func serverProcessStart() time.Time {
+	procs, err := process.Processes()
+	if err != nil {
+		return time.Time{}
+	}
+
+	for _, proc := range procs {
+		name, err := proc.Name()
+		if err != nil || (name != "mysqld" && name != "mariadbd") {
+			continue
+		}
+		if created, err := proc.CreateTime(); err == nil {
+			return time.UnixMilli(created)
+		}
+	}
+	return time.Time{}
+}
+
+// ClassifyStopReason determines why the service stopped. It returns a short reason,
+// which the alerts match on, and the evidence that led to it.
+func ClassifyStopReason(e *ReasonEvidence) (string, string) {
+	// Restarts triggered by the liveness probe are recorded in the restart log
+	if entry := e.recentRestart(livenessRecoveryTag); entry != "" {
+		return "Liveness Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted because it stopped answering queries: %s", entry)
+	}
+
+	// Memory auto-recovery has priority over everything found in the journal
+	if entry := e.recentRestart(memoryRecoveryTag); entry != "" {
+		logger.Info("Found evidence of recent auto-recovery in logs",
+			logger.String("log_entry", entry))
+		return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions: %s", entry)
+	}
+	if entries := matchEntries(e.Agent, func(entry journal.Entry) bool {
+		return strings.Contains(entry.Message, "CHECKHEALTHDO_MEMORY_AUTO_RECOVERY") && e.namesService(entry.Message)
+	}); len(entries) > 0 {
+		return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions (from journal): %s", joinMessages(entries))
+	}
+	if entries := matchEntries(e.Unit, func(entry journal.Entry) bool {
+		return memoryRestartPattern.MatchString(entry.Message)
+	}); len(entries) > 0 {
+		return "Memory Critical Auto-Recovery", fmt.Sprintf("MariaDB was automatically restarted due to critical memory conditions: %s", joinMessages(entries))
+	}
+
+	// OOM kills are reported by the kernel, not by the unit
+	if entries := matchEntries(e.Kernel, isServerOOMKill); len(entries) > 0 {
+		return "Out of Memory Kill", joinMessages(entries)
+	}
+
+	// A stop job means someone asked systemd to stop the service
+	if entries := matchEntries(e.Unit, func(entry journal.Entry) bool { return isSystemdJob(entry, "stop") }); len(entries) > 0 {
+		details := joinMessages(entries)
+		if user := e.systemctlUser("stop"); user != "" {
+			return "Manual Systemctl Stop", fmt.Sprintf("MariaDB was manually stopped by user '%s' via systemctl command: %s", user, details)
+		}
+		return "Manual Systemctl Stop", fmt.Sprintf("MariaDB was manually stopped via systemctl command: %s", details)
+	}
+
+	// Failures reported by the unit itself
+	if entries := matchEntries(e.Unit, func(entry journal.Entry) bool {
+		return failurePattern.MatchString(entry.Message)
+	}); len(entries) > 0 {
+		details := joinMessages(entries)
+		lower := strings.ToLower(details)
+
+		switch {
+		case strings.Contains(lower, "shutdown"):
+			return "Shutdown Normal", details
+		case strings.Contains(lower, "denied") || strings.Contains(lower, "permission"):
+			return "Permission Error", details
+		case strings.Contains(lower, "config"):
+			return "Configuration Error", details
+		case strings.Contains(lower, "disk space") || strings.Contains(lower, "no space"):
+			return "Disk Space Error", details
+		}
+		return "Service Error", details
+	}
+
+	// The error log is the last resort
+	if len(e.ErrorLog) > 0 {
+		return "Database Error", strings.Join(e.ErrorLog, "\n")
+	}
+
+	return "Unknown Failure", "Could not determine the specific reason for service failure"
+}
+
+// ClassifyStartReason determines why the service started. It returns a readable
+// reason, which the alerts match on, and the evidence that led to it.
+func ClassifyStartReason(e *ReasonEvidence) (string, string) {
+	// Our own recoveries come first, systemd logs those starts like any other
+	if entry := e.recentRestart(livenessRecoveryTag); entry != "" {
+		return "Service restarted by liveness auto-recovery after it stopped answering queries", entry
+	}
+	if entry := e.recentRestart(memoryRecoveryTag); entry != "" {
+		return "Service restarted after memory-related shutdown", entry
+	}
+	if entries := matchEntries(e.Agent, func(entry journal.Entry) bool {
+		return strings.Contains(entry.Message, "CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_COMPLETED") && e.namesService(entry.Message)
+	}); len(entries) > 0 {
+		return "Service restarted after memory-related shutdown", joinMessages(entries)
+	}
+
+	if !e.BootTime.IsZero() && e.Now.Sub(e.BootTime) < bootStartWindow {
+		return "System startup detected - MariaDB service started during boot process",
+			fmt.Sprintf("System booted at %s", e.BootTime.Format(time.RFC3339))
+	}
+
+	if entries := matchEntries(e.Unit, func(entry journal.Entry) bool { return isSystemdJob(entry, "start") }); len(entries) > 0 {
+		details := joinMessages(entries)
+		if user := e.systemctlUser("start"); user != "" {
+			return fmt.Sprintf("MariaDB manually started by user '%s' via systemctl", user), details
+		}
+		return "MariaDB manually started via systemctl command", details
+	}
+
+	for i := len(e.ErrorLog) - 1; i >= 0; i-- {
+		lower := strings.ToLower(e.ErrorLog[i])
+		if strings.Contains(lower, "starting") || strings.Contains(lower, "started") || strings.Contains(lower, "ready for connections") {
+			return "MariaDB service started (found startup messages in logs)", strings.Join(e.ErrorLog, "\n")
+		}
+	}
+
+	if !e.ProcessStart.IsZero() {
+		return fmt.Sprintf("MariaDB service started at %s", e.ProcessStart.Format(time.RFC1123)),
+			"Process start time detected from system"
+	}
+
+	load := e.LoadAverage
+	if len(load) < 3 {
+		load = []float64{0, 0, 0}
+	}
+	details := fmt.Sprintf("System uptime: %s, Load average: %.2f, %.2f, %.2f",
+		e.Uptime.Truncate(time.Minute), load[0], load[1], load[2])
+
+	return "MariaDB service started (unable to determine specific trigger)", details
+}
+
+// recentRestart returns the newest restart log line of this service with the given
+// tag when it was written within the window. Every instance shares the log, so
+// lines are matched on "<tag> (<service>)".
+func (e *ReasonEvidence) recentRestart(tag string) string {
+	marker := fmt.Sprintf("%s (%s)", tag, e.ServiceName)
+	for i := len(e.RestartLog) - 1; i >= 0; i-- {
+		line := e.RestartLog[i]
+		if !strings.Contains(line, marker) {
+			continue
+		}
+		end := strings.Index(line, "]")
+		if !strings.HasPrefix(line, "[") || end < 0 {
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339, line[1:end])
+		if err == nil && e.Now.Sub(timestamp) < reasonWindow {
+			return line
+		}
+		return ""
+	}
+	return ""
+}
+
+// namesService reports whether a recovery marker in the journal was written for
+// this service, which the agent puts in parentheses
+func (e *ReasonEvidence) namesService(message string) bool {
+	return strings.Contains(message, "("+e.ServiceName+")")
+}
+
+// systemctlUser returns who ran systemctl for the service: the sudo log names the
+// invoking user, otherwise a "by <user>" phrase in the unit entries is used
+func (e *ReasonEvidence) systemctlUser(action string) string {
+	for i := len(e.Sudo) - 1; i >= 0; i-- {
+		message := e.Sudo[i].Message
+		_, command, found := strings.Cut(message, "COMMAND=")
+		if !found || !strings.Contains(command, "systemctl") || !strings.Contains(command, e.ServiceName) {
+			continue
+		}
+		if !strings.Contains(command, action) && !strings.Contains(command, "restart") {
+			continue
+		}
+		if user, _, found := strings.Cut(message, " : "); found {
+			return strings.TrimSpace(user)
+		}
+	}
+
+	for _, entry := range e.Unit {
+		if matches := byUserPattern.FindStringSubmatch(entry.Message); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// isSystemdJob reports whether an entry is systemd logging a job of the given type
+// ("start" or "stop") for the unit
+func isSystemdJob(entry journal.Entry, jobType string) bool {
+	if entry.JobType != "" {
+		return entry.JobType == jobType
+	}
+	if entry.Identifier != "systemd" {
+		return false
+	}
+	if jobType == "stop" {
+		return strings.HasPrefix(entry.Message, "Stopping ") || strings.HasPrefix(entry.Message, "Stopped ")
+	}
+	return strings.HasPrefix(entry.Message, "Started ")
+}
+
+// isServerOOMKill reports whether a kernel entry is the OOM killer killing the server
+func isServerOOMKill(entry journal.Entry) bool {
+	lower := strings.ToLower(entry.Message)
+	return strings.Contains(lower, "killed process") &&
+		(strings.Contains(lower, "mysqld") || strings.Contains(lower, "mariadb"))
+}
+
+// matchEntries returns the entries accepted by match, at most the last five
+func matchEntries(entries []journal.Entry, match func(journal.Entry) bool) []journal.Entry {
+	var matched []journal.Entry
+	for _, entry := range entries {
+		if match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	if len(matched) > 5 {
+		matched = matched[len(matched)-5:]
+	}
+	return matched
+}
+
+// joinMessages formats entries one per line with their time and identifier
+func joinMessages(entries []journal.Entry) string {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%s %s: %s", entry.Time.Format(time.Stamp), entry.Identifier, entry.Message))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,202 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/journal"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reasonNow is when the test status change is observed, shortly after the captures
+var reasonNow = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+// loadEvidence builds the evidence for a status change from a journalctl JSON capture,
+// routing each entry to the query that would have returned it
+func loadEvidence(t *testing.T, capture string) *ReasonEvidence {
+	evidence := &ReasonEvidence{ServiceName: "mariadb", Now: reasonNow, LoadAverage: []float64{0.5, 0.4, 0.3}}
+	if capture == "" {
+		return evidence
+	}
+
+	file, err := os.Open(filepath.Join("testdata", "journal", capture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	entries, err := journal.ParseEntries(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		switch {
+		case entry.Transport == "kernel":
+			evidence.Kernel = append(evidence.Kernel, entry)
+		case entry.Identifier == sudoIdentifier:
+			evidence.Sudo = append(evidence.Sudo, entry)
+		case entry.Identifier == agentIdentifier:
+			evidence.Agent = append(evidence.Agent, entry)
+		default:
+			evidence.Unit = append(evidence.Unit, entry)
+		}
+	}
+	return evidence
+}
+
+func TestClassifyStopReason(t *testing.T) {
+	tests := []struct {
+		name        string
+		capture     string
+		restartLog  []string
+		errorLog    []string
+		wantReason  string
+		wantDetails string
+	}{
+		{
+			name:        "OOM kill",
+			capture:     "oom_kill.json",
+			wantReason:  "Out of Memory Kill",
+			wantDetails: "Killed process 4321 (mariadbd)",
+		},
+		{
+			name:        "manual systemctl stop with sudo",
+			capture:     "manual_stop.json",
+			wantReason:  "Manual Systemctl Stop",
+			wantDetails: "stopped by user 'admin' via systemctl",
+		},
+		{
+			name:        "memory recovery marker",
+			capture:     "memory_recovery.json",
+			wantReason:  "Memory Critical Auto-Recovery",
+			wantDetails: "CHECKHEALTHDO_MEMORY_AUTO_RECOVERY_20240301_095820",
+		},
+		{
+			name:        "liveness recovery",
+			capture:     "manual_stop.json",
+			restartLog:  []string{"[2024-03-01T09:58:00Z] Liveness Auto-Recovery (mariadb): SQL liveness probe failed 3 consecutive times at query stage: i/o timeout"},
+			wantReason:  "Liveness Auto-Recovery",
+			wantDetails: "stopped answering queries",
+		},
+		{
+			name:       "stale memory recovery",
+			restartLog: []string{"[2024-03-01T08:00:00Z] Memory Critical Auto-Recovery (mariadb): Memory usage was 96.20% - PID before restart: 4321"},
+			wantReason: "Unknown Failure",
+		},
+		{
+			name:        "memory recovery in the restart log",
+			restartLog:  []string{"[2024-03-01T09:58:20Z] Memory Critical Auto-Recovery (mariadb): Memory usage was 96.20% - PID before restart: 4321"},
+			wantReason:  "Memory Critical Auto-Recovery",
+			wantDetails: "PID before restart: 4321",
+		},
+		{
+			name:    "recoveries of another instance",
+			capture: "other_instance_recovery.json",
+			restartLog: []string{
+				"[2024-03-01T09:58:00Z] Liveness Auto-Recovery (mariadb-replica): SQL liveness probe failed",
+				"[2024-03-01T09:58:20Z] Memory Critical Auto-Recovery (mariadb-replica): Memory usage was 96.20% - PID before restart: 4321",
+			},
+			wantReason: "Unknown Failure",
+		},
+		{
+			name:       "unit failure",
+			capture:    "service_failed.json",
+			wantReason: "Service Error",
+		},
+		{
+			name:        "error log",
+			errorLog:    []string{"2024-03-01  9:59:00 0 [ERROR] mariadbd: Got signal 11 ;"},
+			wantReason:  "Database Error",
+			wantDetails: "Got signal 11",
+		},
+		{
+			name:       "fallback",
+			wantReason: "Unknown Failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evidence := loadEvidence(t, tt.capture)
+			evidence.RestartLog = tt.restartLog
+			evidence.ErrorLog = tt.errorLog
+
+			reason, details := ClassifyStopReason(evidence)
+			if reason != tt.wantReason || !strings.Contains(details, tt.wantDetails) {
+				t.Errorf("ClassifyStopReason() = %q, %q\nwant %q with %q", reason, details, tt.wantReason, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestClassifyStartReason(t *testing.T) {
+	tests := []struct {
+		name         string
+		capture      string
+		restartLog   []string
+		errorLog     []string
+		bootTime     time.Time
+		processStart time.Time
+		wantReason   string
+	}{
+		{
+			name:       "manual systemctl start with sudo",
+			capture:    "manual_start.json",
+			bootTime:   reasonNow.Add(-48 * time.Hour),
+			wantReason: "MariaDB manually started by user 'ops' via systemctl",
+		},
+		{
+			name:       "memory recovery marker",
+			capture:    "memory_recovery.json",
+			wantReason: "Service restarted after memory-related shutdown",
+		},
+		{
+			name:       "liveness recovery",
+			capture:    "manual_start.json",
+			restartLog: []string{"[2024-03-01T09:58:00Z] Liveness Auto-Recovery (mariadb): SQL liveness probe failed"},
+			wantReason: "Service restarted by liveness auto-recovery after it stopped answering queries",
+		},
+		{
+			name:       "recoveries of another instance",
+			capture:    "other_instance_recovery.json",
+			restartLog: []string{"[2024-03-01T09:58:00Z] Liveness Auto-Recovery (mariadb-replica): SQL liveness probe failed"},
+			wantReason: "MariaDB service started (unable to determine specific trigger)",
+		},
+		{
+			name:       "boot",
+			capture:    "manual_start.json",
+			bootTime:   reasonNow.Add(-3 * time.Minute),
+			wantReason: "System startup detected - MariaDB service started during boot process",
+		},
+		{
+			name:       "startup messages in the error log",
+			errorLog:   []string{"2024-03-01  9:59:58 0 [Note] /usr/sbin/mariadbd: ready for connections."},
+			wantReason: "MariaDB service started (found startup messages in logs)",
+		},
+		{
+			name:         "process start time",
+			processStart: time.Date(2024, 3, 1, 9, 59, 0, 0, time.UTC),
+			wantReason:   "MariaDB service started at " + time.Date(2024, 3, 1, 9, 59, 0, 0, time.UTC).Format(time.RFC1123),
+		},
+		{
+			name:       "fallback",
+			wantReason: "MariaDB service started (unable to determine specific trigger)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evidence := loadEvidence(t, tt.capture)
+			evidence.RestartLog = tt.restartLog
+			evidence.ErrorLog = tt.errorLog
+			evidence.BootTime = tt.bootTime
+			evidence.ProcessStart = tt.processStart
+
+			if reason, details := ClassifyStartReason(evidence); reason != tt.wantReason {
+				t.Errorf("ClassifyStartReason() = %q (%s)\nwant %q", reason, details, tt.wantReason)
+			}
+		})
+	}
+}
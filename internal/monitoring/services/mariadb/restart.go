@@ -0,0 +1,36 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+)
+
+// autoStart starts a service that stopped unexpectedly when auto_restart is enabled.
+// It outlives the check that saw the stop, so ctx carries no deadline.
+func (m *Monitor) autoStart(ctx context.Context, stopReason string) {
+	defer func() {
+		m.mu.Lock()
+		m.restarting = false
+		m.mu.Unlock()
+	}()
+
+	serviceName := m.config.Monitoring.MariaDB.ServiceName
+
+	decision := mariadb.GetRestartPolicy(m.config).Allow("Auto Restart")
+	if !decision.Allowed {
+		logger.Warn("Skipping automatic start of stopped MariaDB service",
+			logger.String("service", serviceName),
+			logger.String("reason", decision.Reason))
+		return
+	}
+
+	logger.Warn("Starting MariaDB service after unexpected stop",
+		logger.String("service", serviceName),
+		logger.String("stop_reason", stopReason))
+
+	if err := mariadb.StartMariaDBService(ctx, serviceName); err != nil {
+		logger.Error("Failed to start MariaDB service after unexpected stop",
+			logger.String("error", err.Error()))
+	}
+}
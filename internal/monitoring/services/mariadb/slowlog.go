@@ -0,0 +1,209 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"sync"
+	"time"
+)
+
+// Default values for the slow query log watcher
+const (
+	defaultSlowLogPollInterval = 5
+	defaultSlowLogRetention    = 24
+	defaultSlowLogMaxEntries   = 100000
+	defaultSlowLogReportTop    = 10
+)
+
+// SlowLogWatcher follows the slow query log and keeps recent entries for digest reports
+type SlowLogWatcher struct {
+	config     *config.Config
+	notifier   *Notifier
+	parser     *mariadb.SlowLogParser
+	follower   *mariadb.LogFollower
+	mu         sync.RWMutex
+	entries    []mariadb.SlowQuery // Ordered by arrival, trimmed by retention and max entries
+	retention  time.Duration
+	maxEntries int
+}
+
+// NewSlowLogWatcher creates a new slow query log watcher
+func NewSlowLogWatcher(cfg *config.Config, notifier *Notifier) *SlowLogWatcher {
+	slowCfg := cfg.Monitoring.MariaDB.SlowLog
+
+	retention := slowCfg.Retention
+	if retention <= 0 {
+		retention = defaultSlowLogRetention
+	}
+	maxEntries := slowCfg.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultSlowLogMaxEntries
+	}
+
+	return &SlowLogWatcher{
+		config:     cfg,
+		notifier:   notifier,
+		parser:     mariadb.NewSlowLogParser(),
+		retention:  time.Duration(retention) * time.Hour,
+		maxEntries: maxEntries,
+	}
+}
+
+// Run polls the slow query log and sends periodic digest reports until the
+// context is cancelled or stop is closed
+func (w *SlowLogWatcher) Run(ctx context.Context, stop <-chan struct{}) {
+	slowCfg := w.config.Monitoring.MariaDB.SlowLog
+
+	interval := slowCfg.PollInterval
+	if interval <= 0 {
+		interval = defaultSlowLogPollInterval
+	}
+
+	defer func() {
+		if w.follower != nil {
+			w.follower.Close()
+		}
+	}()
+
+	if slowCfg.ReportInterval > 0 {
+		period := time.Duration(slowCfg.ReportInterval) * time.Hour
+		cancelReport, err := scheduler.Schedule(scheduler.Job{
+			Name:     monitorName(w.config) + "/slowlog-report",
+			Interval: period,
+			Delayed:  true,
+			Run: func(context.Context) error {
+				w.sendReport(period)
+				return nil
+			},
+		})
+		if err != nil {
+			logger.Error("Failed to schedule the slow query report", logger.String("error", err.Error()))
+		} else {
+			defer cancelReport()
+		}
+	}
+
+	var lastErr string
+	err := scheduler.RunUntil(ctx, stop, scheduler.Job{
+		Name:     monitorName(w.config) + "/slowlog",
+		Interval: time.Duration(interval) * time.Second,
+		Delayed:  true,
+		Run: func(ctx context.Context) error {
+			err := w.poll(ctx)
+			if err != nil {
+				// Only log when the error changes to avoid flooding while the file is missing
+				if err.Error() != lastErr {
+					logger.Warn("Failed to read MariaDB slow query log",
+						logger.String("error", err.Error()))
+					lastErr = err.Error()
+				}
+			} else {
+				lastErr = ""
+			}
+			return err
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to schedule slow query log polling", logger.String("error", err.Error()))
+	}
+}
+
+// poll reads new lines from the slow query log and stores the parsed entries
+func (w *SlowLogWatcher) poll(ctx context.Context) error {
+	if w.follower == nil {
+		path := w.config.Monitoring.MariaDB.SlowLog.Path
+		if path == "" {
+			resolved, err := mariadb.GetSlowLogPath(ctx, mariadb.GetDBConfigFromConfig(w.config))
+			if err != nil {
+				return err
+			}
+			path = resolved
+		}
+		w.follower = mariadb.NewLogFollower(path, true)
+		logger.Info("Following MariaDB slow query log", logger.String("path", path))
+	}
+
+	lines, err := w.follower.Poll()
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	entries := w.parser.Feed(lines)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries = append(w.entries, entries...)
+
+	// Drop entries past the retention window or over the size limit
+	cutoff := time.Now().Add(-w.retention)
+	start := 0
+	for start < len(w.entries) && w.entries[start].Time.Before(cutoff) {
+		start++
+	}
+	if len(w.entries)-start > w.maxEntries {
+		start = len(w.entries) - w.maxEntries
+	}
+	if start > 0 {
+		w.entries = append(w.entries[:0], w.entries[start:]...)
+	}
+
+	return nil
+}
+
+// Digests aggregates the entries logged between from and to. A zero bound is open.
+func (w *SlowLogWatcher) Digests(from, to time.Time) ([]mariadb.QueryDigestStats, int) {
+	w.mu.RLock()
+	selected := make([]mariadb.SlowQuery, 0, len(w.entries))
+	for _, entry := range w.entries {
+		if !from.IsZero() && entry.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && entry.Time.After(to) {
+			continue
+		}
+		selected = append(selected, entry)
+	}
+	w.mu.RUnlock()
+
+	return mariadb.DigestSlowQueries(selected), len(selected)
+}
+
+// sendReport emails the top digests for the last period
+func (w *SlowLogWatcher) sendReport(period time.Duration) {
+	to := time.Now()
+	from := to.Add(-period)
+	digests, total := w.Digests(from, to)
+	if total == 0 {
+		logger.Debug("No slow queries logged during report period, skipping digest report")
+		return
+	}
+
+	top := w.config.Monitoring.MariaDB.SlowLog.ReportTop
+	if top <= 0 {
+		top = defaultSlowLogReportTop
+	}
+	if len(digests) > top {
+		digests = digests[:top]
+	}
+
+	w.notifier.SendSlowQueryReport(digests, total, from, to)
+}
+
+// GetSlowQueryDigests returns slow query digests between from and to, or false when the slow log watcher is disabled
+func (m *Monitor) GetSlowQueryDigests(from, to time.Time) ([]mariadb.QueryDigestStats, int, bool) {
+	if m.slowLogWatcher == nil {
+		return nil, 0, false
+	}
+	digests, total := m.slowLogWatcher.Digests(from, to)
+	return digests, total, true
+}
@@ -0,0 +1,136 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/scheduler"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"sync"
+	"time"
+)
+
+// Default values for performance_schema sampling
+const (
+	defaultTopQueriesInterval = 60
+	defaultTopQueriesLimit    = 10
+)
+
+// TopQueriesCollector samples performance_schema statement digests and keeps
+// the busiest statements of the last interval
+type TopQueriesCollector struct {
+	config     *config.Config
+	mu         sync.RWMutex
+	previous   map[string]mariadb.StatementDigest
+	sampledAt  time.Time
+	latest     *mariadb.TopQueries
+	available  bool // performance_schema has been confirmed enabled
+	unavailErr string
+}
+
+// NewTopQueriesCollector creates a new performance_schema collector
+func NewTopQueriesCollector(cfg *config.Config) *TopQueriesCollector {
+	return &TopQueriesCollector{
+		config: cfg,
+	}
+}
+
+// Run samples statement digests until the context is cancelled or stop is closed
+func (t *TopQueriesCollector) Run(ctx context.Context, stop <-chan struct{}, isRunning func() bool) {
+	interval := t.config.Monitoring.MariaDB.TopQueries.PollInterval
+	if interval <= 0 {
+		interval = defaultTopQueriesInterval
+	}
+
+	err := scheduler.RunUntil(ctx, stop, scheduler.Job{
+		Name:     monitorName(t.config) + "/topqueries",
+		Interval: time.Duration(interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			t.sample(ctx, isRunning)
+			return nil
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to schedule top queries collection", logger.String("error", err.Error()))
+	}
+}
+
+// sample reads the digest summary and computes the delta against the previous sample
+func (t *TopQueriesCollector) sample(ctx context.Context, isRunning func() bool) {
+	if !isRunning() {
+		// Counters restart with the server, so the next sample starts a new baseline
+		t.mu.Lock()
+		t.previous = nil
+		t.mu.Unlock()
+		return
+	}
+
+	dbConfig := mariadb.GetDBConfigFromConfig(t.config)
+
+	if !t.available {
+		enabled, err := mariadb.IsPerformanceSchemaEnabled(ctx, dbConfig)
+		if err != nil {
+			t.logUnavailable(err.Error())
+			return
+		}
+		if !enabled {
+			t.logUnavailable("performance_schema is disabled on the server")
+			return
+		}
+		t.available = true
+		t.unavailErr = ""
+	}
+
+	current, err := mariadb.GetStatementDigests(ctx, dbConfig)
+	if err != nil {
+		t.logUnavailable(err.Error())
+		return
+	}
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.previous != nil {
+		deltas := mariadb.DiffStatementDigests(t.previous, current)
+		limit := t.config.Monitoring.MariaDB.TopQueries.Limit
+		if limit <= 0 {
+			limit = defaultTopQueriesLimit
+		}
+		t.latest = mariadb.BuildTopQueries(deltas, limit, t.sampledAt, now)
+	}
+	t.previous = current
+	t.sampledAt = now
+}
+
+// logUnavailable logs sampling problems once per distinct error
+func (t *TopQueriesCollector) logUnavailable(reason string) {
+	if reason == t.unavailErr {
+		return
+	}
+	t.unavailErr = reason
+	logger.Warn("Unable to sample performance_schema statement digests",
+		logger.String("reason", reason))
+}
+
+// Latest returns the top statements of the last completed interval
+func (t *TopQueriesCollector) Latest() *mariadb.TopQueries {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.latest
+}
+
+// GetTopQueries returns the top statements of the last interval, or false when sampling is disabled
+func (m *Monitor) GetTopQueries() (*mariadb.TopQueries, bool) {
+	if m.topQueries == nil {
+		return nil, false
+	}
+	return m.topQueries.Latest(), true
+}
+
+// isServiceRunning reports whether the last status check found MariaDB running
+func (m *Monitor) isServiceRunning() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status.Status == "running"
+}
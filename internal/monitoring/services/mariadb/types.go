@@ -1,10 +1,15 @@
 package mariadb
 
-import "time"
+import (
+	"CheckHealthDO/internal/services/mariadb"
+	"time"
+)
 
 // MariaDBMetricsMsg is the message structure for WebSocket updates
 type MariaDBMetricsMsg struct {
-	Timestamp      time.Time `json:"timestamp"`
-	Status         *Status   `json:"status"`
-	LastUpdateTime string    `json:"last_update_time"`
+	Instance       string              `json:"instance,omitempty"`
+	Timestamp      time.Time           `json:"timestamp"`
+	Status         *Status             `json:"status"`
+	LastUpdateTime string              `json:"last_update_time"`
+	TopQueries     *mariadb.TopQueries `json:"top_queries,omitempty"`
 }
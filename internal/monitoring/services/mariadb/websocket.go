@@ -1,26 +1,21 @@
 package mariadb
 
 import (
+	"CheckHealthDO/internal/monitoring"
 	"CheckHealthDO/internal/pkg/logger"
 	"CheckHealthDO/internal/websocket"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // WebSocketHandler creates a handler function for MariaDB status WebSocket
 func (m *Monitor) WebSocketHandler(c *gin.Context) {
-	// Initialize the WebSocket registry if needed
-	registry := websocket.GetRegistry()
-	handler := registry.GetMariaDBHandler() // Use MariaDB handler instead of metrics handler
-
-	// Create a new handler if one doesn't exist
-	if handler == nil {
-		handler = websocket.NewHandler()
-		registry.RegisterMariaDBHandler(handler)
-	}
+	// Get or create the handler for this instance's status topic
+	handler := statusHandler(m.Instance(), true)
 
 	// Force an immediate status check to get fresh data
-	err := m.checkStatus()
+	err := m.checkStatus(c.Request.Context())
 	if err != nil {
 		logger.Warn("Error checking MariaDB status before WebSocket connection",
 			logger.String("error", err.Error()))
@@ -32,3 +27,68 @@ func (m *Monitor) WebSocketHandler(c *gin.Context) {
 	logger.Info("New WebSocket client connected for MariaDB monitoring",
 		logger.String("client_ip", c.ClientIP()))
 }
+
+// LogsWebSocketHandler streams classified error log events to WebSocket clients
+func (m *Monitor) LogsWebSocketHandler(c *gin.Context) {
+	if m == nil || m.logWatcher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": "MariaDB error log monitoring is disabled",
+		})
+		return
+	}
+
+	handler := logsHandler(m.Instance(), true)
+
+	logger.Info("New WebSocket client connected for MariaDB error log",
+		logger.String("instance", m.Instance()),
+		logger.String("client_ip", c.ClientIP()))
+
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// WebSocketTopics implements monitoring.TopicProvider with the status and error log
+// streams of the instance
+func (m *Monitor) WebSocketTopics() []monitoring.Topic {
+	return []monitoring.Topic{
+		{Path: statusTopic(m.Instance()), Handler: m.WebSocketHandler},
+		{Path: logsTopic(m.Instance()), Handler: m.LogsWebSocketHandler},
+	}
+}
+
+// statusHandler returns the status WebSocket handler for an instance, registering
+// a new one when create is set
+func statusHandler(instance string, create bool) *websocket.Handler {
+	registry := websocket.GetRegistry()
+	if create {
+		return registry.HandlerFor(statusTopic(instance))
+	}
+	return registry.GetHandler(statusTopic(instance))
+}
+
+// logsHandler returns the error log WebSocket handler for an instance, registering
+// a new one when create is set
+func logsHandler(instance string, create bool) *websocket.Handler {
+	registry := websocket.GetRegistry()
+	if create {
+		return registry.HandlerFor(logsTopic(instance))
+	}
+	return registry.GetHandler(logsTopic(instance))
+}
+
+// statusTopic returns the status topic of an instance. The default instance keeps
+// /ws/mariadb, named instances are served below it.
+func statusTopic(instance string) string {
+	if instance == "" {
+		return websocket.TopicMariaDB
+	}
+	return websocket.TopicMariaDB + "/" + instance
+}
+
+// logsTopic returns the error log topic of an instance
+func logsTopic(instance string) string {
+	if instance == "" {
+		return websocket.TopicMariaDBLogs
+	}
+	return statusTopic(instance) + "/logs"
+}
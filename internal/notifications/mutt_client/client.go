@@ -3,11 +3,11 @@ package mutt_client
 import (
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
-	"bytes"
+	"CheckHealthDO/internal/pkg/runner"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"strings"
 )
 
@@ -71,36 +71,26 @@ func boolToYesNo(condition bool) string {
 
 // sendWithMutt attempts to send an email using the mutt command
 func (c *MuttClient) sendWithMutt(sender config.SenderEmail, recipients []string, subject, body string) error {
-	muttPath := c.config.Notifications.Email.MuttPath
+	configuredPath := c.config.Notifications.Email.MuttPath
+	muttPath := "mutt"
 
-	// Try to find mutt if path is not valid
-	if _, err := os.Stat(muttPath); os.IsNotExist(err) {
-		logger.Info("Mutt not found at configured path, searching in PATH",
-			logger.String("configured_path", muttPath))
-
-		// First try which command
-		cmdOutput, err := exec.Command("which", "mutt").Output()
-		if err == nil && len(cmdOutput) > 0 {
-			muttPath = strings.TrimSpace(string(cmdOutput))
-			logger.Info("Found mutt using 'which'",
-				logger.String("path", muttPath))
-		} else {
-			// Try common locations
-			commonPaths := []string{"/bin/mutt", "/usr/bin/mutt", "/usr/local/bin/mutt"}
-			for _, path := range commonPaths {
-				if _, err := os.Stat(path); err == nil {
-					muttPath = path
-					logger.Info("Found mutt at common location",
-						logger.String("path", muttPath))
-					break
-				}
-			}
+	// The configured path runs as mutt, other locations are left to the runner
+	if info, err := os.Stat(configuredPath); configuredPath != "" && err == nil && !info.IsDir() {
+		if err := runner.AllowPath(configuredPath, "mutt"); err != nil {
+			return fmt.Errorf("mutt path %s cannot be used: %w", configuredPath, err)
 		}
-	}
+		muttPath = configuredPath
+	} else {
+		logger.Info("Mutt not found at configured path, searching in PATH",
+			logger.String("configured_path", configuredPath))
 
-	// Final check if mutt exists
-	if _, err := os.Stat(muttPath); os.IsNotExist(err) {
-		return fmt.Errorf("mutt executable not found at %s or in PATH", muttPath)
+		// The runner searches PATH and the common locations
+		path, err := runner.LookPath("mutt")
+		if err != nil {
+			return fmt.Errorf("mutt executable not found at %s or in PATH", configuredPath)
+		}
+		logger.Info("Found mutt in PATH",
+			logger.String("path", path))
 	}
 
 	// Create a temporary file for the email body - simple text file, not HTML
@@ -186,14 +176,16 @@ set charset = "utf-8"
 	enhancedPath := fmt.Sprintf("%s:/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin", pathEnv)
 
 	// Execute mutt directly - DO NOT use stdin, use the -i option instead
-	cmd := exec.Command(muttPath, cmdArgs...)
-
-	// Set up the environment with enhanced PATH
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("PATH=%s", enhancedPath),
-		"HOME=/root",             // Ensure HOME is set for .muttrc
-		"CONTENT_TYPE=text/html", // Additional hint for HTML content
-	)
+	cmd := runner.Command{
+		Name: muttPath,
+		Args: cmdArgs,
+		// Set up the environment with enhanced PATH
+		Env: []string{
+			fmt.Sprintf("PATH=%s", enhancedPath),
+			"HOME=/root",             // Ensure HOME is set for .muttrc
+			"CONTENT_TYPE=text/html", // Additional hint for HTML content
+		},
+	}
 
 	// Log the command being executed
 	logger.Debug("Executing mutt command",
@@ -201,7 +193,8 @@ set charset = "utf-8"
 		logger.String("temp_file", tempFilePath),
 		logger.String("muttrc_path", muttrcPath))
 
-	output, err := cmd.CombinedOutput()
+	result, err := runner.Run(context.Background(), cmd)
+	output := result.Combined()
 
 	// Clean up the temporary files
 	os.Remove(tempFilePath)
@@ -222,38 +215,11 @@ set charset = "utf-8"
 
 // sendWithMailCommand attempts to send email using the mail command
 func (c *MuttClient) sendWithMailCommand(sender config.SenderEmail, recipients []string, subject, body string) error {
-	// Find mail command
-	mailPath, err := exec.LookPath("mail")
-	if err != nil {
-		// Check common locations
-		commonPaths := []string{"/bin/mail", "/usr/bin/mail", "/usr/local/bin/mail"}
-		for _, path := range commonPaths {
-			if _, err := os.Stat(path); err == nil {
-				mailPath = path
-				break
-			}
-		}
-	}
-
-	if mailPath == "" {
+	// Find mail command in PATH or common locations
+	if !runner.Available("mail") {
 		return fmt.Errorf("mail command not found in PATH or common locations")
 	}
 
-	// Create a temporary file for the email body
-	tempFile, err := ioutil.TempFile("", "mail_body_*.html")
-	if err != nil {
-		return fmt.Errorf("failed to create temporary file for email body: %w", err)
-	}
-	tempFilePath := tempFile.Name()
-
-	if _, err := tempFile.WriteString(body); err != nil {
-		tempFile.Close()
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to write email body to temporary file: %w", err)
-	}
-	tempFile.Close()
-	os.Chmod(tempFilePath, 0644)
-
 	// Build the mail command with HTML support
 	cmdArgs := []string{
 		"-s", subject,
@@ -266,52 +232,19 @@ func (c *MuttClient) sendWithMailCommand(sender config.SenderEmail, recipients [
 	pathEnv := os.Getenv("PATH")
 	enhancedPath := fmt.Sprintf("%s:/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin", pathEnv)
 
-	// Execute mail command
-	cmd := exec.Command(mailPath, cmdArgs...)
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("PATH=%s", enhancedPath),
-		"HOME=/root", // Ensure HOME is set
-	)
-
-	// Changed approach: write directly to stdin instead of using cat
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to get stdin pipe: %w", err)
-	}
-
-	// Capture output
-	var outputBuffer bytes.Buffer
-	cmd.Stdout = &outputBuffer
-	cmd.Stderr = &outputBuffer
-
-	// Start the command before writing to stdin
-	if err := cmd.Start(); err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to start mail command: %w", err)
-	}
-
-	// Read the file content
-	fileContent, err := ioutil.ReadFile(tempFilePath)
-	if err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	// Write to stdin and close
-	_, err = stdin.Write(fileContent)
-	stdin.Close()
-	if err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to write to mail stdin: %w", err)
-	}
-
-	// Wait for command to finish
-	err = cmd.Wait()
-	os.Remove(tempFilePath)
+	// Execute mail command with the body on stdin
+	result, err := runner.Run(context.Background(), runner.Command{
+		Name:  "mail",
+		Args:  cmdArgs,
+		Stdin: []byte(body),
+		Env: []string{
+			fmt.Sprintf("PATH=%s", enhancedPath),
+			"HOME=/root", // Ensure HOME is set
+		},
+	})
 
 	// Get the command output
-	output := outputBuffer.String()
+	output := string(result.Combined())
 
 	if err != nil {
 		logger.Error("Mail command failed",
@@ -328,20 +261,8 @@ func (c *MuttClient) sendWithMailCommand(sender config.SenderEmail, recipients [
 
 // sendWithSendmail attempts to send email using sendmail
 func (c *MuttClient) sendWithSendmail(sender config.SenderEmail, recipients []string, subject, body string) error {
-	// Find sendmail
-	sendmailPath, err := exec.LookPath("sendmail")
-	if err != nil {
-		// Check common locations
-		commonPaths := []string{"/usr/sbin/sendmail", "/usr/lib/sendmail", "/sbin/sendmail"}
-		for _, path := range commonPaths {
-			if _, err := os.Stat(path); err == nil {
-				sendmailPath = path
-				break
-			}
-		}
-	}
-
-	if sendmailPath == "" {
+	// Find sendmail in PATH or common locations
+	if !runner.Available("sendmail") {
 		return fmt.Errorf("sendmail not found in PATH or common locations")
 	}
 
@@ -352,21 +273,6 @@ func (c *MuttClient) sendWithSendmail(sender config.SenderEmail, recipients []st
 		subject,
 		body)
 
-	// Create a temporary file for the email content
-	tempFile, err := ioutil.TempFile("", "sendmail_*.txt")
-	if err != nil {
-		return fmt.Errorf("failed to create temporary file for email content: %w", err)
-	}
-	tempFilePath := tempFile.Name()
-
-	if _, err := tempFile.WriteString(emailContent); err != nil {
-		tempFile.Close()
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to write email content to temporary file: %w", err)
-	}
-	tempFile.Close()
-	os.Chmod(tempFilePath, 0644)
-
 	// Build sendmail arguments
 	cmdArgs := []string{"-t"}
 	for _, recipient := range recipients {
@@ -377,51 +283,19 @@ func (c *MuttClient) sendWithSendmail(sender config.SenderEmail, recipients []st
 	pathEnv := os.Getenv("PATH")
 	enhancedPath := fmt.Sprintf("%s:/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin", pathEnv)
 
-	// Execute sendmail command with enhanced environment
-	cmd := exec.Command(sendmailPath, cmdArgs...)
-	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("PATH=%s", enhancedPath),
-		"HOME=/root", // Ensure HOME is set
-	)
-
-	// Use the direct stdin pipe approach (similar to mail command changes)
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to get stdin pipe: %w", err)
-	}
-
-	// Capture output
-	var outputBuffer bytes.Buffer
-	cmd.Stdout = &outputBuffer
-	cmd.Stderr = &outputBuffer
-
-	if err := cmd.Start(); err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to start sendmail command: %w", err)
-	}
-
-	// Read the file content
-	fileContent, err := ioutil.ReadFile(tempFilePath)
-	if err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	// Write to stdin and close
-	_, err = stdin.Write(fileContent)
-	stdin.Close()
-	if err != nil {
-		os.Remove(tempFilePath)
-		return fmt.Errorf("failed to write to sendmail stdin: %w", err)
-	}
-
-	// Wait for command to finish
-	err = cmd.Wait()
-	os.Remove(tempFilePath)
+	// Execute sendmail command with the message on stdin
+	result, err := runner.Run(context.Background(), runner.Command{
+		Name:  "sendmail",
+		Args:  cmdArgs,
+		Stdin: []byte(emailContent),
+		Env: []string{
+			fmt.Sprintf("PATH=%s", enhancedPath),
+			"HOME=/root", // Ensure HOME is set
+		},
+	})
 
 	// Get the command output
-	output := outputBuffer.String()
+	output := string(result.Combined())
 
 	if err != nil {
 		logger.Error("Sendmail command failed",
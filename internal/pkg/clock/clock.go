@@ -0,0 +1,27 @@
+// Package clock abstracts the wall clock so time-based logic such as alert
+// throttling and escalation can be driven by a fake clock in tests.
+package clock
+
+import "time"
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+// Real is the system wall clock
+type Real struct{}
+
+// Now implements Clock
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Since implements Clock
+func (Real) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// System is the clock used when none is injected
+var System Clock = Real{}
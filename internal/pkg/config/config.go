@@ -43,24 +43,124 @@ type AuthConfig struct {
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
-	Database string `yaml:"database"`
+	Host            string `yaml:"host"`
+	Port            int    `yaml:"port"`
+	Username        string `yaml:"username"`
+	Password        string `yaml:"password"`
+	Database        string `yaml:"database"`
+	Socket          string `yaml:"socket"`             // Unix socket path, used instead of host and port when set
+	MaxOpenConns    int    `yaml:"max_open_conns"`     // Maximum open connections in the shared pool
+	MaxIdleConns    int    `yaml:"max_idle_conns"`     // Maximum idle connections kept in the pool
+	ConnMaxLifetime int    `yaml:"conn_max_lifetime"`  // In seconds
+	ConnMaxIdleTime int    `yaml:"conn_max_idle_time"` // In seconds
+	ConnectTimeout  int    `yaml:"connect_timeout"`    // In seconds
+	QueryTimeout    int    `yaml:"query_timeout"`      // In seconds
 }
 
 // MariaDBMonitoringConfig contains configuration for MariaDB monitoring
 type MariaDBMonitoringConfig struct {
+	Name               string `yaml:"name"` // Instance name, empty for the default instance
 	Enabled            bool   `yaml:"enabled"`
 	ServiceName        string `yaml:"service_name"`
 	CheckInterval      int    `yaml:"check_interval"`
-	LogPath            string `yaml:"log_path"`
+	LogPath            string `yaml:"log_path"` // Empty to discover it from my.cnf or @@log_error
 	AutoRestart        bool   `yaml:"auto_restart"`
 	RestartOnThreshold struct {
 		Enabled   bool   `yaml:"enabled"`
 		Threshold string `yaml:"threshold"`
 	} `yaml:"restart_on_threshold"`
+	ErrorLog   ErrorLogConfig   `yaml:"error_log"`
+	SlowLog    SlowLogConfig    `yaml:"slow_log"`
+	TopQueries TopQueriesConfig `yaml:"top_queries"`
+	InnoDB     InnoDBConfig     `yaml:"innodb"`
+	Galera     GaleraConfig     `yaml:"galera"`
+	Liveness   LivenessConfig   `yaml:"liveness"`
+
+	// Limits on automatic restarts by auto_restart, restart_on_threshold and the liveness probe
+	RestartPolicy RestartPolicyConfig `yaml:"restart_policy"`
+
+	// Option files read by auto-discovery, defaults to /etc/my.cnf, /etc/mysql/my.cnf and ~/.my.cnf
+	OptionFiles []string `yaml:"option_files"`
+
+	// Additional instances on the same host, each monitored independently
+	Instances []MariaDBInstanceConfig `yaml:"instances"`
+}
+
+// MariaDBInstanceConfig describes one of several MariaDB/MySQL instances on the host.
+// Monitoring settings are inlined so each instance has its own service unit, log path,
+// thresholds and restart policy. Connection settings left empty fall back to the
+// top-level database section.
+type MariaDBInstanceConfig struct {
+	MariaDBMonitoringConfig `yaml:",inline"`
+	Database                DatabaseConfig `yaml:"database"`
+}
+
+// ErrorLogConfig holds configuration for following the MariaDB error log
+type ErrorLogConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	PollInterval    int      `yaml:"poll_interval"`    // In seconds
+	BufferSize      int      `yaml:"buffer_size"`      // Number of classified events kept in memory
+	AlertSeverities []string `yaml:"alert_severities"` // Event severities that trigger an alert (info, warning, critical)
+	AlertCooldown   int      `yaml:"alert_cooldown"`   // Minimum seconds between alerts for the same category
+}
+
+// SlowLogConfig holds configuration for slow query log digests
+type SlowLogConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Path           string `yaml:"path"`            // Empty to use @@slow_query_log_file
+	PollInterval   int    `yaml:"poll_interval"`   // In seconds
+	Retention      int    `yaml:"retention"`       // Hours of entries kept in memory
+	MaxEntries     int    `yaml:"max_entries"`     // Upper bound on entries kept in memory
+	ReportInterval int    `yaml:"report_interval"` // Hours between digest report emails, 0 disables the report
+	ReportTop      int    `yaml:"report_top"`      // Number of digests included in the report
+}
+
+// TopQueriesConfig holds configuration for performance_schema statement digest sampling
+type TopQueriesConfig struct {
+	Enabled      bool `yaml:"enabled"`
+	PollInterval int  `yaml:"poll_interval"` // In seconds
+	Limit        int  `yaml:"limit"`         // Number of statements kept per ranking
+}
+
+// InnoDBConfig holds configuration for SHOW ENGINE INNODB STATUS sampling
+type InnoDBConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	CheckInterval     int  `yaml:"check_interval"`     // In seconds
+	DeadlockHistory   int  `yaml:"deadlock_history"`   // Number of deadlocks kept in memory
+	DeadlockThreshold int  `yaml:"deadlock_threshold"` // Deadlocks per hour that trigger an alert
+	SemaphoreWait     int  `yaml:"semaphore_wait"`     // Seconds a semaphore wait may last before alerting
+	AlertCooldown     int  `yaml:"alert_cooldown"`     // Minimum seconds between alerts of the same kind
+}
+
+// GaleraConfig holds configuration for Galera cluster health monitoring
+type GaleraConfig struct {
+	Enabled              bool    `yaml:"enabled"`
+	CheckInterval        int     `yaml:"check_interval"`         // In seconds
+	ExpectedSize         int     `yaml:"expected_size"`          // Alert when fewer nodes are in the cluster, 0 to only alert on decreases
+	SizeSettleTime       int     `yaml:"size_settle_time"`       // Seconds a smaller cluster must be stable before it becomes the baseline when expected_size is 0
+	FlowControlThreshold float64 `yaml:"flow_control_threshold"` // Paused fraction (0-1) considered flow control
+	FlowControlSamples   int     `yaml:"flow_control_samples"`   // Consecutive samples over the threshold before alerting
+}
+
+// LivenessConfig holds configuration for the SQL liveness probe
+type LivenessConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	Timeout          int    `yaml:"timeout"`            // In seconds
+	FailureThreshold int    `yaml:"failure_threshold"`  // Consecutive failures before the service is unresponsive
+	Mode             string `yaml:"mode"`               // Optional heartbeat table check: read or write
+	HeartbeatTable   string `yaml:"heartbeat_table"`    // Existing [schema.]table used by the read or write mode, see mariadb.HeartbeatTableDDL
+	AutoRestartAfter int    `yaml:"auto_restart_after"` // Consecutive failures before restarting the service, 0 disables
+	RestartCooldown  int    `yaml:"restart_cooldown"`   // Minimum seconds between automatic restarts
+}
+
+// RestartPolicyConfig holds the crash-loop protection for automatic MariaDB restarts
+type RestartPolicyConfig struct {
+	MaxRestarts        int     `yaml:"max_restarts"`          // Automatic restarts per window before restarts are inhibited
+	Window             int     `yaml:"window"`                // In seconds
+	InitialBackoff     int     `yaml:"initial_backoff"`       // Seconds after the first restart, doubled for each further restart
+	MaxBackoff         int     `yaml:"max_backoff"`           // Upper bound on the backoff in seconds
+	MinFreeDiskPercent float64 `yaml:"min_free_disk_percent"` // Free space required on the data directory, negative disables the check
+	ErrorLogLines      int     `yaml:"error_log_lines"`       // Error log lines checked for InnoDB corruption, negative disables the check
 }
 
 // MemoryMonitoringConfig holds memory monitoring configuration
@@ -73,10 +173,26 @@ type MemoryMonitoringConfig struct {
 
 // CPUMonitoringConfig holds CPU monitoring configuration
 type CPUMonitoringConfig struct {
-	Enabled           bool    `yaml:"enabled"`
-	WarningThreshold  float64 `yaml:"warning_threshold"`
-	CriticalThreshold float64 `yaml:"critical_threshold"`
-	CheckInterval     int     `yaml:"check_interval"`
+	Enabled           bool           `yaml:"enabled"`
+	WarningThreshold  float64        `yaml:"warning_threshold"`
+	CriticalThreshold float64        `yaml:"critical_threshold"`
+	CheckInterval     int            `yaml:"check_interval"`
+	Rules             CPURulesConfig `yaml:"rules"` // Alerts on dimensions the overall usage hides
+}
+
+// CPURulesConfig holds the CPU alert rules besides the overall usage
+type CPURulesConfig struct {
+	Steal       CPURuleConfig `yaml:"steal"`        // Percent of CPU time taken by the hypervisor
+	IOWait      CPURuleConfig `yaml:"iowait"`       // Percent of CPU time idle while waiting for I/O
+	CoreHotspot CPURuleConfig `yaml:"core_hotspot"` // Usage percent of the busiest core
+	Load        CPURuleConfig `yaml:"load"`         // 1-minute load average per logical processor
+}
+
+// CPURuleConfig holds the thresholds of a single CPU alert rule
+type CPURuleConfig struct {
+	Warning  float64 `yaml:"warning"`  // 0 disables the warning level
+	Critical float64 `yaml:"critical"` // 0 disables the critical level
+	Duration int     `yaml:"duration"` // Seconds a threshold must be exceeded before alerting, 0 alerts on the first sample
 }
 
 // DiskMonitoringConfig holds Disk monitoring configuration
@@ -88,12 +204,135 @@ type DiskMonitoringConfig struct {
 	MonitoredPath     []string `yaml:"monitored_paths"`
 }
 
+// ChecksMonitoringConfig holds configuration for synthetic endpoint checks
+type ChecksMonitoringConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Targets []CheckConfig `yaml:"targets"`
+}
+
+// CheckConfig describes a single synthetic check target
+type CheckConfig struct {
+	Name             string `yaml:"name"`
+	Type             string `yaml:"type"`              // http, tcp, dns or script
+	Target           string `yaml:"target"`            // URL, host:port, DNS name or script path depending on type
+	Interval         int    `yaml:"interval"`          // In seconds
+	Timeout          int    `yaml:"timeout"`           // In seconds
+	FailureThreshold int    `yaml:"failure_threshold"` // Consecutive failures before alerting
+	MaxLatency       int    `yaml:"max_latency"`       // In milliseconds, slower responses are reported as warning
+
+	// HTTP(S) options
+	Method             string `yaml:"method"`
+	ExpectedStatus     []int  `yaml:"expected_status"`
+	BodyMatch          string `yaml:"body_match"` // Regular expression the response body must match
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+
+	// DNS options
+	RecordType     string   `yaml:"record_type"`     // A, AAAA, CNAME, MX or TXT
+	ExpectedValues []string `yaml:"expected_values"` // Values that must be present in the answer
+	Resolver       string   `yaml:"resolver"`        // Optional resolver address (host:port)
+
+	// Script options
+	Args []string `yaml:"args"` // Arguments passed to the script, Nagios plugin style
+}
+
+// CertificatesMonitoringConfig holds configuration for TLS certificate expiry monitoring
+type CertificatesMonitoringConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	CheckInterval  int      `yaml:"check_interval"`  // In seconds
+	WarningDays    int      `yaml:"warning_days"`    // Days before expiry to raise a warning
+	CriticalDays   int      `yaml:"critical_days"`   // Days before expiry to raise a critical alert
+	Timeout        int      `yaml:"timeout"`         // Handshake timeout for endpoints, in seconds
+	ErrorThreshold int      `yaml:"error_threshold"` // Consecutive failed reads or handshakes before alerting
+	Files          []string `yaml:"files"`           // PEM certificate files on disk
+	CAFile         string   `yaml:"ca_file"`         // PEM bundle trusted besides the system roots, e.g. an internal CA
+	Endpoints      []string `yaml:"endpoints"`       // Remote TLS endpoints in host:port form
+	IncludeMariaDB bool     `yaml:"include_mariadb"` // Also check the certificate in @@ssl_cert, verified against @@ssl_ca
+	APICertFile    string   `yaml:"api_cert_file"`   // Certificate the API is served with, e.g. by a reverse proxy
+}
+
+// PressureMonitoringConfig holds configuration for Linux pressure stall information (PSI) monitoring
+type PressureMonitoringConfig struct {
+	Enabled       bool                   `yaml:"enabled"`
+	CheckInterval int                    `yaml:"check_interval"` // In seconds
+	CPU           PressureResourceConfig `yaml:"cpu"`
+	Memory        PressureResourceConfig `yaml:"memory"`
+	IO            PressureResourceConfig `yaml:"io"`
+}
+
+// PressureResourceConfig holds the stall thresholds of one resource, in percent of time
+type PressureResourceConfig struct {
+	Window       int     `yaml:"window"`        // Average compared with the thresholds: 10, 60 or 300 seconds, 0 uses 60
+	SomeWarning  float64 `yaml:"some_warning"`  // Time at least one task stalled, 0 disables
+	SomeCritical float64 `yaml:"some_critical"` // 0 disables
+	FullWarning  float64 `yaml:"full_warning"`  // Time all non-idle tasks stalled at once, 0 disables
+	FullCritical float64 `yaml:"full_critical"` // 0 disables
+	Duration     int     `yaml:"duration"`      // Seconds a level must hold before alerting or recovering, 0 acts on the first sample
+}
+
+// OOMMonitoringConfig holds configuration for the OOM killer watcher
+type OOMMonitoringConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Source        string `yaml:"source"`         // kmsg, journal or auto (kmsg when readable, otherwise the journal)
+	CheckInterval int    `yaml:"check_interval"` // Seconds between journal reads when reading the journal
+	History       int    `yaml:"history"`        // Number of OOM kills kept in memory
+}
+
+// SchedulerConfig holds configuration for the scheduler that runs all collections
+type SchedulerConfig struct {
+	Jitter    float64 `yaml:"jitter"`     // Percent of the interval by which runs are spread, 0 uses 10, negative disables
+	MaxJitter int     `yaml:"max_jitter"` // Upper bound of the jitter in seconds, 0 uses 5
+	Timeout   int     `yaml:"timeout"`    // Smallest deadline of a collection in seconds, 0 uses 10, the interval is used when longer
+}
+
+// HeartbeatsMonitoringConfig holds configuration for heartbeat (dead man's switch) monitoring
+type HeartbeatsMonitoringConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	CheckInterval int               `yaml:"check_interval"` // How often overdue heartbeats are evaluated, in seconds
+	Jobs          []HeartbeatConfig `yaml:"jobs"`
+}
+
+// HeartbeatConfig describes a single job that is expected to report in periodically
+type HeartbeatConfig struct {
+	Name        string `yaml:"name"`
+	Interval    int    `yaml:"interval"`     // Expected seconds between successful runs
+	Grace       int    `yaml:"grace"`        // Extra seconds allowed before the heartbeat is considered missed
+	MaxDuration int    `yaml:"max_duration"` // Seconds a started run may take before it is considered hung, 0 disables
+	Token       string `yaml:"token"`        // Token required to send pings; without one pings need a JWT while API auth is enabled
+}
+
+// BackupMonitoringConfig holds configuration for MariaDB backup verification
+type BackupMonitoringConfig struct {
+	Enabled       bool                 `yaml:"enabled"`
+	CheckInterval int                  `yaml:"check_interval"` // In seconds
+	Targets       []BackupTargetConfig `yaml:"targets"`
+}
+
+// BackupTargetConfig describes a directory that receives backups
+type BackupTargetConfig struct {
+	Name          string  `yaml:"name"`
+	Path          string  `yaml:"path"`           // Directory holding the backups
+	Type          string  `yaml:"type"`           // mariabackup or mysqldump
+	Pattern       string  `yaml:"pattern"`        // Glob for backup entries inside path, e.g. "*.sql.gz"
+	MaxAge        int     `yaml:"max_age"`        // Hours before the newest backup is considered stale
+	MinSize       int64   `yaml:"min_size"`       // Minimum size of the newest backup, in MB
+	SizeDeviation float64 `yaml:"size_deviation"` // Allowed deviation from previous backups, in percent
+	History       int     `yaml:"history"`        // Number of previous backups to compare sizes against
+	SettleTime    int     `yaml:"settle_time"`    // Minutes since the last write before a backup counts as finished
+}
+
 // MonitoringConfig contains configuration for monitoring
 type MonitoringConfig struct {
-	Memory  MemoryMonitoringConfig  `yaml:"memory"`
-	CPU     CPUMonitoringConfig     `yaml:"cpu"` // Add CPU monitoring config
-	MariaDB MariaDBMonitoringConfig `yaml:"mariadb"`
-	Disk    DiskMonitoringConfig    `yaml:"disk"`
+	Memory     MemoryMonitoringConfig       `yaml:"memory"`
+	CPU        CPUMonitoringConfig          `yaml:"cpu"` // Add CPU monitoring config
+	MariaDB    MariaDBMonitoringConfig      `yaml:"mariadb"`
+	Disk       DiskMonitoringConfig         `yaml:"disk"`
+	Checks     ChecksMonitoringConfig       `yaml:"checks"`
+	Certs      CertificatesMonitoringConfig `yaml:"certificates"`
+	Heartbeats HeartbeatsMonitoringConfig   `yaml:"heartbeats"`
+	Backup     BackupMonitoringConfig       `yaml:"backup"`
+	Pressure   PressureMonitoringConfig     `yaml:"pressure"`
+	OOM        OOMMonitoringConfig          `yaml:"oom"`
+	Scheduler  SchedulerConfig              `yaml:"scheduler"`
 }
 
 // NotificationsConfig holds notification related configuration
@@ -222,7 +461,7 @@ func GetDefaultConfig() *Config {
 					Threshold: "critical",
 				},
 				CheckInterval: 1,
-				LogPath:       "/var/log/mysql/error.log",
+				LogPath:       "", // Discovered from my.cnf or @@log_error
 				AutoRestart:   true,
 			},
 		},
@@ -263,3 +502,56 @@ func GetDefaultConfig() *Config {
 		},
 	}
 }
+
+// ForMariaDBInstance returns a copy of the configuration whose MariaDB monitoring and
+// database sections describe the given instance, so monitors and handlers written for
+// a single instance can serve it unchanged
+func (c *Config) ForMariaDBInstance(instance MariaDBInstanceConfig) *Config {
+	instanceCfg := *c
+	instanceCfg.Monitoring.MariaDB = instance.MariaDBMonitoringConfig
+	instanceCfg.Monitoring.MariaDB.Instances = nil
+	if instanceCfg.Monitoring.MariaDB.CheckInterval <= 0 {
+		instanceCfg.Monitoring.MariaDB.CheckInterval = c.Monitoring.MariaDB.CheckInterval
+	}
+
+	// Fill in connection settings the instance leaves empty
+	db := instance.Database
+	if db.Socket == "" {
+		if db.Host == "" {
+			db.Host = c.Database.Host
+		}
+		if db.Port == 0 {
+			db.Port = c.Database.Port
+		}
+	}
+	if db.Username == "" {
+		db.Username = c.Database.Username
+	}
+	if db.Password == "" {
+		db.Password = c.Database.Password
+	}
+	if db.Database == "" {
+		db.Database = c.Database.Database
+	}
+	if db.MaxOpenConns == 0 {
+		db.MaxOpenConns = c.Database.MaxOpenConns
+	}
+	if db.MaxIdleConns == 0 {
+		db.MaxIdleConns = c.Database.MaxIdleConns
+	}
+	if db.ConnMaxLifetime == 0 {
+		db.ConnMaxLifetime = c.Database.ConnMaxLifetime
+	}
+	if db.ConnMaxIdleTime == 0 {
+		db.ConnMaxIdleTime = c.Database.ConnMaxIdleTime
+	}
+	if db.ConnectTimeout == 0 {
+		db.ConnectTimeout = c.Database.ConnectTimeout
+	}
+	if db.QueryTimeout == 0 {
+		db.QueryTimeout = c.Database.QueryTimeout
+	}
+	instanceCfg.Database = db
+
+	return &instanceCfg
+}
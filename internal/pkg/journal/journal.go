@@ -0,0 +1,175 @@
+// Package journal reads structured entries from the systemd journal through
+// journalctl's JSON output, without going through a shell.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"CheckHealthDO/internal/pkg/runner"
+)
+
+// sinceFormat is the timestamp format accepted by journalctl --since
+const sinceFormat = "2006-01-02 15:04:05"
+
+// maxLineSize bounds a single JSON record; journald caps fields well below this
+const maxLineSize = 1024 * 1024
+
+// Entry is a single journal record with the fields the monitors look at
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Message    string    `json:"message"`
+	Unit       string    `json:"unit,omitempty"`        // _SYSTEMD_UNIT, the unit that logged the entry
+	ObjectUnit string    `json:"object_unit,omitempty"` // UNIT, the unit systemd logged about
+	Identifier string    `json:"identifier,omitempty"`  // SYSLOG_IDENTIFIER
+	PID        int       `json:"pid,omitempty"`
+	Priority   int       `json:"priority"`
+	Transport  string    `json:"transport,omitempty"` // _TRANSPORT, "kernel" for kernel messages
+	Comm       string    `json:"comm,omitempty"`
+	MessageID  string    `json:"message_id,omitempty"`
+	JobType    string    `json:"job_type,omitempty"`   // JOB_TYPE on systemd job messages, e.g. "stop"
+	JobResult  string    `json:"job_result,omitempty"` // JOB_RESULT on systemd job messages, e.g. "done"
+}
+
+// Query selects journal entries. Empty filters match everything; entries must
+// match one of the units or one of the identifiers when both are set.
+type Query struct {
+	Units       []string
+	Identifiers []string
+	Kernel      bool      // Only kernel messages
+	CurrentBoot bool      // Only entries from the current boot
+	Since       time.Time // Only entries at or after this time
+	Lines       int       // Only the most recent entries, 0 for all
+}
+
+// Args returns the journalctl arguments for the query
+func (q Query) Args() []string {
+	args := []string{"-o", "json", "--no-pager", "-q"}
+	for _, unit := range q.Units {
+		args = append(args, "--unit="+unit)
+	}
+	for _, identifier := range q.Identifiers {
+		args = append(args, "--identifier="+identifier)
+	}
+	if q.Kernel {
+		args = append(args, "-k")
+	}
+	if q.CurrentBoot {
+		args = append(args, "-b")
+	}
+	if !q.Since.IsZero() {
+		args = append(args, "--since="+q.Since.Format(sinceFormat))
+	}
+	if q.Lines > 0 {
+		args = append(args, "-n", strconv.Itoa(q.Lines))
+	}
+	return args
+}
+
+// Read runs journalctl for the query and returns the entries oldest first
+func Read(ctx context.Context, q Query) ([]Entry, error) {
+	output, err := runner.Output(ctx, "journalctl", q.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return ParseEntries(bytes.NewReader(output))
+}
+
+// record is the raw journalctl JSON export of an entry. Field values are strings,
+// except for binary data, which journalctl writes as an array of bytes.
+type record struct {
+	RealtimeTimestamp string          `json:"__REALTIME_TIMESTAMP"`
+	Message           json.RawMessage `json:"MESSAGE"`
+	SystemdUnit       string          `json:"_SYSTEMD_UNIT"`
+	Unit              string          `json:"UNIT"`
+	Identifier        string          `json:"SYSLOG_IDENTIFIER"`
+	PID               string          `json:"_PID"`
+	Priority          string          `json:"PRIORITY"`
+	Transport         string          `json:"_TRANSPORT"`
+	Comm              string          `json:"_COMM"`
+	MessageID         string          `json:"MESSAGE_ID"`
+	JobType           string          `json:"JOB_TYPE"`
+	JobResult         string          `json:"JOB_RESULT"`
+}
+
+// ParseEntries parses journalctl JSON output, one object per line. Lines that are
+// not valid JSON are skipped.
+func ParseEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			continue
+		}
+		entries = append(entries, rec.entry())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to parse journal output: %w", err)
+	}
+	return entries, nil
+}
+
+// entry converts a raw record
+func (rec record) entry() Entry {
+	entry := Entry{
+		Message:    decodeMessage(rec.Message),
+		Unit:       rec.SystemdUnit,
+		ObjectUnit: rec.Unit,
+		Identifier: rec.Identifier,
+		Transport:  rec.Transport,
+		Comm:       rec.Comm,
+		MessageID:  rec.MessageID,
+		JobType:    rec.JobType,
+		JobResult:  rec.JobResult,
+		Priority:   -1,
+	}
+
+	if usec, err := strconv.ParseInt(rec.RealtimeTimestamp, 10, 64); err == nil {
+		entry.Time = time.UnixMicro(usec)
+	}
+	if pid, err := strconv.Atoi(rec.PID); err == nil {
+		entry.PID = pid
+	}
+	if priority, err := strconv.Atoi(rec.Priority); err == nil {
+		entry.Priority = priority
+	}
+	return entry
+}
+
+// decodeMessage reads MESSAGE, which is a string, a byte array for non-UTF-8
+// data, or null when the message was too large
+func decodeMessage(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var data []byte
+	var values []int
+	if err := json.Unmarshal(raw, &values); err == nil {
+		data = make([]byte, len(values))
+		for i, value := range values {
+			data[i] = byte(value)
+		}
+	}
+	return string(data)
+}
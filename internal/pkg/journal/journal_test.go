@@ -0,0 +1,137 @@
+package journal
+
+import (
+	"CheckHealthDO/internal/pkg/runner"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEntries(t *testing.T) {
+	file, err := os.Open("testdata/entries.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	entries, err := ParseEntries(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entry{
+		{
+			Time:       time.Unix(1709287200, 0),
+			Message:    "Stopped MariaDB 10.11.6 database server.",
+			Unit:       "init.scope",
+			ObjectUnit: "mariadb.service",
+			Identifier: "systemd",
+			PID:        1,
+			Priority:   6,
+			Transport:  "journal",
+			Comm:       "systemd",
+			MessageID:  "9d1aaa27d60140bd96365438aad20286",
+			JobType:    "stop",
+			JobResult:  "done",
+		},
+		{
+			Time:       time.Unix(1709287201, 500000000),
+			Message:    "mariadbd: bad byte \xff",
+			Unit:       "mariadb.service",
+			Identifier: "mariadbd",
+			PID:        4321,
+			Priority:   3,
+			Transport:  "stdout",
+			Comm:       "mariadbd",
+		},
+		{
+			Time:       time.Unix(1709287202, 0),
+			Unit:       "mariadb.service",
+			Identifier: "mariadbd",
+			PID:        4321,
+			Priority:   6,
+			Transport:  "stdout",
+		},
+		{
+			Time:       time.Unix(1709287203, 0),
+			Message:    "Out of memory: Killed process 4321 (mariadbd) total-vm:4194304kB, anon-rss:3145728kB",
+			Identifier: "kernel",
+			Priority:   3,
+			Transport:  "kernel",
+		},
+		{
+			Message:    "admin : TTY=pts/0 ; PWD=/home/admin ; USER=root ; COMMAND=/usr/bin/systemctl stop mariadb",
+			Identifier: "sudo",
+			Priority:   -1,
+			Transport:  "syslog",
+		},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %d, want %d (banner, blank and truncated lines skipped): %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		got := entries[i]
+		if !got.Time.Equal(want[i].Time) {
+			t.Errorf("entry %d time = %v, want %v", i, got.Time, want[i].Time)
+		}
+		got.Time = want[i].Time
+		if got != want[i] {
+			t.Errorf("entry %d = %+v\nwant %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestParseEntriesLineTooLong(t *testing.T) {
+	input := `{"MESSAGE":"first"}` + "\n" + `{"MESSAGE":"` + strings.Repeat("x", maxLineSize) + `"}` + "\n"
+
+	entries, err := ParseEntries(strings.NewReader(input))
+	if err == nil || len(entries) != 1 || entries[0].Message != "first" {
+		t.Errorf("entries = %d, err = %v, want the first entry and an error", len(entries), err)
+	}
+}
+
+func TestQueryArgs(t *testing.T) {
+	since := time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local)
+	q := Query{
+		Units:       []string{"mariadb"},
+		Identifiers: []string{"sudo"},
+		Kernel:      true,
+		CurrentBoot: true,
+		Since:       since,
+		Lines:       100,
+	}
+
+	want := "-o json --no-pager -q --unit=mariadb --identifier=sudo -k -b --since=2024-03-01 10:00:00 -n 100"
+	if got := strings.Join(q.Args(), " "); got != want {
+		t.Errorf("Args() = %q\nwant %q", got, want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	data, err := os.ReadFile("testdata/entries.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := runner.NewFake()
+	fake.On("journalctl", "-o", "json").Return(string(data))
+	defer runner.SetDefault(runner.SetDefault(fake))
+
+	entries, err := Read(context.Background(), Query{Units: []string{"mariadb"}})
+	if err != nil || len(entries) != 5 {
+		t.Fatalf("entries = %d, err = %v, want 5", len(entries), err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 || strings.Join(calls[0].Args, " ") != "-o json --no-pager -q --unit=mariadb" {
+		t.Errorf("calls = %+v, want one journalctl call for the unit", calls)
+	}
+
+	fake.On("journalctl", "-o", "json").Exit(1, "Failed to open journal")
+	if _, err := Read(context.Background(), Query{}); err == nil {
+		t.Error("want the journalctl failure returned")
+	}
+}
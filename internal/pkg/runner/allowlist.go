@@ -0,0 +1,267 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Spec describes how an allowlisted binary may be run
+type Spec struct {
+	Timeout   time.Duration        // Default deadline, 0 for the package default
+	MaxOutput int                  // Capture limit per stream in bytes, 0 for the package default
+	Paths     []string             // Locations tried when the binary is not on PATH
+	Validate  func([]string) error // Checks the arguments, nil accepts any
+}
+
+// sbinPaths are searched for system binaries when the service runs with a minimal PATH
+var sbinPaths = []string{"/usr/local/sbin", "/usr/local/bin", "/usr/sbin", "/usr/bin", "/sbin", "/bin"}
+
+// unitNamePattern matches systemd unit and init script names
+var unitNamePattern = regexp.MustCompile(`^[A-Za-z0-9@._:-]+$`)
+
+// systemctlVerbs are the systemctl commands the monitors use
+var systemctlVerbs = map[string]bool{
+	"is-active": true, "is-enabled": true, "status": true, "show": true,
+	"start": true, "stop": true, "restart": true, "reload": true,
+}
+
+// serviceActions are the init script actions the monitors use
+var serviceActions = map[string]bool{"start": true, "stop": true, "restart": true, "status": true}
+
+// grepFlags are the grep options the monitors use
+var grepFlags = map[string]bool{"-i": true, "-F": true, "-E": true, "-c": true, "-h": true}
+
+// grepLogDir is the only directory grep may read files from
+const grepLogDir = "/var/log/"
+
+// journalctlWriteFlags change the journal and are never passed to journalctl
+var journalctlWriteFlags = []string{
+	"--rotate", "--flush", "--sync", "--relinquish-var", "--smart-relinquish-var",
+	"--vacuum-size", "--vacuum-time", "--vacuum-files", "--setup-keys", "--update-catalog",
+}
+
+var (
+	allowlist = map[string]Spec{
+		"systemctl": {Timeout: 2 * time.Minute, Validate: validateSystemctl},
+		"service":   {Timeout: 2 * time.Minute, Validate: validateService},
+		"journalctl": {
+			Timeout:   15 * time.Second,
+			MaxOutput: 8 * 1024 * 1024,
+			Validate:  validateJournalctl,
+		},
+		"pgrep":    {Timeout: 5 * time.Second, Validate: validatePgrep},
+		"logger":   {Timeout: 5 * time.Second, Validate: validateLogger},
+		"grep":     {Timeout: 10 * time.Second, Validate: validateGrep},
+		"mutt":     {Timeout: time.Minute, Paths: []string{"/bin/mutt", "/usr/bin/mutt", "/usr/local/bin/mutt"}},
+		"mail":     {Timeout: time.Minute, Paths: []string{"/bin/mail", "/usr/bin/mail", "/usr/local/bin/mail"}},
+		"sendmail": {Timeout: time.Minute, Paths: []string{"/usr/sbin/sendmail", "/usr/lib/sendmail", "/sbin/sendmail"}},
+	}
+	allowlistMu sync.RWMutex
+
+	// scripts are the paths added by AllowScript, which may be registered again
+	scripts = map[string]bool{}
+)
+
+// Allow adds a binary to the allowlist, replacing an existing entry. Absolute
+// paths allow exactly that file, such as a configured check script.
+func Allow(name string, spec Spec) {
+	allowlistMu.Lock()
+	defer allowlistMu.Unlock()
+	allowlist[name] = spec
+}
+
+// AllowScript allows a configured check script at an absolute path. It refuses
+// paths that are already allowlisted with another spec, and scripts named after
+// an allowlisted binary, so a check cannot replace the systemctl or journalctl
+// argument validation.
+func AllowScript(path string, spec Spec) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s is not an absolute path", ErrNotAllowed, path)
+	}
+	path = filepath.Clean(path)
+
+	allowlistMu.Lock()
+	defer allowlistMu.Unlock()
+
+	if _, ok := allowlist[path]; ok && !scripts[path] {
+		return fmt.Errorf("%w: %s is already allowlisted", ErrNotAllowed, path)
+	}
+	if _, ok := allowlist[filepath.Base(path)]; ok {
+		return fmt.Errorf("%w: %s has the name of an allowlisted command", ErrNotAllowed, path)
+	}
+	allowlist[path] = spec
+	scripts[path] = true
+	return nil
+}
+
+// AllowPath allows an absolute path, such as a configured mutt_path, to run with
+// the spec of an allowlisted binary
+func AllowPath(path, name string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s is not an absolute path", ErrNotAllowed, path)
+	}
+
+	allowlistMu.Lock()
+	defer allowlistMu.Unlock()
+
+	spec, ok := allowlist[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, name)
+	}
+	allowlist[filepath.Clean(path)] = spec
+	return nil
+}
+
+// lookupSpec returns the spec for a binary name or a registered absolute path. An
+// unregistered path is rejected even when its base name is allowlisted, so a
+// binary dropped at /tmp/systemctl cannot run.
+func lookupSpec(name string) (Spec, error) {
+	allowlistMu.RLock()
+	defer allowlistMu.RUnlock()
+
+	if spec, ok := allowlist[name]; ok {
+		return spec, nil
+	}
+	return Spec{}, fmt.Errorf("%w: %s", ErrNotAllowed, name)
+}
+
+// resolvePath finds the binary on PATH, then in the spec's locations and the sbin directories
+func resolvePath(name string, spec Spec) (string, error) {
+	if filepath.IsAbs(name) {
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			return name, nil
+		}
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	candidates := append([]string{}, spec.Paths...)
+	for _, dir := range sbinPaths {
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// validateArgs applies the checks common to every binary, then the binary's own
+func validateArgs(command Command, spec Spec) error {
+	for _, arg := range command.Args {
+		if strings.ContainsRune(arg, 0) {
+			return fmt.Errorf("%w: NUL byte in argument to %s", ErrInvalidArgument, command.Name)
+		}
+	}
+	if spec.Validate != nil {
+		if err := spec.Validate(command.Args); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidArgument, command.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateSystemctl allows the known verbs followed by unit names and property flags
+func validateSystemctl(args []string) error {
+	if len(args) == 0 || !systemctlVerbs[args[0]] {
+		return fmt.Errorf("unsupported systemctl command")
+	}
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "-p" || args[i] == "--property":
+			i++
+		case strings.HasPrefix(args[i], "--property=") || args[i] == "--no-pager" || args[i] == "--quiet":
+		case !unitNamePattern.MatchString(args[i]) || strings.HasPrefix(args[i], "-"):
+			return fmt.Errorf("invalid unit name %q", args[i])
+		}
+	}
+	return nil
+}
+
+// validateService allows "service <name> <action>"
+func validateService(args []string) error {
+	if len(args) != 2 || !unitNamePattern.MatchString(args[0]) || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("expected a service name and an action")
+	}
+	if !serviceActions[args[1]] {
+		return fmt.Errorf("unsupported service action %q", args[1])
+	}
+	return nil
+}
+
+// validateJournalctl rejects options that modify the journal or read arbitrary files
+func validateJournalctl(args []string) error {
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		for _, denied := range journalctlWriteFlags {
+			if flag == denied {
+				return fmt.Errorf("option %s is not allowed", flag)
+			}
+		}
+		if flag == "--file" || flag == "-D" || flag == "--directory" || flag == "--root" {
+			return fmt.Errorf("option %s is not allowed", flag)
+		}
+	}
+	return nil
+}
+
+// validatePgrep allows a pattern with the -f and -x options
+func validatePgrep(args []string) error {
+	pattern := 0
+	for _, arg := range args {
+		switch {
+		case arg == "-f" || arg == "-x":
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("option %s is not allowed", arg)
+		default:
+			pattern++
+		}
+	}
+	if pattern != 1 {
+		return fmt.Errorf("expected exactly one pattern")
+	}
+	return nil
+}
+
+// validateLogger allows "logger -t <tag> <message>"
+func validateLogger(args []string) error {
+	if len(args) != 3 || args[0] != "-t" || strings.HasPrefix(args[1], "-") {
+		return fmt.Errorf("expected -t <tag> <message>")
+	}
+	return nil
+}
+
+// validateGrep allows "grep [options] [--] <pattern> <file>..." where the files are
+// logs under /var/log. Options that read patterns from files or recurse are rejected.
+func validateGrep(args []string) error {
+	i := 0
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		if args[i] == "--" {
+			i++
+			break
+		}
+		if !grepFlags[args[i]] {
+			return fmt.Errorf("option %s is not allowed", args[i])
+		}
+	}
+
+	if len(args)-i < 2 {
+		return fmt.Errorf("expected a pattern and at least one file")
+	}
+	for _, file := range args[i+1:] {
+		if !strings.HasPrefix(file, grepLogDir) || filepath.Clean(file) != file {
+			return fmt.Errorf("file %q is not under %s", file, grepLogDir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Response is the canned outcome of a faked command
+type Response struct {
+	Stdout   string `json:"stdout,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+	Err      error  `json:"-"` // Returned instead of running, e.g. ErrTimeout
+}
+
+// Record is a command together with what it returned
+type Record struct {
+	Command  Command  `json:"command"`
+	Response Response `json:"response"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Fake is a Runner that answers from canned responses without starting processes.
+// Commands still go through the allowlist and argument validation, so a test fails
+// the same way production would on a rejected command.
+type Fake struct {
+	mu        sync.Mutex
+	responses map[string]Response
+	missing   map[string]bool
+	calls     []Command
+	fallback  *Response
+}
+
+// NewFake creates a fake runner where every command fails as not installed until
+// a response is set
+func NewFake() *Fake {
+	return &Fake{
+		responses: make(map[string]Response),
+		missing:   make(map[string]bool),
+	}
+}
+
+// NewFakeFromRecords creates a fake that replays recorded commands
+func NewFakeFromRecords(records []Record) *Fake {
+	f := NewFake()
+	for _, record := range records {
+		response := record.Response
+		if response.Err == nil && response.ExitCode == 0 && record.Error != "" {
+			response.Err = errors.New(record.Error)
+		}
+		f.On(record.Command.Name, record.Command.Args...).Set(response)
+	}
+	return f
+}
+
+// Stub sets the response of one command line
+type Stub struct {
+	fake *Fake
+	key  string
+}
+
+// On starts a stub for a command. The arguments are a prefix: the stub with the
+// longest matching prefix answers.
+func (f *Fake) On(name string, args ...string) *Stub {
+	return &Stub{fake: f, key: fakeKey(name, args)}
+}
+
+// Set sets the full response
+func (s *Stub) Set(response Response) {
+	s.fake.mu.Lock()
+	defer s.fake.mu.Unlock()
+	s.fake.responses[s.key] = response
+}
+
+// Return answers with stdout and a zero exit code
+func (s *Stub) Return(stdout string) {
+	s.Set(Response{Stdout: stdout})
+}
+
+// Exit answers with an exit code and stderr
+func (s *Stub) Exit(code int, stderr string) {
+	s.Set(Response{ExitCode: code, Stderr: stderr})
+}
+
+// Fail answers with an error, as if the command could not run
+func (s *Stub) Fail(err error) {
+	s.Set(Response{Err: err})
+}
+
+// SetFallback sets the response for commands without a stub
+func (f *Fake) SetFallback(response Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback = &response
+}
+
+// SetMissing makes LookPath report a binary as not installed
+func (f *Fake) SetMissing(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.missing[name] = true
+}
+
+// Calls returns the commands run so far
+func (f *Fake) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Command{}, f.calls...)
+}
+
+// LookPath implements Runner
+func (f *Fake) LookPath(name string) (string, error) {
+	if _, err := lookupSpec(name); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.missing[name] {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	return "/usr/bin/" + name, nil
+}
+
+// Run implements Runner
+func (f *Fake) Run(ctx context.Context, command Command) (*Result, error) {
+	spec, err := lookupSpec(command.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateArgs(command, spec); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, command)
+	response, ok := f.match(command)
+	missing := f.missing[command.Name]
+	f.mu.Unlock()
+
+	if missing {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, command.Name)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: no fake response for %s", ErrNotFound, command)
+	}
+	return response.result(command)
+}
+
+// match finds the stub with the longest argument prefix. Must be called with the lock held.
+func (f *Fake) match(command Command) (Response, bool) {
+	for n := len(command.Args); n >= 0; n-- {
+		if response, ok := f.responses[fakeKey(command.Name, command.Args[:n])]; ok {
+			return response, true
+		}
+	}
+	if f.fallback != nil {
+		return *f.fallback, true
+	}
+	return Response{}, false
+}
+
+// result turns a canned response into what ExecRunner would return
+func (r Response) result(command Command) (*Result, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	result := &Result{
+		Stdout:   []byte(r.Stdout),
+		Stderr:   []byte(r.Stderr),
+		ExitCode: r.ExitCode,
+	}
+	if r.ExitCode != 0 {
+		return result, &ExitError{Command: command.Name, ExitCode: r.ExitCode, Stderr: strings.TrimSpace(r.Stderr)}
+	}
+	return result, nil
+}
+
+// fakeKey joins a command line into a map key
+func fakeKey(name string, args []string) string {
+	return strings.Join(append([]string{name}, args...), "\x00")
+}
+
+// Recorder is a Runner that passes commands to another runner and keeps every
+// command with its outcome, to build fixtures for a Fake
+type Recorder struct {
+	next    Runner
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewRecorder wraps a runner
+func NewRecorder(next Runner) *Recorder {
+	return &Recorder{next: next}
+}
+
+// LookPath implements Runner
+func (r *Recorder) LookPath(name string) (string, error) {
+	return r.next.LookPath(name)
+}
+
+// Run implements Runner
+func (r *Recorder) Run(ctx context.Context, command Command) (*Result, error) {
+	result, err := r.next.Run(ctx, command)
+
+	record := Record{Command: command}
+	if result != nil {
+		record.Response = Response{
+			Stdout:   string(result.Stdout),
+			Stderr:   string(result.Stderr),
+			ExitCode: result.ExitCode,
+		}
+	} else if err != nil {
+		record.Response.Err = err
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.records = append(r.records, record)
+	r.mu.Unlock()
+
+	return result, err
+}
+
+// Records returns everything recorded so far
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record{}, r.records...)
+}
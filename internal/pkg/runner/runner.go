@@ -0,0 +1,268 @@
+// Package runner executes external commands for the monitors. Every command goes
+// through an allowlist of binaries with per-binary argument validation, runs with a
+// deadline and without a shell, and has its output captured up to a limit. Tests
+// swap the default runner for a Fake.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults used when neither the command nor its spec set a value
+const (
+	defaultTimeout   = 30 * time.Second
+	defaultMaxOutput = 1024 * 1024
+	waitDelay        = 2 * time.Second // How long to wait for children holding the output pipes after a kill
+	maxErrorStderr   = 512             // Stderr kept in ExitError messages
+)
+
+// Errors returned before a command is started
+var (
+	ErrNotAllowed      = errors.New("command is not on the allowlist")
+	ErrInvalidArgument = errors.New("invalid command argument")
+	ErrNotFound        = errors.New("command not found")
+)
+
+// ErrTimeout is returned when a command is killed at its deadline
+var ErrTimeout = errors.New("command timed out")
+
+// Command is a single invocation of an allowlisted binary
+type Command struct {
+	Name    string        // Allowlisted binary name, or an absolute path registered with Allow or AllowPath
+	Args    []string      // Arguments, passed as is without a shell
+	Timeout time.Duration // Deadline for this call, 0 uses the binary's default
+	Stdin   []byte        // Data written to standard input
+	Env     []string      // Variables added to the inherited environment
+}
+
+// String returns the command line for logs
+func (c Command) String() string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
+
+// Result is the outcome of a command that was started
+type Result struct {
+	Stdout    []byte        `json:"stdout"`
+	Stderr    []byte        `json:"stderr"`
+	ExitCode  int           `json:"exit_code"`
+	Duration  time.Duration `json:"duration"`
+	Truncated bool          `json:"truncated,omitempty"` // Output went past the capture limit
+}
+
+// Combined returns stdout followed by stderr
+func (r *Result) Combined() []byte {
+	if r == nil {
+		return nil
+	}
+	return append(append([]byte{}, r.Stdout...), r.Stderr...)
+}
+
+// ExitError reports a command that ran and exited with a non-zero status
+type ExitError struct {
+	Command  string
+	ExitCode int
+	Stderr   string
+}
+
+// Error implements error
+func (e *ExitError) Error() string {
+	if e.Stderr != "" {
+		stderr := e.Stderr
+		if len(stderr) > maxErrorStderr {
+			stderr = stderr[:maxErrorStderr] + "..."
+		}
+		return fmt.Sprintf("%s: exit status %d: %s", e.Command, e.ExitCode, stderr)
+	}
+	return fmt.Sprintf("%s: exit status %d", e.Command, e.ExitCode)
+}
+
+// Runner executes commands
+type Runner interface {
+	// Run executes the command. A non-zero exit returns the result together
+	// with an *ExitError.
+	Run(ctx context.Context, cmd Command) (*Result, error)
+	// LookPath resolves an allowlisted binary to its path
+	LookPath(name string) (string, error)
+}
+
+var (
+	defaultRunner Runner = NewExecRunner()
+	defaultMu     sync.RWMutex
+)
+
+// Default returns the runner used by the package level functions
+func Default() Runner {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultRunner
+}
+
+// SetDefault replaces the runner used by the package level functions and returns
+// the previous one, so tests can restore it
+func SetDefault(r Runner) Runner {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	previous := defaultRunner
+	defaultRunner = r
+	return previous
+}
+
+// Run executes a command with the default runner
+func Run(ctx context.Context, cmd Command) (*Result, error) {
+	return Default().Run(ctx, cmd)
+}
+
+// Output runs a binary with the default runner and returns its stdout
+func Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	result, err := Run(ctx, Command{Name: name, Args: args})
+	if result == nil {
+		return nil, err
+	}
+	return result.Stdout, err
+}
+
+// LookPath resolves an allowlisted binary with the default runner
+func LookPath(name string) (string, error) {
+	return Default().LookPath(name)
+}
+
+// Available reports whether an allowlisted binary is installed
+func Available(name string) bool {
+	_, err := LookPath(name)
+	return err == nil
+}
+
+// ExitCode returns the exit status carried by err, or -1 when the command did not run
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode
+	}
+	if err == nil {
+		return 0
+	}
+	return -1
+}
+
+// ExecRunner runs commands as child processes
+type ExecRunner struct{}
+
+// NewExecRunner creates a runner that executes real processes
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{}
+}
+
+// LookPath implements Runner
+func (r *ExecRunner) LookPath(name string) (string, error) {
+	spec, err := lookupSpec(name)
+	if err != nil {
+		return "", err
+	}
+	return resolvePath(name, spec)
+}
+
+// Run implements Runner
+func (r *ExecRunner) Run(ctx context.Context, command Command) (*Result, error) {
+	spec, err := lookupSpec(command.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateArgs(command, spec); err != nil {
+		return nil, err
+	}
+	path, err := resolvePath(command.Name, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := command.Timeout
+	if timeout <= 0 {
+		timeout = spec.Timeout
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxOutput := spec.MaxOutput
+	if maxOutput <= 0 {
+		maxOutput = defaultMaxOutput
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, command.Args...)
+	cmd.WaitDelay = waitDelay
+	if len(command.Env) > 0 {
+		cmd.Env = append(os.Environ(), command.Env...)
+	}
+	if command.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(command.Stdin)
+	}
+	stdout := &limitedBuffer{limit: maxOutput}
+	stderr := &limitedBuffer{limit: maxOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	runErr := cmd.Run()
+	result := &Result{
+		Stdout:    stdout.Bytes(),
+		Stderr:    stderr.Bytes(),
+		Duration:  time.Since(start),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.ExitCode = -1
+		return result, fmt.Errorf("%s: %w after %s", command.Name, ErrTimeout, timeout)
+	}
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+			return result, &ExitError{
+				Command:  command.Name,
+				ExitCode: result.ExitCode,
+				Stderr:   strings.TrimSpace(string(result.Stderr)),
+			}
+		}
+		result.ExitCode = -1
+		return result, fmt.Errorf("failed to run %s: %w", command.Name, runErr)
+	}
+
+	return result, nil
+}
+
+// limitedBuffer keeps writes up to its limit and drops the rest. The buffer is not
+// embedded, so io.Copy cannot bypass Write through bytes.Buffer.ReadFrom.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write implements io.Writer
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.limit - b.buf.Len()
+	if len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// Bytes returns the captured output
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
@@ -0,0 +1,194 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// allowShell registers a private copy of /bin/sh with the given spec
+func allowShell(t *testing.T, spec Spec) string {
+	path := filepath.Join(t.TempDir(), "sh")
+	if err := os.Symlink("/bin/sh", path); err != nil {
+		t.Fatal(err)
+	}
+	Allow(path, spec)
+	return path
+}
+
+func TestRunTimeoutKillsCommand(t *testing.T) {
+	shell := allowShell(t, Spec{Timeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	result, err := NewExecRunner().Run(context.Background(), Command{Name: shell, Args: []string{"-c", "echo started; exec sleep 10"}})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("err = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %s, want the command killed at its deadline", elapsed)
+	}
+	if result == nil || result.ExitCode != -1 || strings.TrimSpace(string(result.Stdout)) != "started" {
+		t.Errorf("result = %+v, want the output captured before the kill", result)
+	}
+	if ExitCode(err) != -1 {
+		t.Errorf("ExitCode() = %d, want -1", ExitCode(err))
+	}
+}
+
+func TestRunCommandTimeoutOverridesSpec(t *testing.T) {
+	shell := allowShell(t, Spec{Timeout: time.Minute})
+
+	_, err := NewExecRunner().Run(context.Background(), Command{Name: shell, Args: []string{"-c", "exec sleep 10"}, Timeout: 100 * time.Millisecond})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("err = %v, want ErrTimeout", err)
+	}
+}
+
+func TestRunCaptureTruncated(t *testing.T) {
+	shell := allowShell(t, Spec{MaxOutput: 10})
+
+	result, err := NewExecRunner().Run(context.Background(), Command{
+		Name: shell,
+		Args: []string{"-c", "printf 0123456789abcdef; printf error-output-too-long >&2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Stdout) != "0123456789" || string(result.Stderr) != "error-outp" || !result.Truncated {
+		t.Errorf("result = %q / %q truncated %v, want both streams cut at 10 bytes", result.Stdout, result.Stderr, result.Truncated)
+	}
+}
+
+func TestRunExitError(t *testing.T) {
+	shell := allowShell(t, Spec{})
+
+	result, err := NewExecRunner().Run(context.Background(), Command{
+		Name:  shell,
+		Args:  []string{"-c", "cat; echo failed >&2; exit 3"},
+		Stdin: []byte("input"),
+	})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 3 || exitErr.Stderr != "failed" {
+		t.Fatalf("err = %v, want exit status 3 with stderr", err)
+	}
+	if string(result.Stdout) != "input" || ExitCode(err) != 3 {
+		t.Errorf("stdout = %q, exit code = %d, want the stdin echoed and 3", result.Stdout, ExitCode(err))
+	}
+}
+
+func TestValidationRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  Command
+		want error
+	}{
+		{"binary not allowlisted", Command{Name: "curl", Args: []string{"http://example.com"}}, ErrNotAllowed},
+		{"path with an allowlisted base name", Command{Name: "/tmp/systemctl", Args: []string{"status", "mariadb"}}, ErrNotAllowed},
+		{"relative path", Command{Name: "bin/systemctl", Args: []string{"status", "mariadb"}}, ErrNotAllowed},
+		{"systemctl verb", Command{Name: "systemctl", Args: []string{"mask", "mariadb"}}, ErrInvalidArgument},
+		{"systemctl unit option", Command{Name: "systemctl", Args: []string{"stop", "--root=/tmp"}}, ErrInvalidArgument},
+		{"service action", Command{Name: "service", Args: []string{"mariadb", "purge"}}, ErrInvalidArgument},
+		{"journalctl vacuum", Command{Name: "journalctl", Args: []string{"--vacuum-time=1s"}}, ErrInvalidArgument},
+		{"journalctl file", Command{Name: "journalctl", Args: []string{"--file=/etc/shadow"}}, ErrInvalidArgument},
+		{"pgrep signal", Command{Name: "pgrep", Args: []string{"--signal", "KILL", "mysqld"}}, ErrInvalidArgument},
+		{"logger without tag", Command{Name: "logger", Args: []string{"message"}}, ErrInvalidArgument},
+		{"grep recursive", Command{Name: "grep", Args: []string{"-r", "password", "/var/log"}}, ErrInvalidArgument},
+		{"grep pattern file", Command{Name: "grep", Args: []string{"-f", "/etc/shadow", "/var/log/syslog"}}, ErrInvalidArgument},
+		{"grep outside the log directory", Command{Name: "grep", Args: []string{"--", "root", "/etc/shadow"}}, ErrInvalidArgument},
+		{"grep path traversal", Command{Name: "grep", Args: []string{"--", "root", "/var/log/../../etc/shadow"}}, ErrInvalidArgument},
+		{"grep without a file", Command{Name: "grep", Args: []string{"--", "mariadb"}}, ErrInvalidArgument},
+		{"NUL byte", Command{Name: "systemctl", Args: []string{"status", "mariadb\x00"}}, ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFake()
+			fake.SetFallback(Response{})
+			if _, err := fake.Run(context.Background(), tt.cmd); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if len(fake.Calls()) != 0 {
+				t.Error("want the command rejected before it runs")
+			}
+		})
+	}
+}
+
+func TestValidationAccepts(t *testing.T) {
+	tests := []Command{
+		{Name: "systemctl", Args: []string{"show", "mariadb", "-p", "ActiveState", "--no-pager"}},
+		{Name: "service", Args: []string{"mariadb", "status"}},
+		{Name: "journalctl", Args: []string{"-o", "json", "--no-pager", "-q", "--unit=mariadb", "-n", "100"}},
+		{Name: "pgrep", Args: []string{"-f", "mysqld"}},
+		{Name: "logger", Args: []string{"-t", "CheckHealthDO", "message"}},
+		{Name: "grep", Args: []string{"--", "mariadb", "/var/log/syslog"}},
+		{Name: "grep", Args: []string{"-i", "-F", "--", "-not-an-option", "/var/log/syslog", "/var/log/messages"}},
+	}
+
+	for _, cmd := range tests {
+		fake := NewFake()
+		fake.SetFallback(Response{})
+		if _, err := fake.Run(context.Background(), cmd); err != nil {
+			t.Errorf("%s: err = %v, want it accepted", cmd, err)
+		}
+	}
+}
+
+func TestAllowPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mutt")
+	if err := AllowPath(path, "mutt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lookupSpec(path); err != nil {
+		t.Errorf("lookupSpec(%s) = %v, want the registered path allowed", path, err)
+	}
+	if _, err := lookupSpec(filepath.Join(filepath.Dir(path), "other", "mutt")); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("lookupSpec() = %v, want other paths rejected", err)
+	}
+
+	if err := AllowPath("mutt", "mutt"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("AllowPath(relative) = %v, want ErrNotAllowed", err)
+	}
+	if err := AllowPath(path, "curl"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("AllowPath(curl) = %v, want ErrNotAllowed", err)
+	}
+}
+
+func TestAllowScript(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "check_disk")
+	if err := AllowScript(path, Spec{MaxOutput: 1024}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AllowScript(path, Spec{MaxOutput: 1024}); err != nil {
+		t.Errorf("AllowScript() again = %v, want the script registered again", err)
+	}
+	if spec, err := lookupSpec(path); err != nil || spec.MaxOutput != 1024 {
+		t.Errorf("lookupSpec(%s) = %+v, %v, want the script spec", path, spec, err)
+	}
+
+	mutt := filepath.Join(dir, "mail", "mutt")
+	if err := AllowPath(mutt, "mutt"); err != nil {
+		t.Fatal(err)
+	}
+
+	rejected := []string{
+		"check_disk",
+		"scripts/check_disk",
+		mutt,
+		filepath.Join(dir, "systemctl"),
+		filepath.Join(dir, "journalctl"),
+	}
+	for _, name := range rejected {
+		if err := AllowScript(name, Spec{}); !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("AllowScript(%s) = %v, want ErrNotAllowed", name, err)
+		}
+	}
+	if spec, _ := lookupSpec("systemctl"); spec.Validate == nil {
+		t.Error("systemctl lost its argument validation")
+	}
+}
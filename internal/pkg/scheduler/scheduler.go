@@ -0,0 +1,431 @@
+// Package scheduler runs the periodic collections of the monitors from one
+// dispatcher. Runs are spread with jitter so collections sharing an interval do not
+// fire together, a run is skipped while the previous one of the same job is still
+// going, every run gets a deadline, and each job keeps counters about itself.
+package scheduler
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults used when the options leave a value unset
+const (
+	defaultJitter    = 0.1              // Fraction of the interval
+	defaultMaxJitter = 5 * time.Second  // Upper bound of the jitter
+	defaultTimeout   = 10 * time.Second // Smallest default deadline
+	idleWait         = time.Hour        // Dispatcher wake-up when no job is scheduled
+)
+
+// ErrTimeout is recorded as the error of a run that passed its deadline
+var ErrTimeout = errors.New("collection passed its deadline")
+
+// Job is a collection run periodically by the scheduler
+type Job struct {
+	Name     string        // Unique within the scheduler, e.g. cpu or mariadb/galera
+	Interval time.Duration // Time between runs
+	Timeout  time.Duration // Deadline of one run, 0 uses the scheduler default
+	Delayed  bool          // Wait one interval before the first run instead of running at once
+	Run      func(ctx context.Context) error
+}
+
+// Options tune how a scheduler spreads and bounds the runs
+type Options struct {
+	Jitter    float64       // Fraction of the interval by which runs are moved, 0 uses 10%, negative disables
+	MaxJitter time.Duration // Upper bound of the jitter, 0 uses 5s
+	Timeout   time.Duration // Smallest default deadline, 0 uses 10s. Jobs without their own use the longer of this and their interval.
+}
+
+// Stats are the counters a scheduler keeps about one job
+type Stats struct {
+	Name         string     `json:"name"`
+	Interval     float64    `json:"interval_seconds"`
+	Timeout      float64    `json:"timeout_seconds"`
+	Running      bool       `json:"running"`
+	Runs         int64      `json:"runs"`
+	Skips        int64      `json:"skips"` // Ticks skipped because the previous run was still going
+	Errors       int64      `json:"errors"`
+	Timeouts     int64      `json:"timeouts"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	NextRun      time.Time  `json:"next_run"`
+	LastDuration float64    `json:"last_duration_ms"`
+	AvgDuration  float64    `json:"avg_duration_ms"`
+	MaxDuration  float64    `json:"max_duration_ms"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// job is a scheduled Job with its state
+type job struct {
+	Job
+	timeout  time.Duration
+	due      time.Time // Next run without jitter, so the jitter does not accumulate
+	next     time.Time // Next run with jitter
+	running  bool
+	inflight sync.WaitGroup // Runs in progress
+
+	runs          int64
+	skips         int64
+	errors        int64
+	timeouts      int64
+	lastRun       time.Time
+	lastDuration  time.Duration
+	totalDuration time.Duration
+	maxDuration   time.Duration
+	lastError     string
+}
+
+// Scheduler dispatches the runs of its jobs
+type Scheduler struct {
+	options Options
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	wake   chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	closed bool
+}
+
+// New creates a scheduler. Its dispatcher starts with the first job.
+func New(options Options) *Scheduler {
+	if options.Jitter == 0 {
+		options.Jitter = defaultJitter
+	}
+	if options.Jitter < 0 {
+		options.Jitter = 0
+	}
+	if options.MaxJitter <= 0 {
+		options.MaxJitter = defaultMaxJitter
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	return &Scheduler{
+		options: options,
+		jobs:    make(map[string]*job),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+var (
+	defaultMu        sync.Mutex
+	defaultScheduler = New(Options{})
+)
+
+// Default returns the scheduler used by the package level functions
+func Default() *Scheduler {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultScheduler
+}
+
+// SetDefault replaces the scheduler used by the package level functions and returns
+// the previous one. Jobs already scheduled keep running on the previous scheduler.
+func SetDefault(s *Scheduler) *Scheduler {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	previous := defaultScheduler
+	defaultScheduler = s
+	return previous
+}
+
+// Schedule adds a job to the default scheduler
+func Schedule(j Job) (func(), error) {
+	return Default().Schedule(j)
+}
+
+// RunUntil schedules a job on the default scheduler and removes it when ctx ends or
+// stop is closed. It blocks until then and until a run in progress has finished, so
+// collectors written as a loop can hand their loop to the scheduler and still clean
+// up after the last run.
+func RunUntil(ctx context.Context, stop <-chan struct{}, j Job) error {
+	s := Default()
+	scheduled, err := s.add(j)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-stop:
+	}
+	s.remove(scheduled)
+	scheduled.inflight.Wait()
+	return nil
+}
+
+// Schedule adds a job and returns the function that removes it. A run in progress
+// when the job is removed is not interrupted.
+func (s *Scheduler) Schedule(j Job) (func(), error) {
+	scheduled, err := s.add(j)
+	if err != nil {
+		return nil, err
+	}
+	return func() { s.remove(scheduled) }, nil
+}
+
+// add validates a job and puts it on the schedule
+func (s *Scheduler) add(j Job) (*job, error) {
+	if j.Name == "" {
+		return nil, fmt.Errorf("cannot schedule a job without a name")
+	}
+	if j.Interval <= 0 {
+		return nil, fmt.Errorf("job %q needs a positive interval", j.Name)
+	}
+	if j.Run == nil {
+		return nil, fmt.Errorf("job %q has nothing to run", j.Name)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("scheduler is closed")
+	}
+	if _, exists := s.jobs[j.Name]; exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("job %q is already scheduled", j.Name)
+	}
+
+	scheduled := &job{Job: j, timeout: s.timeoutFor(j)}
+	now := time.Now()
+	scheduled.due = now
+	if j.Delayed {
+		scheduled.due = now.Add(j.Interval)
+	}
+	// Only delay the first run, so jobs added together start spread out
+	scheduled.next = scheduled.due.Add(time.Duration(rand.Int63n(int64(s.jitterFor(j)) + 1)))
+	s.jobs[j.Name] = scheduled
+	s.mu.Unlock()
+
+	s.start.Do(func() { go s.loop() })
+	s.notify()
+
+	logger.Debug("Scheduled collection",
+		logger.String("job", j.Name),
+		logger.Float64("interval_seconds", j.Interval.Seconds()),
+		logger.Float64("timeout_seconds", scheduled.timeout.Seconds()))
+
+	return scheduled, nil
+}
+
+// remove takes a job off the schedule unless it was replaced under the same name
+func (s *Scheduler) remove(j *job) {
+	s.mu.Lock()
+	if s.jobs[j.Name] == j {
+		delete(s.jobs, j.Name)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Close stops the dispatcher. Runs in progress finish on their own.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.jobs = make(map[string]*job)
+	close(s.done)
+}
+
+// Stats returns the counters of every scheduled job, sorted by name
+func (s *Scheduler) Stats() []Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]Stats, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		entry := Stats{
+			Name:         j.Name,
+			Interval:     j.Interval.Seconds(),
+			Timeout:      j.timeout.Seconds(),
+			Running:      j.running,
+			Runs:         j.runs,
+			Skips:        j.skips,
+			Errors:       j.errors,
+			Timeouts:     j.timeouts,
+			NextRun:      j.next,
+			LastDuration: milliseconds(j.lastDuration),
+			MaxDuration:  milliseconds(j.maxDuration),
+			LastError:    j.lastError,
+		}
+		if !j.lastRun.IsZero() {
+			lastRun := j.lastRun
+			entry.LastRun = &lastRun
+		}
+		if j.runs > 0 {
+			entry.AvgDuration = milliseconds(j.totalDuration / time.Duration(j.runs))
+		}
+		stats = append(stats, entry)
+	}
+
+	sort.Slice(stats, func(i, k int) bool {
+		return stats[i].Name < stats[k].Name
+	})
+	return stats
+}
+
+// loop starts the runs that are due and sleeps until the next one
+func (s *Scheduler) loop() {
+	timer := time.NewTimer(idleWait)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		now := time.Now()
+		wait := idleWait
+		for _, j := range s.jobs {
+			if !j.next.After(now) {
+				s.dispatch(j, now)
+			}
+			if until := j.next.Sub(now); until < wait {
+				wait = until
+			}
+		}
+		s.mu.Unlock()
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// dispatch starts a run of a due job, or skips it while the previous run is still
+// going, and sets the next run. Called with s.mu held.
+func (s *Scheduler) dispatch(j *job, now time.Time) {
+	if j.running {
+		j.skips++
+		logger.Debug("Skipping collection, the previous run is still going",
+			logger.String("job", j.Name),
+			logger.Int("skips", int(j.skips)))
+	} else {
+		j.running = true
+		j.inflight.Add(1)
+		go s.execute(j)
+	}
+
+	j.due = j.due.Add(j.Interval)
+	if !j.due.After(now) {
+		// Fell behind, e.g. after a suspend, so start over from now instead of catching up
+		j.due = now.Add(j.Interval)
+	}
+	jitter := s.jitterFor(j.Job)
+	j.next = j.due.Add(time.Duration(rand.Int63n(int64(jitter)+1)) - jitter/2)
+	if j.next.Before(now) {
+		j.next = now
+	}
+}
+
+// execute performs one run of a job and updates its counters. A run that passes its
+// deadline has its context cancelled and is counted as timed out, but still holds
+// the job until it returns so slow collections cannot pile up.
+func (s *Scheduler) execute(j *job) {
+	defer j.inflight.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+
+	// Warn when the deadline passes rather than when the run returns, which may be
+	// much later for a collector that ignores its context
+	context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.Warn("Collection passed its deadline",
+				logger.String("job", j.Name),
+				logger.Float64("timeout_seconds", j.timeout.Seconds()))
+		}
+	})
+
+	started := time.Now()
+	err := s.call(j, ctx)
+	duration := time.Since(started)
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.running = false
+	if timedOut {
+		j.timeouts++
+	}
+	j.runs++
+	j.lastRun = started
+	j.lastDuration = duration
+	j.totalDuration += duration
+	if duration > j.maxDuration {
+		j.maxDuration = duration
+	}
+
+	if timedOut && (err == nil || errors.Is(err, context.DeadlineExceeded)) {
+		err = ErrTimeout
+	}
+	if err != nil {
+		j.errors++
+		j.lastError = err.Error()
+		logger.Debug("Collection failed",
+			logger.String("job", j.Name),
+			logger.String("error", err.Error()))
+		return
+	}
+	j.lastError = ""
+}
+
+// call runs a job, turning a panic into an error so one collector cannot take the
+// service down
+func (s *Scheduler) call(j *job, ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("collection panicked: %v", r)
+			logger.Error("Collection panicked",
+				logger.String("job", j.Name),
+				logger.String("error", err.Error()))
+		}
+	}()
+	return j.Run(ctx)
+}
+
+// timeoutFor returns the deadline of one run of a job
+func (s *Scheduler) timeoutFor(j Job) time.Duration {
+	if j.Timeout > 0 {
+		return j.Timeout
+	}
+	if s.options.Timeout > j.Interval {
+		return s.options.Timeout
+	}
+	return j.Interval
+}
+
+// jitterFor returns how far the runs of a job may move
+func (s *Scheduler) jitterFor(j Job) time.Duration {
+	jitter := time.Duration(float64(j.Interval) * s.options.Jitter)
+	if jitter > s.options.MaxJitter {
+		jitter = s.options.MaxJitter
+	}
+	return jitter
+}
+
+// notify wakes the dispatcher so it picks up added and removed jobs
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// milliseconds converts a duration for the stats
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
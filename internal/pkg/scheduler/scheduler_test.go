@@ -0,0 +1,249 @@
+package scheduler
+
+import (
+	"CheckHealthDO/internal/pkg/logger"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// waitFor polls cond until it holds, failing the test after two seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// statsOf returns the counters of the named job
+func statsOf(s *Scheduler, name string) Stats {
+	for _, entry := range s.Stats() {
+		if entry.Name == name {
+			return entry
+		}
+	}
+	return Stats{}
+}
+
+func TestJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		interval time.Duration
+		want     time.Duration
+	}{
+		{"default fraction", Options{}, 10 * time.Second, time.Second},
+		{"capped", Options{}, time.Minute, defaultMaxJitter},
+		{"own cap", Options{Jitter: 0.5, MaxJitter: 20 * time.Second}, time.Minute, 20 * time.Second},
+		{"disabled", Options{Jitter: -1}, time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.options)
+			if got := s.jitterFor(Job{Interval: tt.interval}); got != tt.want {
+				t.Errorf("jitterFor(%s) = %s, want %s", tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstRunWithinJitter(t *testing.T) {
+	s := New(Options{})
+	defer s.Close()
+
+	before := time.Now()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if _, err := s.Schedule(Job{Name: name, Interval: 10 * time.Second, Delayed: true, Run: func(context.Context) error { return nil }}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	after := time.Now()
+
+	for _, entry := range s.Stats() {
+		earliest := before.Add(10 * time.Second)
+		latest := after.Add(11 * time.Second)
+		if entry.NextRun.Before(earliest) || entry.NextRun.After(latest) {
+			t.Errorf("job %s next run %s, want within a second after the interval", entry.Name, entry.NextRun.Sub(before))
+		}
+	}
+}
+
+func TestTimeoutFor(t *testing.T) {
+	s := New(Options{Timeout: 30 * time.Second})
+
+	tests := []struct {
+		job  Job
+		want time.Duration
+	}{
+		{Job{Interval: 10 * time.Second}, 30 * time.Second},
+		{Job{Interval: time.Minute}, time.Minute},
+		{Job{Interval: time.Minute, Timeout: 5 * time.Second}, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := s.timeoutFor(tt.job); got != tt.want {
+			t.Errorf("timeoutFor(%+v) = %s, want %s", tt.job, got, tt.want)
+		}
+	}
+}
+
+func TestSkipsWhileRunning(t *testing.T) {
+	s := New(Options{Jitter: -1})
+	defer s.Close()
+
+	release := make(chan struct{})
+	if _, err := s.Schedule(Job{
+		Name:     "slow",
+		Interval: 5 * time.Millisecond,
+		Timeout:  time.Minute,
+		Run: func(context.Context) error {
+			<-release
+			return nil
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "skipped ticks", func() bool { return statsOf(s, "slow").Skips >= 2 })
+	if entry := statsOf(s, "slow"); !entry.Running || entry.Runs != 0 {
+		t.Errorf("stats = %+v, want the first run still going", entry)
+	}
+
+	close(release)
+	waitFor(t, "the slow run to finish", func() bool { return statsOf(s, "slow").Runs >= 1 })
+}
+
+func TestTimeout(t *testing.T) {
+	// A collector that honours its context returns ctx.Err(), one that ignores the
+	// error may return nil; both are recorded as ErrTimeout
+	results := map[string]func(ctx context.Context) error{
+		"returns ctx.Err()": func(ctx context.Context) error { return ctx.Err() },
+		"returns nil":       func(context.Context) error { return nil },
+	}
+
+	for name, result := range results {
+		t.Run(name, func(t *testing.T) {
+			s := New(Options{Jitter: -1})
+			defer s.Close()
+
+			cancelled := make(chan error, 1)
+			if _, err := s.Schedule(Job{
+				Name:     "stuck",
+				Interval: time.Hour,
+				Timeout:  20 * time.Millisecond,
+				Run: func(ctx context.Context) error {
+					<-ctx.Done()
+					cancelled <- ctx.Err()
+					return result(ctx)
+				},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case err := <-cancelled:
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("ctx.Err() = %v, want the deadline exceeded", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("the run context was not cancelled at its deadline")
+			}
+
+			waitFor(t, "the run to be counted", func() bool { return statsOf(s, "stuck").Runs == 1 })
+			entry := statsOf(s, "stuck")
+			if entry.Timeouts != 1 || entry.Errors != 1 || entry.LastError != ErrTimeout.Error() {
+				t.Errorf("stats = %+v, want one timeout recorded as ErrTimeout", entry)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	s := New(Options{Jitter: -1})
+	defer s.Close()
+
+	failure := errors.New("collector failed")
+	jobs := []Job{
+		{Name: "b", Interval: time.Hour, Run: func(context.Context) error { return failure }},
+		{Name: "a", Interval: time.Hour, Run: func(context.Context) error { return nil }},
+		{Name: "c", Interval: time.Hour, Run: func(context.Context) error { panic("boom") }},
+	}
+	for _, j := range jobs {
+		if _, err := s.Schedule(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitFor(t, "every job to run", func() bool {
+		for _, entry := range s.Stats() {
+			if entry.Runs == 0 {
+				return false
+			}
+		}
+		return true
+	})
+
+	stats := s.Stats()
+	if len(stats) != 3 || stats[0].Name != "a" || stats[1].Name != "b" || stats[2].Name != "c" {
+		t.Fatalf("stats = %+v, want a, b and c sorted by name", stats)
+	}
+	if a := stats[0]; a.Errors != 0 || a.LastError != "" || a.LastRun == nil || a.Interval != 3600 || a.Timeout != 3600 {
+		t.Errorf("a = %+v, want one successful run", a)
+	}
+	if b := stats[1]; b.Errors != 1 || b.LastError != failure.Error() {
+		t.Errorf("b = %+v, want the error recorded", b)
+	}
+	if c := stats[2]; c.Errors != 1 || c.LastError != "collection panicked: boom" {
+		t.Errorf("c = %+v, want the panic recorded as an error", c)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	s := New(Options{})
+	run := func(context.Context) error { return nil }
+
+	invalid := []Job{
+		{Interval: time.Minute, Run: run},
+		{Name: "no interval", Run: run},
+		{Name: "no run", Interval: time.Minute},
+	}
+	for _, j := range invalid {
+		if _, err := s.Schedule(j); err == nil {
+			t.Errorf("Schedule(%+v) succeeded, want an error", j)
+		}
+	}
+
+	cancel, err := s.Schedule(Job{Name: "cpu", Interval: time.Hour, Delayed: true, Run: run})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Schedule(Job{Name: "cpu", Interval: time.Hour, Run: run}); err == nil {
+		t.Error("want an error for a duplicate name")
+	}
+
+	cancel()
+	if len(s.Stats()) != 0 {
+		t.Errorf("stats = %+v, want the job removed", s.Stats())
+	}
+	if _, err := s.Schedule(Job{Name: "cpu", Interval: time.Hour, Delayed: true, Run: run}); err != nil {
+		t.Errorf("rescheduling a removed job: %v", err)
+	}
+
+	s.Close()
+	if _, err := s.Schedule(Job{Name: "memory", Interval: time.Hour, Run: run}); err == nil {
+		t.Error("want an error once the scheduler is closed")
+	}
+}
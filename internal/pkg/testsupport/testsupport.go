@@ -0,0 +1,103 @@
+// Package testsupport holds helpers shared by the monitor tests: a TestMain that
+// keeps log files out of the source tree, and fakes to drive a monitor through a
+// sequence of samples. Only test files import it.
+package testsupport
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/logger"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Start is where fake clocks begin: a Monday morning, far from a day boundary
+var Start = time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+
+// packageDir is the directory of the package under test, where Main found the tests
+var packageDir string
+
+// Main runs the tests of a package with a silent logger from a temporary working
+// directory, so monitors writing to ./logs leave the source tree alone
+func Main(m *testing.M) {
+	logger.Log = zap.NewNop()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	packageDir = wd
+
+	dir, err := os.MkdirTemp("", "checkhealth-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// Testdata returns the path of a file in the testdata directory of the package
+// under test, which Main moved away from
+func Testdata(name string) string {
+	return filepath.Join(packageDir, "testdata", name)
+}
+
+// Monitor is a monitor whose time source and notifications a test controls
+type Monitor interface {
+	SetClock(c clock.Clock)
+	SetNotificationManager(manager alerts.NotificationManager)
+}
+
+// Env is the fake clock and notification recorder a monitor under test is wired to
+type Env struct {
+	Clock    *clock.Fake
+	Recorder *alerts.Recorder
+}
+
+// Wire connects a monitor to a fake clock stopped at Start and to a new recorder
+func Wire(monitor Monitor) *Env {
+	env := &Env{
+		Clock:    clock.NewFake(Start),
+		Recorder: alerts.NewRecorder(),
+	}
+	monitor.SetClock(env.Clock)
+	monitor.SetNotificationManager(env.Recorder)
+	return env
+}
+
+// Run calls check with each usage in turn, advancing the clock by step before every check but the first
+func (e *Env) Run(step time.Duration, check func(usage float64), usages ...float64) {
+	for i, usage := range usages {
+		if i > 0 {
+			e.Clock.Advance(step)
+		}
+		check(usage)
+	}
+}
+
+// Repeat returns n copies of usage
+func Repeat(usage float64, n int) []float64 {
+	usages := make([]float64, n)
+	for i := range usages {
+		usages[i] = usage
+	}
+	return usages
+}
+
+// Concat joins usage sequences
+func Concat(parts ...[]float64) []float64 {
+	var usages []float64
+	for _, part := range parts {
+		usages = append(usages, part...)
+	}
+	return usages
+}
@@ -0,0 +1,131 @@
+package mariadb
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Log event severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Log event categories
+const (
+	CategoryInnoDBCorruption  = "innodb_corruption"
+	CategoryCrashRecovery     = "crash_recovery"
+	CategoryOutOfMemory       = "out_of_memory"
+	CategoryDiskFull          = "disk_full"
+	CategoryError             = "error"
+	CategoryAbortedConnection = "aborted_connection"
+	CategoryWarning           = "warning"
+)
+
+// LogEvent is a classified line from the MariaDB error log
+type LogEvent struct {
+	ID       uint64    `json:"id"`
+	Time     time.Time `json:"time"`
+	Category string    `json:"category"`
+	Severity string    `json:"severity"`
+	Line     string    `json:"line"`
+}
+
+// logRule maps a set of lowercase substrings or patterns to a category
+type logRule struct {
+	category string
+	severity string
+	patterns []string
+	regexps  []*regexp.Regexp // Matched against the lowercase line
+}
+
+// logRules are evaluated in order; the first match wins
+var logRules = []logRule{
+	{CategoryInnoDBCorruption, SeverityCritical, []string{
+		"database page corruption", "page corruption", "checksum mismatch",
+		"innodb_force_recovery", "is in the future",
+	}, []*regexp.Regexp{
+		// Only corruption InnoDB reports, not table or user names that contain the word
+		regexp.MustCompile(`innodb: .*\bcorrupt(ed|ion)?\b`),
+	}},
+	{CategoryCrashRecovery, SeverityCritical, []string{
+		"starting crash recovery", "crash recovery", "mysqld got signal", "mariadbd got signal",
+		"this could be because you hit a bug", "was not shut down normally",
+	}, nil},
+	{CategoryOutOfMemory, SeverityCritical, []string{
+		"out of memory", "cannot allocate memory", "failed to allocate", "errno 12", "errno: 12",
+	}, nil},
+	{CategoryDiskFull, SeverityCritical, []string{
+		"disk is full", "disk full", "no space left on device", "errno 28", "errno: 28",
+	}, nil},
+	{CategoryAbortedConnection, SeverityInfo, []string{
+		"aborted connection", "aborted_connects",
+	}, nil},
+}
+
+// logTimestampPattern matches the timestamp prefixes written by MariaDB and older MySQL releases
+var logTimestampPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}(?:T|\s+)\d{1,2}:\d{2}:\d{2}|\d{6}\s+\d{1,2}:\d{2}:\d{2})`)
+
+// ClassifyLogLine turns an error log line into an event. Lines that are not
+// errors, warnings or one of the known categories are reported as not matched.
+func ClassifyLogLine(line string) (LogEvent, bool) {
+	lower := strings.ToLower(line)
+	event := LogEvent{
+		Time: parseLogTime(line),
+		Line: line,
+	}
+
+	for _, rule := range logRules {
+		if rule.matches(lower) {
+			event.Category = rule.category
+			event.Severity = rule.severity
+			return event, true
+		}
+	}
+
+	switch {
+	case strings.Contains(line, "[ERROR]"):
+		// The server only logs [ERROR] for failures that need attention
+		event.Category = CategoryError
+		event.Severity = SeverityCritical
+		return event, true
+	case strings.Contains(line, "[Warning]"):
+		event.Category = CategoryWarning
+		event.Severity = SeverityInfo
+		return event, true
+	}
+
+	return event, false
+}
+
+// matches reports whether the lowercase line matches any of the rule's patterns
+func (r logRule) matches(lower string) bool {
+	for _, pattern := range r.patterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	for _, re := range r.regexps {
+		if re.MatchString(lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLogTime extracts the timestamp at the start of a log line, falling back to now
+func parseLogTime(line string) time.Time {
+	match := logTimestampPattern.FindString(line)
+	if match != "" {
+		// MariaDB pads single digit hours with a space: 2024-03-01  9:05:02
+		match = strings.Join(strings.Fields(match), " ")
+		for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "060102 15:04:05"} {
+			if t, err := time.ParseInLocation(layout, match, time.Local); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Now()
+}
@@ -0,0 +1,91 @@
+package mariadb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyLogLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		matched  bool
+		category string
+		severity string
+	}{
+		{
+			name:     "page corruption",
+			line:     "2024-03-01 10:15:02 0 [ERROR] InnoDB: Database page corruption on disk or a failed file read of tablespace shop/orders page [page id: space=12, page number=345]",
+			matched:  true,
+			category: CategoryInnoDBCorruption,
+			severity: SeverityCritical,
+		},
+		{
+			name:     "corrupted index",
+			line:     "2024-03-01 10:15:02 7 [ERROR] InnoDB: Index `PRIMARY` of table `shop`.`orders` is corrupted",
+			matched:  true,
+			category: CategoryInnoDBCorruption,
+			severity: SeverityCritical,
+		},
+		{
+			name:     "table name containing corrupt",
+			line:     "2024-03-01 10:15:02 12 [Warning] Aborted connection 12 to db: 'corrupt_reports' user: 'app' host: 'localhost' (Got timeout reading communication packets)",
+			matched:  true,
+			category: CategoryAbortedConnection,
+			severity: SeverityInfo,
+		},
+		{
+			name:    "note mentioning corrupt",
+			line:    "2024-03-01 10:15:02 0 [Note] Plugin 'corrupt_test' is disabled.",
+			matched: false,
+		},
+		{
+			name:     "server error",
+			line:     "2024-03-01 10:15:02 0 [ERROR] Can't start server: Bind on TCP/IP port. Got error: 98: Address already in use",
+			matched:  true,
+			category: CategoryError,
+			severity: SeverityCritical,
+		},
+		{
+			name:     "disk full",
+			line:     "2024-03-01 10:15:02 5 [ERROR] mariadbd: Disk full (/tmp/#sql_1.MAI); waiting for someone to free some space... (errno: 28 \"No space left on device\")",
+			matched:  true,
+			category: CategoryDiskFull,
+			severity: SeverityCritical,
+		},
+		{
+			name:     "warning",
+			line:     "2024-03-01 10:15:02 0 [Warning] 'proxies_priv' entry '@% root@db1' ignored in --skip-name-resolve mode.",
+			matched:  true,
+			category: CategoryWarning,
+			severity: SeverityInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, matched := ClassifyLogLine(tt.line)
+			if matched != tt.matched || event.Category != tt.category || event.Severity != tt.severity {
+				t.Errorf("got %v %s/%s, want %v %s/%s", matched, event.Category, event.Severity, tt.matched, tt.category, tt.severity)
+			}
+		})
+	}
+}
+
+func TestParseLogTime(t *testing.T) {
+	tests := []struct {
+		line string
+		want time.Time
+	}{
+		{"2024-03-01 10:15:02 0 [Note] Starting", time.Date(2024, 3, 1, 10, 15, 2, 0, time.Local)},
+		{"2024-03-01  9:05:02 0 [Note] Starting", time.Date(2024, 3, 1, 9, 5, 2, 0, time.Local)},
+		{"240301 10:15:02 [Note] Starting", time.Date(2024, 3, 1, 10, 15, 2, 0, time.Local)},
+		{"240301  9:05:02 [Note] Starting", time.Date(2024, 3, 1, 9, 5, 2, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		if got := parseLogTime(tt.line); !got.Equal(tt.want) {
+			t.Errorf("parseLogTime(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
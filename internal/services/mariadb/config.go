@@ -24,6 +24,14 @@ func GetDBConfigFromConfig(cfg *config.Config) *DBConfig {
 		Username: cfg.Database.Username,
 		Password: cfg.Database.Password,
 		Database: cfg.Database.Database,
+		Socket:   cfg.Database.Socket,
+
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		ConnectTimeout:  cfg.Database.ConnectTimeout,
+		QueryTimeout:    cfg.Database.QueryTimeout,
 	}
 
 	// Ensure we have default values for critical connection parameters
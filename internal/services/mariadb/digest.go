@@ -0,0 +1,119 @@
+package mariadb
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// QueryDigestStats aggregates the slow query log entries sharing a fingerprint
+type QueryDigestStats struct {
+	Digest            string    `json:"digest"`
+	Fingerprint       string    `json:"fingerprint"`
+	Example           string    `json:"example"` // Slowest sample of the digest
+	Schemas           []string  `json:"schemas,omitempty"`
+	Count             int       `json:"count"`
+	TotalTime         float64   `json:"total_time"` // In seconds
+	AvgTime           float64   `json:"avg_time"`
+	P95Time           float64   `json:"p95_time"`
+	MaxTime           float64   `json:"max_time"`
+	TotalLockTime     float64   `json:"total_lock_time"`
+	RowsExamined      int64     `json:"rows_examined"`
+	RowsSent          int64     `json:"rows_sent"`
+	AvgRowsExamined   float64   `json:"avg_rows_examined"`
+	ResponseTimeShare float64   `json:"response_time_share"` // Percentage of total time across all digests
+	FirstSeen         time.Time `json:"first_seen"`
+	LastSeen          time.Time `json:"last_seen"`
+}
+
+// DigestSlowQueries groups entries by fingerprint and returns the digests
+// ordered by total query time, slowest first
+func DigestSlowQueries(entries []SlowQuery) []QueryDigestStats {
+	type accumulator struct {
+		stats   QueryDigestStats
+		times   []float64
+		schemas map[string]bool
+	}
+
+	groups := make(map[string]*accumulator)
+	var grandTotal float64
+
+	for _, entry := range entries {
+		fingerprint := FingerprintQuery(entry.Query)
+		digest := QueryDigest(fingerprint)
+
+		acc, ok := groups[digest]
+		if !ok {
+			acc = &accumulator{
+				stats: QueryDigestStats{
+					Digest:      digest,
+					Fingerprint: fingerprint,
+					FirstSeen:   entry.Time,
+					LastSeen:    entry.Time,
+				},
+				schemas: make(map[string]bool),
+			}
+			groups[digest] = acc
+		}
+
+		s := &acc.stats
+		s.Count++
+		s.TotalTime += entry.QueryTime
+		s.TotalLockTime += entry.LockTime
+		s.RowsExamined += entry.RowsExamined
+		s.RowsSent += entry.RowsSent
+		if entry.QueryTime >= s.MaxTime {
+			s.MaxTime = entry.QueryTime
+			s.Example = entry.Query
+		}
+		if entry.Time.Before(s.FirstSeen) {
+			s.FirstSeen = entry.Time
+		}
+		if entry.Time.After(s.LastSeen) {
+			s.LastSeen = entry.Time
+		}
+		if entry.Schema != "" && !acc.schemas[entry.Schema] {
+			acc.schemas[entry.Schema] = true
+			s.Schemas = append(s.Schemas, entry.Schema)
+		}
+		acc.times = append(acc.times, entry.QueryTime)
+		grandTotal += entry.QueryTime
+	}
+
+	digests := make([]QueryDigestStats, 0, len(groups))
+	for _, acc := range groups {
+		s := acc.stats
+		s.AvgTime = s.TotalTime / float64(s.Count)
+		s.AvgRowsExamined = float64(s.RowsExamined) / float64(s.Count)
+		s.P95Time = percentile(acc.times, 95)
+		if grandTotal > 0 {
+			s.ResponseTimeShare = s.TotalTime / grandTotal * 100
+		}
+		sort.Strings(s.Schemas)
+		digests = append(digests, s)
+	}
+
+	sort.Slice(digests, func(i, j int) bool {
+		if digests[i].TotalTime != digests[j].TotalTime {
+			return digests[i].TotalTime > digests[j].TotalTime
+		}
+		return digests[i].Digest < digests[j].Digest
+	})
+
+	return digests
+}
+
+// percentile returns the nearest-rank percentile of the values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
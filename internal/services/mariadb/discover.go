@@ -0,0 +1,257 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/runner"
+)
+
+// Names of discovered settings
+const (
+	SettingServiceName  = "service_name"
+	SettingSocket       = "socket"
+	SettingPort         = "port"
+	SettingDataDir      = "datadir"
+	SettingErrorLog     = "log_error"
+	SettingSlowQueryLog = "slow_query_log_file"
+	SettingUser         = "user"
+	SettingPassword     = "password"
+)
+
+// Sources of discovered settings other than option file paths
+const (
+	SourceServer  = "server"
+	SourceSystemd = "systemd"
+)
+
+// serviceNameCandidates are the unit names used by distribution packages
+var serviceNameCandidates = []string{"mariadb", "mysql", "mysqld"}
+
+// DiscoveredSetting is a value found during discovery and where it came from
+type DiscoveredSetting struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Discovery holds the MariaDB settings found in option files, systemd and the running server
+type Discovery struct {
+	OptionFiles []string                     `json:"option_files"`
+	Settings    map[string]DiscoveredSetting `json:"settings"`
+	Connected   bool                         `json:"connected"`
+	Error       string                       `json:"error,omitempty"`
+}
+
+// NewDiscovery creates an empty discovery result
+func NewDiscovery() *Discovery {
+	return &Discovery{
+		OptionFiles: []string{},
+		Settings:    make(map[string]DiscoveredSetting),
+	}
+}
+
+// Get returns the value of a discovered setting, empty when it was not found
+func (d *Discovery) Get(name string) string {
+	return d.Settings[name].Value
+}
+
+// set records a setting, later sources override earlier ones
+func (d *Discovery) set(name, value, source string) {
+	if value == "" {
+		return
+	}
+	d.Settings[name] = DiscoveredSetting{Value: value, Source: source}
+}
+
+// OptionFilePaths returns the option files to read for a configuration: the configured
+// list, or the global files followed by the user's ~/.my.cnf
+func OptionFilePaths(cfg *config.Config) []string {
+	if len(cfg.Monitoring.MariaDB.OptionFiles) > 0 {
+		return cfg.Monitoring.MariaDB.OptionFiles
+	}
+
+	paths := append([]string{}, DefaultOptionFiles...)
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".my.cnf"))
+	}
+	return paths
+}
+
+// Discover collects MariaDB settings from option files, systemd and the running server.
+// Credentials and the socket found in option files are used to connect when the
+// configuration leaves them empty.
+func Discover(ctx context.Context, cfg *config.Config, optionFiles []string) *Discovery {
+	d := NewDiscovery()
+
+	if err := DiscoverOptionFiles(d, optionFiles); err != nil {
+		logger.Warn("Failed to read MariaDB option files",
+			logger.String("error", err.Error()))
+	}
+
+	if serviceName := discoverServiceName(ctx); serviceName != "" {
+		d.set(SettingServiceName, serviceName, SourceSystemd)
+	}
+
+	// Connect with what the configuration would end up using after discovery
+	connectCfg := *cfg
+	applyConnectionSettings(&connectCfg.Database, d)
+	DiscoverServer(ctx, d, GetDBConfigFromConfig(&connectCfg))
+
+	return d
+}
+
+// DiscoverOptionFiles reads server paths and client credentials from my.cnf and its includes
+func DiscoverOptionFiles(d *Discovery, paths []string) error {
+	options, err := LoadOptionFiles(paths)
+	if options != nil {
+		d.OptionFiles = append(d.OptionFiles, options.Files...)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{SettingUser, SettingPassword, SettingSocket, SettingPort} {
+		if entry, ok := options.Get(name, clientOptionGroups...); ok {
+			d.set(name, entry.Value, entry.File)
+		}
+	}
+
+	// Server groups describe the instance itself and win over client defaults
+	for _, name := range []string{SettingSocket, SettingPort, SettingDataDir, SettingErrorLog, SettingSlowQueryLog} {
+		if entry, ok := options.Get(name, serverOptionGroups...); ok {
+			d.set(name, entry.Value, entry.File)
+		}
+	}
+
+	// Relative log paths are relative to the data directory
+	for _, name := range []string{SettingErrorLog, SettingSlowQueryLog} {
+		if setting, ok := d.Settings[name]; ok && !filepath.IsAbs(setting.Value) && d.Get(SettingDataDir) != "" {
+			setting.Value = filepath.Join(d.Get(SettingDataDir), setting.Value)
+			d.Settings[name] = setting
+		}
+	}
+
+	return nil
+}
+
+// DiscoverServer queries the running server for its paths. Values reported by the
+// server override those read from option files.
+func DiscoverServer(ctx context.Context, d *Discovery, dbConfig *DBConfig) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		d.Error = err.Error()
+		return
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	var errorLog, dataDir, slowLog, socket sql.NullString
+	var port sql.NullInt64
+	err = db.QueryRowContext(ctx, "SELECT @@log_error, @@datadir, @@slow_query_log_file, @@socket, @@port").
+		Scan(&errorLog, &dataDir, &slowLog, &socket, &port)
+	if err != nil {
+		d.Error = fmt.Sprintf("failed to query MariaDB server paths: %v", err)
+		return
+	}
+
+	d.Connected = true
+	d.set(SettingDataDir, dataDir.String, SourceServer)
+	d.set(SettingSocket, socket.String, SourceServer)
+	if port.Valid && port.Int64 > 0 {
+		d.set(SettingPort, strconv.FormatInt(port.Int64, 10), SourceServer)
+	}
+
+	// An empty log_error or "stderr" means the error log goes to the journal
+	if errorLog.String != "" && errorLog.String != "stderr" {
+		d.set(SettingErrorLog, serverPath(errorLog.String, dataDir.String), SourceServer)
+	}
+	d.set(SettingSlowQueryLog, serverPath(slowLog.String, dataDir.String), SourceServer)
+}
+
+// ApplyDiscovery fills MariaDB settings the configuration leaves empty and returns
+// the names of the settings it filled
+func ApplyDiscovery(cfg *config.Config, d *Discovery) []string {
+	var applied []string
+	mariaDB := &cfg.Monitoring.MariaDB
+
+	if mariaDB.ServiceName == "" && d.Get(SettingServiceName) != "" {
+		mariaDB.ServiceName = d.Get(SettingServiceName)
+		applied = append(applied, SettingServiceName)
+	}
+
+	// A configured log path that does not exist is replaced by the one the server uses
+	if errorLog := d.Get(SettingErrorLog); errorLog != "" && errorLog != mariaDB.LogPath {
+		if _, err := os.Stat(mariaDB.LogPath); mariaDB.LogPath == "" || err != nil {
+			mariaDB.LogPath = errorLog
+			applied = append(applied, SettingErrorLog)
+		}
+	}
+
+	if mariaDB.SlowLog.Path == "" && d.Get(SettingSlowQueryLog) != "" {
+		mariaDB.SlowLog.Path = d.Get(SettingSlowQueryLog)
+		applied = append(applied, SettingSlowQueryLog)
+	}
+
+	return append(applied, applyConnectionSettings(&cfg.Database, d)...)
+}
+
+// applyConnectionSettings fills empty credentials, and the socket when neither host
+// nor port are configured
+func applyConnectionSettings(database *config.DatabaseConfig, d *Discovery) []string {
+	var applied []string
+
+	if database.Username == "" && d.Get(SettingUser) != "" {
+		database.Username = d.Get(SettingUser)
+		applied = append(applied, SettingUser)
+	}
+	if database.Password == "" && d.Get(SettingPassword) != "" {
+		database.Password = d.Get(SettingPassword)
+		applied = append(applied, SettingPassword)
+	}
+	if database.Socket == "" && database.Host == "" && database.Port == 0 && d.Get(SettingSocket) != "" {
+		database.Socket = d.Get(SettingSocket)
+		applied = append(applied, SettingSocket)
+	}
+
+	return applied
+}
+
+// discoverServiceName returns the first MariaDB or MySQL unit known to systemd,
+// preferring one that is active
+func discoverServiceName(ctx context.Context) string {
+	if !runner.Available("systemctl") {
+		return ""
+	}
+
+	loaded := ""
+	for _, name := range serviceNameCandidates {
+		output, _ := runner.Output(ctx, "systemctl", "show", "-p", "LoadState,ActiveState", name)
+		state := string(output)
+		if !strings.Contains(state, "LoadState=loaded") {
+			continue
+		}
+		if strings.Contains(state, "ActiveState=active") {
+			return name
+		}
+		if loaded == "" {
+			loaded = name
+		}
+	}
+	return loaded
+}
+
+// serverPath resolves a path reported by the server against its data directory
+func serverPath(path, dataDir string) string {
+	if path == "" || filepath.IsAbs(path) || dataDir == "" {
+		return path
+	}
+	return filepath.Join(dataDir, path)
+}
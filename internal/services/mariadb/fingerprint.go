@@ -0,0 +1,52 @@
+package mariadb
+
+import (
+	"crypto/md5"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Strings and comments are matched together so comment markers inside a string
+	// and quotes inside a comment are left alone. A "--" comment needs a space after
+	// it; without one it is two minus signs.
+	fpStringOrComment = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"|(?s:/\*.*?\*/)|(?m:--(?:[ \t][^\n]*)?$)|#[^\n]*`)
+	fpHex             = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`)
+	fpNumber          = regexp.MustCompile(`(^|[^\w.])[-+]?\d+(?:\.\d+)?(?:e[-+]?\d+)?\b`)
+	fpInList          = regexp.MustCompile(`\bin\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	fpValuesList      = regexp.MustCompile(`\bvalues?\s*\(\s*\?(?:\s*,\s*\?)*\s*\)(?:\s*,\s*\(\s*\?(?:\s*,\s*\?)*\s*\))*`)
+	fpWhitespace      = regexp.MustCompile(`\s+`)
+	fpNull            = regexp.MustCompile(`\bis\s+not\s+null\b|\bis\s+null\b`)
+	fpOperator        = regexp.MustCompile(`\s*(<=>|<>|!=|<=|>=|=|<|>|,)\s*`)
+)
+
+// FingerprintQuery normalises a query so that statements differing only in
+// literal values share the same fingerprint, in the spirit of pt-query-digest.
+func FingerprintQuery(query string) string {
+	fp := fpStringOrComment.ReplaceAllStringFunc(query, func(s string) string {
+		if s[0] == '\'' || s[0] == '"' {
+			return "?"
+		}
+		return " "
+	})
+	fp = strings.ToLower(fp)
+	fp = fpHex.ReplaceAllString(fp, "?")
+	fp = fpNumber.ReplaceAllString(fp, "${1}?")
+	fp = fpNull.ReplaceAllStringFunc(fp, func(s string) string {
+		return fpWhitespace.ReplaceAllString(s, " ")
+	})
+	fp = fpInList.ReplaceAllString(fp, "in(?+)")
+	fp = fpValuesList.ReplaceAllString(fp, "values(?+)")
+	fp = fpOperator.ReplaceAllString(fp, "$1")
+	fp = fpWhitespace.ReplaceAllString(fp, " ")
+	fp = strings.TrimSpace(fp)
+	fp = strings.TrimSuffix(fp, ";")
+	return strings.TrimSpace(fp)
+}
+
+// QueryDigest returns a short stable identifier for a fingerprint
+func QueryDigest(fingerprint string) string {
+	sum := md5.Sum([]byte(fingerprint))
+	return strings.ToUpper(fmt.Sprintf("%x", sum[8:]))
+}
@@ -0,0 +1,153 @@
+package mariadb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxPendingLine caps a partial line kept between polls so a log without newlines cannot grow memory unbounded
+const maxPendingLine = 64 * 1024
+
+// LogFollower follows a log file across polls, surviving rotation and truncation
+type LogFollower struct {
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	pending []byte
+	fromEnd bool
+}
+
+// NewLogFollower creates a follower for the given path. When fromEnd is true the
+// existing content is skipped and only lines written afterwards are returned.
+func NewLogFollower(path string, fromEnd bool) *LogFollower {
+	return &LogFollower{
+		path:    path,
+		fromEnd: fromEnd,
+	}
+}
+
+// Path returns the path being followed
+func (f *LogFollower) Path() string {
+	return f.path
+}
+
+// Poll returns the complete lines appended since the previous call
+func (f *LogFollower) Poll() ([]string, error) {
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	var lines []string
+
+	current, err := os.Stat(f.path)
+	switch {
+	case err != nil || !os.SameFile(current, f.info):
+		// The file was rotated away: drain what is left of the old file, then
+		// switch to the new one from its beginning
+		lines = append(lines, f.read()...)
+		lines = append(lines, f.flushPending()...)
+		f.Close()
+		if err != nil {
+			return lines, fmt.Errorf("log file is not available: %w", err)
+		}
+		f.fromEnd = false
+		if err := f.open(); err != nil {
+			return lines, err
+		}
+	case current.Size() < f.offset:
+		// The file was truncated in place (copytruncate)
+		f.pending = nil
+		f.offset = 0
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return lines, fmt.Errorf("failed to rewind truncated log file: %w", err)
+		}
+	}
+
+	lines = append(lines, f.read()...)
+	return lines, nil
+}
+
+// Close releases the underlying file
+func (f *LogFollower) Close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
+
+// open opens the log file and positions the read offset
+func (f *LogFollower) open() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.offset = 0
+	if f.fromEnd {
+		if f.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to seek log file: %w", err)
+		}
+	}
+
+	f.file = file
+	f.info = info
+	f.fromEnd = false
+	return nil
+}
+
+// read consumes everything available and returns the complete lines
+func (f *LogFollower) read() []string {
+	if f.file == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(f.file)
+	f.offset += int64(len(data))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	data = append(f.pending, data...)
+	var lines []string
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(data[:i], "\r")
+		if len(line) > 0 {
+			lines = append(lines, string(line))
+		}
+		data = data[i+1:]
+	}
+
+	if len(data) > maxPendingLine {
+		lines = append(lines, string(data))
+		data = nil
+	}
+	f.pending = append([]byte(nil), data...)
+
+	return lines
+}
+
+// flushPending returns a trailing partial line, if any
+func (f *LogFollower) flushPending() []string {
+	if len(f.pending) == 0 {
+		return nil
+	}
+	line := string(f.pending)
+	f.pending = nil
+	return []string{line}
+}
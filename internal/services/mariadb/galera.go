@@ -0,0 +1,128 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GaleraStatus holds the wsrep status variables relevant to cluster health
+type GaleraStatus struct {
+	Enabled              bool    `json:"enabled"` // False when the server is not a Galera node
+	ClusterSize          int     `json:"cluster_size"`
+	ClusterStatus        string  `json:"cluster_status"` // Primary, non-Primary or Disconnected
+	ClusterStateUUID     string  `json:"cluster_state_uuid,omitempty"`
+	LocalState           string  `json:"local_state"` // Synced, Donor/Desynced, Joining, Joined
+	Ready                bool    `json:"ready"`
+	Connected            bool    `json:"connected"`
+	FlowControlPaused    float64 `json:"flow_control_paused"`    // Fraction of time paused since the previous sample
+	FlowControlPausedNs  uint64  `json:"flow_control_paused_ns"` // Cumulative pause time
+	FlowControlSent      uint64  `json:"flow_control_sent"`
+	FlowControlRecv      uint64  `json:"flow_control_recv"`
+	RecvQueue            int64   `json:"recv_queue"`
+	RecvQueueAvg         float64 `json:"recv_queue_avg"`
+	SendQueue            int64   `json:"send_queue"`
+	SendQueueAvg         float64 `json:"send_queue_avg"`
+	CertFailures         uint64  `json:"cert_failures"`
+	BFAborts             uint64  `json:"bf_aborts"`
+	IncomingAddresses    string  `json:"incoming_addresses,omitempty"`
+	ProviderVersion      string  `json:"provider_version,omitempty"`
+	cumulativePausedFrac float64 // wsrep_flow_control_paused, used when the _ns counter is missing
+}
+
+// GetGaleraStatus reads the wsrep_% global status variables
+func GetGaleraStatus(ctx context.Context, dbConfig *DBConfig) (*GaleraStatus, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "SHOW GLOBAL STATUS LIKE 'wsrep_%'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wsrep status: %w", err)
+	}
+	defer rows.Close()
+
+	vars := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan wsrep status: %w", err)
+		}
+		vars[strings.ToLower(name)] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wsrep status: %w", err)
+	}
+
+	return ParseGaleraStatus(vars), nil
+}
+
+// ParseGaleraStatus builds a GaleraStatus from lower-cased wsrep status variables
+func ParseGaleraStatus(vars map[string]string) *GaleraStatus {
+	status := &GaleraStatus{}
+
+	// Servers without a loaded wsrep provider still report a few wsrep variables
+	if vars["wsrep_provider_name"] == "" && parseStatusInt(vars["wsrep_cluster_size"]) == 0 {
+		return status
+	}
+	status.Enabled = true
+
+	status.ClusterSize = int(parseStatusInt(vars["wsrep_cluster_size"]))
+	status.ClusterStatus = vars["wsrep_cluster_status"]
+	status.ClusterStateUUID = vars["wsrep_cluster_state_uuid"]
+	status.LocalState = vars["wsrep_local_state_comment"]
+	status.Ready = strings.EqualFold(vars["wsrep_ready"], "ON")
+	status.Connected = strings.EqualFold(vars["wsrep_connected"], "ON")
+	status.FlowControlPausedNs = uint64(parseStatusInt(vars["wsrep_flow_control_paused_ns"]))
+	status.FlowControlSent = uint64(parseStatusInt(vars["wsrep_flow_control_sent"]))
+	status.FlowControlRecv = uint64(parseStatusInt(vars["wsrep_flow_control_recv"]))
+	status.RecvQueue = parseStatusInt(vars["wsrep_local_recv_queue"])
+	status.RecvQueueAvg = parseStatusFloat(vars["wsrep_local_recv_queue_avg"])
+	status.SendQueue = parseStatusInt(vars["wsrep_local_send_queue"])
+	status.SendQueueAvg = parseStatusFloat(vars["wsrep_local_send_queue_avg"])
+	status.CertFailures = uint64(parseStatusInt(vars["wsrep_local_cert_failures"]))
+	status.BFAborts = uint64(parseStatusInt(vars["wsrep_local_bf_aborts"]))
+	status.IncomingAddresses = vars["wsrep_incoming_addresses"]
+	status.ProviderVersion = vars["wsrep_provider_version"]
+	status.cumulativePausedFrac = parseStatusFloat(vars["wsrep_flow_control_paused"])
+	status.FlowControlPaused = status.cumulativePausedFrac
+
+	return status
+}
+
+// ComputeFlowControlPaused sets the paused fraction for the interval between two samples.
+// The cumulative _ns counter is preferred; wsrep_flow_control_paused is only reset by FLUSH STATUS.
+func ComputeFlowControlPaused(previous, current *GaleraStatus, intervalNs int64) {
+	if previous == nil || intervalNs <= 0 || current.FlowControlPausedNs == 0 && previous.FlowControlPausedNs == 0 {
+		current.FlowControlPaused = current.cumulativePausedFrac
+		return
+	}
+	if current.FlowControlPausedNs < previous.FlowControlPausedNs {
+		// Counter reset after a restart
+		current.FlowControlPaused = 0
+		return
+	}
+
+	fraction := float64(current.FlowControlPausedNs-previous.FlowControlPausedNs) / float64(intervalNs)
+	if fraction > 1 {
+		fraction = 1
+	}
+	current.FlowControlPaused = fraction
+}
+
+// parseStatusInt parses an integer status value, returning 0 when empty or invalid
+func parseStatusInt(value string) int64 {
+	n, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	return n
+}
+
+// parseStatusFloat parses a float status value, returning 0 when empty or invalid
+func parseStatusFloat(value string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return f
+}
@@ -1,8 +1,10 @@
 package mariadb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	// Import MySQL driver
@@ -10,24 +12,18 @@ import (
 )
 
 // GetUptime returns the uptime of MariaDB in seconds
-func GetUptime(dbConfig *DBConfig) (int64, error) {
-	// Connect to MariaDB
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-		dbConfig.Username, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Database)
-	db, err := sql.Open("mysql", dsn)
+func GetUptime(ctx context.Context, dbConfig *DBConfig) (int64, error) {
+	db, err := GetDB(dbConfig)
 	if err != nil {
-		return 0, fmt.Errorf("failed to connect to MariaDB: %w", err)
+		return 0, err
 	}
-	defer db.Close()
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return 0, fmt.Errorf("failed to ping MariaDB: %w", err)
-	}
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
 
 	// Query for uptime
 	var uptime int64
-	err = db.QueryRow("SELECT VARIABLE_VALUE FROM information_schema.GLOBAL_STATUS WHERE VARIABLE_NAME = 'Uptime'").Scan(&uptime)
+	err = db.QueryRowContext(ctx, "SELECT VARIABLE_VALUE FROM information_schema.GLOBAL_STATUS WHERE VARIABLE_NAME = 'Uptime'").Scan(&uptime)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query MariaDB uptime: %w", err)
 	}
@@ -36,24 +32,18 @@ func GetUptime(dbConfig *DBConfig) (int64, error) {
 }
 
 // GetVersion returns the version of MariaDB
-func GetVersion(dbConfig *DBConfig) (string, error) {
-	// Connect to MariaDB
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-		dbConfig.Username, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Database)
-	db, err := sql.Open("mysql", dsn)
+func GetVersion(ctx context.Context, dbConfig *DBConfig) (string, error) {
+	db, err := GetDB(dbConfig)
 	if err != nil {
-		return "", fmt.Errorf("failed to connect to MariaDB: %w", err)
+		return "", err
 	}
-	defer db.Close()
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return "", fmt.Errorf("failed to ping MariaDB: %w", err)
-	}
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
 
 	// Query for version
 	var version string
-	err = db.QueryRow("SELECT VERSION()").Scan(&version)
+	err = db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
 	if err != nil {
 		return "", fmt.Errorf("failed to query MariaDB version: %w", err)
 	}
@@ -62,24 +52,18 @@ func GetVersion(dbConfig *DBConfig) (string, error) {
 }
 
 // GetActiveConnections returns the number of active connections
-func GetActiveConnections(dbConfig *DBConfig) (int, error) {
-	// Connect to MariaDB
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-		dbConfig.Username, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Database)
-	db, err := sql.Open("mysql", dsn)
+func GetActiveConnections(ctx context.Context, dbConfig *DBConfig) (int, error) {
+	db, err := GetDB(dbConfig)
 	if err != nil {
-		return 0, fmt.Errorf("failed to connect to MariaDB: %w", err)
+		return 0, err
 	}
-	defer db.Close()
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return 0, fmt.Errorf("failed to ping MariaDB: %w", err)
-	}
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
 
 	// Query for active connections
 	var connections int
-	err = db.QueryRow("SELECT COUNT(*) FROM information_schema.PROCESSLIST").Scan(&connections)
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.PROCESSLIST").Scan(&connections)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query MariaDB connections: %w", err)
 	}
@@ -96,3 +80,69 @@ func FormatUptime(seconds int64) string {
 
 	return fmt.Sprintf("%d days, %d hours, %d minutes", days, hours, minutes)
 }
+
+// GetSSLPaths returns the paths of the server certificate and CA configured in
+// @@ssl_cert and @@ssl_ca. Relative paths are resolved against @@datadir. An empty
+// certificate path means SSL is not configured.
+func GetSSLPaths(ctx context.Context, dbConfig *DBConfig) (string, string, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	// Query for certificate paths and data directory
+	var certPath, caPath, dataDir sql.NullString
+	err = db.QueryRowContext(ctx, "SELECT @@ssl_cert, @@ssl_ca, @@datadir").Scan(&certPath, &caPath, &dataDir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to query MariaDB ssl_cert: %w", err)
+	}
+
+	if !certPath.Valid || certPath.String == "" {
+		return "", "", nil
+	}
+
+	resolve := func(path sql.NullString) string {
+		if !path.Valid || path.String == "" {
+			return ""
+		}
+		if !filepath.IsAbs(path.String) && dataDir.Valid {
+			return filepath.Join(dataDir.String, path.String)
+		}
+		return path.String
+	}
+
+	return resolve(certPath), resolve(caPath), nil
+}
+
+// GetSlowLogPath returns the path of the slow query log configured in @@slow_query_log_file.
+// Relative paths are resolved against @@datadir.
+func GetSlowLogPath(ctx context.Context, dbConfig *DBConfig) (string, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	// Query for slow log path and data directory
+	var logPath, dataDir sql.NullString
+	err = db.QueryRowContext(ctx, "SELECT @@slow_query_log_file, @@datadir").Scan(&logPath, &dataDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to query MariaDB slow_query_log_file: %w", err)
+	}
+
+	if !logPath.Valid || logPath.String == "" {
+		return "", fmt.Errorf("slow_query_log_file is not set")
+	}
+
+	path := logPath.String
+	if !filepath.IsAbs(path) && dataDir.Valid {
+		path = filepath.Join(dataDir.String, path)
+	}
+
+	return path, nil
+}
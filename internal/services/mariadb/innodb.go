@@ -0,0 +1,469 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InnoDBStatus is the parsed output of SHOW ENGINE INNODB STATUS
+type InnoDBStatus struct {
+	Time           time.Time          `json:"time"`
+	Semaphores     InnoDBSemaphores   `json:"semaphores"`
+	LatestDeadlock *InnoDBDeadlock    `json:"latest_deadlock,omitempty"`
+	Transactions   InnoDBTransactions `json:"transactions"`
+	FileIO         InnoDBFileIO       `json:"file_io"`
+	Log            InnoDBLog          `json:"log"`
+	BufferPool     InnoDBBufferPool   `json:"buffer_pool"`
+}
+
+// InnoDBSemaphores holds the SEMAPHORES section
+type InnoDBSemaphores struct {
+	ReservationCount int64           `json:"reservation_count"`
+	SignalCount      int64           `json:"signal_count"`
+	Waits            []SemaphoreWait `json:"waits,omitempty"`
+	MaxWaitSeconds   float64         `json:"max_wait_seconds"`
+}
+
+// SemaphoreWait is a thread currently waiting on an InnoDB semaphore
+type SemaphoreWait struct {
+	Thread  string  `json:"thread"`
+	File    string  `json:"file"`
+	Line    int     `json:"line"`
+	Seconds float64 `json:"seconds"`
+}
+
+// InnoDBDeadlock holds the LATEST DETECTED DEADLOCK section
+type InnoDBDeadlock struct {
+	Time         time.Time             `json:"time"`
+	Transactions []DeadlockTransaction `json:"transactions"`
+	RolledBack   int                   `json:"rolled_back"` // Number of the transaction chosen as victim
+	Raw          string                `json:"raw"`
+}
+
+// DeadlockTransaction is one of the transactions involved in a deadlock
+type DeadlockTransaction struct {
+	Number        int    `json:"number"`
+	TransactionID string `json:"transaction_id"`
+	Active        string `json:"active,omitempty"`
+	ThreadID      int64  `json:"thread_id"`
+	User          string `json:"user,omitempty"`
+	Host          string `json:"host,omitempty"`
+	Query         string `json:"query"`
+	HoldsLock     string `json:"holds_lock,omitempty"`
+	WaitingFor    string `json:"waiting_for,omitempty"`
+}
+
+// InnoDBTransactions holds the TRANSACTIONS section
+type InnoDBTransactions struct {
+	TrxIDCounter       uint64 `json:"trx_id_counter"`
+	HistoryListLength  int64  `json:"history_list_length"`
+	ActiveTransactions int    `json:"active_transactions"`
+	LockWaits          int    `json:"lock_waits"`
+}
+
+// InnoDBFileIO holds the FILE I/O section
+type InnoDBFileIO struct {
+	PendingReads           int64   `json:"pending_reads"`
+	PendingWrites          int64   `json:"pending_writes"`
+	PendingFsyncLog        int64   `json:"pending_fsync_log"`
+	PendingFsyncBufferPool int64   `json:"pending_fsync_buffer_pool"`
+	OSFileReads            int64   `json:"os_file_reads"`
+	OSFileWrites           int64   `json:"os_file_writes"`
+	OSFsyncs               int64   `json:"os_fsyncs"`
+	ReadsPerSec            float64 `json:"reads_per_sec"`
+	WritesPerSec           float64 `json:"writes_per_sec"`
+	FsyncsPerSec           float64 `json:"fsyncs_per_sec"`
+}
+
+// InnoDBLog holds the LOG section
+type InnoDBLog struct {
+	SequenceNumber   uint64 `json:"sequence_number"`
+	FlushedUpTo      uint64 `json:"flushed_up_to"`
+	PagesFlushedUpTo uint64 `json:"pages_flushed_up_to"`
+	LastCheckpoint   uint64 `json:"last_checkpoint"`
+	CheckpointAge    uint64 `json:"checkpoint_age"`
+}
+
+// InnoDBBufferPool holds the BUFFER POOL AND MEMORY section
+type InnoDBBufferPool struct {
+	TotalMemory   int64   `json:"total_memory"`
+	PoolSize      int64   `json:"pool_size"` // In pages
+	FreeBuffers   int64   `json:"free_buffers"`
+	DatabasePages int64   `json:"database_pages"`
+	ModifiedPages int64   `json:"modified_pages"`
+	PagesRead     int64   `json:"pages_read"`
+	PagesCreated  int64   `json:"pages_created"`
+	PagesWritten  int64   `json:"pages_written"`
+	HitRate       float64 `json:"hit_rate"` // Percentage, 0 when there was no activity
+}
+
+var (
+	innodbSemaphoreWait  = regexp.MustCompile(`--Thread (\S+) has waited at (\S+) line (\d+) for ([0-9.]+) seconds`)
+	innodbReservation    = regexp.MustCompile(`reservation count (\d+)`)
+	innodbSignal         = regexp.MustCompile(`signal count (\d+)`)
+	innodbDeadlockTrx    = regexp.MustCompile(`^\*\*\* \((\d+)\) TRANSACTION:`)
+	innodbDeadlockHolds  = regexp.MustCompile(`^\*\*\* \((\d+)\) HOLDS THE LOCK`)
+	innodbDeadlockWaits  = regexp.MustCompile(`^\*\*\* (?:\((\d+)\) )?WAITING FOR THIS LOCK`)
+	innodbDeadlockOther  = regexp.MustCompile(`^\*\*\* CONFLICTING WITH:`)
+	innodbRollback       = regexp.MustCompile(`WE ROLL BACK TRANSACTION \((\d+)\)`)
+	innodbTrxHeader      = regexp.MustCompile(`^TRANSACTION (\S+?),(?: ACTIVE (.*?)(?:,|$))?`)
+	innodbThreadLine     = regexp.MustCompile(`^(?:MySQL|MariaDB) thread id (\d+),.*?query id \d+\s*(.*)$`)
+	innodbDigits         = regexp.MustCompile(`\d+`)
+	innodbNumbers        = regexp.MustCompile(`[0-9]+(?:\.[0-9]+)?`)
+	innodbHitRate        = regexp.MustCompile(`Buffer pool hit rate (\d+) / (\d+)`)
+	innodbTimestampStart = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}|\d{6} {1,2}\d{1,2}:\d{2}:\d{2})`)
+)
+
+// GetInnoDBStatus returns the raw output of SHOW ENGINE INNODB STATUS
+func GetInnoDBStatus(ctx context.Context, dbConfig *DBConfig) (string, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	var engineType, name, status string
+	if err := db.QueryRowContext(ctx, "SHOW ENGINE INNODB STATUS").Scan(&engineType, &name, &status); err != nil {
+		return "", fmt.Errorf("failed to query InnoDB status: %w", err)
+	}
+
+	return status, nil
+}
+
+// GetDeadlockCount returns the Innodb_deadlocks global status counter
+func GetDeadlockCount(ctx context.Context, dbConfig *DBConfig) (int64, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	var name string
+	var count int64
+	if err := db.QueryRowContext(ctx, "SHOW GLOBAL STATUS LIKE 'Innodb_deadlocks'").Scan(&name, &count); err != nil {
+		return 0, fmt.Errorf("failed to query Innodb_deadlocks: %w", err)
+	}
+
+	return count, nil
+}
+
+// ParseInnoDBStatus parses the text of SHOW ENGINE INNODB STATUS into sections
+func ParseInnoDBStatus(text string) *InnoDBStatus {
+	status := &InnoDBStatus{Time: time.Now()}
+
+	for title, lines := range splitInnoDBSections(text) {
+		switch title {
+		case "SEMAPHORES":
+			status.Semaphores = parseSemaphores(lines)
+		case "LATEST DETECTED DEADLOCK":
+			status.LatestDeadlock = parseDeadlock(lines)
+		case "TRANSACTIONS":
+			status.Transactions = parseTransactions(lines)
+		case "FILE I/O":
+			status.FileIO = parseFileIO(lines)
+		case "LOG":
+			status.Log = parseLog(lines)
+		case "BUFFER POOL AND MEMORY":
+			status.BufferPool = parseBufferPool(lines)
+		}
+	}
+
+	return status
+}
+
+// splitInnoDBSections splits the status text on its dashed section headers
+func splitInnoDBSections(text string) map[string][]string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	sections := make(map[string][]string)
+
+	var title string
+	for i := 0; i < len(lines); i++ {
+		if i+2 < len(lines) && isDashLine(lines[i]) && isDashLine(lines[i+2]) && !isDashLine(lines[i+1]) {
+			title = strings.TrimSpace(lines[i+1])
+			sections[title] = nil
+			i += 2
+			continue
+		}
+		if title != "" {
+			sections[title] = append(sections[title], lines[i])
+		}
+	}
+
+	return sections
+}
+
+// isDashLine reports whether a line consists only of dashes
+func isDashLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) >= 3 && strings.Trim(line, "-") == ""
+}
+
+// parseSemaphores parses the SEMAPHORES section
+func parseSemaphores(lines []string) InnoDBSemaphores {
+	var s InnoDBSemaphores
+	for _, line := range lines {
+		if m := innodbReservation.FindStringSubmatch(line); m != nil {
+			s.ReservationCount, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		if m := innodbSignal.FindStringSubmatch(line); m != nil {
+			s.SignalCount, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		if m := innodbSemaphoreWait.FindStringSubmatch(line); m != nil {
+			wait := SemaphoreWait{Thread: m[1], File: m[2]}
+			wait.Line, _ = strconv.Atoi(m[3])
+			wait.Seconds, _ = strconv.ParseFloat(m[4], 64)
+			s.Waits = append(s.Waits, wait)
+			if wait.Seconds > s.MaxWaitSeconds {
+				s.MaxWaitSeconds = wait.Seconds
+			}
+		}
+	}
+	return s
+}
+
+// parseDeadlock parses the LATEST DETECTED DEADLOCK section
+func parseDeadlock(lines []string) *InnoDBDeadlock {
+	deadlock := &InnoDBDeadlock{Raw: strings.TrimSpace(strings.Join(lines, "\n"))}
+	if deadlock.Raw == "" {
+		return nil
+	}
+
+	var current *DeadlockTransaction
+	var block string // Which part of the transaction the following lines belong to
+	var query []string
+
+	flushQuery := func() {
+		if current != nil && len(query) > 0 && current.Query == "" {
+			current.Query = strings.TrimSpace(strings.Join(query, "\n"))
+		}
+		query = nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if deadlock.Time.IsZero() {
+			if m := innodbTimestampStart.FindStringSubmatch(trimmed); m != nil {
+				deadlock.Time = parseLogTime(m[1])
+				continue
+			}
+		}
+
+		if m := innodbDeadlockTrx.FindStringSubmatch(trimmed); m != nil {
+			flushQuery()
+			number, _ := strconv.Atoi(m[1])
+			deadlock.Transactions = append(deadlock.Transactions, DeadlockTransaction{Number: number})
+			current = &deadlock.Transactions[len(deadlock.Transactions)-1]
+			block = "header"
+			continue
+		}
+		if m := innodbRollback.FindStringSubmatch(trimmed); m != nil {
+			flushQuery()
+			deadlock.RolledBack, _ = strconv.Atoi(m[1])
+			current = nil
+			continue
+		}
+		if m := innodbDeadlockHolds.FindStringSubmatch(trimmed); m != nil {
+			flushQuery()
+			current = deadlockTransaction(deadlock, m[1], current)
+			block = "holds"
+			continue
+		}
+		if m := innodbDeadlockWaits.FindStringSubmatch(trimmed); m != nil {
+			flushQuery()
+			current = deadlockTransaction(deadlock, m[1], current)
+			block = "waits"
+			continue
+		}
+		if innodbDeadlockOther.MatchString(trimmed) {
+			// MariaDB 10.6+ lists the locks of the other transaction here
+			flushQuery()
+			block = "conflicts"
+			continue
+		}
+		if current == nil || trimmed == "" {
+			continue
+		}
+
+		switch block {
+		case "header":
+			if m := innodbTrxHeader.FindStringSubmatch(trimmed); m != nil && current.TransactionID == "" {
+				current.TransactionID = m[1]
+				current.Active = strings.TrimSpace(m[2])
+			} else if m := innodbThreadLine.FindStringSubmatch(trimmed); m != nil {
+				// MySQL thread id 5, OS thread handle 140, query id 50 localhost root updating
+				current.ThreadID, _ = strconv.ParseInt(m[1], 10, 64)
+				if fields := strings.Fields(m[2]); len(fields) >= 2 {
+					current.Host = fields[0]
+					current.User = fields[1]
+				}
+				block = "query"
+			}
+		case "query":
+			query = append(query, trimmed)
+		case "holds":
+			if current.HoldsLock == "" {
+				current.HoldsLock = trimmed
+			}
+		case "waits":
+			if current.WaitingFor == "" {
+				current.WaitingFor = trimmed
+			}
+		}
+	}
+	flushQuery()
+
+	return deadlock
+}
+
+// deadlockTransaction returns the transaction a lock block refers to, defaulting to the current one
+func deadlockTransaction(deadlock *InnoDBDeadlock, number string, current *DeadlockTransaction) *DeadlockTransaction {
+	if number == "" {
+		return current
+	}
+	n, _ := strconv.Atoi(number)
+	for i := range deadlock.Transactions {
+		if deadlock.Transactions[i].Number == n {
+			return &deadlock.Transactions[i]
+		}
+	}
+	return current
+}
+
+// parseTransactions parses the TRANSACTIONS section
+func parseTransactions(lines []string) InnoDBTransactions {
+	var t InnoDBTransactions
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Trx id counter"):
+			t.TrxIDCounter = parseUintField(trimmed, 0)
+		case strings.HasPrefix(trimmed, "History list length"):
+			t.HistoryListLength = int64(parseUintField(trimmed, 0))
+		case strings.HasPrefix(trimmed, "---TRANSACTION") && strings.Contains(trimmed, "ACTIVE"):
+			t.ActiveTransactions++
+		case strings.HasPrefix(trimmed, "------- TRX HAS BEEN WAITING"):
+			t.LockWaits++
+		}
+	}
+	return t
+}
+
+// parseFileIO parses the FILE I/O section
+func parseFileIO(lines []string) InnoDBFileIO {
+	var f InnoDBFileIO
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Pending normal aio reads"):
+			// Pending normal aio reads: 0 [0, 0] , aio writes: 0 [0, 0] ,
+			if _, reads, ok := strings.Cut(trimmed, "reads:"); ok {
+				f.PendingReads = int64(parseUintField(reads, 0))
+			}
+			if _, writes, ok := strings.Cut(trimmed, "writes:"); ok {
+				f.PendingWrites = int64(parseUintField(writes, 0))
+			}
+		case strings.HasPrefix(trimmed, "Pending flushes (fsync)"):
+			// Pending flushes (fsync) log: 0; buffer pool: 0
+			if _, logPart, ok := strings.Cut(trimmed, "log:"); ok {
+				f.PendingFsyncLog = int64(parseUintField(logPart, 0))
+			}
+			if _, pool, ok := strings.Cut(trimmed, "buffer pool:"); ok {
+				f.PendingFsyncBufferPool = int64(parseUintField(pool, 0))
+			}
+		case strings.Contains(trimmed, "OS file reads"):
+			// 123 OS file reads, 456 OS file writes, 78 OS fsyncs
+			numbers := innodbNumbers.FindAllString(trimmed, -1)
+			if len(numbers) >= 3 {
+				f.OSFileReads, _ = strconv.ParseInt(numbers[0], 10, 64)
+				f.OSFileWrites, _ = strconv.ParseInt(numbers[1], 10, 64)
+				f.OSFsyncs, _ = strconv.ParseInt(numbers[2], 10, 64)
+			}
+		case strings.Contains(trimmed, "reads/s") && strings.Contains(trimmed, "fsyncs/s"):
+			// 0.00 reads/s, 0 avg bytes/read, 0.00 writes/s, 0.00 fsyncs/s
+			numbers := innodbNumbers.FindAllString(trimmed, -1)
+			if len(numbers) >= 4 {
+				f.ReadsPerSec, _ = strconv.ParseFloat(numbers[0], 64)
+				f.WritesPerSec, _ = strconv.ParseFloat(numbers[2], 64)
+				f.FsyncsPerSec, _ = strconv.ParseFloat(numbers[3], 64)
+			}
+		}
+	}
+	return f
+}
+
+// parseLog parses the LOG section
+func parseLog(lines []string) InnoDBLog {
+	var l InnoDBLog
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Log sequence number"):
+			l.SequenceNumber = parseUintField(trimmed, 0)
+		case strings.HasPrefix(trimmed, "Log flushed up to"):
+			l.FlushedUpTo = parseUintField(trimmed, 0)
+		case strings.HasPrefix(trimmed, "Pages flushed up to"):
+			l.PagesFlushedUpTo = parseUintField(trimmed, 0)
+		case strings.HasPrefix(trimmed, "Last checkpoint at"):
+			l.LastCheckpoint = parseUintField(trimmed, 0)
+		}
+	}
+	if l.SequenceNumber >= l.LastCheckpoint {
+		l.CheckpointAge = l.SequenceNumber - l.LastCheckpoint
+	}
+	return l
+}
+
+// parseBufferPool parses the BUFFER POOL AND MEMORY section
+func parseBufferPool(lines []string) InnoDBBufferPool {
+	var b InnoDBBufferPool
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Total large memory allocated"), strings.HasPrefix(trimmed, "Total memory allocated"):
+			b.TotalMemory = int64(parseUintField(trimmed, 0))
+		case strings.HasPrefix(trimmed, "Buffer pool size "), strings.HasPrefix(trimmed, "Buffer pool size\t"):
+			b.PoolSize = int64(parseUintField(trimmed, 0))
+		case strings.HasPrefix(trimmed, "Free buffers"):
+			b.FreeBuffers = int64(parseUintField(trimmed, 0))
+		case strings.HasPrefix(trimmed, "Database pages"):
+			b.DatabasePages = int64(parseUintField(trimmed, 0))
+		case strings.HasPrefix(trimmed, "Modified db pages"):
+			b.ModifiedPages = int64(parseUintField(trimmed, 0))
+		case strings.HasPrefix(trimmed, "Pages read") && strings.Contains(trimmed, "created"):
+			// Pages read 900, created 100, written 200
+			numbers := innodbNumbers.FindAllString(trimmed, -1)
+			if len(numbers) >= 3 {
+				b.PagesRead, _ = strconv.ParseInt(numbers[0], 10, 64)
+				b.PagesCreated, _ = strconv.ParseInt(numbers[1], 10, 64)
+				b.PagesWritten, _ = strconv.ParseInt(numbers[2], 10, 64)
+			}
+		}
+		if m := innodbHitRate.FindStringSubmatch(trimmed); m != nil {
+			hits, _ := strconv.ParseFloat(m[1], 64)
+			total, _ := strconv.ParseFloat(m[2], 64)
+			if total > 0 {
+				b.HitRate = hits / total * 100
+			}
+		}
+	}
+	return b
+}
+
+// parseUintField returns the n-th unsigned integer found in a line
+func parseUintField(line string, n int) uint64 {
+	numbers := innodbDigits.FindAllString(line, -1)
+	if len(numbers) <= n {
+		return 0
+	}
+	value, _ := strconv.ParseUint(numbers[n], 10, 64)
+	return value
+}
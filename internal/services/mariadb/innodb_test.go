@@ -0,0 +1,152 @@
+package mariadb
+
+import (
+	"os"
+	"testing"
+)
+
+// readStatus returns the text of a captured status sample in testdata
+func readStatus(t *testing.T, name string) string {
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseInnoDBStatus(t *testing.T) {
+	status := ParseInnoDBStatus(readStatus(t, "innodb_status_10.11.txt"))
+
+	s := status.Semaphores
+	if s.ReservationCount != 120 || s.SignalCount != 98 || len(s.Waits) != 2 || s.MaxWaitSeconds != 12 {
+		t.Errorf("semaphores = %+v, want 2 waits of at most 12s", s)
+	}
+	if len(s.Waits) > 0 && (s.Waits[0].File != "btr0cur.cc" || s.Waits[0].Line != 1535) {
+		t.Errorf("first wait = %+v, want btr0cur.cc line 1535", s.Waits[0])
+	}
+
+	trx := status.Transactions
+	if trx.TrxIDCounter != 4531 || trx.HistoryListLength != 85 || trx.ActiveTransactions != 2 || trx.LockWaits != 1 {
+		t.Errorf("transactions = %+v, want 2 active with 1 lock wait", trx)
+	}
+
+	want := InnoDBFileIO{
+		PendingReads:           3,
+		PendingWrites:          4,
+		PendingFsyncLog:        1,
+		PendingFsyncBufferPool: 2,
+		OSFileReads:            2110,
+		OSFileWrites:           6330,
+		OSFsyncs:               3127,
+		ReadsPerSec:            1.5,
+		WritesPerSec:           12.25,
+		FsyncsPerSec:           6,
+	}
+	if status.FileIO != want {
+		t.Errorf("file I/O = %+v\nwant %+v", status.FileIO, want)
+	}
+
+	if status.Log.SequenceNumber != 52346789 || status.Log.LastCheckpoint != 52290000 || status.Log.CheckpointAge != 56789 {
+		t.Errorf("log = %+v, want a checkpoint age of 56789", status.Log)
+	}
+
+	b := status.BufferPool
+	if b.PoolSize != 8112 || b.FreeBuffers != 7000 || b.DatabasePages != 1100 || b.ModifiedPages != 12 || b.HitRate != 99 {
+		t.Errorf("buffer pool = %+v, want 8112 pages with a 99%% hit rate", b)
+	}
+	if b.PagesRead != 900 || b.PagesCreated != 200 || b.PagesWritten != 300 {
+		t.Errorf("buffer pool pages = %d/%d/%d, want 900/200/300", b.PagesRead, b.PagesCreated, b.PagesWritten)
+	}
+}
+
+func TestParseInnoDBDeadlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		rolledBack int
+		want       []DeadlockTransaction
+	}{
+		{
+			name:       "MariaDB 10.11",
+			file:       "innodb_status_10.11.txt",
+			rolledBack: 2,
+			want: []DeadlockTransaction{
+				{
+					Number:        1,
+					TransactionID: "4521",
+					Active:        "3 sec starting index read",
+					ThreadID:      31,
+					Host:          "10.0.0.5",
+					User:          "app",
+					Query:         "UPDATE accounts\nSET balance = balance - 10\nWHERE id = 2",
+					WaitingFor:    "RECORD LOCKS space id 12 page no 3 n bits 72 index PRIMARY of table `shop`.`accounts` trx id 4521 lock_mode X locks rec but not gap waiting",
+				},
+				{
+					Number:        2,
+					TransactionID: "4522",
+					Active:        "2 sec starting index read",
+					ThreadID:      32,
+					Host:          "localhost",
+					User:          "billing",
+					Query:         "UPDATE accounts SET balance = balance + 10 WHERE id = 1",
+					WaitingFor:    "RECORD LOCKS space id 12 page no 3 n bits 72 index PRIMARY of table `shop`.`accounts` trx id 4522 lock_mode X locks rec but not gap waiting",
+				},
+			},
+		},
+		{
+			name:       "MariaDB 10.4",
+			file:       "innodb_deadlock_10.4.txt",
+			rolledBack: 1,
+			want: []DeadlockTransaction{
+				{
+					Number:        1,
+					TransactionID: "9001",
+					Active:        "1 sec inserting",
+					ThreadID:      15,
+					Host:          "localhost",
+					User:          "root",
+					Query:         "INSERT INTO orders (id, customer_id) VALUES (10, 1)",
+					WaitingFor:    "RECORD LOCKS space id 20 page no 4 n bits 80 index customer_idx of table `shop`.`orders` trx id 9001 lock_mode X insert intention waiting",
+				},
+				{
+					Number:        2,
+					TransactionID: "9002",
+					Active:        "1 sec inserting",
+					ThreadID:      16,
+					Host:          "localhost",
+					User:          "root",
+					Query:         "INSERT INTO orders (id, customer_id) VALUES (11, 1)",
+					HoldsLock:     "RECORD LOCKS space id 20 page no 4 n bits 80 index customer_idx of table `shop`.`orders` trx id 9002 lock_mode X",
+					WaitingFor:    "RECORD LOCKS space id 20 page no 4 n bits 80 index customer_idx of table `shop`.`orders` trx id 9002 lock_mode X insert intention waiting",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadlock := ParseInnoDBStatus(readStatus(t, tt.file)).LatestDeadlock
+			if deadlock == nil {
+				t.Fatal("want a deadlock")
+			}
+			if deadlock.Time.IsZero() || deadlock.RolledBack != tt.rolledBack {
+				t.Errorf("time = %v, rolled back = %d, want %d", deadlock.Time, deadlock.RolledBack, tt.rolledBack)
+			}
+			if len(deadlock.Transactions) != len(tt.want) {
+				t.Fatalf("transactions = %+v, want %d", deadlock.Transactions, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := deadlock.Transactions[i]; got != want {
+					t.Errorf("transaction %d = %+v\nwant %+v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInnoDBStatusWithoutDeadlock(t *testing.T) {
+	status := ParseInnoDBStatus("------------\nTRANSACTIONS\n------------\nTrx id counter 10\nHistory list length 0\n")
+	if status.LatestDeadlock != nil || status.Transactions.TrxIDCounter != 10 {
+		t.Errorf("status = %+v, want no deadlock", status)
+	}
+}
@@ -2,46 +2,21 @@ package mariadb
 
 import (
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/runner"
 	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 )
 
 // GetLatestMariaDBLogs retrieves the most recent log entries from the MariaDB error log
 func GetLatestMariaDBLogs(logPath string, maxEntries int) ([]string, error) {
-	// Check if log file exists
-	if _, err := os.Stat(logPath); os.IsNotExist(err) {
-		logger.Warn("MariaDB log file not found, checking alternative locations",
-			logger.String("path", logPath))
-
-		// Try common alternative log locations
-		alternativePaths := []string{
-			"/var/log/mysql/error.log",
-			"/var/log/mariadb/mariadb.log",
-			"/var/log/mariadb/error.log",
-			"/var/log/mysql/mariadb.log",
-			"/var/log/mysql.log",
-			"/var/lib/mysql/mysql.log",
-		}
-
-		for _, altPath := range alternativePaths {
-			if altPath != logPath {
-				if _, err := os.Stat(altPath); err == nil {
-					logger.Info("Using alternative MariaDB log path",
-						logger.String("original_path", logPath),
-						logger.String("alternative_path", altPath))
-					logPath = altPath
-					break
-				}
-			}
-		}
+	logPath = ResolveLogPath(logPath)
 
-		// If no log file is found, return a fallback message instead of an error
-		if _, err := os.Stat(logPath); os.IsNotExist(err) {
-			return []string{"Log file not found. This is not critical for basic monitoring."}, nil
-		}
+	// If no log file is found, return a fallback message instead of an error
+	if _, err := os.Stat(logPath); os.IsNotExist(err) {
+		return []string{"Log file not found. This is not critical for basic monitoring."}, nil
 	}
 
 	// Open the log file
@@ -86,11 +61,44 @@ func GetLatestMariaDBLogs(logPath string, maxEntries int) ([]string, error) {
 	return logs, nil
 }
 
+// ResolveLogPath returns logPath if it exists, otherwise the first common
+// MariaDB error log location that does. The original path is returned when none exist.
+func ResolveLogPath(logPath string) string {
+	if _, err := os.Stat(logPath); err == nil {
+		return logPath
+	}
+
+	logger.Warn("MariaDB log file not found, checking alternative locations",
+		logger.String("path", logPath))
+
+	// Try common alternative log locations
+	alternativePaths := []string{
+		"/var/log/mysql/error.log",
+		"/var/log/mariadb/mariadb.log",
+		"/var/log/mariadb/error.log",
+		"/var/log/mysql/mariadb.log",
+		"/var/log/mysql.log",
+		"/var/lib/mysql/mysql.log",
+	}
+
+	for _, altPath := range alternativePaths {
+		if altPath != logPath {
+			if _, err := os.Stat(altPath); err == nil {
+				logger.Info("Using alternative MariaDB log path",
+					logger.String("original_path", logPath),
+					logger.String("alternative_path", altPath))
+				return altPath
+			}
+		}
+	}
+
+	return logPath
+}
+
 // Add new function to get system logs if MariaDB logs aren't available
-func GetSystemdServiceLogs(serviceName string, maxEntries int) ([]string, error) {
+func GetSystemdServiceLogs(ctx context.Context, serviceName string, maxEntries int) ([]string, error) {
 	// Try journalctl for systemd logs
-	cmd := exec.Command("journalctl", "-u", serviceName, "--no-pager", "-n", fmt.Sprintf("%d", maxEntries))
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, "journalctl", "-u", serviceName, "--no-pager", "-n", fmt.Sprintf("%d", maxEntries))
 	if err != nil {
 		return nil, err
 	}
@@ -108,9 +116,8 @@ func GetSystemdServiceLogs(serviceName string, maxEntries int) ([]string, error)
 	}
 
 	// Try traditional syslog if systemd logs aren't available
-	cmd = exec.Command("grep", serviceName, "/var/log/syslog")
-	output, err = cmd.Output()
-	if err != nil && err.Error() != "exit status 1" { // grep returns 1 if no matches
+	output, err = runner.Output(ctx, "grep", "--", serviceName, "/var/log/syslog")
+	if err != nil && runner.ExitCode(err) != 1 { // grep returns 1 if no matches
 		return nil, err
 	}
 
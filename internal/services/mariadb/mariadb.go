@@ -1,6 +1,7 @@
 package mariadb
 
 import (
+	"context"
 	"fmt"
 
 	"CheckHealthDO/internal/pkg/config"
@@ -8,11 +9,11 @@ import (
 )
 
 // GetMariaDBInfo returns comprehensive information about the MariaDB service
-func GetMariaDBInfo(cfg *config.Config) (*MariaDBInfo, error) {
+func GetMariaDBInfo(ctx context.Context, cfg *config.Config) (*MariaDBInfo, error) {
 	serviceName := cfg.Monitoring.MariaDB.ServiceName
 
 	// Check if service is running with the improved check
-	isRunning, err := CheckServiceStatus(serviceName, cfg)
+	isRunning, err := CheckServiceStatus(ctx, serviceName, cfg)
 	if err != nil {
 		logger.Error("Failed to check MariaDB status",
 			logger.String("service", serviceName),
@@ -31,7 +32,7 @@ func GetMariaDBInfo(cfg *config.Config) (*MariaDBInfo, error) {
 		dbConfig := GetDBConfigFromConfig(cfg)
 
 		// Get uptime
-		uptime, err := GetUptime(dbConfig)
+		uptime, err := GetUptime(ctx, dbConfig)
 		if err != nil {
 			logger.Error("Failed to get MariaDB uptime",
 				logger.String("error", err.Error()))
@@ -41,7 +42,7 @@ func GetMariaDBInfo(cfg *config.Config) (*MariaDBInfo, error) {
 		info.Uptime = FormatUptime(uptime) // Using FormatUptime from info.go
 
 		// Get version
-		version, err := GetVersion(dbConfig)
+		version, err := GetVersion(ctx, dbConfig)
 		if err != nil {
 			logger.Error("Failed to get MariaDB version",
 				logger.String("error", err.Error()))
@@ -50,7 +51,7 @@ func GetMariaDBInfo(cfg *config.Config) (*MariaDBInfo, error) {
 		info.Version = version
 
 		// Get active connections
-		connections, err := GetActiveConnections(dbConfig)
+		connections, err := GetActiveConnections(ctx, dbConfig)
 		if err != nil {
 			logger.Error("Failed to get MariaDB connections",
 				logger.String("error", err.Error()))
@@ -59,7 +60,7 @@ func GetMariaDBInfo(cfg *config.Config) (*MariaDBInfo, error) {
 		info.ConnectionsActive = connections
 
 		// Get memory usage
-		memUsed, memPercent, err := GetMariaDBMemoryUsage()
+		memUsed, memPercent, err := GetMariaDBMemoryUsage(ctx)
 		if err != nil {
 			logger.Warn("Failed to get MariaDB memory usage",
 				logger.String("error", err.Error()))
@@ -1,17 +1,19 @@
 package mariadb
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"strconv"
 	"strings"
 
+	"CheckHealthDO/internal/pkg/runner"
+
 	"github.com/shirou/gopsutil/mem"
 	"github.com/shirou/gopsutil/process"
 )
 
 // GetMariaDBMemoryUsage retrieves memory usage for the MariaDB process
-func GetMariaDBMemoryUsage() (uint64, float64, error) {
+func GetMariaDBMemoryUsage(ctx context.Context) (uint64, float64, error) {
 	// Try different patterns to find MariaDB process
 	patterns := []string{"mariadb", "maria", "mysqld"}
 
@@ -19,7 +21,7 @@ func GetMariaDBMemoryUsage() (uint64, float64, error) {
 	var err error
 
 	for _, pattern := range patterns {
-		pid, err = findProcessByPattern(pattern)
+		pid, err = findProcessByPattern(ctx, pattern)
 		if err == nil && pid > 0 {
 			break
 		}
@@ -30,18 +32,18 @@ func GetMariaDBMemoryUsage() (uint64, float64, error) {
 	}
 
 	// Use gopsutil to get memory info for this process
-	proc, err := process.NewProcess(int32(pid))
+	proc, err := process.NewProcessWithContext(ctx, int32(pid))
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get process info: %w", err)
 	}
 
-	memInfo, err := proc.MemoryInfo()
+	memInfo, err := proc.MemoryInfoWithContext(ctx)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get memory info: %w", err)
 	}
 
 	// Get total system memory to calculate percentage
-	vmStat, err := mem.VirtualMemory()
+	vmStat, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
 		return memInfo.RSS, 0, fmt.Errorf("failed to get system memory info: %w", err)
 	}
@@ -53,13 +55,12 @@ func GetMariaDBMemoryUsage() (uint64, float64, error) {
 }
 
 // findProcessByPattern attempts to find a process ID using the given pattern
-func findProcessByPattern(pattern string) (int, error) {
-	cmd := exec.Command("pgrep", "-f", pattern)
-	output, err := cmd.Output()
+func findProcessByPattern(ctx context.Context, pattern string) (int, error) {
+	output, err := runner.Output(ctx, "pgrep", "-f", pattern)
 
 	if err != nil {
 		// Check if it's just that no processes were found
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		if runner.ExitCode(err) == 1 {
 			return 0, fmt.Errorf("no processes found matching pattern '%s'", pattern)
 		}
 		return 0, fmt.Errorf("error running pgrep: %w", err)
@@ -0,0 +1,200 @@
+package mariadb
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultOptionFiles are the global option files read by MariaDB and MySQL on Linux
+var DefaultOptionFiles = []string{"/etc/my.cnf", "/etc/mysql/my.cnf"}
+
+// Option groups read by the server and by client programs. Version-specific groups
+// such as [mariadb-10.6] or [mysqld-8.0] are not included: the server version is
+// not known when the files are read, so options in those groups are ignored.
+var (
+	serverOptionGroups = []string{"mysqld", "server", "mariadb", "mariadbd", "mysqld_safe", "mariadbd-safe", "galera"}
+	clientOptionGroups = []string{"client", "client-server", "client-mariadb"}
+)
+
+// OptionEntry is a single option read from an option file
+type OptionEntry struct {
+	Group string
+	Key   string
+	Value string
+	File  string
+}
+
+// OptionFiles holds the options read from my.cnf and the files it includes, in read order
+type OptionFiles struct {
+	Files   []string
+	Entries []OptionEntry
+}
+
+// LoadOptionFiles parses the given option files and everything they include through
+// !include and !includedir. Files that do not exist are skipped.
+func LoadOptionFiles(paths []string) (*OptionFiles, error) {
+	options := &OptionFiles{}
+	visited := make(map[string]bool)
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := options.parseFile(path, visited); err != nil {
+			return options, err
+		}
+	}
+
+	return options, nil
+}
+
+// Get returns the value of an option as the server or client would see it: the
+// last occurrence in any of the groups wins
+func (o *OptionFiles) Get(key string, groups ...string) (OptionEntry, bool) {
+	key = normalizeOptionKey(key)
+
+	var found OptionEntry
+	ok := false
+	for _, entry := range o.Entries {
+		if entry.Key != key {
+			continue
+		}
+		for _, group := range groups {
+			if entry.Group == group {
+				found = entry
+				ok = true
+				break
+			}
+		}
+	}
+	return found, ok
+}
+
+// parseFile reads one option file, following include directives
+func (o *OptionFiles) parseFile(path string, visited map[string]bool) error {
+	absPath, err := filepath.Abs(path)
+	if err == nil {
+		path = absPath
+	}
+	if visited[path] {
+		return nil
+	}
+	visited[path] = true
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open option file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	o.Files = append(o.Files, path)
+
+	group := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "!includedir"):
+			dir := o.resolveInclude(path, strings.TrimSpace(strings.TrimPrefix(line, "!includedir")))
+			if err := o.parseDir(dir, visited); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(line, "!include"):
+			included := o.resolveInclude(path, strings.TrimSpace(strings.TrimPrefix(line, "!include")))
+			if err := o.parseFile(included, visited); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			group = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+
+		key, value := splitOptionLine(line)
+		if key == "" {
+			continue
+		}
+		o.Entries = append(o.Entries, OptionEntry{
+			Group: group,
+			Key:   key,
+			Value: value,
+			File:  path,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read option file %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseDir reads every .cnf file in an !includedir directory in name order
+func (o *OptionFiles) parseDir(dir string, visited map[string]bool) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read option directory %s: %w", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".cnf") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := o.parseFile(filepath.Join(dir, name), visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveInclude resolves an include target relative to the including file
+func (o *OptionFiles) resolveInclude(from, target string) string {
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(filepath.Dir(from), target)
+}
+
+// splitOptionLine splits "key = value" into a normalized key and an unquoted value.
+// Options without a value, such as "skip-name-resolve", have an empty value.
+func splitOptionLine(line string) (string, string) {
+	key, value, _ := strings.Cut(line, "=")
+	key = normalizeOptionKey(key)
+
+	value = strings.TrimSpace(value)
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return key, value[1 : end+1]
+		}
+		return key, value[1:]
+	}
+
+	// An unquoted # starts a comment
+	if idx := strings.Index(value, "#"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return key, value
+}
+
+// normalizeOptionKey makes "log-error", "log_error" and "loose-log-error" the same key
+func normalizeOptionKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.ReplaceAll(key, "-", "_")
+	return strings.TrimPrefix(key, "loose_")
+}
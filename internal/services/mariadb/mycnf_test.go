@@ -0,0 +1,128 @@
+package mariadb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeOptionFile writes an option file under dir, creating parent directories
+func writeOptionFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSplitOptionLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		key   string
+		value string
+	}{
+		{"datadir = /var/lib/mysql", "datadir", "/var/lib/mysql"},
+		{"datadir=/var/lib/mysql", "datadir", "/var/lib/mysql"},
+		{"log-error = /var/log/mysql/error.log", "log_error", "/var/log/mysql/error.log"},
+		{"loose-log-error = /var/log/mysql/error.log", "log_error", "/var/log/mysql/error.log"},
+		{"loose_innodb_buffer_pool_size = 1G", "innodb_buffer_pool_size", "1G"},
+		{"LOG_ERROR = error.log", "log_error", "error.log"},
+		{"skip-name-resolve", "skip_name_resolve", ""},
+		{`socket = "/run/my sql/mysqld.sock"`, "socket", "/run/my sql/mysqld.sock"},
+		{`password = 'p#ss=word' # comment`, "password", "p#ss=word"},
+		{`password = "unterminated`, "password", "unterminated"},
+		{"port = 3306 # default port", "port", "3306"},
+	}
+
+	for _, tt := range tests {
+		key, value := splitOptionLine(tt.line)
+		if key != tt.key || value != tt.value {
+			t.Errorf("splitOptionLine(%q) = %q, %q, want %q, %q", tt.line, key, value, tt.key, tt.value)
+		}
+	}
+}
+
+func TestLoadOptionFiles(t *testing.T) {
+	dir := t.TempDir()
+	main := writeOptionFile(t, dir, "my.cnf", `
+# Global defaults
+[client]
+port = 3306
+socket = /run/mysqld/mysqld.sock
+
+[mysqld]
+datadir = /var/lib/mysql
+log-error = /var/log/mysql/error.log
+
+!include extra.cnf
+!includedir conf.d
+!includedir missing.d
+`)
+	writeOptionFile(t, dir, "extra.cnf", `
+[mariadb]
+loose-log-error = /var/log/mariadb/extra.log
+!include my.cnf
+`)
+	writeOptionFile(t, dir, "conf.d/50-server.cnf", `
+[Server]
+datadir = "/srv/mysql data"
+; A comment
+[mariadb-10.6]
+datadir = /srv/mariadb-10.6
+`)
+	writeOptionFile(t, dir, "conf.d/10-client.cnf", `
+[client-mariadb]
+socket = '/run/mariadb/mariadb.sock'
+`)
+	writeOptionFile(t, dir, "conf.d/README", "[mysqld]\ndatadir = /ignored\n")
+
+	options, err := LoadOptionFiles([]string{filepath.Join(dir, "absent.cnf"), main})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read order, each file once despite extra.cnf including my.cnf again
+	var files []string
+	for _, file := range options.Files {
+		rel, _ := filepath.Rel(dir, file)
+		files = append(files, rel)
+	}
+	if got := strings.Join(files, ","); got != "my.cnf,extra.cnf,conf.d/10-client.cnf,conf.d/50-server.cnf" {
+		t.Errorf("files = %s", got)
+	}
+
+	tests := []struct {
+		key    string
+		groups []string
+		value  string
+		file   string
+	}{
+		{"datadir", serverOptionGroups, "/srv/mysql data", "conf.d/50-server.cnf"}, // [mariadb-10.6] is ignored
+		{"log_error", serverOptionGroups, "/var/log/mariadb/extra.log", "extra.cnf"},
+		{"log-error", []string{"mysqld"}, "/var/log/mysql/error.log", "my.cnf"},
+		{"socket", clientOptionGroups, "/run/mariadb/mariadb.sock", "conf.d/10-client.cnf"},
+		{"port", clientOptionGroups, "3306", "my.cnf"},
+	}
+	for _, tt := range tests {
+		entry, ok := options.Get(tt.key, tt.groups...)
+		if !ok {
+			t.Errorf("Get(%s, %v) found nothing", tt.key, tt.groups)
+			continue
+		}
+		if entry.Value != tt.value || entry.File != filepath.Join(dir, tt.file) {
+			t.Errorf("Get(%s, %v) = %q from %s, want %q from %s", tt.key, tt.groups, entry.Value, entry.File, tt.value, tt.file)
+		}
+	}
+
+	if entry, ok := options.Get("port", serverOptionGroups...); ok {
+		t.Errorf("Get(port) in server groups = %+v, want client options left out", entry)
+	}
+	if entry, _ := options.Get("datadir", "mariadb-10.6"); entry.Value != "/srv/mariadb-10.6" {
+		t.Errorf("Get(datadir, mariadb-10.6) = %q, want the group readable when asked for", entry.Value)
+	}
+}
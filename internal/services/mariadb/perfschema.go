@@ -0,0 +1,198 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// picosecondsPerSecond converts performance_schema timer values to seconds
+const picosecondsPerSecond = 1e12
+
+// StatementDigest is a cumulative row of performance_schema.events_statements_summary_by_digest
+type StatementDigest struct {
+	Schema       string
+	Digest       string
+	DigestText   string
+	Executions   uint64
+	TotalLatency uint64 // In picoseconds
+	RowsExamined uint64
+	RowsSent     uint64
+	Errors       uint64
+	Warnings     uint64
+	NoIndexUsed  uint64
+}
+
+// StatementDelta is the activity of a statement digest during one sampling interval
+type StatementDelta struct {
+	Schema       string  `json:"schema,omitempty"`
+	Digest       string  `json:"digest"`
+	DigestText   string  `json:"digest_text"`
+	Executions   uint64  `json:"executions"`
+	TotalLatency float64 `json:"total_latency"` // In seconds
+	AvgLatency   float64 `json:"avg_latency"`   // In seconds
+	RowsExamined uint64  `json:"rows_examined"`
+	RowsSent     uint64  `json:"rows_sent"`
+	Errors       uint64  `json:"errors"`
+	Warnings     uint64  `json:"warnings"`
+	NoIndexUsed  uint64  `json:"no_index_used"`
+}
+
+// TopQueries holds the busiest statement digests of the last sampling interval
+type TopQueries struct {
+	IntervalStart  time.Time        `json:"interval_start"`
+	IntervalEnd    time.Time        `json:"interval_end"`
+	ByLatency      []StatementDelta `json:"by_latency"`
+	ByExecutions   []StatementDelta `json:"by_executions"`
+	ByRowsExamined []StatementDelta `json:"by_rows_examined"`
+	ByErrors       []StatementDelta `json:"by_errors"`
+}
+
+// IsPerformanceSchemaEnabled reports whether @@performance_schema is on
+func IsPerformanceSchemaEnabled(ctx context.Context, dbConfig *DBConfig) (bool, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	var enabled int
+	if err := db.QueryRowContext(ctx, "SELECT @@performance_schema").Scan(&enabled); err != nil {
+		return false, fmt.Errorf("failed to query MariaDB performance_schema: %w", err)
+	}
+
+	return enabled == 1, nil
+}
+
+// GetStatementDigests reads the cumulative statement digest summary
+func GetStatementDigests(ctx context.Context, dbConfig *DBConfig) (map[string]StatementDigest, error) {
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `SELECT SCHEMA_NAME, DIGEST, DIGEST_TEXT, COUNT_STAR, SUM_TIMER_WAIT,
+		SUM_ROWS_EXAMINED, SUM_ROWS_SENT, SUM_ERRORS, SUM_WARNINGS, SUM_NO_INDEX_USED
+		FROM performance_schema.events_statements_summary_by_digest`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query statement digests: %w", err)
+	}
+	defer rows.Close()
+
+	digests := make(map[string]StatementDigest)
+	for rows.Next() {
+		var schema, digest, text sql.NullString
+		var d StatementDigest
+		if err := rows.Scan(&schema, &digest, &text, &d.Executions, &d.TotalLatency,
+			&d.RowsExamined, &d.RowsSent, &d.Errors, &d.Warnings, &d.NoIndexUsed); err != nil {
+			return nil, fmt.Errorf("failed to scan statement digest: %w", err)
+		}
+		d.Schema = schema.String
+		d.Digest = digest.String
+		d.DigestText = text.String
+
+		// The same digest text is tracked separately per schema
+		digests[d.Schema+"|"+d.Digest] = d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read statement digests: %w", err)
+	}
+
+	return digests, nil
+}
+
+// DiffStatementDigests returns the activity between two digest samples.
+// Counters that went backwards (e.g. after TRUNCATE or a restart) are treated as starting from zero.
+func DiffStatementDigests(previous, current map[string]StatementDigest) []StatementDelta {
+	deltas := make([]StatementDelta, 0)
+
+	for key, cur := range current {
+		prev, ok := previous[key]
+		if ok && cur.Executions < prev.Executions {
+			ok = false
+		}
+		if !ok {
+			prev = StatementDigest{}
+		}
+
+		executions := cur.Executions - prev.Executions
+		if executions == 0 {
+			continue
+		}
+
+		delta := StatementDelta{
+			Schema:       cur.Schema,
+			Digest:       cur.Digest,
+			DigestText:   cur.DigestText,
+			Executions:   executions,
+			TotalLatency: float64(counterDelta(cur.TotalLatency, prev.TotalLatency)) / picosecondsPerSecond,
+			RowsExamined: counterDelta(cur.RowsExamined, prev.RowsExamined),
+			RowsSent:     counterDelta(cur.RowsSent, prev.RowsSent),
+			Errors:       counterDelta(cur.Errors, prev.Errors),
+			Warnings:     counterDelta(cur.Warnings, prev.Warnings),
+			NoIndexUsed:  counterDelta(cur.NoIndexUsed, prev.NoIndexUsed),
+		}
+		delta.AvgLatency = delta.TotalLatency / float64(executions)
+		deltas = append(deltas, delta)
+	}
+
+	return deltas
+}
+
+// BuildTopQueries ranks interval deltas by each dimension, keeping at most n per ranking
+func BuildTopQueries(deltas []StatementDelta, n int, start, end time.Time) *TopQueries {
+	return &TopQueries{
+		IntervalStart: start,
+		IntervalEnd:   end,
+		ByLatency: topStatements(deltas, n, func(d StatementDelta) float64 {
+			return d.TotalLatency
+		}),
+		ByExecutions: topStatements(deltas, n, func(d StatementDelta) float64 {
+			return float64(d.Executions)
+		}),
+		ByRowsExamined: topStatements(deltas, n, func(d StatementDelta) float64 {
+			return float64(d.RowsExamined)
+		}),
+		ByErrors: topStatements(deltas, n, func(d StatementDelta) float64 {
+			return float64(d.Errors)
+		}),
+	}
+}
+
+// topStatements returns the n deltas with the highest non-zero value
+func topStatements(deltas []StatementDelta, n int, value func(StatementDelta) float64) []StatementDelta {
+	ranked := make([]StatementDelta, 0, len(deltas))
+	for _, d := range deltas {
+		if value(d) > 0 {
+			ranked = append(ranked, d)
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		vi, vj := value(ranked[i]), value(ranked[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return ranked[i].Digest < ranked[j].Digest
+	})
+
+	if n > 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// counterDelta subtracts two cumulative counters, guarding against resets
+func counterDelta(current, previous uint64) uint64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
@@ -0,0 +1,143 @@
+package mariadb
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// digest builds a cumulative digest row with latency given in seconds
+func digest(name string, executions uint64, latency float64, rowsExamined, errors uint64) StatementDigest {
+	return StatementDigest{
+		Schema:       "shop",
+		Digest:       name,
+		DigestText:   "SELECT " + name,
+		Executions:   executions,
+		TotalLatency: uint64(latency * picosecondsPerSecond),
+		RowsExamined: rowsExamined,
+		Errors:       errors,
+	}
+}
+
+// samples keys digest rows the way GetStatementDigests does
+func samples(rows ...StatementDigest) map[string]StatementDigest {
+	digests := make(map[string]StatementDigest)
+	for _, d := range rows {
+		digests[d.Schema+"|"+d.Digest] = d
+	}
+	return digests
+}
+
+func TestDiffStatementDigests(t *testing.T) {
+	previous := samples(
+		digest("a", 100, 10, 1000, 1),
+		digest("idle", 50, 5, 500, 0),
+		digest("truncated", 400, 40, 4000, 4),
+	)
+	current := samples(
+		digest("a", 110, 12, 1500, 1),
+		digest("idle", 50, 5, 500, 0),
+		digest("truncated", 30, 3, 300, 0), // TRUNCATE or a restart reset the counters
+		digest("new", 5, 0.5, 50, 2),
+	)
+
+	deltas := DiffStatementDigests(previous, current)
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Digest < deltas[j].Digest })
+
+	want := []struct {
+		digest       string
+		executions   uint64
+		latency      float64
+		rowsExamined uint64
+		errors       uint64
+	}{
+		{"a", 10, 2, 500, 0},
+		{"new", 5, 0.5, 50, 2},
+		{"truncated", 30, 3, 300, 0},
+	}
+	if len(deltas) != len(want) {
+		t.Fatalf("deltas = %+v, want %d digests with activity", deltas, len(want))
+	}
+	for i, w := range want {
+		d := deltas[i]
+		if d.Digest != w.digest || d.Executions != w.executions || d.RowsExamined != w.rowsExamined || d.Errors != w.errors {
+			t.Errorf("delta %d = %+v, want %+v", i, d, w)
+		}
+		if !approxEqual(d.TotalLatency, w.latency) || !approxEqual(d.AvgLatency, w.latency/float64(w.executions)) {
+			t.Errorf("%s latency = %g (avg %g), want %g", d.Digest, d.TotalLatency, d.AvgLatency, w.latency)
+		}
+	}
+}
+
+func TestDiffStatementDigestsCounterReset(t *testing.T) {
+	// Executions grew but another counter went backwards: that counter restarts from zero
+	previous := samples(digest("a", 100, 10, 1000, 5))
+	current := samples(digest("a", 120, 12, 200, 1))
+
+	deltas := DiffStatementDigests(previous, current)
+	if len(deltas) != 1 {
+		t.Fatalf("deltas = %+v, want one", deltas)
+	}
+	if d := deltas[0]; d.Executions != 20 || d.RowsExamined != 200 || d.Errors != 1 {
+		t.Errorf("delta = %+v, want 20 executions, 200 rows examined and 1 error", d)
+	}
+
+	if deltas := DiffStatementDigests(previous, previous); len(deltas) != 0 {
+		t.Errorf("unchanged sample gave deltas %+v, want none", deltas)
+	}
+}
+
+func TestBuildTopQueries(t *testing.T) {
+	deltas := []StatementDelta{
+		{Digest: "a", Executions: 10, TotalLatency: 3, RowsExamined: 100},
+		{Digest: "b", Executions: 50, TotalLatency: 1, RowsExamined: 100, Errors: 2},
+		{Digest: "c", Executions: 20, TotalLatency: 5, RowsExamined: 900},
+		{Digest: "d", Executions: 5, TotalLatency: 3, Errors: 1},
+	}
+	start := time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+
+	top := BuildTopQueries(deltas, 3, start, end)
+	if !top.IntervalStart.Equal(start) || !top.IntervalEnd.Equal(end) {
+		t.Errorf("interval = %s - %s, want %s - %s", top.IntervalStart, top.IntervalEnd, start, end)
+	}
+
+	rankings := []struct {
+		name string
+		got  []StatementDelta
+		want []string
+	}{
+		{"by latency", top.ByLatency, []string{"c", "a", "d"}}, // a and d tie, ordered by digest
+		{"by executions", top.ByExecutions, []string{"b", "c", "a"}},
+		{"by rows examined", top.ByRowsExamined, []string{"c", "a", "b"}},
+		{"by errors", top.ByErrors, []string{"b", "d"}}, // Statements without errors are left out
+	}
+	for _, r := range rankings {
+		if got := digestNames(r.got); !reflect.DeepEqual(got, r.want) {
+			t.Errorf("%s = %v, want %v", r.name, got, r.want)
+		}
+	}
+
+	if all := BuildTopQueries(deltas, 0, start, end); len(all.ByExecutions) != len(deltas) {
+		t.Errorf("no limit kept %d statements, want %d", len(all.ByExecutions), len(deltas))
+	}
+	if empty := BuildTopQueries(nil, 3, start, end); empty.ByLatency == nil || len(empty.ByLatency) != 0 {
+		t.Errorf("no activity = %+v, want empty rankings", empty.ByLatency)
+	}
+}
+
+// digestNames lists the digests of a ranking in order
+func digestNames(deltas []StatementDelta) []string {
+	names := make([]string, len(deltas))
+	for i, d := range deltas {
+		names[i] = d.Digest
+	}
+	return names
+}
+
+// approxEqual compares latencies converted from picoseconds
+func approxEqual(a, b float64) bool {
+	diff := a - b
+	return diff < 1e-9 && diff > -1e-9
+}
@@ -0,0 +1,123 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"CheckHealthDO/internal/pkg/logger"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Default connection pool settings
+const (
+	defaultMaxOpenConns    = 5
+	defaultMaxIdleConns    = 2
+	defaultConnMaxLifetime = 3600
+	defaultConnMaxIdleTime = 300
+	defaultConnectTimeout  = 5
+	defaultQueryTimeout    = 5
+)
+
+var (
+	pools   = make(map[string]*sql.DB)
+	poolsMu sync.Mutex
+)
+
+// DSN returns the driver connection string for the configuration
+func (c *DBConfig) DSN() string {
+	driverCfg := mysql.NewConfig()
+	driverCfg.User = c.Username
+	driverCfg.Passwd = c.Password
+	if c.Socket != "" {
+		driverCfg.Net = "unix"
+		driverCfg.Addr = c.Socket
+	} else {
+		driverCfg.Net = "tcp"
+		driverCfg.Addr = fmt.Sprintf("%s:%d", c.Host, c.Port)
+	}
+	driverCfg.DBName = c.Database
+	driverCfg.Timeout = seconds(c.ConnectTimeout, defaultConnectTimeout)
+	// Guard against a server that stops responding mid-query
+	driverCfg.ReadTimeout = seconds(c.QueryTimeout, defaultQueryTimeout) * 2
+	driverCfg.WriteTimeout = seconds(c.QueryTimeout, defaultQueryTimeout) * 2
+	return driverCfg.FormatDSN()
+}
+
+// GetDB returns the shared connection pool for the configuration, creating it on first use.
+// Pools are keyed by DSN so every collector targeting the same instance shares connections.
+// Connections broken by a server restart are discarded by database/sql and the driver's
+// liveness check, and new ones are dialed on the next query.
+func GetDB(dbConfig *DBConfig) (*sql.DB, error) {
+	dsn := dbConfig.DSN()
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	if db, ok := pools[dsn]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MariaDB: %w", err)
+	}
+
+	maxOpen := dbConfig.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = defaultMaxOpenConns
+	}
+	maxIdle := dbConfig.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = defaultMaxIdleConns
+	}
+	if maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(seconds(dbConfig.ConnMaxLifetime, defaultConnMaxLifetime))
+	db.SetConnMaxIdleTime(seconds(dbConfig.ConnMaxIdleTime, defaultConnMaxIdleTime))
+
+	pools[dsn] = db
+
+	logger.Debug("Created MariaDB connection pool",
+		logger.String("host", dbConfig.Host),
+		logger.Int("port", dbConfig.Port),
+		logger.String("socket", dbConfig.Socket),
+		logger.Int("max_open_conns", maxOpen),
+		logger.Int("max_idle_conns", maxIdle))
+
+	return db, nil
+}
+
+// QueryContext returns a context bounded by the configured query timeout and by parent
+func (c *DBConfig) QueryContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, seconds(c.QueryTimeout, defaultQueryTimeout))
+}
+
+// ClosePools closes every shared connection pool
+func ClosePools() {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	for dsn, db := range pools {
+		if err := db.Close(); err != nil {
+			logger.Warn("Failed to close MariaDB connection pool",
+				logger.String("error", err.Error()))
+		}
+		delete(pools, dsn)
+	}
+}
+
+// seconds converts a configured number of seconds to a duration, applying a default when unset
+func seconds(value, fallback int) time.Duration {
+	if value <= 0 {
+		value = fallback
+	}
+	return time.Duration(value) * time.Second
+}
@@ -0,0 +1,169 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Liveness probe stages
+const (
+	ProbeStageConnect   = "connect"
+	ProbeStageQuery     = "query"
+	ProbeStageHeartbeat = "heartbeat"
+)
+
+// Liveness probe modes for the optional heartbeat table check
+const (
+	ProbeModeNone  = ""
+	ProbeModeRead  = "read"
+	ProbeModeWrite = "write"
+)
+
+// heartbeatTablePattern restricts heartbeat table names to plain [schema.]table identifiers
+var heartbeatTablePattern = regexp.MustCompile(`^[A-Za-z0-9_$]+(\.[A-Za-z0-9_$]+)?$`)
+
+// ProbeResult is the outcome of a SQL liveness probe
+type ProbeResult struct {
+	Success  bool          `json:"success"`
+	Stage    string        `json:"stage"` // Last stage attempted
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error,omitempty"`
+	ReadOnly bool          `json:"read_only,omitempty"` // The server is read-only, so write mode fell back to a read
+}
+
+// HeartbeatTableDDL returns the statement that creates the heartbeat table the
+// read and write modes expect. The probe never creates it itself.
+func HeartbeatTableDDL(table string) string {
+	return fmt.Sprintf("CREATE TABLE %s (id TINYINT UNSIGNED NOT NULL PRIMARY KEY, ts DATETIME(6) NOT NULL) ENGINE=InnoDB", table)
+}
+
+// ProbeLiveness connects to MariaDB, runs SELECT 1 and, depending on mode,
+// reads or writes a heartbeat table. On a read-only server the write mode
+// falls back to a read. The whole probe is bounded by timeout and by ctx.
+func ProbeLiveness(ctx context.Context, dbConfig *DBConfig, mode, table string, timeout time.Duration) ProbeResult {
+	start := time.Now()
+	result := ProbeResult{Stage: ProbeStageConnect}
+
+	fail := func(err error) ProbeResult {
+		result.Latency = time.Since(start)
+		result.Error = err.Error()
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	db, err := GetDB(dbConfig)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Take a dedicated connection so a dead pooled one is noticed at this stage
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fail(fmt.Errorf("failed to get connection: %w", err))
+	}
+	defer conn.Close()
+
+	result.Stage = ProbeStageQuery
+	var one int
+	if err := conn.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return fail(fmt.Errorf("SELECT 1 failed: %w", err))
+	}
+
+	if mode != ProbeModeNone {
+		result.Stage = ProbeStageHeartbeat
+		quoted, err := quoteHeartbeatTable(table)
+		if err != nil {
+			return fail(err)
+		}
+
+		if mode == ProbeModeWrite {
+			// Replicas and servers fenced off with read_only reject the write
+			readOnly, err := isReadOnly(ctx, conn)
+			if err != nil {
+				return fail(fmt.Errorf("failed to read read_only: %w", err))
+			}
+			if readOnly {
+				result.ReadOnly = true
+				mode = ProbeModeRead
+			}
+		}
+
+		switch mode {
+		case ProbeModeWrite:
+			// Writes exercise the storage engine, redo log and disk, which reads alone do not
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("REPLACE INTO %s (id, ts) VALUES (1, NOW(6))", quoted)); err != nil {
+				return fail(heartbeatError("write", quoted, err))
+			}
+		case ProbeModeRead:
+			var ts string
+			err := conn.QueryRowContext(ctx, fmt.Sprintf("SELECT ts FROM %s ORDER BY ts DESC LIMIT 1", quoted)).Scan(&ts)
+			// An empty table still proves the server answers reads
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return fail(heartbeatError("read", quoted, err))
+			}
+		default:
+			return fail(fmt.Errorf("unsupported liveness probe mode %q", mode))
+		}
+	}
+
+	result.Success = true
+	result.Latency = time.Since(start)
+	return result
+}
+
+// isReadOnly reports whether read_only or, where the server has it, super_read_only is on
+func isReadOnly(ctx context.Context, conn *sql.Conn) (bool, error) {
+	rows, err := conn.QueryContext(ctx, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('read_only', 'super_read_only')")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	readOnly := false
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return false, err
+		}
+		if strings.EqualFold(value, "ON") || value == "1" {
+			readOnly = true
+		}
+	}
+	return readOnly, rows.Err()
+}
+
+// heartbeatError wraps a heartbeat query error, explaining how to create a missing table
+func heartbeatError(action, table string, err error) error {
+	if isMissingTable(err) {
+		return fmt.Errorf("heartbeat table %s does not exist, create it with: %s", table, HeartbeatTableDDL(table))
+	}
+	return fmt.Errorf("heartbeat %s failed: %w", action, err)
+}
+
+// isMissingTable reports whether err is MariaDB error 1146 (table doesn't exist)
+func isMissingTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1146
+}
+
+// quoteHeartbeatTable validates and backtick-quotes a [schema.]table name
+func quoteHeartbeatTable(table string) (string, error) {
+	if !heartbeatTablePattern.MatchString(table) {
+		return "", fmt.Errorf("invalid heartbeat table name %q", table)
+	}
+
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = "`" + part + "`"
+	}
+	return strings.Join(parts, "."), nil
+}
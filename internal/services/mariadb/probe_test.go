@@ -0,0 +1,42 @@
+package mariadb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestHeartbeatError(t *testing.T) {
+	missing := heartbeatError("write", "`checkhealth`.`heartbeat`", &mysql.MySQLError{Number: 1146, Message: "Table 'checkhealth.heartbeat' doesn't exist"})
+	if !strings.Contains(missing.Error(), "CREATE TABLE `checkhealth`.`heartbeat`") {
+		t.Errorf("error = %q, want the statement that creates the table", missing)
+	}
+
+	denied := &mysql.MySQLError{Number: 1142, Message: "INSERT command denied"}
+	if err := heartbeatError("write", "`heartbeat`", denied); !errors.Is(err, denied) {
+		t.Errorf("error = %q, want the driver error wrapped", err)
+	}
+}
+
+func TestQuoteHeartbeatTable(t *testing.T) {
+	tests := []struct {
+		table string
+		want  string
+		ok    bool
+	}{
+		{"heartbeat", "`heartbeat`", true},
+		{"checkhealth.heartbeat", "`checkhealth`.`heartbeat`", true},
+		{"a.b.c", "", false},
+		{"heartbeat; DROP TABLE users", "", false},
+		{"`heartbeat`", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := quoteHeartbeatTable(tt.table)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("quoteHeartbeatTable(%q) = %q, %v, want %q", tt.table, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package mariadb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"CheckHealthDO/internal/pkg/config"
+)
+
+// RestartLogName is the file in the log directory where automatic restarts are recorded
+const RestartLogName = "mariadb_restarts.log"
+
+// defaultRestartLogDir is used when logs.file_path is not configured
+const defaultRestartLogDir = "logs"
+
+// RestartLogPath returns the restart log in the configured log directory. The
+// status reason classifier reads it back to explain restarts the agent caused.
+func RestartLogPath(cfg *config.Config) string {
+	dir := cfg.Logs.FilePath
+	if dir == "" {
+		dir = defaultRestartLogDir
+	}
+	return filepath.Join(dir, RestartLogName)
+}
+
+// RecordRestart appends "[<at>] <entry>" to the restart log, creating the log
+// directory when needed
+func RecordRestart(cfg *config.Config, at time.Time, entry string) error {
+	path := RestartLogPath(cfg)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create restart log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open restart log: %w", err)
+	}
+	if _, err := fmt.Fprintf(f, "[%s] %s\n", at.Format(time.RFC3339), entry); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write restart log: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package mariadb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"CheckHealthDO/internal/pkg/config"
+)
+
+func TestRecordRestart(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Logs.FilePath = filepath.Join(t.TempDir(), "var", "log")
+
+	at := time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+	if err := RecordRestart(cfg, at, "Liveness Auto-Recovery (mariadb): probe failed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordRestart(cfg, at.Add(time.Minute), "Memory Critical Auto-Recovery: usage 96%"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.Logs.FilePath, RestartLogName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[2025-01-06T08:00:00Z] Liveness Auto-Recovery (mariadb): probe failed\n" +
+		"[2025-01-06T08:01:00Z] Memory Critical Auto-Recovery: usage 96%\n"
+	if string(data) != want {
+		t.Errorf("restart log = %q, want %q", data, want)
+	}
+}
+
+func TestRecordRestartErrors(t *testing.T) {
+	// A file where the log directory should be
+	blocker := filepath.Join(t.TempDir(), "logs")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Logs.FilePath = blocker
+	err := RecordRestart(cfg, time.Now(), "Liveness Auto-Recovery (mariadb): probe failed")
+	if err == nil || !strings.Contains(err.Error(), "restart log") {
+		t.Errorf("err = %v, want the restart log failure reported", err)
+	}
+}
+
+func TestRestartLogPath(t *testing.T) {
+	cfg := &config.Config{}
+	if got := RestartLogPath(cfg); got != filepath.Join("logs", RestartLogName) {
+		t.Errorf("default path = %s", got)
+	}
+	cfg.Logs.FilePath = "/var/log/checkhealth"
+	if got := RestartLogPath(cfg); got != "/var/log/checkhealth/"+RestartLogName {
+		t.Errorf("configured path = %s", got)
+	}
+}
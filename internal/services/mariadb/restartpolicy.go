@@ -0,0 +1,320 @@
+package mariadb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+
+	"github.com/shirou/gopsutil/disk"
+)
+
+// Default restart policy settings
+const (
+	defaultMaxRestarts        = 3
+	defaultRestartWindow      = 3600
+	defaultInitialBackoff     = 60
+	defaultMaxBackoff         = 1800
+	defaultMinFreeDiskPercent = 5.0
+	defaultErrorLogLines      = 200
+	defaultDataDir            = "/var/lib/mysql"
+)
+
+var (
+	restartPolicies   = make(map[string]*RestartPolicy)
+	restartPoliciesMu sync.Mutex
+)
+
+// RestartDecision is the outcome of asking the policy for an automatic restart
+type RestartDecision struct {
+	Allowed    bool      `json:"allowed"`
+	Reason     string    `json:"reason,omitempty"`
+	RetryAfter time.Time `json:"retry_after,omitempty"`
+}
+
+// RestartPolicyStatus describes the state of a restart policy
+type RestartPolicyStatus struct {
+	ServiceName      string    `json:"service_name"`
+	Inhibited        bool      `json:"inhibited"`
+	InhibitReason    string    `json:"inhibit_reason,omitempty"`
+	InhibitedAt      time.Time `json:"inhibited_at,omitempty"`
+	RestartsInWindow int       `json:"restarts_in_window"`
+	MaxRestarts      int       `json:"max_restarts"`
+	WindowSeconds    int       `json:"window_seconds"`
+	NextAllowed      time.Time `json:"next_allowed,omitempty"`
+	LastTrigger      string    `json:"last_trigger,omitempty"`
+	LastRestart      time.Time `json:"last_restart,omitempty"`
+}
+
+// RestartPolicy guards automatic restarts of one MariaDB service against crash loops.
+// Restarts are limited per window and spaced by an exponential backoff. Before each
+// restart the data directory must have free space and the error log must not show
+// InnoDB corruption. When the limit is hit or a safety check fails the policy is
+// inhibited: automatic restarts stop until someone resets it.
+type RestartPolicy struct {
+	mu          sync.Mutex
+	config      *config.Config
+	serviceName string
+	clock       clock.Clock                                // Time source for the window and backoff
+	diskUsage   func(path string) (*disk.UsageStat, error) // Free space check, replaceable in tests
+	restarts    []time.Time
+	nextAllowed time.Time
+	lastTrigger string
+
+	inhibited     bool
+	inhibitReason string
+	inhibitedAt   time.Time
+	onInhibit     func(RestartPolicyStatus)
+}
+
+// GetRestartPolicy returns the shared restart policy for the configured service, creating
+// it on first use. Every component restarting the same service goes through one policy.
+func GetRestartPolicy(cfg *config.Config) *RestartPolicy {
+	serviceName := cfg.Monitoring.MariaDB.ServiceName
+
+	restartPoliciesMu.Lock()
+	defer restartPoliciesMu.Unlock()
+
+	if policy, ok := restartPolicies[serviceName]; ok {
+		return policy
+	}
+
+	policy := newRestartPolicy(cfg, serviceName)
+	restartPolicies[serviceName] = policy
+	return policy
+}
+
+// newRestartPolicy creates a restart policy using the system clock
+func newRestartPolicy(cfg *config.Config, serviceName string) *RestartPolicy {
+	return &RestartPolicy{
+		config:      cfg,
+		serviceName: serviceName,
+		clock:       clock.System,
+		diskUsage:   disk.Usage,
+	}
+}
+
+// SetClock replaces the time source used for the restart window and backoff
+func (p *RestartPolicy) SetClock(c clock.Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = c
+}
+
+// SetInhibitHandler sets the function called once each time restarts become inhibited
+func (p *RestartPolicy) SetInhibitHandler(handler func(RestartPolicyStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onInhibit = handler
+}
+
+// Allow decides whether an automatic restart may run now. An allowed restart is
+// recorded immediately, so it counts against the limits whether or not it succeeds.
+func (p *RestartPolicy) Allow(trigger string) RestartDecision {
+	p.mu.Lock()
+
+	if p.inhibited {
+		p.mu.Unlock()
+		return RestartDecision{Reason: "automatic restarts are inhibited: " + p.inhibitReason}
+	}
+
+	now := p.clock.Now()
+	policyCfg := p.config.Monitoring.MariaDB.RestartPolicy
+	maxRestarts := policyCfg.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = defaultMaxRestarts
+	}
+	p.pruneRestarts(now)
+
+	if now.Before(p.nextAllowed) {
+		p.mu.Unlock()
+		return RestartDecision{
+			Reason:     fmt.Sprintf("backing off after %d recent restarts", len(p.restarts)),
+			RetryAfter: p.nextAllowed,
+		}
+	}
+
+	reason := ""
+	if len(p.restarts) >= maxRestarts {
+		reason = fmt.Sprintf("%d automatic restarts within %s", len(p.restarts), p.window())
+	} else if err := p.checkSafety(); err != nil {
+		reason = err.Error()
+	}
+
+	if reason != "" {
+		status := p.inhibit(reason, now)
+		handler := p.onInhibit
+		p.mu.Unlock()
+
+		// Paging must not hold up the caller
+		if handler != nil {
+			go handler(status)
+		}
+		return RestartDecision{Reason: "automatic restarts are inhibited: " + reason}
+	}
+
+	p.restarts = append(p.restarts, now)
+	p.nextAllowed = now.Add(p.backoff(len(p.restarts)))
+	p.lastTrigger = trigger
+	p.mu.Unlock()
+
+	logger.Info("Restart policy allowed automatic MariaDB restart",
+		logger.String("service", p.serviceName),
+		logger.String("trigger", trigger),
+		logger.Int("restarts_in_window", len(p.restarts)))
+
+	return RestartDecision{Allowed: true}
+}
+
+// Reset clears the inhibited state and the restart history
+func (p *RestartPolicy) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inhibited = false
+	p.inhibitReason = ""
+	p.inhibitedAt = time.Time{}
+	p.restarts = nil
+	p.nextAllowed = time.Time{}
+
+	logger.Info("Restart policy reset", logger.String("service", p.serviceName))
+}
+
+// Status returns the current state of the policy
+func (p *RestartPolicy) Status() RestartPolicyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pruneRestarts(p.clock.Now())
+	return p.statusLocked()
+}
+
+// inhibit stops automatic restarts. Must be called with the lock held.
+func (p *RestartPolicy) inhibit(reason string, now time.Time) RestartPolicyStatus {
+	p.inhibited = true
+	p.inhibitReason = reason
+	p.inhibitedAt = now
+
+	logger.Error("Automatic MariaDB restarts inhibited",
+		logger.String("service", p.serviceName),
+		logger.String("reason", reason))
+
+	return p.statusLocked()
+}
+
+// statusLocked builds the status. Must be called with the lock held.
+func (p *RestartPolicy) statusLocked() RestartPolicyStatus {
+	maxRestarts := p.config.Monitoring.MariaDB.RestartPolicy.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = defaultMaxRestarts
+	}
+
+	status := RestartPolicyStatus{
+		ServiceName:      p.serviceName,
+		Inhibited:        p.inhibited,
+		InhibitReason:    p.inhibitReason,
+		InhibitedAt:      p.inhibitedAt,
+		RestartsInWindow: len(p.restarts),
+		MaxRestarts:      maxRestarts,
+		WindowSeconds:    int(p.window().Seconds()),
+		LastTrigger:      p.lastTrigger,
+	}
+	if p.clock.Now().Before(p.nextAllowed) {
+		status.NextAllowed = p.nextAllowed
+	}
+	if len(p.restarts) > 0 {
+		status.LastRestart = p.restarts[len(p.restarts)-1]
+	}
+	return status
+}
+
+// pruneRestarts drops restarts older than the window. Must be called with the lock held.
+func (p *RestartPolicy) pruneRestarts(now time.Time) {
+	cutoff := now.Add(-p.window())
+	kept := p.restarts[:0]
+	for _, restart := range p.restarts {
+		if restart.After(cutoff) {
+			kept = append(kept, restart)
+		}
+	}
+	p.restarts = kept
+}
+
+// window returns the period over which restarts are counted
+func (p *RestartPolicy) window() time.Duration {
+	return seconds(p.config.Monitoring.MariaDB.RestartPolicy.Window, defaultRestartWindow)
+}
+
+// backoff returns the wait after the given number of restarts within the window,
+// doubling from the initial backoff up to the maximum
+func (p *RestartPolicy) backoff(restarts int) time.Duration {
+	policyCfg := p.config.Monitoring.MariaDB.RestartPolicy
+	wait := seconds(policyCfg.InitialBackoff, defaultInitialBackoff)
+	limit := seconds(policyCfg.MaxBackoff, defaultMaxBackoff)
+
+	for i := 1; i < restarts && wait < limit; i++ {
+		wait *= 2
+	}
+	if wait > limit {
+		wait = limit
+	}
+	return wait
+}
+
+// checkSafety runs the pre-restart checks: free space on the data directory and no
+// InnoDB corruption reported in the error log within the window
+func (p *RestartPolicy) checkSafety() error {
+	policyCfg := p.config.Monitoring.MariaDB.RestartPolicy
+
+	minFree := policyCfg.MinFreeDiskPercent
+	if minFree == 0 {
+		minFree = defaultMinFreeDiskPercent
+	}
+	if minFree > 0 {
+		dataDir := p.dataDir()
+		usage, err := p.diskUsage(dataDir)
+		if err != nil {
+			logger.Warn("Failed to check free space on MariaDB data directory",
+				logger.String("path", dataDir),
+				logger.String("error", err.Error()))
+		} else if free := 100 - usage.UsedPercent; free < minFree {
+			return fmt.Errorf("only %.1f%% free on data directory %s (minimum %.1f%%)", free, dataDir, minFree)
+		}
+	}
+
+	lines := policyCfg.ErrorLogLines
+	if lines == 0 {
+		lines = defaultErrorLogLines
+	}
+	if lines > 0 && p.config.Monitoring.MariaDB.LogPath != "" {
+		logLines, err := GetLatestMariaDBLogs(p.config.Monitoring.MariaDB.LogPath, lines)
+		if err != nil {
+			logger.Warn("Failed to read MariaDB error log for restart safety check",
+				logger.String("error", err.Error()))
+			return nil
+		}
+
+		cutoff := p.clock.Now().Add(-p.window())
+		for _, line := range logLines {
+			event, ok := ClassifyLogLine(line)
+			if ok && event.Category == CategoryInnoDBCorruption && event.Time.After(cutoff) {
+				return fmt.Errorf("InnoDB corruption reported in the error log: %s", line)
+			}
+		}
+	}
+
+	return nil
+}
+
+// dataDir returns the data directory from the option files, falling back to the packaged default
+func (p *RestartPolicy) dataDir() string {
+	options, err := LoadOptionFiles(OptionFilePaths(p.config))
+	if err == nil {
+		if entry, ok := options.Get(SettingDataDir, serverOptionGroups...); ok && entry.Value != "" {
+			return entry.Value
+		}
+	}
+	return defaultDataDir
+}
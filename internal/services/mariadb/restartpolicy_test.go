@@ -0,0 +1,170 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/disk"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// newTestPolicy creates a restart policy on a fake clock with plenty of free disk space
+func newTestPolicy(cfg *config.Config) (*RestartPolicy, *clock.Fake) {
+	fake := clock.NewFake(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC))
+	policy := newRestartPolicy(cfg, "mariadb")
+	policy.SetClock(fake)
+	policy.diskUsage = func(string) (*disk.UsageStat, error) {
+		return &disk.UsageStat{UsedPercent: 50}, nil
+	}
+	return policy, fake
+}
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Monitoring.MariaDB.RestartPolicy.MaxRestarts = 10
+	cfg.Monitoring.MariaDB.RestartPolicy.InitialBackoff = 60
+	cfg.Monitoring.MariaDB.RestartPolicy.MaxBackoff = 300
+	policy, fake := newTestPolicy(cfg)
+
+	// Each restart doubles the wait before the next one, up to the maximum
+	steps := []struct {
+		advance time.Duration
+		allowed bool
+	}{
+		{0, true},
+		{59 * time.Second, false},
+		{time.Second, true},
+		{119 * time.Second, false},
+		{time.Second, true},
+		{240 * time.Second, true},
+		{299 * time.Second, false},
+		{time.Second, true},
+	}
+
+	for i, step := range steps {
+		fake.Advance(step.advance)
+		if decision := policy.Allow("test"); decision.Allowed != step.allowed {
+			t.Fatalf("step %d: allowed = %v (%s), want %v", i, decision.Allowed, decision.Reason, step.allowed)
+		}
+	}
+}
+
+func TestRestartPolicyWindow(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Monitoring.MariaDB.RestartPolicy.MaxRestarts = 2
+	cfg.Monitoring.MariaDB.RestartPolicy.Window = 3600
+	cfg.Monitoring.MariaDB.RestartPolicy.InitialBackoff = 60
+	cfg.Monitoring.MariaDB.RestartPolicy.MaxBackoff = 60
+	policy, fake := newTestPolicy(cfg)
+
+	policy.Allow("test")
+	fake.Advance(50 * time.Minute)
+	policy.Allow("test")
+	if got := policy.Status().RestartsInWindow; got != 2 {
+		t.Fatalf("restarts in window = %d, want 2", got)
+	}
+
+	// The first restart leaves the window, so a third one fits under the limit
+	fake.Advance(11 * time.Minute)
+	if got := policy.Status().RestartsInWindow; got != 1 {
+		t.Errorf("restarts in window = %d, want the oldest pruned", got)
+	}
+	if decision := policy.Allow("test"); !decision.Allowed {
+		t.Errorf("allowed = false (%s), want true", decision.Reason)
+	}
+}
+
+func TestRestartPolicyInhibitAndReset(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Monitoring.MariaDB.RestartPolicy.MaxRestarts = 2
+	cfg.Monitoring.MariaDB.RestartPolicy.InitialBackoff = 60
+	cfg.Monitoring.MariaDB.RestartPolicy.MaxBackoff = 60
+	policy, fake := newTestPolicy(cfg)
+
+	inhibited := make(chan RestartPolicyStatus, 1)
+	policy.SetInhibitHandler(func(status RestartPolicyStatus) { inhibited <- status })
+
+	policy.Allow("test")
+	fake.Advance(time.Minute)
+	policy.Allow("test")
+	fake.Advance(time.Minute)
+	if decision := policy.Allow("test"); decision.Allowed || !strings.Contains(decision.Reason, "inhibited") {
+		t.Fatalf("decision = %+v, want inhibited after 2 restarts", decision)
+	}
+
+	select {
+	case status := <-inhibited:
+		if !status.Inhibited || status.RestartsInWindow != 2 {
+			t.Errorf("inhibit status = %+v, want 2 restarts", status)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("want the inhibit handler called")
+	}
+
+	// Inhibited until reset, even after the window has passed
+	fake.Advance(2 * time.Hour)
+	if decision := policy.Allow("test"); decision.Allowed {
+		t.Error("want restarts to stay inhibited")
+	}
+
+	policy.Reset()
+	if status := policy.Status(); status.Inhibited || status.RestartsInWindow != 0 {
+		t.Errorf("status after reset = %+v, want a clean policy", status)
+	}
+	if decision := policy.Allow("test"); !decision.Allowed {
+		t.Errorf("allowed = false (%s), want true after reset", decision.Reason)
+	}
+}
+
+func TestRestartPolicySafetyChecks(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "error.log")
+	corrupt := "2024-03-01  9:55:00 0 [ERROR] InnoDB: Database page corruption on disk or a failed file read of tablespace shop/orders page [page id: space=12, page number=345]\n"
+	stale := "2024-02-29  8:00:00 0 [ERROR] InnoDB: Database page corruption on disk or a failed file read of tablespace shop/orders page [page id: space=12, page number=345]\n"
+
+	tests := []struct {
+		name       string
+		usedDisk   float64
+		log        string
+		wantReason string
+	}{
+		{name: "healthy", usedDisk: 50, log: "2024-03-01  9:55:00 0 [Note] InnoDB: Buffer pool(s) load completed\n"},
+		{name: "data directory full", usedDisk: 97, wantReason: "free on data directory"},
+		{name: "recent corruption", usedDisk: 50, log: corrupt, wantReason: "InnoDB corruption"},
+		{name: "corruption before the window", usedDisk: 50, log: stale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(logPath, []byte(tt.log), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &config.Config{}
+			cfg.Monitoring.MariaDB.LogPath = logPath
+			policy, fake := newTestPolicy(cfg)
+			fake.Set(time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local))
+			policy.diskUsage = func(string) (*disk.UsageStat, error) {
+				return &disk.UsageStat{UsedPercent: tt.usedDisk}, nil
+			}
+
+			err := policy.checkSafety()
+			if tt.wantReason == "" && err != nil {
+				t.Errorf("checkSafety() = %v, want nil", err)
+			}
+			if tt.wantReason != "" && (err == nil || !strings.Contains(err.Error(), tt.wantReason)) {
+				t.Errorf("checkSafety() = %v, want %q", err, tt.wantReason)
+			}
+		})
+	}
+}
@@ -1,60 +1,78 @@
 package mariadb
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
 	"CheckHealthDO/internal/pkg/config"
 	"CheckHealthDO/internal/pkg/logger"
+	"CheckHealthDO/internal/pkg/runner"
 )
 
-// CheckServiceStatus checks if MariaDB service is running
-func CheckServiceStatus(serviceName string, cfg *config.Config) (bool, error) {
+// statusCheckTimeout bounds the commands run on every status check
+const statusCheckTimeout = 10 * time.Second
+
+// CheckProcessStatus checks whether the MariaDB service process is running
+// according to systemd, the init script or the process table. It does not
+// verify that the server answers queries.
+func CheckProcessStatus(ctx context.Context, serviceName string) bool {
 	// First check if we're on a systemd system
-	_, err := exec.LookPath("systemctl")
-	systemdAvailable := err == nil
+	systemdAvailable := runner.Available("systemctl")
 
 	if systemdAvailable {
 		// On systemd systems, trust systemctl status as the source of truth
-		cmd := exec.Command("systemctl", "is-active", serviceName)
-		output, _ := cmd.Output()
+		result, _ := runner.Run(ctx, runner.Command{
+			Name:    "systemctl",
+			Args:    []string{"is-active", serviceName},
+			Timeout: statusCheckTimeout,
+		})
 
 		// Check the actual output regardless of error (systemctl returns non-zero if service is not active)
-		status := strings.TrimSpace(string(output))
+		status := ""
+		if result != nil {
+			status = strings.TrimSpace(string(result.Stdout))
+		}
 		serviceRunning := (status == "active")
 
 		if !serviceRunning {
 			// logger.Debug("MariaDB service is not active according to systemctl",
 			// 	logger.String("service", serviceName),
 			// 	logger.String("status", status))
-			return false, nil
+			return false
 		}
 	} else {
 		// Not a systemd system, fallback to other checks
 		serviceRunning := false
 
 		// Try the service command (for init.d systems)
-		cmd := exec.Command("service", serviceName, "status")
-		output, err := cmd.Output()
+		result, err := runner.Run(ctx, runner.Command{
+			Name:    "service",
+			Args:    []string{serviceName, "status"},
+			Timeout: statusCheckTimeout,
+		})
 		if err == nil {
-			serviceRunning = strings.Contains(string(output), "running")
+			serviceRunning = strings.Contains(string(result.Stdout), "running")
 		}
 
 		if !serviceRunning {
 			// Last resort, try checking if the process is running
-			cmd = exec.Command("pgrep", "-f", "mysqld")
-			if _, err := cmd.Output(); err == nil {
+			if _, err := runner.Output(ctx, "pgrep", "-f", "mysqld"); err == nil {
 				serviceRunning = true
 			}
 		}
 
-		// If service appears to be down, return immediately
-		if !serviceRunning {
-			return false, nil
-		}
+		return serviceRunning
+	}
+
+	return true
+}
+
+// CheckServiceStatus checks if MariaDB service is running
+func CheckServiceStatus(ctx context.Context, serviceName string, cfg *config.Config) (bool, error) {
+	if !CheckProcessStatus(ctx, serviceName) {
+		return false, nil
 	}
 
 	// At this point, the service appears to be running, let's verify connectivity
@@ -67,23 +85,19 @@ func CheckServiceStatus(serviceName string, cfg *config.Config) (bool, error) {
 	// This ensures the service is not just running but actually functional
 	dbConfig := GetDBConfigFromConfig(cfg)
 
-	// Set a short timeout for this check
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?timeout=5s",
-		dbConfig.Username, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Database)
-
-	db, err := sql.Open("mysql", dsn)
+	db, err := GetDB(dbConfig)
 	if err != nil {
 		logger.Warn("MariaDB service appears to be running but connection failed",
 			logger.String("error", err.Error()))
 		return false, nil
 	}
-	defer db.Close()
 
-	// Set a short context timeout for ping
-	db.SetConnMaxLifetime(time.Second * 5)
+	// Use the query timeout so a hung server is reported as not functional
+	pingCtx, cancel := dbConfig.QueryContext(ctx)
+	defer cancel()
 
 	// Test connection with ping
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(pingCtx); err != nil {
 		logger.Warn("MariaDB service appears to be running but ping failed",
 			logger.String("error", err.Error()))
 		return false, nil
@@ -94,14 +108,13 @@ func CheckServiceStatus(serviceName string, cfg *config.Config) (bool, error) {
 }
 
 // ControlMariaDBService executes a control command on the MariaDB service
-func ControlMariaDBService(serviceName, action string) error {
+func ControlMariaDBService(ctx context.Context, serviceName, action string) error {
 	logger.Info("Attempting to control MariaDB service",
 		logger.String("service", serviceName),
 		logger.String("action", action))
 
 	// Try using systemctl first (systemd-based systems)
-	cmd := exec.Command("systemctl", action, serviceName)
-	if err := cmd.Run(); err == nil {
+	if _, err := runner.Output(ctx, "systemctl", action, serviceName); err == nil {
 		logger.Info("Successfully controlled MariaDB service using systemctl",
 			logger.String("service", serviceName),
 			logger.String("action", action))
@@ -109,8 +122,7 @@ func ControlMariaDBService(serviceName, action string) error {
 	}
 
 	// If systemctl fails, try the service command (for init.d systems)
-	cmd = exec.Command("service", serviceName, action)
-	if err := cmd.Run(); err == nil {
+	if _, err := runner.Output(ctx, "service", serviceName, action); err == nil {
 		logger.Info("Successfully controlled MariaDB service using service command",
 			logger.String("service", serviceName),
 			logger.String("action", action))
@@ -125,30 +137,32 @@ func ControlMariaDBService(serviceName, action string) error {
 }
 
 // StartMariaDBService starts the MariaDB service
-func StartMariaDBService(serviceName string) error {
-	return ControlMariaDBService(serviceName, "start")
+func StartMariaDBService(ctx context.Context, serviceName string) error {
+	return ControlMariaDBService(ctx, serviceName, "start")
 }
 
 // StopMariaDBService stops the MariaDB service
-func StopMariaDBService(serviceName string) error {
-	return ControlMariaDBService(serviceName, "stop")
+func StopMariaDBService(ctx context.Context, serviceName string) error {
+	return ControlMariaDBService(ctx, serviceName, "stop")
 }
 
 // RestartMariaDBService restarts the MariaDB service
-func RestartMariaDBService(serviceName string) error {
+func RestartMariaDBService(ctx context.Context, serviceName string) error {
 	// Log the restart attempt
 	logger.Info("Attempting to restart MariaDB service",
 		logger.String("service", serviceName))
 
 	// Use systemctl to restart the service
-	cmd := exec.Command("systemctl", "restart", serviceName)
-	output, err := cmd.CombinedOutput()
+	result, err := runner.Run(ctx, runner.Command{
+		Name: "systemctl",
+		Args: []string{"restart", serviceName},
+	})
 
 	if err != nil {
 		logger.Error("Failed to restart MariaDB service",
 			logger.String("service", serviceName),
 			logger.String("error", err.Error()),
-			logger.String("output", string(output)))
+			logger.String("output", string(result.Combined())))
 		return fmt.Errorf("failed to restart MariaDB service: %w", err)
 	}
 
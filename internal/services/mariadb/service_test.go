@@ -0,0 +1,100 @@
+package mariadb
+
+import (
+	"CheckHealthDO/internal/pkg/runner"
+	"context"
+	"testing"
+)
+
+// useFakeRunner swaps the default runner for a fake until the test ends
+func useFakeRunner(t *testing.T) *runner.Fake {
+	fake := runner.NewFake()
+	previous := runner.SetDefault(fake)
+	t.Cleanup(func() { runner.SetDefault(previous) })
+	return fake
+}
+
+func TestCheckProcessStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*runner.Fake)
+		want  bool
+	}{
+		{
+			name:  "systemd active",
+			setup: func(f *runner.Fake) { f.On("systemctl", "is-active", "mariadb").Return("active\n") },
+			want:  true,
+		},
+		{
+			name: "systemd inactive",
+			setup: func(f *runner.Fake) {
+				f.On("systemctl", "is-active", "mariadb").Set(runner.Response{Stdout: "inactive\n", ExitCode: 3})
+			},
+			want: false,
+		},
+		{
+			name: "init script running",
+			setup: func(f *runner.Fake) {
+				f.SetMissing("systemctl")
+				f.On("service", "mariadb", "status").Return(" * MariaDB is running (pid 4321)\n")
+			},
+			want: true,
+		},
+		{
+			name: "process table",
+			setup: func(f *runner.Fake) {
+				f.SetMissing("systemctl")
+				f.On("service", "mariadb", "status").Exit(3, "")
+				f.On("pgrep", "-f", "mysqld").Return("4321\n")
+			},
+			want: true,
+		},
+		{
+			name: "not running anywhere",
+			setup: func(f *runner.Fake) {
+				f.SetMissing("systemctl")
+				f.SetMissing("service")
+				f.On("pgrep", "-f", "mysqld").Exit(1, "")
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFakeRunner(t)
+			tt.setup(fake)
+
+			if got := CheckProcessStatus(context.Background(), "mariadb"); got != tt.want {
+				t.Errorf("CheckProcessStatus() = %v, want %v (calls %v)", got, tt.want, fake.Calls())
+			}
+		})
+	}
+}
+
+func TestControlMariaDBServiceFallsBackToInitScript(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.On("systemctl", "stop", "mariadb").Exit(1, "System has not been booted with systemd")
+	fake.On("service", "mariadb", "stop").Return("Stopping MariaDB database server mariadbd\n")
+
+	if err := StopMariaDBService(context.Background(), "mariadb"); err != nil {
+		t.Fatalf("StopMariaDBService() = %v, want the init script used", err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[1].String() != "service mariadb stop" {
+		t.Errorf("calls = %v, want systemctl then service", calls)
+	}
+}
+
+func TestRestartMariaDBServiceError(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.On("systemctl", "restart").Exit(1, "Job for mariadb.service failed")
+
+	if err := RestartMariaDBService(context.Background(), "mariadb"); err == nil {
+		t.Error("want the systemctl failure returned")
+	}
+	if err := RestartMariaDBService(context.Background(), "mariadb; reboot"); err == nil || len(fake.Calls()) != 1 {
+		t.Errorf("err = %v, calls = %v, want an invalid unit name rejected before running", err, fake.Calls())
+	}
+}
@@ -0,0 +1,172 @@
+package mariadb
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlowQuery is a single entry parsed from the MariaDB slow query log
+type SlowQuery struct {
+	Time         time.Time `json:"time"`
+	User         string    `json:"user,omitempty"`
+	Host         string    `json:"host,omitempty"`
+	Schema       string    `json:"schema,omitempty"`
+	QueryTime    float64   `json:"query_time"` // In seconds
+	LockTime     float64   `json:"lock_time"`  // In seconds
+	RowsSent     int64     `json:"rows_sent"`
+	RowsExamined int64     `json:"rows_examined"`
+	Query        string    `json:"query"`
+}
+
+var (
+	slowTimePattern    = regexp.MustCompile(`^# Time:\s+(.+?)\s*$`)
+	slowUserPattern    = regexp.MustCompile(`^# User@Host:\s+(\S+?)(?:\[\S*\])?\s+@\s+(\S*)`)
+	slowSchemaPattern  = regexp.MustCompile(`\bSchema: +([^\s:]+)(?:\s|$)`) // Empty when no database is selected
+	slowMetricsPattern = regexp.MustCompile(`(\w+):\s+([0-9.]+)`)
+	slowTimestampSQL   = regexp.MustCompile(`(?i)^SET\s+timestamp\s*=\s*(\d+)\s*;`)
+	slowUseSQL         = regexp.MustCompile("(?i)^use\\s+`?([^`;\\s]+)`?\\s*;")
+)
+
+// SlowLogParser turns slow query log lines into entries. Lines may arrive in
+// arbitrary batches; an entry is emitted once its statement is complete.
+type SlowLogParser struct {
+	current  SlowQuery
+	lastTime time.Time // "# Time:" is only written when the second changes
+	inHeader bool
+	started  bool
+	sql      []string
+}
+
+// NewSlowLogParser creates a new slow query log parser
+func NewSlowLogParser() *SlowLogParser {
+	return &SlowLogParser{}
+}
+
+// Feed parses the given lines and returns every entry completed by them
+func (p *SlowLogParser) Feed(lines []string) []SlowQuery {
+	var entries []SlowQuery
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			// A header after statement text starts a new entry
+			if len(p.sql) > 0 {
+				if entry, ok := p.finish(); ok {
+					entries = append(entries, entry)
+				}
+			}
+			p.parseHeader(trimmed)
+			continue
+		}
+
+		if !p.started || isSlowLogPreamble(trimmed) {
+			continue
+		}
+
+		if m := slowTimestampSQL.FindStringSubmatch(trimmed); m != nil {
+			if ts, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+				p.current.Time = time.Unix(ts, 0)
+			}
+			continue
+		}
+		if m := slowUseSQL.FindStringSubmatch(trimmed); m != nil {
+			if p.current.Schema == "" {
+				p.current.Schema = m[1]
+			}
+			continue
+		}
+
+		p.sql = append(p.sql, trimmed)
+		if strings.HasSuffix(trimmed, ";") {
+			if entry, ok := p.finish(); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries
+}
+
+// parseHeader reads the metadata comment lines that precede a statement
+func (p *SlowLogParser) parseHeader(line string) {
+	if !p.inHeader {
+		p.current = SlowQuery{Time: p.lastTime}
+		p.inHeader = true
+		p.started = true
+	}
+
+	if m := slowTimePattern.FindStringSubmatch(line); m != nil {
+		if t, ok := parseSlowLogTime(m[1]); ok {
+			p.current.Time = t
+			p.lastTime = t
+		}
+		return
+	}
+
+	if m := slowUserPattern.FindStringSubmatch(line); m != nil {
+		p.current.User = m[1]
+		p.current.Host = m[2]
+		return
+	}
+
+	if m := slowSchemaPattern.FindStringSubmatch(line); m != nil {
+		p.current.Schema = m[1]
+	}
+
+	for _, m := range slowMetricsPattern.FindAllStringSubmatch(line, -1) {
+		switch m[1] {
+		case "Query_time":
+			p.current.QueryTime, _ = strconv.ParseFloat(m[2], 64)
+		case "Lock_time":
+			p.current.LockTime, _ = strconv.ParseFloat(m[2], 64)
+		case "Rows_sent":
+			p.current.RowsSent, _ = strconv.ParseInt(m[2], 10, 64)
+		case "Rows_examined":
+			p.current.RowsExamined, _ = strconv.ParseInt(m[2], 10, 64)
+		}
+	}
+}
+
+// finish completes the current entry
+func (p *SlowLogParser) finish() (SlowQuery, bool) {
+	entry := p.current
+	entry.Query = strings.Join(p.sql, " ")
+
+	p.sql = nil
+	p.inHeader = false
+	p.current = SlowQuery{Time: p.lastTime}
+
+	if entry.Query == "" {
+		return entry, false
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	return entry, true
+}
+
+// parseSlowLogTime parses both the MariaDB (yymmdd hh:mm:ss) and MySQL 5.7+ (RFC3339) formats
+func parseSlowLogTime(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{"060102 15:04:05", "060102  15:04:05", "060102 15:04:05.999999"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isSlowLogPreamble reports whether a line is part of the banner written when the server (re)opens the log
+func isSlowLogPreamble(line string) bool {
+	return strings.Contains(line, ", Version: ") ||
+		strings.HasPrefix(line, "Tcp port:") ||
+		strings.HasPrefix(line, "Time ") && strings.Contains(line, "Command") && strings.Contains(line, "Argument")
+}
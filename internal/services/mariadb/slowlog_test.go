@@ -0,0 +1,177 @@
+package mariadb
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readLines returns the lines of a file in testdata
+func readLines(t *testing.T, name string) []string {
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(string(data), "\n")
+}
+
+func TestSlowLogParser(t *testing.T) {
+	entries := NewSlowLogParser().Feed(readLines(t, "slow.log"))
+
+	want := []SlowQuery{
+		{
+			Time:         time.Unix(1709288102, 0),
+			User:         "app",
+			Host:         "web1",
+			Schema:       "shop",
+			QueryTime:    2.500123,
+			LockTime:     0.00012,
+			RowsSent:     10,
+			RowsExamined: 250000,
+			Query:        "SELECT o.id, o.total FROM orders o WHERE o.customer_id = 1234 AND o.note = 'call -- later; #2' ORDER BY o.created_at DESC;",
+		},
+		{
+			Time:         time.Unix(1709288103, 0),
+			User:         "report",
+			Host:         "localhost",
+			Schema:       "analytics",
+			QueryTime:    12.000001,
+			RowsSent:     1,
+			RowsExamined: 9000000,
+			Query:        "SELECT COUNT(*) FROM events WHERE day BETWEEN '2024-01-01' AND '2024-02-01';",
+		},
+		{
+			Time:         time.Unix(1709288200, 0),
+			User:         "root",
+			Host:         "localhost",
+			QueryTime:    0.75,
+			LockTime:     0.00005,
+			RowsExamined: 3,
+			Query:        "INSERT INTO audit (a, b) VALUES (1, 'x'), (2, 'y');",
+		},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %d, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		got := entries[i]
+		if !got.Time.Equal(want[i].Time) {
+			t.Errorf("entry %d time = %v, want %v", i, got.Time, want[i].Time)
+		}
+		got.Time = want[i].Time
+		if got != want[i] {
+			t.Errorf("entry %d = %+v\nwant %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestSlowLogParserBatches(t *testing.T) {
+	lines := readLines(t, "slow.log")
+
+	// The follower hands over whatever was appended since the last read, which may
+	// split an entry anywhere
+	parser := NewSlowLogParser()
+	var entries []SlowQuery
+	for _, line := range lines {
+		entries = append(entries, parser.Feed([]string{line})...)
+	}
+
+	if len(entries) != 3 || entries[0].Schema != "shop" || entries[2].User != "root" {
+		t.Errorf("entries = %+v, want the same three entries as a single batch", entries)
+	}
+}
+
+func TestSlowLogTimeFormats(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"240301 10:15:02", time.Date(2024, 3, 1, 10, 15, 2, 0, time.Local)},
+		{"240301  9:05:02", time.Date(2024, 3, 1, 9, 5, 2, 0, time.Local)},
+		{"2024-03-01T10:16:40.123456Z", time.Date(2024, 3, 1, 10, 16, 40, 123456000, time.UTC)},
+		{"2024-03-01T10:16:40.123456+07:00", time.Date(2024, 3, 1, 3, 16, 40, 123456000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseSlowLogTime(tt.value)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("parseSlowLogTime(%q) = %v, %v, want %v", tt.value, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := parseSlowLogTime("yesterday"); ok {
+		t.Error("want an unknown format rejected")
+	}
+}
+
+func TestFingerprintQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "numbers and strings",
+			query: "SELECT * FROM orders WHERE customer_id = 1234 AND status = 'paid' AND total > 10.5",
+			want:  "select * from orders where customer_id=? and status=? and total>?",
+		},
+		{
+			name:  "escaped quotes",
+			query: `SELECT id FROM users WHERE name = 'O\'Brien' OR name = 'it''s' OR nick = "say \"hi\""`,
+			want:  "select id from users where name=? or name=? or nick=?",
+		},
+		{
+			name:  "comment markers inside strings",
+			query: "SELECT id FROM notes WHERE body = 'call -- later' AND tag = '#urgent' AND id = 3",
+			want:  "select id from notes where body=? and tag=? and id=?",
+		},
+		{
+			name:  "comments",
+			query: "/* app:checkout */ SELECT id FROM carts -- lookup\nWHERE id = 9 # trailing",
+			want:  "select id from carts where id=?",
+		},
+		{
+			name:  "in lists",
+			query: "SELECT * FROM t WHERE id IN (1, 2, 3) AND k IN ('a','b')",
+			want:  "select * from t where id in(?+) and k in(?+)",
+		},
+		{
+			name:  "multi-row values",
+			query: "INSERT INTO audit (a, b) VALUES (1, 'x'), (2, 'y');",
+			want:  "insert into audit (a,b) values(?+)",
+		},
+		{
+			name:  "identifiers with digits",
+			query: "SELECT c1 FROM t2 JOIN `2024_orders` o ON o.id = t2.id WHERE hex = 0xFF AND n = -5 AND e = 1e3",
+			want:  "select c1 from t2 join `2024_orders` o on o.id=t2.id where hex=? and n=? and e=?",
+		},
+		{
+			name:  "null checks",
+			query: "SELECT 1 FROM t WHERE a IS   NOT\tNULL AND b IS NULL",
+			want:  "select ? from t where a is not null and b is null",
+		},
+		{
+			name:  "arithmetic is not a comment",
+			query: "UPDATE t SET a = a--1 WHERE id = 7",
+			want:  "update t set a=a-? where id=?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FingerprintQuery(tt.query); got != tt.want {
+				t.Errorf("FingerprintQuery() = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryDigestStable(t *testing.T) {
+	a := QueryDigest(FingerprintQuery("SELECT * FROM t WHERE id = 1"))
+	b := QueryDigest(FingerprintQuery("select *  from t where id=42"))
+	if a != b || len(a) != 16 {
+		t.Errorf("digests = %s, %s, want the same 16 character digest", a, b)
+	}
+}
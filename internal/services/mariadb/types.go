@@ -19,4 +19,13 @@ type DBConfig struct {
 	Username string
 	Password string
 	Database string
+	Socket   string // Unix socket path, used instead of Host and Port when set
+
+	// Connection pool settings, zero values fall back to defaults
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime int // In seconds
+	ConnMaxIdleTime int // In seconds
+	ConnectTimeout  int // In seconds
+	QueryTimeout    int // In seconds
 }
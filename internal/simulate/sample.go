@@ -0,0 +1,125 @@
+// Package simulate replays recorded or synthetic metric samples through the real
+// monitors and alert handlers on a virtual clock. Notifications go to a recorder
+// and external commands to a fake runner, so nothing is sent or restarted.
+package simulate
+
+import (
+	"CheckHealthDO/internal/monitoring/server/cpu"
+	"CheckHealthDO/internal/monitoring/server/disk"
+	"CheckHealthDO/internal/monitoring/server/memory"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Sample types
+const (
+	TypeCPU     = "cpu"
+	TypeMemory  = "memory"
+	TypeDisk    = "disk"
+	TypeMariaDB = "mariadb"
+)
+
+// MariaDB states a sample can report
+const (
+	StatusRunning      = "running"
+	StatusStopped      = "stopped"
+	StatusUnresponsive = "unresponsive"
+)
+
+// maxLineSize bounds a single sample line
+const maxLineSize = 1024 * 1024
+
+// Sample is one recorded observation. CPU and memory samples give either a usage
+// percentage or the full record served by the API.
+type Sample struct {
+	Time    time.Time          `json:"time"`
+	Type    string             `json:"type"`              // cpu, memory, disk or mariadb
+	Usage   *float64           `json:"usage,omitempty"`   // CPU or memory usage percentage
+	Load    []float64          `json:"load,omitempty"`    // 1, 5 and 15 minute load averages
+	CPU     *cpu.CPUInfo       `json:"cpu,omitempty"`     // Full CPU record, instead of usage
+	Memory  *memory.MemoryInfo `json:"memory,omitempty"`  // Full memory record, instead of usage
+	Disks   []disk.StorageInfo `json:"disks,omitempty"`   // Mounted partitions
+	Status  string             `json:"status,omitempty"`  // MariaDB: running, stopped or unresponsive
+	Reason  string             `json:"reason,omitempty"`  // MariaDB stop or start reason, e.g. "Manual Systemctl Stop"
+	Details string             `json:"details,omitempty"` // Details for the reason
+}
+
+// Validate checks that the sample carries the data its type needs
+func (s Sample) Validate() error {
+	if s.Time.IsZero() {
+		return fmt.Errorf("missing time")
+	}
+	if s.Load != nil && len(s.Load) != 3 {
+		return fmt.Errorf("load needs 3 values, got %d", len(s.Load))
+	}
+
+	switch s.Type {
+	case TypeCPU:
+		if s.Usage == nil && s.CPU == nil {
+			return fmt.Errorf("cpu sample needs usage or cpu")
+		}
+	case TypeMemory:
+		if s.Usage == nil && s.Memory == nil {
+			return fmt.Errorf("memory sample needs usage or memory")
+		}
+	case TypeDisk:
+		if len(s.Disks) == 0 {
+			return fmt.Errorf("disk sample needs disks")
+		}
+	case TypeMariaDB:
+		switch s.Status {
+		case StatusRunning, StatusStopped, StatusUnresponsive:
+		default:
+			return fmt.Errorf("invalid mariadb status %q", s.Status)
+		}
+	default:
+		return fmt.Errorf("unknown sample type %q", s.Type)
+	}
+	return nil
+}
+
+// ReadSamples parses samples in JSON Lines format. Blank lines are skipped.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var sample Sample
+		if err := json.Unmarshal(line, &sample); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if err := sample.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		samples = append(samples, sample)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read samples: %w", err)
+	}
+	return samples, nil
+}
+
+// ReadSamplesFile parses a JSON Lines sample file
+func ReadSamplesFile(path string) ([]Sample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open samples: %w", err)
+	}
+	defer file.Close()
+
+	return ReadSamples(file)
+}
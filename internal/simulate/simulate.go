@@ -0,0 +1,313 @@
+package simulate
+
+import (
+	"CheckHealthDO/internal/alerts"
+	"CheckHealthDO/internal/monitoring/server/cpu"
+	"CheckHealthDO/internal/monitoring/server/disk"
+	"CheckHealthDO/internal/monitoring/server/memory"
+	mariadbMonitor "CheckHealthDO/internal/monitoring/services/mariadb"
+	"CheckHealthDO/internal/pkg/clock"
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/runner"
+	"CheckHealthDO/internal/services/mariadb"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Event kinds
+const (
+	EventFired      = "fired"      // A notification would have been sent
+	EventSuppressed = "suppressed" // The status called for attention but nothing was sent
+	EventStatus     = "status"     // The status changed without a notification
+	EventAction     = "action"     // A recovery action was attempted against the fake runner, or skipped
+)
+
+// Event is one outcome of the simulation
+type Event struct {
+	Time    time.Time  `json:"time"`
+	Until   *time.Time `json:"until,omitempty"` // Last occurrence when Count > 1
+	Count   int        `json:"count"`           // Consecutive identical events merged into this one
+	Monitor string     `json:"monitor"`         // cpu, memory, mariadb or disk:<mount point>
+	Status  string     `json:"status"`
+	Kind    string     `json:"kind"`
+	Subject string     `json:"subject,omitempty"` // Notification subject, for fired events
+	Reason  string     `json:"reason,omitempty"`  // Why nothing was sent, or what changed
+}
+
+// Report is the outcome of a simulation run
+type Report struct {
+	Samples    int       `json:"samples"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Fired      int       `json:"fired"`
+	Suppressed int       `json:"suppressed"`
+	Events     []Event   `json:"events"`
+}
+
+// Simulator feeds samples to monitors built from one configuration
+type Simulator struct {
+	config   *config.Config
+	clock    *clock.Fake
+	recorder *alerts.Recorder
+	report   *Report
+
+	cpuCollector    *cpu.FakeCollector
+	cpuMonitor      *cpu.Monitor
+	memoryCollector *memory.FakeCollector
+	memoryMonitor   *memory.Monitor
+	diskCollector   *disk.FakeCollector
+	diskMonitor     *disk.Monitor
+	mariadbMonitor  *mariadbMonitor.Monitor
+
+	statuses map[string]string // Last status per monitor
+	last     map[string]int    // Index of the last event per monitor
+}
+
+// New creates a simulator for a configuration. Email is treated as enabled so
+// every notification the configuration allows shows up in the report.
+func New(cfg *config.Config) *Simulator {
+	simCfg := *cfg
+	simCfg.Notifications.Email.Enabled = true
+
+	return &Simulator{
+		config:   &simCfg,
+		recorder: alerts.NewRecorder(),
+		statuses: make(map[string]string),
+		last:     make(map[string]int),
+	}
+}
+
+// Run replays the samples in time order and reports what the alert logic did.
+// The global command runner is swapped for the duration of the run.
+func (s *Simulator) Run(samples []Sample) (*Report, error) {
+	sorted := append([]Sample{}, samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	s.report = &Report{Samples: len(sorted), Events: []Event{}}
+	if len(sorted) == 0 {
+		return s.report, nil
+	}
+	s.report.From = sorted[0].Time
+	s.report.To = sorted[len(sorted)-1].Time
+	s.clock = clock.NewFake(sorted[0].Time)
+
+	// Recovery actions run against a runner that starts nothing
+	previousRunner := runner.SetDefault(runner.NewFake())
+	defer runner.SetDefault(previousRunner)
+
+	for i, sample := range sorted {
+		s.clock.Set(sample.Time)
+		if err := s.apply(sample); err != nil {
+			return nil, fmt.Errorf("sample %d at %s: %w", i+1, sample.Time.Format(time.RFC3339), err)
+		}
+	}
+
+	return s.report, nil
+}
+
+// apply feeds one sample to its monitor and records the outcome
+func (s *Simulator) apply(sample Sample) error {
+	sent := len(s.recorder.Notifications())
+	reported := len(s.recorder.Outcomes())
+
+	switch sample.Type {
+	case TypeCPU:
+		s.applyCPU(sample)
+		info := s.cpuMonitor.GetLastCPUInfo()
+		if info == nil {
+			return fmt.Errorf("cpu sample was not collected")
+		}
+		s.record(sample.Time, TypeCPU, info.CPUStatus, sent, reported)
+	case TypeMemory:
+		s.applyMemory(sample)
+		info := s.memoryMonitor.GetLastMemoryInfo()
+		if info == nil {
+			return fmt.Errorf("memory sample was not collected")
+		}
+		s.record(sample.Time, TypeMemory, info.MemoryStatus, sent, reported)
+	case TypeDisk:
+		s.applyDisk(sample)
+	case TypeMariaDB:
+		if err := s.applyMariaDB(sample); err != nil {
+			return err
+		}
+		s.record(sample.Time, TypeMariaDB, s.mariadbMonitor.GetStatus().Status, sent, reported)
+	default:
+		return fmt.Errorf("unknown sample type %q", sample.Type)
+	}
+	return nil
+}
+
+// applyCPU runs one CPU check
+func (s *Simulator) applyCPU(sample Sample) {
+	if s.cpuMonitor == nil {
+		s.cpuCollector = cpu.NewFakeCollector()
+		s.cpuMonitor = cpu.NewMonitor(s.config)
+		s.cpuMonitor.SetCollector(s.cpuCollector)
+		s.cpuMonitor.SetClock(s.clock)
+		s.cpuMonitor.SetNotificationManager(s.recorder)
+		s.cpuMonitor.SetStatusLog(false)
+	}
+
+	if sample.Load != nil {
+		s.cpuCollector.SetLoadAverage(sample.Load[0], sample.Load[1], sample.Load[2])
+	}
+	if sample.CPU != nil {
+		s.cpuCollector.Push(*sample.CPU)
+	} else {
+		s.cpuCollector.PushUsage(*sample.Usage)
+	}
+	s.cpuMonitor.CheckCPU(context.Background())
+}
+
+// applyMemory runs one memory check
+func (s *Simulator) applyMemory(sample Sample) {
+	if s.memoryMonitor == nil {
+		s.memoryCollector = memory.NewFakeCollector()
+		s.memoryMonitor = memory.NewMonitor(s.config)
+		s.memoryMonitor.SetCollector(s.memoryCollector)
+		s.memoryMonitor.SetClock(s.clock)
+		s.memoryMonitor.SetNotificationManager(s.recorder)
+		s.memoryMonitor.SetStatusLog(false)
+	}
+
+	if sample.Load != nil {
+		s.memoryCollector.SetLoadAverage(sample.Load[0], sample.Load[1], sample.Load[2])
+	}
+	if sample.Memory != nil {
+		s.memoryCollector.Push(*sample.Memory)
+	} else {
+		s.memoryCollector.PushUsage(*sample.Usage)
+	}
+	s.memoryMonitor.CheckMemory(context.Background())
+}
+
+// applyDisk runs one storage check. The disk monitor sends no notifications, so
+// only per-disk status changes are reported.
+func (s *Simulator) applyDisk(sample Sample) {
+	if s.diskMonitor == nil {
+		s.diskCollector = disk.NewFakeCollector()
+		s.diskMonitor = disk.NewMonitor(s.config)
+		s.diskMonitor.SetCollector(s.diskCollector)
+		s.diskMonitor.SetClock(s.clock)
+	}
+
+	s.diskCollector.Push(sample.Disks...)
+	s.diskMonitor.CheckStorage(context.Background())
+
+	for _, info := range s.diskMonitor.GetLastStorageInfo() {
+		name := "disk:" + info.MountPoint
+		status := s.diskMonitor.DiskStatus(info)
+		previous, seen := s.statuses[name]
+		s.statuses[name] = status
+		if seen && previous != status {
+			s.add(Event{
+				Time:    sample.Time,
+				Monitor: name,
+				Status:  status,
+				Kind:    EventStatus,
+				Reason:  fmt.Sprintf("%s -> %s (%.1f%% used), the disk monitor sends no notifications", previous, status, info.Usage),
+			})
+		}
+	}
+}
+
+// applyMariaDB feeds one observation to the MariaDB monitor
+func (s *Simulator) applyMariaDB(sample Sample) error {
+	if s.mariadbMonitor == nil {
+		monitor, err := mariadbMonitor.NewMonitor(s.config)
+		if err != nil {
+			return err
+		}
+		monitor.SetReplay(true)
+		monitor.SetClock(s.clock)
+		monitor.SetNotificationManager(s.recorder)
+		s.mariadbMonitor = monitor
+	}
+
+	observation := mariadbMonitor.Observation{
+		Running: sample.Status != StatusStopped,
+		Reason:  sample.Reason,
+		Details: sample.Details,
+	}
+	switch sample.Status {
+	case StatusUnresponsive:
+		details := sample.Details
+		if details == "" {
+			details = "recorded as unresponsive"
+		}
+		observation.Probe = &mariadb.ProbeResult{Stage: mariadb.ProbeStageQuery, Error: details}
+	case StatusRunning:
+		if s.config.Monitoring.MariaDB.Liveness.Enabled {
+			observation.Probe = &mariadb.ProbeResult{Success: true, Stage: mariadb.ProbeStageQuery}
+		}
+	}
+
+	s.mariadbMonitor.Observe(observation)
+	return nil
+}
+
+// record turns the notifications and outcomes one check reported into events
+func (s *Simulator) record(at time.Time, monitor, status string, sent, reported int) {
+	previous, seen := s.statuses[monitor]
+	s.statuses[monitor] = status
+	changed := seen && previous != status
+
+	var reason string
+	for _, outcome := range s.recorder.Outcomes()[reported:] {
+		switch {
+		case outcome.Kind == alerts.OutcomeAction:
+			s.add(Event{Time: at, Monitor: monitor, Status: status, Kind: EventAction, Reason: outcome.Reason})
+		case reason == "":
+			reason = outcome.Reason
+		}
+	}
+
+	notifications := s.recorder.Notifications()[sent:]
+	for _, notification := range notifications {
+		s.add(Event{Time: at, Monitor: monitor, Status: status, Kind: EventFired, Subject: notification.Subject})
+	}
+	if len(notifications) > 0 {
+		return
+	}
+
+	switch {
+	case monitor != TypeMariaDB && status != "normal":
+		if reason == "" {
+			reason = "No notification for a continuing " + status + " status"
+		}
+		s.add(Event{Time: at, Monitor: monitor, Status: status, Kind: EventSuppressed, Reason: reason})
+	case changed:
+		if reason == "" {
+			reason = fmt.Sprintf("%s -> %s without a notification", previous, status)
+		}
+		s.add(Event{Time: at, Monitor: monitor, Status: status, Kind: EventStatus, Reason: reason})
+	}
+}
+
+// add appends an event, merging it into the previous event of the same monitor
+// when the two only differ in time
+func (s *Simulator) add(event Event) {
+	event.Count = 1
+	if event.Kind == EventFired {
+		s.report.Fired++
+	} else if event.Kind == EventSuppressed {
+		s.report.Suppressed++
+	}
+
+	if i, ok := s.last[event.Monitor]; ok && event.Kind != EventFired {
+		last := &s.report.Events[i]
+		if last.Status == event.Status && last.Kind == event.Kind && last.Reason == event.Reason {
+			last.Count++
+			until := event.Time
+			last.Until = &until
+			return
+		}
+	}
+	s.last[event.Monitor] = len(s.report.Events)
+	s.report.Events = append(s.report.Events, event)
+}
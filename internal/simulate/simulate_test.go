@@ -0,0 +1,116 @@
+package simulate
+
+import (
+	"CheckHealthDO/internal/pkg/config"
+	"CheckHealthDO/internal/pkg/testsupport"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	testsupport.Main(m)
+}
+
+// testConfig enables the CPU, memory and MariaDB monitors with fixed thresholds
+func testConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Monitoring.CPU.Enabled = true
+	cfg.Monitoring.CPU.CheckInterval = 60
+	cfg.Monitoring.CPU.WarningThreshold = 70
+	cfg.Monitoring.CPU.CriticalThreshold = 90
+	cfg.Monitoring.Memory.Enabled = true
+	cfg.Monitoring.Memory.CheckInterval = 60
+	cfg.Monitoring.Memory.WarningThreshold = 80
+	cfg.Monitoring.Memory.CriticalThreshold = 95
+	cfg.Monitoring.Disk.WarningThreshold = 80
+	cfg.Monitoring.Disk.CriticalThreshold = 90
+	cfg.Monitoring.MariaDB.Enabled = true
+	cfg.Monitoring.MariaDB.ServiceName = "mariadb"
+	return cfg
+}
+
+// describe summarizes an event as "time monitor status kind detail"
+func describe(event Event) string {
+	detail := event.Reason
+	if event.Kind == EventFired {
+		detail = event.Subject
+	}
+	result := event.Kind
+	if event.Count > 1 {
+		result = fmt.Sprintf("%s x%d", event.Kind, event.Count)
+	}
+	return fmt.Sprintf("%s %s %s %s: %s", event.Time.Format("15:04:05"), event.Monitor, event.Status, result, detail)
+}
+
+func TestRun(t *testing.T) {
+	samples, err := ReadSamplesFile(testsupport.Testdata("samples.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := New(testConfig()).Run(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"08:01:00 cpu critical fired: CRITICAL CPU Alert",
+		"08:01:00 memory warning fired: Memory Warning",
+		"08:01:30 cpu critical suppressed: CPU alert re-check interval has not passed",
+		"08:02:00 memory warning suppressed x2: Memory warning within the throttle window",
+		"08:05:00 disk:/ critical status: normal -> critical (93.0% used), the disk monitor sends no notifications",
+		"08:06:00 cpu normal fired: CPU Status Normalized",
+		"08:07:00 mariadb stopped fired: NOTICE: MariaDB Service Manually Stopped",
+	}
+	var got []string
+	for _, event := range report.Events {
+		got = append(got, describe(event))
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if report.Samples != 12 || report.Fired != 4 || report.Suppressed != 3 {
+		t.Errorf("report = %d samples, %d fired, %d suppressed, want 12, 4 and 3", report.Samples, report.Fired, report.Suppressed)
+	}
+	if !report.From.Equal(testsupport.Start) || !report.To.Equal(testsupport.Start.Add(7*time.Minute)) {
+		t.Errorf("report covers %s to %s, want 08:00 to 08:07", report.From, report.To)
+	}
+}
+
+func TestRunReportsRecoveryActions(t *testing.T) {
+	cfg := testConfig()
+	cfg.Monitoring.MariaDB.RestartOnThreshold.Enabled = true
+
+	usage := func(v float64) *float64 { return &v }
+	samples := []Sample{
+		{Time: testsupport.Start.Add(time.Minute), Type: TypeMemory, Usage: usage(97)},
+		{Time: testsupport.Start, Type: TypeMemory, Usage: usage(40)},
+	}
+
+	report, err := New(cfg).Run(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, event := range report.Events {
+		got = append(got, describe(event))
+	}
+	want := []string{
+		"08:01:00 memory critical action: Memory recovery actions",
+		"08:01:00 memory critical fired: CRITICAL Memory Alert",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant the samples sorted and:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRunWithoutSamples(t *testing.T) {
+	report, err := New(testConfig()).Run(nil)
+	if err != nil || report.Samples != 0 || len(report.Events) != 0 {
+		t.Errorf("report = %+v, err = %v, want an empty report", report, err)
+	}
+}
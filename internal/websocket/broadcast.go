@@ -5,13 +5,18 @@ import (
 	"encoding/json"
 )
 
+// CPUMessage encodes CPU metrics the way BroadcastCPU sends them
+func CPUMessage(metrics interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"cpu":       metrics,
+		"timestamp": timeNow(),
+	})
+}
+
 // BroadcastCPU sends CPU metrics to all connected clients
 func (r *Registry) BroadcastCPU(metrics interface{}) {
-	if handler := r.GetCPUHandler(); handler != nil {
-		data, err := json.Marshal(map[string]interface{}{
-			"cpu":       metrics,
-			"timestamp": timeNow(),
-		})
+	if handler := r.GetHandler(TopicCPU); handler != nil {
+		data, err := CPUMessage(metrics)
 		if err != nil {
 			logger.Error("Failed to marshal CPU metrics for WebSocket broadcast",
 				logger.String("error", err.Error()))
@@ -23,7 +28,7 @@ func (r *Registry) BroadcastCPU(metrics interface{}) {
 
 // BroadcastSysInfo sends SysInfo metrics to all connected clients
 func (r *Registry) BroadcastSysInfo(metrics interface{}) {
-	if handler := r.GetSysHandler(); handler != nil {
+	if handler := r.GetHandler(TopicSysInfo); handler != nil {
 		data, err := json.Marshal(map[string]interface{}{
 			"sys_info":  metrics,
 			"timestamp": timeNow(),
@@ -39,7 +44,7 @@ func (r *Registry) BroadcastSysInfo(metrics interface{}) {
 
 // BroadcastSysInfo sends SysInfo metrics to all connected clients
 func (r *Registry) BroadcastDisk(metrics interface{}) {
-	if handler := r.GetDiskHandler(); handler != nil {
+	if handler := r.GetHandler(TopicDisk); handler != nil {
 		data, err := json.Marshal(map[string]interface{}{
 			"disk":      metrics,
 			"timestamp": timeNow(),
@@ -55,7 +60,7 @@ func (r *Registry) BroadcastDisk(metrics interface{}) {
 
 // BroadcastMemory sends memory metrics to all connected clients
 func (r *Registry) BroadcastMemory(metrics interface{}) {
-	if handler := r.GetMemoryHandler(); handler != nil {
+	if handler := r.GetHandler(TopicMemory); handler != nil {
 		data, err := json.Marshal(map[string]interface{}{
 			"memory":    metrics,
 			"timestamp": timeNow(),
@@ -69,12 +74,13 @@ func (r *Registry) BroadcastMemory(metrics interface{}) {
 	}
 }
 
-// BroadcastMariaDB sends MariaDB metrics to all connected clients
-func (r *Registry) BroadcastMariaDB(metrics interface{}) {
-	if handler := r.GetMariaDBHandler(); handler != nil {
-		data, err := json.Marshal(metrics)
+// Broadcast sends a message as JSON to the clients of a topic
+func (r *Registry) Broadcast(topic string, message interface{}) {
+	if handler := r.GetHandler(topic); handler != nil {
+		data, err := json.Marshal(message)
 		if err != nil {
-			logger.Error("Failed to marshal MariaDB metrics for WebSocket broadcast",
+			logger.Error("Failed to marshal message for WebSocket broadcast",
+				logger.String("topic", topic),
 				logger.String("error", err.Error()))
 			return
 		}
@@ -92,29 +98,14 @@ func (r *Registry) BroadcastMetrics(metrics interface{}) {
 		return
 	}
 
-	// Determine which handler to use based on metric_type in the data
-	// This allows specific metric types to go to their specific handlers
-	// instead of broadcasting to all handlers
+	// Send the metrics to the topic named by metric_type instead of every handler
 	metricsMap, ok := metrics.(map[string]interface{})
 	if ok {
-		if metricType, exists := metricsMap["metric_type"]; exists {
-			switch metricType {
-			case "cpu":
-				if h := r.GetCPUHandler(); h != nil {
-					h.Broadcast(data)
-				}
-				return
-			case "memory":
-				if h := r.GetMemoryHandler(); h != nil {
-					h.Broadcast(data)
-				}
-				return
-			case "mariadb":
-				if h := r.GetMariaDBHandler(); h != nil {
-					h.Broadcast(data)
-				}
-				return
+		if metricType, exists := metricsMap["metric_type"].(string); exists {
+			if h := r.GetHandler(metricType); h != nil {
+				h.Broadcast(data)
 			}
+			return
 		}
 	}
 
@@ -3,6 +3,7 @@ package websocket
 import (
 	"CheckHealthDO/internal/pkg/logger"
 	"net/http"
+	"sort"
 	"sync"
 
 	"github.com/gorilla/websocket"
@@ -14,20 +15,28 @@ var (
 	once     sync.Once
 )
 
-// Registry manages WebSocket handlers for different services
+// Topics of the built-in monitors, each served at /ws/<topic>
+const (
+	TopicCPU         = "cpu"
+	TopicMemory      = "memory"
+	TopicSysInfo     = "sysinfo"
+	TopicDisk        = "disk"
+	TopicChecks      = "checks"
+	TopicPressure    = "pressure"
+	TopicMariaDB     = "mariadb"
+	TopicMariaDBLogs = "mariadb/logs"
+)
+
+// Registry manages WebSocket handlers for different services, keyed by topic
 type Registry struct {
-	mu              sync.RWMutex
-	cpuHandler      *Handler
-	memoryHandler   *Handler
-	mariaDBHandler  *Handler
-	sysInfoHandlers *Handler
-	diskHandlers    *Handler
+	mu     sync.RWMutex
+	topics map[string]*Handler
 }
 
 // GetRegistry returns the WebSocket registry singleton
 func GetRegistry() *Registry {
 	once.Do(func() {
-		registry = &Registry{}
+		registry = &Registry{topics: make(map[string]*Handler)}
 	})
 	return registry
 }
@@ -60,6 +69,13 @@ func NewHandler() *Handler {
 
 // ServeHTTP handles WebSocket connections
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.ServeWithInitialMessage(w, r)
+}
+
+// ServeWithInitialMessage handles a WebSocket connection like ServeHTTP, sending
+// messages to the client first, in order, so they arrive before any broadcast.
+// Nil messages are skipped.
+func (h *Handler) ServeWithInitialMessage(w http.ResponseWriter, r *http.Request, messages ...[]byte) {
 	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		logger.Error("Failed to upgrade to WebSocket connection",
@@ -67,6 +83,18 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, message := range messages {
+		if message == nil {
+			continue
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			logger.Error("Failed to send initial WebSocket message",
+				logger.String("error", err.Error()))
+			conn.Close()
+			return
+		}
+	}
+
 	client := &Client{conn: conn}
 
 	// Register client
@@ -108,71 +136,42 @@ func (h *Handler) Broadcast(message []byte) {
 	}
 }
 
-// Handler getters and setters for the registry
-
-// GetCPUHandler returns the CPU-specific WebSocket handler
-func (r *Registry) GetCPUHandler() *Handler {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.cpuHandler
-}
-
-func (r *Registry) GetSysHandler() *Handler {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.sysInfoHandlers
-}
-
-func (r *Registry) GetDiskHandler() *Handler {
+// GetHandler returns the handler of a topic, nil until one is registered
+func (r *Registry) GetHandler(topic string) *Handler {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.diskHandlers
+	return r.topics[topic]
 }
 
-// RegisterCPUHandler sets the CPU-specific WebSocket handler
-func (r *Registry) RegisterDiskHandler(handler *Handler) {
+// RegisterHandler sets the handler of a topic
+func (r *Registry) RegisterHandler(topic string, handler *Handler) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.diskHandlers = handler
+	r.topics[topic] = handler
 }
 
-// RegisterCPUHandler sets the CPU-specific WebSocket handler
-func (r *Registry) RegisterCPUHandler(handler *Handler) {
+// HandlerFor returns the handler of a topic, registering a new one on first use
+func (r *Registry) HandlerFor(topic string) *Handler {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.cpuHandler = handler
-}
 
-func (r *Registry) RegisterSysHandler(handler *Handler) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.sysInfoHandlers = handler
-}
-
-// GetMemoryHandler returns the memory-specific WebSocket handler
-func (r *Registry) GetMemoryHandler() *Handler {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return r.memoryHandler
-}
-
-// RegisterMemoryHandler sets the memory-specific WebSocket handler
-func (r *Registry) RegisterMemoryHandler(handler *Handler) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.memoryHandler = handler
+	handler := r.topics[topic]
+	if handler == nil {
+		handler = NewHandler()
+		r.topics[topic] = handler
+	}
+	return handler
 }
 
-// GetMariaDBHandler returns the MariaDB-specific WebSocket handler
-func (r *Registry) GetMariaDBHandler() *Handler {
+// Topics returns the topics with a registered handler, sorted
+func (r *Registry) Topics() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.mariaDBHandler
-}
 
-// RegisterMariaDBHandler sets the MariaDB-specific WebSocket handler
-func (r *Registry) RegisterMariaDBHandler(handler *Handler) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.mariaDBHandler = handler
+	topics := make([]string, 0, len(r.topics))
+	for topic := range r.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
 }